@@ -0,0 +1,51 @@
+// Package barcode encodes QR codes and linear barcodes into module bitmaps,
+// quiet zones are left to the caller
+package barcode
+
+// Bitmap is a grid of modules, dark modules are true. Linear barcodes have a
+// height of one module
+type Bitmap struct {
+	Width   int
+	Height  int
+	modules []bool
+}
+
+func newBitmap(width, height int) *Bitmap {
+	return &Bitmap{
+		Width:   width,
+		Height:  height,
+		modules: make([]bool, width*height),
+	}
+}
+
+// At reports whether the module at x, y is dark
+func (b *Bitmap) At(x, y int) bool {
+	if x < 0 || y < 0 || x >= b.Width || y >= b.Height {
+		return false
+	}
+	return b.modules[y*b.Width+x]
+}
+
+func (b *Bitmap) set(x, y int, dark bool) {
+	b.modules[y*b.Width+x] = dark
+}
+
+// linear returns a one module high bitmap from a pattern of alternating bar
+// and space widths, starting with a bar
+func linear(widths []int) *Bitmap {
+	width := 0
+	for _, w := range widths {
+		width += w
+	}
+
+	b := newBitmap(width, 1)
+	x := 0
+	for i, w := range widths {
+		for j := 0; j < w; j++ {
+			b.set(x, 0, i%2 == 0)
+			x++
+		}
+	}
+
+	return b
+}
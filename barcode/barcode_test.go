@@ -0,0 +1,373 @@
+package barcode
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestQR(t *testing.T) {
+	testcases := []struct {
+		name  string
+		data  string
+		level Level
+		size  int
+		err   bool
+	}{
+		{"short", "hello", M, 21, false},
+		{"url", "https://example.com/orders/230101-ABCDEF?item=1", H, 41, false},
+		{"version 1 full", strings.Repeat("a", 17), L, 21, false},
+		{"version 2", strings.Repeat("a", 18), L, 25, false},
+		{"version info", strings.Repeat("b", 200), Q, 65, false},
+		{"version 40", strings.Repeat("c", 2953), L, 177, false},
+		{"too long", strings.Repeat("c", 2954), L, 0, true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := QR([]byte(tc.data), tc.level)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if b.Width != tc.size || b.Height != tc.size {
+				t.Fatalf("got size %dx%d, want %d", b.Width, b.Height, tc.size)
+			}
+
+			level, mask := readQRFormat(t, b)
+			if level != tc.level {
+				t.Errorf("got level %d, want %d", level, tc.level)
+			}
+
+			if got := readQRData(t, b, level, mask); got != tc.data {
+				t.Errorf("got data %q, want %q", got, tc.data)
+			}
+		})
+	}
+}
+
+// TestQRGolden compares the modules against matrices made by an independent
+// encoder (rsc.io/qr) for the same data, level and mask
+func TestQRGolden(t *testing.T) {
+	testcases := []struct {
+		golden string
+		data   string
+		level  Level
+		mask   int
+	}{
+		{"qr-hello-m.txt", "hello", M, 5},
+		{"qr-url-l.txt", "https://example.com", L, 3},
+		{"qr-order-q.txt", "Orderflo 2024", Q, 6},
+		{"qr-blocks-q.txt", strings.Repeat("0123456789", 5), Q, 7},
+		{"qr-version-h.txt", strings.Repeat("b", 60), H, 1},
+		{"qr-version-m.txt", strings.Repeat("Z", 200), M, 2},
+		{"qr-digits-l.txt", "01234567", L, 0},
+		{"qr-utf8-h.txt", "Zoë ☕", H, 4},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.golden, func(t *testing.T) {
+			golden, err := os.ReadFile(filepath.Join("testdata", tc.golden))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rows := strings.Split(strings.TrimSpace(string(golden)), "\n")
+
+			q, err := qrEncode([]byte(tc.data), tc.level)
+			if err != nil {
+				t.Fatal(err)
+			}
+			q.applyMask(tc.mask)
+			q.drawFormat(tc.level, tc.mask)
+
+			if q.Width != len(rows) || q.Height != len(rows) {
+				t.Fatalf("got size %dx%d, want %d", q.Width, q.Height, len(rows))
+			}
+			for y, row := range rows {
+				for x := range row {
+					if want := row[x] == '#'; q.At(x, y) != want {
+						t.Fatalf("module (%d, %d): got %v, want %v", x, y, q.At(x, y), want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestQRCapacity(t *testing.T) {
+	want := map[Level]int{L: 2953, M: 2331, Q: 1663, H: 1273}
+	for level, n := range want {
+		if got := QRCapacity(level); got != n {
+			t.Errorf("level %d: got %d, want %d", level, got, n)
+		}
+	}
+}
+
+// readQRFormat decodes both copies of the format information
+func readQRFormat(t *testing.T, b *Bitmap) (Level, int) {
+	t.Helper()
+
+	size := b.Width
+	var first, second int
+	for i := 0; i < 15; i++ {
+		var x, y int
+		switch {
+		case i <= 5:
+			x, y = 8, i
+		case i == 6:
+			x, y = 8, 7
+		case i == 7:
+			x, y = 8, 8
+		case i == 8:
+			x, y = 7, 8
+		default:
+			x, y = 14-i, 8
+		}
+		if b.At(x, y) {
+			first |= 1 << i
+		}
+
+		if i < 8 {
+			x, y = size-1-i, 8
+		} else {
+			x, y = 8, size-15+i
+		}
+		if b.At(x, y) {
+			second |= 1 << i
+		}
+	}
+
+	if first != second {
+		t.Fatalf("format copies differ: %015b %015b", first, second)
+	}
+
+	data := (first ^ 0x5412) >> 10
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	if (data<<10|rem)^0x5412 != first {
+		t.Fatalf("invalid format bits %015b", first)
+	}
+
+	for level, bits := range formatBits {
+		if bits == data>>3 {
+			return Level(level), data & 7
+		}
+	}
+	t.Fatalf("unknown level bits %02b", data>>3)
+	return 0, 0
+}
+
+// readQRData unmasks the data modules, checks the error correction of every
+// block and decodes the byte mode segment
+func readQRData(t *testing.T, b *Bitmap, level Level, mask int) string {
+	t.Helper()
+
+	version := (b.Width - 17) / 4
+	q := newQRMatrix(version)
+	q.drawFunctionPatterns()
+
+	var codewords []byte
+	var cur byte
+	n := 0
+	for right := b.Width - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < b.Width; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = b.Width - 1 - vert
+				}
+				if q.isFunction(x, y) {
+					continue
+				}
+				cur <<= 1
+				if b.At(x, y) != qrMasked(mask, x, y) {
+					cur |= 1
+				}
+				if n++; n%8 == 0 {
+					codewords = append(codewords, cur)
+					cur = 0
+				}
+			}
+		}
+	}
+
+	numBlocks := qrBlocks[level][version]
+	eccLen := qrECCPerBlock[level][version]
+	raw := qrRawModules(version) / 8
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+	codewords = codewords[:raw]
+
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i < shortLen+1; i++ {
+		for j := range blocks {
+			if i == shortLen-eccLen && j < numShort {
+				continue
+			}
+			blocks[j] = append(blocks[j], codewords[k])
+			k++
+		}
+	}
+
+	divisor := reedSolomonDivisor(eccLen)
+	var data []byte
+	for i, block := range blocks {
+		n := len(block) - eccLen
+		if !bytes.Equal(reedSolomonRemainder(block[:n], divisor), block[n:]) {
+			t.Fatalf("block %d has invalid error correction", i)
+		}
+		data = append(data, block[:n]...)
+	}
+
+	bit := func(i int) int { return int(data[i/8]>>(7-i%8)) & 1 }
+	read := func(pos, n int) int {
+		v := 0
+		for i := 0; i < n; i++ {
+			v = v<<1 | bit(pos+i)
+		}
+		return v
+	}
+
+	if mode := read(0, 4); mode != 0b0100 {
+		t.Fatalf("got mode %04b, want byte mode", mode)
+	}
+	countBits := qrHeaderBits(version) - 4
+	count := read(4, countBits)
+
+	out := make([]byte, count)
+	for i := range out {
+		out[i] = byte(read(4+countBits+8*i, 8))
+	}
+
+	return string(out)
+}
+
+func TestCode128(t *testing.T) {
+	for i, p := range code128Patterns {
+		sum := 0
+		for _, w := range p {
+			sum += int(w - '0')
+		}
+		if want := 11; i == code128Stop && sum != 13 || i != code128Stop && sum != want {
+			t.Errorf("pattern %d has %d modules", i, sum)
+		}
+	}
+
+	testcases := []struct {
+		name   string
+		data   string
+		values []int
+		err    bool
+	}{
+		// the checksum is the start value plus every value weighted by its
+		// position, modulo 103
+		{"set b", "AB", []int{104, 33, 34, (104 + 33 + 2*34) % 103, 106}, false},
+		{"set c", "123456", []int{105, 12, 34, 56, (105 + 12 + 2*34 + 3*56) % 103, 106}, false},
+		{"odd digit run", "12345", []int{104, 17, 99, 23, 45, (104 + 17 + 2*99 + 3*23 + 4*45) % 103, 106}, false},
+		{"short digit run", "A12", []int{104, 33, 17, 18, (104 + 33 + 2*17 + 3*18) % 103, 106}, false},
+		{"mixed", "AB1234C", []int{104, 33, 34, 99, 12, 34, 100, 35, (104 + 33 + 2*34 + 3*99 + 4*12 + 5*34 + 6*100 + 7*35) % 103, 106}, false},
+		{"empty", "", nil, true},
+		{"non ascii", "café", nil, true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := Code128(tc.data)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var want strings.Builder
+			for _, v := range tc.values {
+				for i, w := range code128Patterns[v] {
+					module := "0"
+					if i%2 == 0 {
+						module = "1"
+					}
+					want.WriteString(strings.Repeat(module, int(w-'0')))
+				}
+			}
+
+			if got := bitmapRow(b); got != want.String() {
+				t.Errorf("got\n%s\nwant\n%s", got, want.String())
+			}
+		})
+	}
+}
+
+func TestEAN(t *testing.T) {
+	testcases := []struct {
+		name    string
+		data    string
+		pattern string
+		err     bool
+	}{
+		{
+			"ean13",
+			"4006381333931",
+			"10100011010100111010111101111010001001011001101010100001010000101000010111010010000101100110101",
+			false,
+		},
+		{
+			"ean13 without check digit",
+			"400638133393",
+			"10100011010100111010111101111010001001011001101010100001010000101000010111010010000101100110101",
+			false,
+		},
+		{"ean8", "96385074", "1010001011010111101111010110111010101001110111001010001001011100101", false},
+		{"invalid check digit", "4006381333932", "", true},
+		{"invalid length", "123", "", true},
+		{"not a digit", "40063813339a", "", true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := EAN(tc.data)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := bitmapRow(b); got != tc.pattern {
+				t.Errorf("got\n%s\nwant\n%s", got, tc.pattern)
+			}
+		})
+	}
+}
+
+func bitmapRow(b *Bitmap) string {
+	var s strings.Builder
+	for x := 0; x < b.Width; x++ {
+		if b.At(x, 0) {
+			s.WriteByte('1')
+		} else {
+			s.WriteByte('0')
+		}
+	}
+	return s.String()
+}
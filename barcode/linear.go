@@ -0,0 +1,185 @@
+package barcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+var code128Patterns = [107]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128CodeC  = 99
+	code128CodeB  = 100
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// Code128 encodes printable ASCII data, runs of four or more digits are
+// packed in code set C
+func Code128(data string) (*Bitmap, error) {
+	if data == "" {
+		return nil, errors.New("code128: empty data")
+	}
+
+	var values []int
+	set := 0
+	for i := 0; i < len(data); {
+		run := 0
+		for i+run < len(data) && isDigit(data[i+run]) {
+			run++
+		}
+
+		if run >= 4 {
+			if run%2 == 1 {
+				// the odd digit goes in code set B so the rest can be paired
+				if set != code128StartB {
+					values = append(values, switchCode128(set, code128StartB))
+					set = code128StartB
+				}
+				values = append(values, int(data[i])-' ')
+				i++
+				run--
+			}
+
+			if set != code128StartC {
+				values = append(values, switchCode128(set, code128StartC))
+				set = code128StartC
+			}
+			for ; run > 0; run -= 2 {
+				values = append(values, int(data[i]-'0')*10+int(data[i+1]-'0'))
+				i += 2
+			}
+			continue
+		}
+
+		c := data[i]
+		if c < ' ' || c > 127 {
+			return nil, fmt.Errorf("code128: unsupported character %q", c)
+		}
+		if set != code128StartB {
+			values = append(values, switchCode128(set, code128StartB))
+			set = code128StartB
+		}
+		values = append(values, int(c)-' ')
+		i++
+	}
+
+	checksum := values[0]
+	for i, v := range values[1:] {
+		checksum += (i + 1) * v
+	}
+	values = append(values, checksum%103, code128Stop)
+
+	var widths []int
+	for _, v := range values {
+		for _, w := range code128Patterns[v] {
+			widths = append(widths, int(w-'0'))
+		}
+	}
+
+	return linear(widths), nil
+}
+
+// switchCode128 returns the start symbol of the code set, or the symbol that
+// switches to it from the current set
+func switchCode128(current, set int) int {
+	if current == 0 {
+		return set
+	}
+	if set == code128StartC {
+		return code128CodeC
+	}
+	return code128CodeB
+}
+
+var (
+	eanL = [10]string{"0001101", "0011001", "0010011", "0111101", "0100011", "0110001", "0101111", "0111011", "0110111", "0001011"}
+	eanG = [10]string{"0100111", "0110011", "0011011", "0100001", "0011101", "0111001", "0000101", "0010001", "0001001", "0010111"}
+	eanR = [10]string{"1110010", "1100110", "1101100", "1000010", "1011100", "1001110", "1010000", "1000100", "1001000", "1110100"}
+
+	// ean13Parity is the L/G pattern of the left half, it encodes the first
+	// digit of EAN-13 codes
+	ean13Parity = [10]string{"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG", "LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL"}
+)
+
+// EAN encodes EAN-13 and EAN-8 codes. The check digit is appended to 12 and 7
+// digit data and verified on 13 and 8 digit data
+func EAN(data string) (*Bitmap, error) {
+	for i := 0; i < len(data); i++ {
+		if !isDigit(data[i]) {
+			return nil, fmt.Errorf("ean: unsupported character %q", data[i])
+		}
+	}
+
+	switch len(data) {
+	case 7, 12:
+		data += string(rune('0' + eanCheckDigit(data)))
+	case 8, 13:
+		if check := eanCheckDigit(data[:len(data)-1]); int(data[len(data)-1]-'0') != check {
+			return nil, fmt.Errorf("ean: invalid check digit, expected %d", check)
+		}
+	default:
+		return nil, fmt.Errorf("ean: data must have 7, 8, 12 or 13 digits, got %d", len(data))
+	}
+
+	var left, right string
+	var parity string
+	if len(data) == 13 {
+		parity = ean13Parity[data[0]-'0']
+		left, right = data[1:7], data[7:]
+	} else {
+		parity = "LLLL"
+		left, right = data[:4], data[4:]
+	}
+
+	pattern := "101"
+	for i := 0; i < len(left); i++ {
+		if parity[i] == 'G' {
+			pattern += eanG[left[i]-'0']
+		} else {
+			pattern += eanL[left[i]-'0']
+		}
+	}
+	pattern += "01010"
+	for i := 0; i < len(right); i++ {
+		pattern += eanR[right[i]-'0']
+	}
+	pattern += "101"
+
+	b := newBitmap(len(pattern), 1)
+	for x := 0; x < len(pattern); x++ {
+		b.set(x, 0, pattern[x] == '1')
+	}
+
+	return b, nil
+}
+
+// eanCheckDigit weights the digits 3 and 1 alternately from the right
+func eanCheckDigit(data string) int {
+	sum := 0
+	for i := 0; i < len(data); i++ {
+		d := int(data[i] - '0')
+		if (len(data)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,512 @@
+package barcode
+
+import (
+	"fmt"
+)
+
+// Level is the error correction level of a QR code
+type Level int
+
+const (
+	// L recovers about 7% of the codewords
+	L Level = iota
+	// M recovers about 15% of the codewords
+	M
+	// Q recovers about 25% of the codewords
+	Q
+	// H recovers about 30% of the codewords
+	H
+)
+
+// formatBits are the level bits of the format information
+var formatBits = [4]int{L: 1, M: 0, Q: 3, H: 2}
+
+// qrECCPerBlock and qrBlocks are indexed by level and version, version 0 is
+// unused
+var qrECCPerBlock = [4][41]int{
+	{0, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{0, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var qrBlocks = [4][41]int{
+	{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{0, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{0, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// QR encodes the data in byte mode using the smallest version that fits it
+func QR(data []byte, level Level) (*Bitmap, error) {
+	q, err := qrEncode(data, level)
+	if err != nil {
+		return nil, err
+	}
+
+	best, bestPenalty := -1, 0
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormat(level, mask)
+		if p := q.penalty(); best < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		q.applyMask(mask)
+	}
+	q.applyMask(best)
+	q.drawFormat(level, best)
+
+	return q.Bitmap, nil
+}
+
+// qrEncode returns the smallest matrix holding the data with its function
+// patterns and codewords drawn, the mask and format are left to the caller
+func qrEncode(data []byte, level Level) (*qrMatrix, error) {
+	if level < L || level > H {
+		return nil, fmt.Errorf("qr: invalid error correction level %d", level)
+	}
+
+	version := 0
+	for v := 1; v <= 40; v++ {
+		if qrHeaderBits(v)+8*len(data) <= 8*qrDataCodewords(v, level) {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("qr: %d bytes don't fit in a QR code", len(data))
+	}
+
+	codewords := qrInterleave(qrData(data, version, level), version, level)
+
+	q := newQRMatrix(version)
+	q.drawFunctionPatterns()
+	q.drawCodewords(codewords)
+
+	return q, nil
+}
+
+// QRCapacity returns the maximum number of bytes a QR code can hold at the
+// level
+func QRCapacity(level Level) int {
+	return (8*qrDataCodewords(40, level) - qrHeaderBits(40)) / 8
+}
+
+// qrHeaderBits is the size of the byte mode indicator and character count
+func qrHeaderBits(version int) int {
+	if version < 10 {
+		return 4 + 8
+	}
+	return 4 + 16
+}
+
+// qrRawModules is the number of modules left for data and error correction
+// once the function patterns are drawn
+func qrRawModules(version int) int {
+	n := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		n -= (25*align-10)*align - 55
+		if version >= 7 {
+			n -= 36
+		}
+	}
+	return n
+}
+
+func qrDataCodewords(version int, level Level) int {
+	return qrRawModules(version)/8 - qrECCPerBlock[level][version]*qrBlocks[level][version]
+}
+
+// qrData returns the data codewords: header, data, terminator and padding
+func qrData(data []byte, version int, level Level) []byte {
+	capacity := qrDataCodewords(version, level) * 8
+
+	var bits []bool
+	appendBits := func(v, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, (v>>i)&1 == 1)
+		}
+	}
+
+	appendBits(0b0100, 4)
+	appendBits(len(data), qrHeaderBits(version)-4)
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+
+	terminator := capacity - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	appendBits(0, terminator)
+	appendBits(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		appendBits(pad, 8)
+	}
+
+	out := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			out[i/8] |= 1 << (7 - i%8)
+		}
+	}
+
+	return out
+}
+
+// qrInterleave splits the data in blocks, appends their error correction
+// codewords and interleaves them
+func qrInterleave(data []byte, version int, level Level) []byte {
+	numBlocks := qrBlocks[level][version]
+	eccLen := qrECCPerBlock[level][version]
+	raw := qrRawModules(version) / 8
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+
+	divisor := reedSolomonDivisor(eccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		n := shortLen - eccLen
+		if i >= numShort {
+			n++
+		}
+		block := append([]byte{}, data[k:k+n]...)
+		k += n
+		ecc := reedSolomonRemainder(block, divisor)
+		if i < numShort {
+			// short blocks are padded so every block has the same length
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	out := make([]byte, 0, raw)
+	for i := 0; i < len(blocks[0]); i++ {
+		for j, block := range blocks {
+			if i != shortLen-eccLen || j >= numShort {
+				out = append(out, block[i])
+			}
+		}
+	}
+
+	return out
+}
+
+// reedSolomonDivisor returns the generator polynomial of the given degree
+// without its leading term
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+
+	return result
+}
+
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMultiply(d, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+type qrMatrix struct {
+	*Bitmap
+	version  int
+	function []bool
+}
+
+func newQRMatrix(version int) *qrMatrix {
+	size := version*4 + 17
+	return &qrMatrix{
+		Bitmap:   newBitmap(size, size),
+		version:  version,
+		function: make([]bool, size*size),
+	}
+}
+
+func (q *qrMatrix) setFunction(x, y int, dark bool) {
+	q.set(x, y, dark)
+	q.function[y*q.Width+x] = true
+}
+
+func (q *qrMatrix) isFunction(x, y int) bool {
+	return q.function[y*q.Width+x]
+}
+
+func (q *qrMatrix) drawFunctionPatterns() {
+	size := q.Width
+
+	for i := 0; i < size; i++ {
+		q.setFunction(6, i, i%2 == 0)
+		q.setFunction(i, 6, i%2 == 0)
+	}
+
+	for _, c := range [][2]int{{3, 3}, {size - 4, 3}, {3, size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := c[0]+dx, c[1]+dy
+				if x < 0 || y < 0 || x >= size || y >= size {
+					continue
+				}
+				dist := chebyshev(dx, dy)
+				q.setFunction(x, y, dist != 2 && dist != 4)
+			}
+		}
+	}
+
+	positions := qrAlignmentPositions(q.version)
+	last := len(positions) - 1
+	for i, y := range positions {
+		for j, x := range positions {
+			// alignment patterns overlapping the finder patterns are skipped
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					q.setFunction(x+dx, y+dy, chebyshev(dx, dy) != 1)
+				}
+			}
+		}
+	}
+
+	// reserve the format areas, they are drawn once the mask is chosen
+	q.drawFormat(L, 0)
+
+	if q.version >= 7 {
+		rem := q.version
+		for i := 0; i < 12; i++ {
+			rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+		}
+		bits := q.version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := (bits>>i)&1 == 1
+			a, b := size-11+i%3, i/3
+			q.setFunction(a, b, dark)
+			q.setFunction(b, a, dark)
+		}
+	}
+}
+
+func qrAlignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+
+	align := version/7 + 2
+	step := (version*4 + align*2 + 1) / (align*2 - 2) * 2
+	if version == 32 {
+		step = 26
+	}
+
+	positions := make([]int, align)
+	positions[0] = 6
+	for i, pos := align-1, version*4+10; i > 0; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+
+	return positions
+}
+
+// drawFormat draws both copies of the format information and the dark module
+func (q *qrMatrix) drawFormat(level Level, mask int) {
+	data := formatBits[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	size := q.Width
+	for i := 0; i <= 5; i++ {
+		q.setFunction(8, i, bit(i))
+	}
+	q.setFunction(8, 7, bit(6))
+	q.setFunction(8, 8, bit(7))
+	q.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		q.setFunction(size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunction(8, size-15+i, bit(i))
+	}
+	q.setFunction(8, size-8, true)
+}
+
+// drawCodewords places the codewords in the zigzag order, two columns at a
+// time from the bottom right corner
+func (q *qrMatrix) drawCodewords(codewords []byte) {
+	size := q.Width
+	i := 0
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// the vertical timing pattern
+			right = 5
+		}
+		for vert := 0; vert < size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = size - 1 - vert
+				}
+				if !q.isFunction(x, y) && i < len(codewords)*8 {
+					q.set(x, y, (codewords[i/8]>>(7-i%8))&1 == 1)
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules selected by the mask, applying it twice
+// undoes it
+func (q *qrMatrix) applyMask(mask int) {
+	for y := 0; y < q.Height; y++ {
+		for x := 0; x < q.Width; x++ {
+			if !q.isFunction(x, y) && qrMasked(mask, x, y) {
+				q.set(x, y, !q.At(x, y))
+			}
+		}
+	}
+}
+
+func qrMasked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+var finderLike = [][]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+// penalty scores the masked matrix with the rules of the specification, lower
+// is easier to scan
+func (q *qrMatrix) penalty() int {
+	size := q.Width
+	penalty := 0
+
+	for _, vertical := range []bool{false, true} {
+		at := func(i, j int) bool {
+			if vertical {
+				return q.At(i, j)
+			}
+			return q.At(j, i)
+		}
+
+		for i := 0; i < size; i++ {
+			run := 1
+			for j := 1; j < size; j++ {
+				if at(i, j) == at(i, j-1) {
+					run++
+					if run == 5 {
+						penalty += 3
+					} else if run > 5 {
+						penalty++
+					}
+				} else {
+					run = 1
+				}
+			}
+
+			for j := 0; j+11 <= size; j++ {
+				for _, pattern := range finderLike {
+					match := true
+					for k, dark := range pattern {
+						if at(i, j+k) != dark {
+							match = false
+							break
+						}
+					}
+					if match {
+						penalty += 40
+					}
+				}
+			}
+		}
+	}
+
+	dark := 0
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			c := q.At(x, y)
+			if c {
+				dark++
+			}
+			if x+1 < size && y+1 < size && c == q.At(x+1, y) && c == q.At(x, y+1) && c == q.At(x+1, y+1) {
+				penalty += 3
+			}
+		}
+	}
+
+	total := size * size
+	deviation := dark*100/total - 50
+	if deviation < 0 {
+		deviation = -deviation
+	}
+	penalty += deviation / 5 * 10
+
+	return penalty
+}
+
+func chebyshev(dx, dy int) int {
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	if dx > dy {
+		return dx
+	}
+	return dy
+}
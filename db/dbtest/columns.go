@@ -0,0 +1,113 @@
+package dbtest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// Columns returns the columns of a table of the database
+type Columns func(t *testing.T, table string) []string
+
+// tables are the tables of the layerhub types stored in the database
+var tables = map[string]string{
+	"User":             "users",
+	"Company":          "companies",
+	"Customer":         "customers",
+	"Font":             "fonts",
+	"EnabledFont":      "enabled_fonts",
+	"Template":         "templates",
+	"Metadata":         "template_metadata",
+	"Project":          "projects",
+	"Frame":            "frames",
+	"Component":        "components",
+	"Upload":           "uploads",
+	"SubscriptionPlan": "subscription_plans",
+	"Billing":          "subscription_plan_billings",
+	"MockupTemplate":   "mockup_templates",
+	"Mockup":           "mockups",
+	"Order":            "orders",
+	"OrderItem":        "order_items",
+	"Proof":            "proofs",
+	"ProofComment":     "proof_comments",
+	"Folder":           "folders",
+	"TenantJob":        "tenant_jobs",
+}
+
+// RunColumns checks that every db tag of the layerhub types is a column of
+// the type's table, columns returns the columns of the migrated database
+func RunColumns(t *testing.T, columns Columns) {
+	tags, err := layerhubTags()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for typ, names := range tags {
+		table, ok := tables[typ]
+		if !ok {
+			t.Errorf("layerhub.%s has db tags but no table in dbtest", typ)
+			continue
+		}
+
+		existing := map[string]bool{}
+		for _, c := range columns(t, table) {
+			existing[c] = true
+		}
+		for _, name := range names {
+			if !existing[name] {
+				t.Errorf("layerhub.%s: column %s.%s doesn't exist", typ, table, name)
+			}
+		}
+	}
+}
+
+// layerhubTags parses the layerhub package and returns the db tags of its
+// struct types
+func layerhubTags() (map[string][]string, error) {
+	_, file, _, _ := runtime.Caller(0)
+	dir := filepath.Join(filepath.Dir(file), "..", "..", "layerhub")
+
+	notTest := func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, notTest, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := map[string][]string{}
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			ast.Inspect(f, func(n ast.Node) bool {
+				spec, ok := n.(*ast.TypeSpec)
+				if !ok {
+					return true
+				}
+				st, ok := spec.Type.(*ast.StructType)
+				if !ok {
+					return true
+				}
+
+				for _, field := range st.Fields.List {
+					if field.Tag == nil {
+						continue
+					}
+					tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("db")
+					if tag == "" || tag == "-" {
+						continue
+					}
+					tags[spec.Name.Name] = append(tags[spec.Name.Name], tag)
+				}
+				return true
+			})
+		}
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,4863 @@
+// Package dbtest is the conformance suite shared by the layerhub.DB
+// implementations
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/fontinfo"
+	"github.com/echovl/orderflo-dev/layerhub"
+)
+
+// NewDB returns an empty database, it's called by every test case
+type NewDB func(t *testing.T) layerhub.DB
+
+// Run runs the conformance suite of layerhub.DB against the implementation
+// returned by newDB
+func Run(t *testing.T, newDB NewDB) {
+	t.Run("PutUser", func(t *testing.T) { testPutUser(t, newDB) })
+	t.Run("FindUsers", func(t *testing.T) { testFindUsers(t, newDB) })
+	t.Run("DeleteUser", func(t *testing.T) { testDeleteUser(t, newDB) })
+	t.Run("BatchCreateFonts", func(t *testing.T) { testBatchCreateFonts(t, newDB) })
+	t.Run("PutFont", func(t *testing.T) { testPutFont(t, newDB) })
+	t.Run("FindFonts", func(t *testing.T) { testFindFonts(t, newDB) })
+	t.Run("CountFonts", func(t *testing.T) { testCountFonts(t, newDB) })
+	t.Run("DeleteFont", func(t *testing.T) { testDeleteFont(t, newDB) })
+	t.Run("PutTemplate", func(t *testing.T) { testPutTemplate(t, newDB) })
+	t.Run("FindTemplates", func(t *testing.T) { testFindTemplates(t, newDB) })
+	t.Run("CountTemplates", func(t *testing.T) { testCountTemplates(t, newDB) })
+	t.Run("DeleteTemplate", func(t *testing.T) { testDeleteTemplate(t, newDB) })
+	t.Run("PutProject", func(t *testing.T) { testPutProject(t, newDB) })
+	t.Run("FindProjects", func(t *testing.T) { testFindProjects(t, newDB) })
+	t.Run("CountProjects", func(t *testing.T) { testCountProjects(t, newDB) })
+	t.Run("DeleteProject", func(t *testing.T) { testDeleteProject(t, newDB) })
+	t.Run("PutUpload", func(t *testing.T) { testPutUpload(t, newDB) })
+	t.Run("FindUploads", func(t *testing.T) { testFindUploads(t, newDB) })
+	t.Run("CountUploads", func(t *testing.T) { testCountUploads(t, newDB) })
+	t.Run("DeleteUploads", func(t *testing.T) { testDeleteUploads(t, newDB) })
+	t.Run("PutComponent", func(t *testing.T) { testPutComponent(t, newDB) })
+	t.Run("FindComponents", func(t *testing.T) { testFindComponents(t, newDB) })
+	t.Run("CountComponents", func(t *testing.T) { testCountComponents(t, newDB) })
+	t.Run("DeleteComponent", func(t *testing.T) { testDeleteComponent(t, newDB) })
+	t.Run("PutFrame", func(t *testing.T) { testPutFrame(t, newDB) })
+	t.Run("FindFrames", func(t *testing.T) { testFindFrames(t, newDB) })
+	t.Run("CountFrames", func(t *testing.T) { testCountFrames(t, newDB) })
+	t.Run("DeleteFrame", func(t *testing.T) { testDeleteFrame(t, newDB) })
+	t.Run("FindEnabledFonts", func(t *testing.T) { testFindEnabledFonts(t, newDB) })
+	t.Run("BatchDeleteEnabledFonts", func(t *testing.T) { testBatchDeleteEnabledFonts(t, newDB) })
+	t.Run("PutSubscriptionPlan", func(t *testing.T) { testPutSubscriptionPlan(t, newDB) })
+	t.Run("PutMockupTemplate", func(t *testing.T) { testPutMockupTemplate(t, newDB) })
+	t.Run("PutMockup", func(t *testing.T) { testPutMockup(t, newDB) })
+	t.Run("DeleteMockup", func(t *testing.T) { testDeleteMockup(t, newDB) })
+	t.Run("PutOrder", func(t *testing.T) { testPutOrder(t, newDB) })
+	t.Run("DeleteOrder", func(t *testing.T) { testDeleteOrder(t, newDB) })
+	t.Run("ClaimOrder", func(t *testing.T) { testClaimOrder(t, newDB) })
+	t.Run("PutProof", func(t *testing.T) { testPutProof(t, newDB) })
+	t.Run("DeleteProof", func(t *testing.T) { testDeleteProof(t, newDB) })
+	t.Run("ProofVersion", func(t *testing.T) { testProofVersion(t, newDB) })
+	t.Run("FindFolders", func(t *testing.T) { testFindFolders(t, newDB) })
+	t.Run("Trash", func(t *testing.T) { testTrash(t, newDB) })
+	t.Run("WithTx", func(t *testing.T) { testWithTx(t, newDB) })
+	t.Run("PutTenantJob", func(t *testing.T) { testPutTenantJob(t, newDB) })
+	t.Run("ClaimTenantJob", func(t *testing.T) { testClaimTenantJob(t, newDB) })
+}
+
+func testPutUser(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testscases := []struct {
+		name         string
+		newUser      layerhub.User
+		updateEmail  string
+		expectedUser layerhub.User
+	}{
+		{
+			name: "new user",
+			newUser: layerhub.User{
+				ID:        "user_1",
+				FirstName: "Jhon",
+				LastName:  "Doe",
+				Email:     "jhon.doe@mail.com",
+				PlanID:    "plan_1",
+				ApiToken:  "token_1",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			expectedUser: layerhub.User{
+				ID:        "user_1",
+				FirstName: "Jhon",
+				LastName:  "Doe",
+				Email:     "jhon.doe@mail.com",
+				PlanID:    "plan_1",
+				ApiToken:  "token_1",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+		{
+			name: "update email",
+			newUser: layerhub.User{
+				ID:        "user_1",
+				FirstName: "Jhon",
+				LastName:  "Doe",
+				Email:     "jhon.doe@mail.com",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			updateEmail: "jhon.doe.123@mail.com",
+			expectedUser: layerhub.User{
+				ID:        "user_1",
+				FirstName: "Jhon",
+				LastName:  "Doe",
+				Email:     "jhon.doe.123@mail.com",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+	}
+
+	for _, tc := range testscases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			err := db.PutUser(context.TODO(), &tc.newUser)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.updateEmail != "" {
+				tc.newUser.Email = tc.updateEmail
+				err := db.PutUser(context.TODO(), &tc.newUser)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			users, err := db.FindUsers(context.TODO(), &layerhub.Filter{ID: tc.newUser.ID, Limit: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(users) == 0 {
+				t.Fatal("user not found")
+			}
+
+			got := users[0]
+			if tc.expectedUser != got {
+				t.Errorf("mismatched users:\ngot: %v\n want: %v", got, tc.expectedUser)
+			}
+		})
+	}
+}
+
+func testFindUsers(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testcases := []struct {
+		name          string
+		query         *layerhub.Filter
+		currentUsers  []layerhub.User
+		expectedUsers []layerhub.User
+	}{
+		{
+			name:  "empty result",
+			query: &layerhub.Filter{ID: "user_2"},
+			currentUsers: []layerhub.User{
+				{
+					ID:        "user_1",
+					FirstName: "Jhon",
+					LastName:  "Doe",
+					Email:     "jhon.doe@mail.com",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedUsers: []layerhub.User{},
+		},
+		{
+			name: "one result",
+			query: &layerhub.Filter{
+				Email: "jhon.doe@mail.com",
+			},
+			currentUsers: []layerhub.User{
+				{
+					ID:        "user_1",
+					FirstName: "Jhon",
+					LastName:  "Doe",
+					Email:     "jhon.doe@mail.com",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "user_2",
+					FirstName: "Jhon",
+					LastName:  "Doe",
+					Email:     "jhon.doe2@mail.com",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedUsers: []layerhub.User{
+				{
+					ID:        "user_1",
+					FirstName: "Jhon",
+					LastName:  "Doe",
+					Email:     "jhon.doe@mail.com",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+		{
+			name:  "multiple results",
+			query: &layerhub.Filter{},
+			currentUsers: []layerhub.User{
+				{
+					ID:        "user_1",
+					FirstName: "Jhon",
+					LastName:  "Doe",
+					Email:     "jhon.doe@mail.com",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "user_2",
+					FirstName: "Jhon",
+					LastName:  "Doe",
+					Email:     "jhon.doe@mail.com",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedUsers: []layerhub.User{
+				{
+					ID:        "user_1",
+					FirstName: "Jhon",
+					LastName:  "Doe",
+					Email:     "jhon.doe@mail.com",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "user_2",
+					FirstName: "Jhon",
+					LastName:  "Doe",
+					Email:     "jhon.doe@mail.com",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentUsers) > 0 {
+				for _, u := range tc.currentUsers {
+					err := db.PutUser(context.TODO(), &u)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			users, err := db.FindUsers(context.TODO(), tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(users, tc.expectedUsers) {
+				t.Fatalf("mismatched find result:\ngot: %v\nwant: %v", users, tc.expectedUsers)
+			}
+		})
+	}
+}
+
+func testDeleteUser(t *testing.T, newDB NewDB) {
+	db := newDB(t)
+	now := layerhub.Now()
+
+	users := []layerhub.User{
+		{ID: "user_1", FirstName: "Jhon", Email: "jhon.doe@mail.com", CompanyID: "company_1", CreatedAt: now, UpdatedAt: now},
+		{ID: "user_2", FirstName: "Jane", Email: "jane.doe@mail.com", CompanyID: "company_1", CreatedAt: now, UpdatedAt: now},
+	}
+	for _, u := range users {
+		if err := db.PutUser(context.TODO(), &u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := db.DeleteUser(context.TODO(), "user_1"); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := db.FindUsers(context.TODO(), &layerhub.Filter{CompanyID: "company_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].ID != "user_2" {
+		t.Errorf("user not deleted:\ngot: %v", found)
+	}
+}
+
+func testBatchCreateFonts(t *testing.T, newDB NewDB) {
+	testcases := []struct {
+		name          string
+		newFonts      []layerhub.Font
+		expectedFonts []layerhub.Font
+	}{
+		{
+			name: "one font",
+			newFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+			},
+			expectedFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+			},
+		},
+		{
+			name: "multiple fonts",
+			newFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+				{
+					ID:             "font_2",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+				{
+					ID:             "font_3",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+			},
+			expectedFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+				{
+					ID:             "font_2",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+				{
+					ID:             "font_3",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			err := db.BatchCreateFonts(context.TODO(), tc.newFonts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fonts, err := db.FindFonts(context.TODO(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(fonts, tc.expectedFonts) {
+				t.Fatalf("mismatched fonts:\ngot: %v\nwant: %v", fonts, tc.expectedFonts)
+			}
+		})
+	}
+}
+
+func testPutFont(t *testing.T, newDB NewDB) {
+	testscases := []struct {
+		name         string
+		newFont      layerhub.Font
+		updateURL    string
+		expectedFont layerhub.Font
+	}{
+		{
+			name: "new font",
+			newFont: layerhub.Font{
+				ID:             "font_1",
+				FullName:       "Fake Font Regular",
+				Family:         "Fake Font",
+				Style:          "Regular",
+				PostscriptName: "FakeFontRegular",
+			},
+			expectedFont: layerhub.Font{
+				ID:             "font_1",
+				FullName:       "Fake Font Regular",
+				Family:         "Fake Font",
+				Style:          "Regular",
+				PostscriptName: "FakeFontRegular",
+			},
+		},
+		{
+			name: "update URL",
+			newFont: layerhub.Font{
+				ID:             "font_1",
+				FullName:       "Fake Font Regular",
+				Family:         "Fake Font",
+				Style:          "Regular",
+				PostscriptName: "FakeFontRegular",
+			},
+			updateURL: "cloudfront.com/layerhub/fakefont.ttf",
+			expectedFont: layerhub.Font{
+				ID:             "font_1",
+				FullName:       "Fake Font Regular",
+				Family:         "Fake Font",
+				Style:          "Regular",
+				PostscriptName: "FakeFontRegular",
+				URL:            "cloudfront.com/layerhub/fakefont.ttf",
+			},
+		},
+		{
+			name: "font metadata",
+			newFont: layerhub.Font{
+				ID:             "font_1",
+				FullName:       "Fake Variable",
+				Family:         "Fake Variable",
+				Style:          "Regular",
+				PostscriptName: "FakeVariable-Regular",
+				Format:         fontinfo.FormatWOFF2,
+				Weight:         400,
+				Width:          5,
+				Italic:         true,
+				Axes:           layerhub.FontAxes{{Tag: "wght", Name: "Weight", Min: 100, Default: 400, Max: 900}},
+				NumGlyphs:      512,
+				Subsets:        layerhub.FontSubsets{"latin", "greek"},
+				Embedding:      fontinfo.EmbeddingEditable,
+				NoSubsetting:   true,
+			},
+			expectedFont: layerhub.Font{
+				ID:             "font_1",
+				FullName:       "Fake Variable",
+				Family:         "Fake Variable",
+				Style:          "Regular",
+				PostscriptName: "FakeVariable-Regular",
+				Format:         fontinfo.FormatWOFF2,
+				Weight:         400,
+				Width:          5,
+				Italic:         true,
+				Axes:           layerhub.FontAxes{{Tag: "wght", Name: "Weight", Min: 100, Default: 400, Max: 900}},
+				NumGlyphs:      512,
+				Subsets:        layerhub.FontSubsets{"latin", "greek"},
+				Embedding:      fontinfo.EmbeddingEditable,
+				NoSubsetting:   true,
+			},
+		},
+	}
+
+	for _, tc := range testscases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			err := db.PutFont(context.TODO(), &tc.newFont)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.updateURL != "" {
+				tc.newFont.URL = tc.updateURL
+				err := db.PutFont(context.TODO(), &tc.newFont)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			fonts, err := db.FindFonts(context.TODO(), &layerhub.Filter{ID: tc.newFont.ID, Limit: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fonts) == 0 {
+				t.Fatal("font not found")
+			}
+
+			got := fonts[0]
+			if !reflect.DeepEqual(tc.expectedFont, got) {
+				t.Errorf("mismatched fonts:\ngot: %v\n want: %v", got, tc.expectedFont)
+			}
+		})
+	}
+}
+
+func testFindFonts(t *testing.T, newDB NewDB) {
+	testcases := []struct {
+		name          string
+		query         *layerhub.Filter
+		currentFonts  []layerhub.Font
+		expectedFonts []layerhub.Font
+		enabledFonts  []string
+	}{
+		{
+			name:  "empty result",
+			query: &layerhub.Filter{ID: "font_2"},
+			currentFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+			},
+			expectedFonts: []layerhub.Font{},
+		},
+		{
+			name: "one result",
+			query: &layerhub.Filter{
+				PostscriptName: "FakeFontRegular",
+			},
+			currentFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+				{
+					ID:             "font_2",
+					FullName:       "Fake Font 2 Regular",
+					Family:         "Fake Font 2",
+					Style:          "Regular",
+					PostscriptName: "FakeFont2Regular",
+				},
+			},
+			expectedFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+			},
+		},
+		{
+			name:  "multiple results",
+			query: &layerhub.Filter{},
+			currentFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+				{
+					ID:             "font_2",
+					FullName:       "Fake Font 2 Regular",
+					Family:         "Fake Font 2",
+					Style:          "Regular",
+					PostscriptName: "FakeFont2Regular",
+				},
+			},
+			expectedFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+				{
+					ID:             "font_2",
+					FullName:       "Fake Font 2 Regular",
+					Family:         "Fake Font 2",
+					Style:          "Regular",
+					PostscriptName: "FakeFont2Regular",
+				},
+			},
+		},
+		{
+			name:  "search enabled fonts",
+			query: &layerhub.Filter{OptionalCustomerID: "user_1", EnabledFonts: ptr.Bool(true)},
+			currentFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+				{
+					ID:             "font_2",
+					FullName:       "Fake Font 2 Regular",
+					Family:         "Fake Font 2",
+					Style:          "Regular",
+					PostscriptName: "FakeFont2Regular",
+				},
+			},
+			enabledFonts: []string{"font_1"},
+			expectedFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+			},
+		},
+		{
+			name:  "search not enabled fonts",
+			query: &layerhub.Filter{OptionalCustomerID: "user_1", EnabledFonts: ptr.Bool(false)},
+			currentFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+				{
+					ID:             "font_2",
+					FullName:       "Fake Font 2 Regular",
+					Family:         "Fake Font 2",
+					Style:          "Regular",
+					PostscriptName: "FakeFont2Regular",
+				},
+			},
+			enabledFonts: []string{},
+			expectedFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+				{
+					ID:             "font_2",
+					FullName:       "Fake Font 2 Regular",
+					Family:         "Fake Font 2",
+					Style:          "Regular",
+					PostscriptName: "FakeFont2Regular",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentFonts) > 0 {
+				for _, f := range tc.currentFonts {
+					err := db.PutFont(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			if len(tc.enabledFonts) > 0 {
+				enabledFonts := make([]*layerhub.EnabledFont, len(tc.enabledFonts))
+				for i, fid := range tc.enabledFonts {
+					enabledFonts[i] = layerhub.NewEnabledFont(tc.query.OptionalCustomerID, fid)
+				}
+				err := db.BatchCreateEnabledFonts(context.TODO(), enabledFonts)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			fonts, err := db.FindFonts(context.TODO(), tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(fonts, tc.expectedFonts) {
+				t.Fatalf("mismatched find result:\ngot: %v\nwant: %v", fonts, tc.expectedFonts)
+			}
+		})
+	}
+}
+
+func testCountFonts(t *testing.T, newDB NewDB) {
+	testcases := []struct {
+		name          string
+		query         *layerhub.Filter
+		enabledFonts  []string
+		currentFonts  []layerhub.Font
+		expectedCount int
+	}{
+		{
+			name:  "empty result",
+			query: &layerhub.Filter{ID: "font_2"},
+			currentFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "one result",
+			query: &layerhub.Filter{
+				PostscriptName: "FakeFontRegular",
+			},
+			currentFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+				{
+					ID:             "font_2",
+					FullName:       "Fake Font 2 Regular",
+					Family:         "Fake Font 2",
+					Style:          "Regular",
+					PostscriptName: "FakeFont2Regular",
+				},
+			},
+			expectedCount: 1,
+		},
+		{
+			name:  "multiple results",
+			query: &layerhub.Filter{},
+			currentFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+				{
+					ID:             "font_2",
+					FullName:       "Fake Font 2 Regular",
+					Family:         "Fake Font 2",
+					Style:          "Regular",
+					PostscriptName: "FakeFont2Regular",
+				},
+			},
+			expectedCount: 2,
+		},
+		{
+			name:  "search enabled fonts",
+			query: &layerhub.Filter{OptionalCustomerID: "user_1", EnabledFonts: ptr.Bool(true)},
+			currentFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+				{
+					ID:             "font_2",
+					FullName:       "Fake Font 2 Regular",
+					Family:         "Fake Font 2",
+					Style:          "Regular",
+					PostscriptName: "FakeFont2Regular",
+				},
+			},
+			enabledFonts:  []string{"font_1"},
+			expectedCount: 1,
+		},
+		{
+			name:  "search not enabled fonts",
+			query: &layerhub.Filter{OptionalCustomerID: "user_1", EnabledFonts: ptr.Bool(false)},
+			currentFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+				{
+					ID:             "font_2",
+					FullName:       "Fake Font 2 Regular",
+					Family:         "Fake Font 2",
+					Style:          "Regular",
+					PostscriptName: "FakeFont2Regular",
+				},
+			},
+			enabledFonts:  []string{},
+			expectedCount: 2,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentFonts) > 0 {
+				for _, f := range tc.currentFonts {
+					err := db.PutFont(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			if len(tc.enabledFonts) > 0 {
+				enabledFonts := make([]*layerhub.EnabledFont, len(tc.enabledFonts))
+				for i, fid := range tc.enabledFonts {
+					enabledFonts[i] = layerhub.NewEnabledFont(tc.query.OptionalCustomerID, fid)
+				}
+				err := db.BatchCreateEnabledFonts(context.TODO(), enabledFonts)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			count, err := db.CountFonts(context.TODO(), tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if count != tc.expectedCount {
+				t.Fatalf("mismatched count result:\ngot: %v\nwant: %v", count, tc.expectedCount)
+			}
+		})
+	}
+}
+
+func testDeleteFont(t *testing.T, newDB NewDB) {
+	testcases := []struct {
+		name         string
+		deleteID     string
+		currentFonts []layerhub.Font
+	}{
+		{
+			name:     "font found",
+			deleteID: "font_1",
+			currentFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+			},
+		},
+		{
+			name:     "font not found",
+			deleteID: "font_2",
+			currentFonts: []layerhub.Font{
+				{
+					ID:             "font_1",
+					FullName:       "Fake Font Regular",
+					Family:         "Fake Font",
+					Style:          "Regular",
+					PostscriptName: "FakeFontRegular",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentFonts) > 0 {
+				for _, f := range tc.currentFonts {
+					err := db.PutFont(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			err := db.DeleteFont(context.TODO(), tc.deleteID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fonts, err := db.FindFonts(context.TODO(), &layerhub.Filter{ID: tc.deleteID})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(fonts) != 0 {
+				t.Fatalf("font not deleted:\ngot: %v", fonts[0])
+			}
+		})
+	}
+}
+
+func testPutTemplate(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testscases := []struct {
+		name             string
+		newTemplate      layerhub.Template
+		updateName       string
+		expectedTemplate layerhub.Template
+	}{
+		{
+			name: "new template",
+			newTemplate: layerhub.Template{
+				ID:   "template_1",
+				Name: "Fake design",
+				Frame: layerhub.Frame{
+					ID:             "template_1",
+					Width:          420,
+					Height:         420,
+					UsedInTemplate: true,
+				},
+				Metadata: layerhub.Metadata{
+					ID:      "template_1",
+					License: "MIT",
+				},
+				Tags:      []string{"awesome", "free"},
+				Colors:    []string{"white", "blue"},
+				Preview:   "cloudfront.com/preview/1.png",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			expectedTemplate: layerhub.Template{
+				ID:   "template_1",
+				Name: "Fake design",
+				Frame: layerhub.Frame{
+					ID:             "template_1",
+					Width:          420,
+					Height:         420,
+					UsedInTemplate: true,
+				},
+				Metadata: layerhub.Metadata{
+					ID:      "template_1",
+					License: "MIT",
+				},
+				Tags:      []string{"awesome", "free"},
+				Colors:    []string{"white", "blue"},
+				Preview:   "cloudfront.com/preview/1.png",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+		{
+			name: "update name",
+			newTemplate: layerhub.Template{
+				ID:   "template_1",
+				Name: "Fake design",
+				Frame: layerhub.Frame{
+					ID:             "template_1",
+					Width:          420,
+					Height:         420,
+					UsedInTemplate: true,
+				},
+				Metadata: layerhub.Metadata{
+					ID:      "template_1",
+					License: "MIT",
+				},
+				Tags:      []string{},
+				Colors:    []string{},
+				Preview:   "cloudfront.com/preview/1.png",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			updateName: "Updated fake design",
+			expectedTemplate: layerhub.Template{
+				ID:   "template_1",
+				Name: "Updated fake design",
+				Frame: layerhub.Frame{
+					ID:             "template_1",
+					Width:          420,
+					Height:         420,
+					UsedInTemplate: true,
+				},
+				Metadata: layerhub.Metadata{
+					ID:      "template_1",
+					License: "MIT",
+				},
+				Tags:      []string{},
+				Colors:    []string{},
+				Preview:   "cloudfront.com/preview/1.png",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+	}
+
+	for _, tc := range testscases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			err := db.PutTemplate(context.TODO(), &tc.newTemplate)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.updateName != "" {
+				tc.newTemplate.Name = tc.updateName
+				err := db.PutTemplate(context.TODO(), &tc.newTemplate)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			templates, err := db.FindTemplates(context.TODO(), &layerhub.Filter{ID: tc.newTemplate.ID, Limit: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(templates) == 0 {
+				t.Fatal("template not found")
+			}
+
+			got := templates[0]
+			if !reflect.DeepEqual(tc.expectedTemplate, got) {
+				t.Errorf("mismatched templates:\ngot: %v\n want: %v", got, tc.expectedTemplate)
+			}
+		})
+	}
+}
+
+func testFindTemplates(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	published := true
+	testcases := []struct {
+		name              string
+		query             *layerhub.Filter
+		currentTemplates  []layerhub.Template
+		expectedTemplates []layerhub.Template
+	}{
+		{
+			name:  "empty result",
+			query: &layerhub.Filter{ID: "template_2"},
+			currentTemplates: []layerhub.Template{
+				{
+					ID:   "template_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "template_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_1",
+						License: "MIT",
+					},
+					Tags:      []string{},
+					Colors:    []string{},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedTemplates: []layerhub.Template{},
+		},
+		{
+			name: "one result",
+			query: &layerhub.Filter{
+				ID: "template_2",
+			},
+			currentTemplates: []layerhub.Template{
+				{
+					ID:   "template_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "template_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_1",
+						License: "MIT",
+					},
+					Tags:      []string{},
+					Colors:    []string{},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:   "template_2",
+					Name: "Fake design 2",
+					Frame: layerhub.Frame{
+						ID:             "template_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_2",
+						License: "MIT",
+					},
+					Tags:      []string{},
+					Colors:    []string{},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedTemplates: []layerhub.Template{
+				{
+					ID:   "template_2",
+					Name: "Fake design 2",
+					Frame: layerhub.Frame{
+						ID:             "template_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_2",
+						License: "MIT",
+					},
+					Tags:      []string{},
+					Colors:    []string{},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+		{
+			name:  "multiple result",
+			query: &layerhub.Filter{},
+			currentTemplates: []layerhub.Template{
+				{
+					ID:   "template_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "template_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_1",
+						License: "MIT",
+					},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:   "template_2",
+					Name: "Fake design 2",
+					Frame: layerhub.Frame{
+						ID:             "template_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_2",
+						License: "MIT",
+					},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedTemplates: []layerhub.Template{
+				{
+					ID:   "template_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "template_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_1",
+						License: "MIT",
+					},
+					Tags:      []string{},
+					Colors:    []string{},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:   "template_2",
+					Name: "Fake design 2",
+					Frame: layerhub.Frame{
+						ID:             "template_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_2",
+						License: "MIT",
+					},
+					Tags:      []string{},
+					Colors:    []string{},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+		{
+			name: "regular or short id",
+			query: &layerhub.Filter{
+				RegularOrShortID: "short_2",
+			},
+			currentTemplates: []layerhub.Template{
+				{
+					ID:        "template_1",
+					ShortID:   "short_1",
+					Name:      "Fake design 1",
+					CompanyID: "company_1",
+					Frame: layerhub.Frame{
+						ID:             "template_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_1",
+						License: "MIT",
+					},
+					Tags:      []string{},
+					Colors:    []string{},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "template_2",
+					ShortID:   "short_2",
+					Name:      "Fake design 2",
+					CompanyID: "company_2",
+					Frame: layerhub.Frame{
+						ID:             "template_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_2",
+						License: "MIT",
+					},
+					Tags:      []string{},
+					Colors:    []string{},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedTemplates: []layerhub.Template{
+				{
+					ID:        "template_2",
+					ShortID:   "short_2",
+					Name:      "Fake design 2",
+					CompanyID: "company_2",
+					Frame: layerhub.Frame{
+						ID:             "template_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_2",
+						License: "MIT",
+					},
+					Tags:      []string{},
+					Colors:    []string{},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+		{
+			name: "regular or short id of another company",
+			query: &layerhub.Filter{
+				RegularOrShortID: "template_1",
+				CompanyID:        "company_2",
+			},
+			currentTemplates: []layerhub.Template{
+				{
+					ID:        "template_1",
+					ShortID:   "short_1",
+					Name:      "Fake design 1",
+					CompanyID: "company_1",
+					Frame: layerhub.Frame{
+						ID:             "template_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_1",
+						License: "MIT",
+					},
+					Tags:      []string{},
+					Colors:    []string{},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "template_2",
+					ShortID:   "short_2",
+					Name:      "Fake design 2",
+					CompanyID: "company_2",
+					Frame: layerhub.Frame{
+						ID:             "template_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_2",
+						License: "MIT",
+					},
+					Tags:      []string{},
+					Colors:    []string{},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedTemplates: []layerhub.Template{},
+		},
+		{
+			name: "short id of another company",
+			query: &layerhub.Filter{
+				RegularOrShortID: "short_1",
+				CompanyID:        "company_2",
+			},
+			currentTemplates: []layerhub.Template{
+				{
+					ID:        "template_1",
+					ShortID:   "short_1",
+					Name:      "Fake design 1",
+					CompanyID: "company_1",
+					Frame: layerhub.Frame{
+						ID:             "template_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_1",
+						License: "MIT",
+					},
+					Tags:      []string{},
+					Colors:    []string{},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "template_2",
+					ShortID:   "short_2",
+					Name:      "Fake design 2",
+					CompanyID: "company_2",
+					Frame: layerhub.Frame{
+						ID:             "template_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_2",
+						License: "MIT",
+					},
+					Tags:      []string{},
+					Colors:    []string{},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedTemplates: []layerhub.Template{},
+		},
+		{
+			name: "gallery frame size",
+			query: &layerhub.Filter{
+				Published:   &published,
+				FrameWidth:  8.27,
+				FrameHeight: 11.69,
+				FrameUnit:   layerhub.Inches,
+			},
+			currentTemplates: []layerhub.Template{
+				{
+					ID:        "template_1",
+					Name:      "Fake design 1",
+					Published: true,
+					Public:    true,
+					Frame: layerhub.Frame{
+						ID:             "template_1",
+						Width:          8.27,
+						Height:         11.69,
+						Unit:           layerhub.Inches,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_1",
+						License: "MIT",
+					},
+					Tags:      []string{},
+					Colors:    []string{},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "template_2",
+					Name:      "Fake design 2",
+					Published: true,
+					Public:    true,
+					Frame: layerhub.Frame{
+						ID:             "template_2",
+						Width:          8.5,
+						Height:         11,
+						Unit:           layerhub.Inches,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_2",
+						License: "MIT",
+					},
+					Tags:      []string{},
+					Colors:    []string{},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedTemplates: []layerhub.Template{
+				{
+					ID:        "template_1",
+					Name:      "Fake design 1",
+					Published: true,
+					Public:    true,
+					Frame: layerhub.Frame{
+						ID:             "template_1",
+						Width:          8.27,
+						Height:         11.69,
+						Unit:           layerhub.Inches,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_1",
+						License: "MIT",
+					},
+					Tags:      []string{},
+					Colors:    []string{},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+		{
+			name: "gallery",
+			query: &layerhub.Filter{
+				Published:         &published,
+				Tag:               "birthday",
+				Color:             "#ffffff",
+				License:           "MIT",
+				FrameWidth:        420,
+				FrameHeight:       420,
+				PublicOrCompanyID: "company_3",
+			},
+			currentTemplates: []layerhub.Template{
+				{
+					ID:        "template_1",
+					Name:      "Fake design 1",
+					Published: true,
+					Public:    true,
+					CompanyID: "company_1",
+					Frame: layerhub.Frame{
+						ID:             "template_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_1",
+						License: "MIT",
+					},
+					Tags:      []string{"birthday"},
+					Colors:    []string{"#ffffff"},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "template_2",
+					Name:      "Fake design 2",
+					Published: true,
+					Public:    false,
+					CompanyID: "company_2",
+					Frame: layerhub.Frame{
+						ID:             "template_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_2",
+						License: "MIT",
+					},
+					Tags:      []string{"birthday"},
+					Colors:    []string{"#ffffff"},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedTemplates: []layerhub.Template{
+				{
+					ID:        "template_1",
+					Name:      "Fake design 1",
+					Published: true,
+					Public:    true,
+					CompanyID: "company_1",
+					Frame: layerhub.Frame{
+						ID:             "template_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Metadata: layerhub.Metadata{
+						ID:      "template_1",
+						License: "MIT",
+					},
+					Tags:      []string{"birthday"},
+					Colors:    []string{"#ffffff"},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentTemplates) > 0 {
+				for _, f := range tc.currentTemplates {
+					err := db.PutTemplate(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			templates, err := db.FindTemplates(context.TODO(), tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(templates, tc.expectedTemplates) {
+				t.Fatalf("mismatched find result:\ngot: %v\nwant: %v", templates, tc.expectedTemplates)
+			}
+		})
+	}
+}
+
+func testCountTemplates(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testcases := []struct {
+		name             string
+		query            *layerhub.Filter
+		currentTemplates []layerhub.Template
+		expectedCount    int
+	}{
+		{
+			name:  "empty result",
+			query: &layerhub.Filter{ID: "template_2"},
+			currentTemplates: []layerhub.Template{
+				{
+					ID:   "template_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "template_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "one result",
+			query: &layerhub.Filter{
+				ID: "template_2",
+			},
+			currentTemplates: []layerhub.Template{
+				{
+					ID:   "template_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "template_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:   "template_2",
+					Name: "Fake design 2",
+					Frame: layerhub.Frame{
+						ID:             "template_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedCount: 1,
+		},
+		{
+			name:  "multiple result",
+			query: &layerhub.Filter{},
+			currentTemplates: []layerhub.Template{
+				{
+					ID:   "template_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "template_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:   "template_2",
+					Name: "Fake design 2",
+					Frame: layerhub.Frame{
+						ID:             "template_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedCount: 2,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentTemplates) > 0 {
+				for _, f := range tc.currentTemplates {
+					err := db.PutTemplate(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			count, err := db.CountTemplates(context.TODO(), tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if count != tc.expectedCount {
+				t.Fatalf("mismatched count result:\ngot: %v\nwant: %v", count, tc.expectedCount)
+			}
+		})
+	}
+}
+
+func testDeleteTemplate(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testcases := []struct {
+		name             string
+		deleteID         string
+		currentTemplates []layerhub.Template
+	}{
+		{
+			name:     "template found",
+			deleteID: "template_1",
+			currentTemplates: []layerhub.Template{
+				{
+					ID:   "template_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "template_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+		{
+			name:     "template not found",
+			deleteID: "template_2",
+			currentTemplates: []layerhub.Template{
+				{
+					ID:   "template_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "template_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentTemplates) > 0 {
+				for _, f := range tc.currentTemplates {
+					err := db.PutTemplate(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			err := db.DeleteTemplate(context.TODO(), tc.deleteID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			templates, err := db.FindTemplates(context.TODO(), &layerhub.Filter{ID: tc.deleteID})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(templates) != 0 {
+				t.Fatalf("template not deleted:\ngot: %v", templates[0])
+			}
+		})
+	}
+}
+
+func testPutProject(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testscases := []struct {
+		name            string
+		newProject      layerhub.Project
+		updateName      string
+		expectedProject layerhub.Project
+	}{
+		{
+			name: "new project",
+			newProject: layerhub.Project{
+				ID:   "project_1",
+				Name: "Fake design",
+				Frame: layerhub.Frame{
+					ID:             "project_1",
+					Width:          420,
+					Height:         420,
+					UsedInTemplate: true,
+				},
+				Preview:   "cloudfront.com/preview/1.png",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			expectedProject: layerhub.Project{
+				ID:   "project_1",
+				Name: "Fake design",
+				Frame: layerhub.Frame{
+					ID:             "project_1",
+					Width:          420,
+					Height:         420,
+					UsedInTemplate: true,
+				},
+				Preview:   "cloudfront.com/preview/1.png",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+		{
+			name: "update name",
+			newProject: layerhub.Project{
+				ID:   "project_1",
+				Name: "Fake design",
+				Frame: layerhub.Frame{
+					ID:     "project_1",
+					Width:  420,
+					Height: 420,
+				},
+				Preview:   "cloudfront.com/preview/1.png",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			updateName: "Updated fake design",
+			expectedProject: layerhub.Project{
+				ID:   "project_1",
+				Name: "Updated fake design",
+				Frame: layerhub.Frame{
+					ID:             "project_1",
+					Width:          420,
+					Height:         420,
+					UsedInTemplate: true,
+				},
+				Preview:   "cloudfront.com/preview/1.png",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+	}
+
+	for _, tc := range testscases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			err := db.PutProject(context.TODO(), &tc.newProject)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.updateName != "" {
+				tc.newProject.Name = tc.updateName
+				err := db.PutProject(context.TODO(), &tc.newProject)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			projects, err := db.FindProjects(context.TODO(), &layerhub.Filter{ID: tc.newProject.ID, Limit: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(projects) == 0 {
+				t.Fatal("project not found")
+			}
+
+			got := projects[0]
+			if !reflect.DeepEqual(tc.expectedProject, got) {
+				t.Errorf("mismatched projects:\ngot: %+v\n want: %+v", got, tc.expectedProject)
+			}
+		})
+	}
+}
+
+func testFindProjects(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testcases := []struct {
+		name             string
+		query            *layerhub.Filter
+		currentProjects  []layerhub.Project
+		expectedProjects []layerhub.Project
+	}{
+		{
+			name:  "empty result",
+			query: &layerhub.Filter{ID: "project_2"},
+			currentProjects: []layerhub.Project{
+				{
+					ID:   "project_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "project_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedProjects: []layerhub.Project{},
+		},
+		{
+			name: "one result",
+			query: &layerhub.Filter{
+				ID: "project_2",
+			},
+			currentProjects: []layerhub.Project{
+				{
+					ID:   "project_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "project_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:   "project_2",
+					Name: "Fake design 2",
+					Frame: layerhub.Frame{
+						ID:             "project_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedProjects: []layerhub.Project{
+				{
+					ID:   "project_2",
+					Name: "Fake design 2",
+					Frame: layerhub.Frame{
+						ID:             "project_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+		{
+			name:  "multiple result",
+			query: &layerhub.Filter{},
+			currentProjects: []layerhub.Project{
+				{
+					ID:   "project_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "project_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:   "project_2",
+					Name: "Fake design 2",
+					Frame: layerhub.Frame{
+						ID:             "project_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedProjects: []layerhub.Project{
+				{
+					ID:   "project_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "project_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:   "project_2",
+					Name: "Fake design 2",
+					Frame: layerhub.Frame{
+						ID:             "project_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentProjects) > 0 {
+				for _, f := range tc.currentProjects {
+					err := db.PutProject(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			projects, err := db.FindProjects(context.TODO(), tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(projects, tc.expectedProjects) {
+				t.Fatalf("mismatched find result:\ngot: %v\nwant: %v", projects, tc.expectedProjects)
+			}
+		})
+	}
+}
+
+func testCountProjects(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testcases := []struct {
+		name            string
+		query           *layerhub.Filter
+		currentProjects []layerhub.Project
+		expectedCount   int
+	}{
+		{
+			name:  "empty result",
+			query: &layerhub.Filter{ID: "project_2"},
+			currentProjects: []layerhub.Project{
+				{
+					ID:   "project_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "project_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "one result",
+			query: &layerhub.Filter{
+				ID: "project_2",
+			},
+			currentProjects: []layerhub.Project{
+				{
+					ID:   "project_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "project_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:   "project_2",
+					Name: "Fake design 2",
+					Frame: layerhub.Frame{
+						ID:             "project_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedCount: 1,
+		},
+		{
+			name:  "multiple result",
+			query: &layerhub.Filter{},
+			currentProjects: []layerhub.Project{
+				{
+					ID:   "project_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "project_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:   "project_2",
+					Name: "Fake design 2",
+					Frame: layerhub.Frame{
+						ID:             "project_2",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedCount: 2,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentProjects) > 0 {
+				for _, f := range tc.currentProjects {
+					err := db.PutProject(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			count, err := db.CountProjects(context.TODO(), tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if count != tc.expectedCount {
+				t.Fatalf("mismatched count result:\ngot: %v\nwant: %v", count, tc.expectedCount)
+			}
+		})
+	}
+}
+
+func testDeleteProject(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testcases := []struct {
+		name            string
+		deleteID        string
+		currentProjects []layerhub.Project
+	}{
+		{
+			name:     "project found",
+			deleteID: "project_1",
+			currentProjects: []layerhub.Project{
+				{
+					ID:   "project_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "project_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+		{
+			name:     "project not found",
+			deleteID: "project_2",
+			currentProjects: []layerhub.Project{
+				{
+					ID:   "project_1",
+					Name: "Fake design",
+					Frame: layerhub.Frame{
+						ID:             "project_1",
+						Width:          420,
+						Height:         420,
+						UsedInTemplate: true,
+					},
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentProjects) > 0 {
+				for _, f := range tc.currentProjects {
+					err := db.PutProject(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			err := db.DeleteProject(context.TODO(), tc.deleteID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			projects, err := db.FindProjects(context.TODO(), &layerhub.Filter{ID: tc.deleteID})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(projects) != 0 {
+				t.Fatalf("project not deleted:\ngot: %v", projects[0])
+			}
+		})
+	}
+}
+
+func testPutUpload(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testscases := []struct {
+		name           string
+		newUpload      layerhub.Upload
+		updateURL      string
+		expectedUpload layerhub.Upload
+	}{
+		{
+			name: "new upload",
+			newUpload: layerhub.Upload{
+				ID:        "upload_1",
+				Name:      "Fake image",
+				CompanyID: "company_1",
+				URL:       "cloudfront.com/uploads/1.png",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			expectedUpload: layerhub.Upload{
+				ID:        "upload_1",
+				Name:      "Fake image",
+				CompanyID: "company_1",
+				URL:       "cloudfront.com/uploads/1.png",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+		{
+			name: "update url",
+			newUpload: layerhub.Upload{
+				ID:        "upload_1",
+				Name:      "Fake image",
+				CompanyID: "company_1",
+				URL:       "cloudfront.com/uploads/1.png",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			updateURL: "cloudfront.com/uploads/6.png",
+			expectedUpload: layerhub.Upload{
+				ID:        "upload_1",
+				Name:      "Fake image",
+				CompanyID: "company_1",
+				URL:       "cloudfront.com/uploads/6.png",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+	}
+
+	for _, tc := range testscases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			err := db.PutUpload(context.TODO(), &tc.newUpload)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.updateURL != "" {
+				tc.newUpload.URL = tc.updateURL
+				err := db.PutUpload(context.TODO(), &tc.newUpload)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			uploads, err := db.FindUploads(context.TODO(), &layerhub.Filter{ID: tc.newUpload.ID, Limit: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(uploads) == 0 {
+				t.Fatal("upload not found")
+			}
+
+			got := uploads[0]
+			if tc.expectedUpload != got {
+				t.Errorf("mismatched uploads:\ngot: %v\n want: %v", got, tc.expectedUpload)
+			}
+		})
+	}
+}
+
+func testFindUploads(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testcases := []struct {
+		name            string
+		query           *layerhub.Filter
+		currentUploads  []layerhub.Upload
+		expectedUploads []layerhub.Upload
+	}{
+		{
+			name:  "empty result",
+			query: &layerhub.Filter{ID: "upload_2"},
+			currentUploads: []layerhub.Upload{
+				{
+					ID:        "upload_1",
+					Name:      "Fake image",
+					CompanyID: "company_1",
+					URL:       "cloudfront.com/uploads/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedUploads: []layerhub.Upload{},
+		},
+		{
+			name: "one result",
+			query: &layerhub.Filter{
+				ID: "upload_2",
+			},
+			currentUploads: []layerhub.Upload{
+				{
+					ID:        "upload_1",
+					Name:      "Fake image",
+					CompanyID: "company_1",
+					URL:       "cloudfront.com/uploads/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "upload_2",
+					Name:      "Fake image",
+					CompanyID: "company_1",
+					URL:       "cloudfront.com/uploads/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedUploads: []layerhub.Upload{
+				{
+					ID:        "upload_2",
+					Name:      "Fake image",
+					CompanyID: "company_1",
+					URL:       "cloudfront.com/uploads/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+		{
+			name:  "multiple result",
+			query: &layerhub.Filter{},
+			currentUploads: []layerhub.Upload{
+				{
+					ID:        "upload_1",
+					Name:      "Fake image",
+					CompanyID: "company_1",
+					URL:       "cloudfront.com/uploads/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "upload_2",
+					Name:      "Fake image",
+					CompanyID: "company_1",
+					URL:       "cloudfront.com/uploads/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedUploads: []layerhub.Upload{
+				{
+					ID:        "upload_1",
+					Name:      "Fake image",
+					CompanyID: "company_1",
+					URL:       "cloudfront.com/uploads/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "upload_2",
+					Name:      "Fake image",
+					CompanyID: "company_1",
+					URL:       "cloudfront.com/uploads/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentUploads) > 0 {
+				for _, f := range tc.currentUploads {
+					err := db.PutUpload(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			uploads, err := db.FindUploads(context.TODO(), tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(uploads, tc.expectedUploads) {
+				t.Fatalf("mismatched find result:\ngot: %v\nwant: %v", uploads, tc.expectedUploads)
+			}
+		})
+	}
+}
+
+func testCountUploads(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testcases := []struct {
+		name           string
+		query          *layerhub.Filter
+		currentUploads []layerhub.Upload
+		expectedCount  int
+	}{
+		{
+			name:  "empty result",
+			query: &layerhub.Filter{ID: "upload_2"},
+			currentUploads: []layerhub.Upload{
+				{
+					ID:        "upload_1",
+					Name:      "Fake image",
+					CompanyID: "company_1",
+					URL:       "cloudfront.com/uploads/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "one result",
+			query: &layerhub.Filter{
+				ID: "upload_2",
+			},
+			currentUploads: []layerhub.Upload{
+				{
+					ID:        "upload_1",
+					Name:      "Fake image",
+					CompanyID: "company_1",
+					URL:       "cloudfront.com/uploads/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "upload_2",
+					Name:      "Fake image",
+					CompanyID: "company_1",
+					URL:       "cloudfront.com/uploads/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedCount: 1,
+		},
+		{
+			name:  "multiple result",
+			query: &layerhub.Filter{},
+			currentUploads: []layerhub.Upload{
+				{
+					ID:        "upload_1",
+					Name:      "Fake image",
+					CompanyID: "company_1",
+					URL:       "cloudfront.com/uploads/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "upload_2",
+					Name:      "Fake image",
+					CompanyID: "company_1",
+					URL:       "cloudfront.com/uploads/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedCount: 2,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentUploads) > 0 {
+				for _, f := range tc.currentUploads {
+					err := db.PutUpload(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			count, err := db.CountUploads(context.TODO(), tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if count != tc.expectedCount {
+				t.Fatalf("mismatched find result:\ngot: %v\nwant: %v", count, tc.expectedCount)
+			}
+		})
+	}
+}
+
+func testDeleteUploads(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testcases := []struct {
+		name           string
+		deleteID       string
+		currentUploads []layerhub.Upload
+	}{
+		{
+			name:     "upload found",
+			deleteID: "upload_1",
+			currentUploads: []layerhub.Upload{
+				{
+					ID:        "upload_1",
+					Name:      "Fake image",
+					CompanyID: "company_1",
+					URL:       "cloudfront.com/uploads/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+		{
+			name:     "upload not found",
+			deleteID: "upload_2",
+			currentUploads: []layerhub.Upload{
+				{
+					ID:        "upload_1",
+					Name:      "Fake image",
+					CompanyID: "company_1",
+					URL:       "cloudfront.com/uploads/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentUploads) > 0 {
+				for _, f := range tc.currentUploads {
+					err := db.PutUpload(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			err := db.DeleteUpload(context.TODO(), tc.deleteID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			uploads, err := db.FindUploads(context.TODO(), &layerhub.Filter{ID: tc.deleteID})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(uploads) != 0 {
+				t.Fatalf("upload not deleted:\ngot: %v", uploads[0])
+			}
+		})
+	}
+}
+
+func testPutComponent(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testscases := []struct {
+		name              string
+		newComponent      layerhub.Component
+		updateName        string
+		expectedComponent layerhub.Component
+	}{
+		{
+			name: "new component",
+			newComponent: layerhub.Component{
+				ID:        "component_1",
+				Name:      "Fake design",
+				Preview:   "cloudfront.com/preview/1.png",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			expectedComponent: layerhub.Component{
+				ID:        "component_1",
+				Name:      "Fake design",
+				Preview:   "cloudfront.com/preview/1.png",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+		{
+			name: "update name",
+			newComponent: layerhub.Component{
+				ID:        "component_1",
+				Name:      "Fake design",
+				Preview:   "cloudfront.com/preview/1.png",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			updateName: "Updated fake design",
+			expectedComponent: layerhub.Component{
+				ID:        "component_1",
+				Name:      "Updated fake design",
+				Preview:   "cloudfront.com/preview/1.png",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+	}
+
+	for _, tc := range testscases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			err := db.PutComponent(context.TODO(), &tc.newComponent)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.updateName != "" {
+				tc.newComponent.Name = tc.updateName
+				err := db.PutComponent(context.TODO(), &tc.newComponent)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			components, err := db.FindComponents(context.TODO(), &layerhub.Filter{ID: tc.newComponent.ID, Limit: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(components) == 0 {
+				t.Fatal("component not found")
+			}
+
+			got := components[0]
+			if !reflect.DeepEqual(tc.expectedComponent, got) {
+				t.Errorf("mismatched components:\ngot: %v\n want: %v", got, tc.expectedComponent)
+			}
+		})
+	}
+}
+
+func testFindComponents(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testcases := []struct {
+		name               string
+		query              *layerhub.Filter
+		currentComponents  []layerhub.Component
+		expectedComponents []layerhub.Component
+	}{
+		{
+			name:  "empty result",
+			query: &layerhub.Filter{ID: "component_2"},
+			currentComponents: []layerhub.Component{
+				{
+					ID:        "component_1",
+					Name:      "Fake design",
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedComponents: []layerhub.Component{},
+		},
+		{
+			name: "one result",
+			query: &layerhub.Filter{
+				ID: "component_2",
+			},
+			currentComponents: []layerhub.Component{
+				{
+					ID:        "component_1",
+					Name:      "Fake design",
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "component_2",
+					Name:      "Fake design 2",
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedComponents: []layerhub.Component{
+				{
+					ID:        "component_2",
+					Name:      "Fake design 2",
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+		{
+			name:  "multiple result",
+			query: &layerhub.Filter{},
+			currentComponents: []layerhub.Component{
+				{
+					ID:        "component_1",
+					Name:      "Fake design",
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "component_2",
+					Name:      "Fake design 2",
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedComponents: []layerhub.Component{
+				{
+					ID:        "component_1",
+					Name:      "Fake design",
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "component_2",
+					Name:      "Fake design 2",
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentComponents) > 0 {
+				for _, f := range tc.currentComponents {
+					err := db.PutComponent(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			components, err := db.FindComponents(context.TODO(), tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(components, tc.expectedComponents) {
+				t.Fatalf("mismatched find result:\ngot: %v\nwant: %v", components, tc.expectedComponents)
+			}
+		})
+	}
+}
+
+func testCountComponents(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testcases := []struct {
+		name              string
+		query             *layerhub.Filter
+		currentComponents []layerhub.Component
+		expectedCount     int
+	}{
+		{
+			name:  "empty result",
+			query: &layerhub.Filter{ID: "component_2"},
+			currentComponents: []layerhub.Component{
+				{
+					ID:        "component_1",
+					Name:      "Fake design",
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "one result",
+			query: &layerhub.Filter{
+				ID: "component_2",
+			},
+			currentComponents: []layerhub.Component{
+				{
+					ID:        "component_1",
+					Name:      "Fake design",
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "component_2",
+					Name:      "Fake design 2",
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedCount: 1,
+		},
+		{
+			name:  "multiple result",
+			query: &layerhub.Filter{},
+			currentComponents: []layerhub.Component{
+				{
+					ID:        "component_1",
+					Name:      "Fake design",
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:        "component_2",
+					Name:      "Fake design 2",
+					Preview:   "cloudfront.com/preview/2.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedCount: 2,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentComponents) > 0 {
+				for _, f := range tc.currentComponents {
+					err := db.PutComponent(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			count, err := db.CountComponents(context.TODO(), tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if count != tc.expectedCount {
+				t.Fatalf("mismatched count result:\ngot: %v\nwant: %v", count, tc.expectedCount)
+			}
+		})
+	}
+}
+
+func testDeleteComponent(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testcases := []struct {
+		name              string
+		deleteID          string
+		currentComponents []layerhub.Component
+	}{
+		{
+			name:     "component found",
+			deleteID: "component_1",
+			currentComponents: []layerhub.Component{
+				{
+					ID:        "component_1",
+					Name:      "Fake design",
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+		{
+			name:     "component not found",
+			deleteID: "component_2",
+			currentComponents: []layerhub.Component{
+				{
+					ID:        "component_1",
+					Name:      "Fake design",
+					Preview:   "cloudfront.com/preview/1.png",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentComponents) > 0 {
+				for _, f := range tc.currentComponents {
+					err := db.PutComponent(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			err := db.DeleteComponent(context.TODO(), tc.deleteID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			components, err := db.FindComponents(context.TODO(), &layerhub.Filter{ID: tc.deleteID})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(components) != 0 {
+				t.Fatalf("component not deleted:\ngot: %v", components[0])
+			}
+		})
+	}
+}
+
+func testPutFrame(t *testing.T, newDB NewDB) {
+	testscases := []struct {
+		name          string
+		newFrame      layerhub.Frame
+		updateHeight  float64
+		expectedFrame layerhub.Frame
+	}{
+		{
+			name: "new frame",
+			newFrame: layerhub.Frame{
+				ID:      "frame_1",
+				Name:    "Default",
+				Width:   420,
+				Height:  420,
+				Public:  true,
+				Unit:    layerhub.Pixels,
+				Preview: "cloudfront.com/frames/1.png",
+			},
+			expectedFrame: layerhub.Frame{
+				ID:      "frame_1",
+				Name:    "Default",
+				Width:   420,
+				Height:  420,
+				Public:  true,
+				Unit:    layerhub.Pixels,
+				Preview: "cloudfront.com/frames/1.png",
+			},
+		},
+		{
+			name: "update width",
+			newFrame: layerhub.Frame{
+				ID:      "frame_1",
+				Name:    "Default",
+				Width:   420,
+				Height:  420,
+				Public:  true,
+				Unit:    layerhub.Pixels,
+				Preview: "cloudfront.com/frames/1.png",
+			},
+			updateHeight: 600,
+			expectedFrame: layerhub.Frame{
+				ID:      "frame_1",
+				Name:    "Default",
+				Width:   420,
+				Height:  600,
+				Public:  true,
+				Unit:    layerhub.Pixels,
+				Preview: "cloudfront.com/frames/1.png",
+			},
+		},
+	}
+
+	for _, tc := range testscases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			err := db.PutFrame(context.TODO(), &tc.newFrame)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.updateHeight != 0 {
+				tc.newFrame.Height = tc.updateHeight
+				err := db.PutFrame(context.TODO(), &tc.newFrame)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			frames, err := db.FindFrames(context.TODO(), &layerhub.Filter{ID: tc.newFrame.ID, Limit: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(frames) == 0 {
+				t.Fatal("frame not found")
+			}
+
+			got := frames[0]
+			if tc.expectedFrame != got {
+				t.Errorf("mismatched frames:\ngot: %v\n want: %v", got, tc.expectedFrame)
+			}
+		})
+	}
+}
+
+func testFindFrames(t *testing.T, newDB NewDB) {
+	testcases := []struct {
+		name           string
+		query          *layerhub.Filter
+		currentFrames  []layerhub.Frame
+		expectedFrames []layerhub.Frame
+	}{
+		{
+			name:  "empty result",
+			query: &layerhub.Filter{ID: "frame_2"},
+			currentFrames: []layerhub.Frame{
+				{
+					ID:      "frame_1",
+					Name:    "Default",
+					Width:   420,
+					Height:  420,
+					Public:  true,
+					Unit:    layerhub.Pixels,
+					Preview: "cloudfront.com/frames/1.png",
+				},
+			},
+			expectedFrames: []layerhub.Frame{},
+		},
+		{
+			name: "one result",
+			query: &layerhub.Filter{
+				ID: "frame_2",
+			},
+			currentFrames: []layerhub.Frame{
+				{
+					ID:      "frame_1",
+					Name:    "Default",
+					Width:   420,
+					Height:  420,
+					Public:  true,
+					Unit:    layerhub.Pixels,
+					Preview: "cloudfront.com/frames/1.png",
+				},
+				{
+					ID:      "frame_2",
+					Name:    "Default",
+					Width:   420,
+					Height:  420,
+					Public:  true,
+					Unit:    layerhub.Pixels,
+					Preview: "cloudfront.com/frames/1.png",
+				},
+			},
+			expectedFrames: []layerhub.Frame{
+				{
+					ID:      "frame_2",
+					Name:    "Default",
+					Width:   420,
+					Height:  420,
+					Public:  true,
+					Unit:    layerhub.Pixels,
+					Preview: "cloudfront.com/frames/1.png",
+				},
+			},
+		},
+		{
+			name:  "multiple result",
+			query: &layerhub.Filter{},
+			currentFrames: []layerhub.Frame{
+				{
+					ID:      "frame_1",
+					Name:    "Default",
+					Width:   420,
+					Height:  420,
+					Public:  true,
+					Unit:    layerhub.Pixels,
+					Preview: "cloudfront.com/frames/1.png",
+				},
+				{
+					ID:      "frame_2",
+					Name:    "Default",
+					Width:   420,
+					Height:  420,
+					Public:  true,
+					Unit:    layerhub.Pixels,
+					Preview: "cloudfront.com/frames/1.png",
+				},
+			},
+			expectedFrames: []layerhub.Frame{
+				{
+					ID:      "frame_1",
+					Name:    "Default",
+					Width:   420,
+					Height:  420,
+					Public:  true,
+					Unit:    layerhub.Pixels,
+					Preview: "cloudfront.com/frames/1.png",
+				},
+				{
+					ID:      "frame_2",
+					Name:    "Default",
+					Width:   420,
+					Height:  420,
+					Public:  true,
+					Unit:    layerhub.Pixels,
+					Preview: "cloudfront.com/frames/1.png",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentFrames) > 0 {
+				for _, f := range tc.currentFrames {
+					err := db.PutFrame(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			frames, err := db.FindFrames(context.TODO(), tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(frames, tc.expectedFrames) {
+				t.Fatalf("mismatched find result:\ngot: %v\nwant: %v", frames, tc.expectedFrames)
+			}
+		})
+	}
+}
+
+func testCountFrames(t *testing.T, newDB NewDB) {
+	testcases := []struct {
+		name          string
+		query         *layerhub.Filter
+		currentFrames []layerhub.Frame
+		expectedCount int
+	}{
+		{
+			name:  "empty result",
+			query: &layerhub.Filter{ID: "frame_2"},
+			currentFrames: []layerhub.Frame{
+				{
+					ID:      "frame_1",
+					Name:    "Default",
+					Width:   420,
+					Height:  420,
+					Public:  true,
+					Unit:    layerhub.Pixels,
+					Preview: "cloudfront.com/frames/1.png",
+				},
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "one result",
+			query: &layerhub.Filter{
+				ID: "frame_2",
+			},
+			currentFrames: []layerhub.Frame{
+				{
+					ID:      "frame_1",
+					Name:    "Default",
+					Width:   420,
+					Height:  420,
+					Public:  true,
+					Unit:    layerhub.Pixels,
+					Preview: "cloudfront.com/frames/1.png",
+				},
+				{
+					ID:      "frame_2",
+					Name:    "Default",
+					Width:   420,
+					Height:  420,
+					Public:  true,
+					Unit:    layerhub.Pixels,
+					Preview: "cloudfront.com/frames/1.png",
+				},
+			},
+			expectedCount: 1,
+		},
+		{
+			name:  "multiple result",
+			query: &layerhub.Filter{},
+			currentFrames: []layerhub.Frame{
+				{
+					ID:      "frame_1",
+					Name:    "Default",
+					Width:   420,
+					Height:  420,
+					Public:  true,
+					Unit:    layerhub.Pixels,
+					Preview: "cloudfront.com/frames/1.png",
+				},
+				{
+					ID:      "frame_2",
+					Name:    "Default",
+					Width:   420,
+					Height:  420,
+					Public:  true,
+					Unit:    layerhub.Pixels,
+					Preview: "cloudfront.com/frames/1.png",
+				},
+			},
+			expectedCount: 2,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentFrames) > 0 {
+				for _, f := range tc.currentFrames {
+					err := db.PutFrame(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			count, err := db.CountFrames(context.TODO(), tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if count != tc.expectedCount {
+				t.Fatalf("mismatched find result:\ngot: %v\nwant: %v", count, tc.expectedCount)
+			}
+		})
+	}
+}
+
+func testDeleteFrame(t *testing.T, newDB NewDB) {
+	testcases := []struct {
+		name          string
+		deleteID      string
+		currentFrames []layerhub.Frame
+	}{
+		{
+			name:     "frame found",
+			deleteID: "frame_1",
+			currentFrames: []layerhub.Frame{
+				{
+					ID:      "frame_1",
+					Name:    "Default",
+					Width:   420,
+					Height:  420,
+					Public:  true,
+					Unit:    layerhub.Pixels,
+					Preview: "cloudfront.com/frames/1.png",
+				},
+			},
+		},
+		{
+			name:     "frame not found",
+			deleteID: "frame_2",
+			currentFrames: []layerhub.Frame{
+				{
+					ID:      "frame_1",
+					Name:    "Default",
+					Width:   420,
+					Height:  420,
+					Public:  true,
+					Unit:    layerhub.Pixels,
+					Preview: "cloudfront.com/frames/1.png",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentFrames) > 0 {
+				for _, f := range tc.currentFrames {
+					err := db.PutFrame(context.TODO(), &f)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			err := db.DeleteFrame(context.TODO(), tc.deleteID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			projects, err := db.FindFrames(context.TODO(), &layerhub.Filter{ID: tc.deleteID})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(projects) != 0 {
+				t.Fatalf("frame not deleted:\ngot: %v", projects[0])
+			}
+		})
+	}
+}
+
+func testFindEnabledFonts(t *testing.T, newDB NewDB) {
+	testcases := []struct {
+		name                 string
+		query                string
+		currentEnabledFonts  []*layerhub.EnabledFont
+		expectedEnabledFonts []layerhub.EnabledFont
+	}{
+		{
+			name:  "empty result",
+			query: "user_2",
+			currentEnabledFonts: []*layerhub.EnabledFont{
+				{
+					ID:         "enabled_font_1",
+					CustomerID: "user_1",
+					FontID:     "font_1",
+				},
+			},
+			expectedEnabledFonts: []layerhub.EnabledFont{},
+		},
+		{
+			name:  "one result",
+			query: "user_1",
+			currentEnabledFonts: []*layerhub.EnabledFont{
+				{
+					ID:         "enabled_font_1",
+					CustomerID: "user_1",
+					FontID:     "font_1",
+				},
+				{
+					ID:         "enabled_font_2",
+					CustomerID: "user_2",
+					FontID:     "font_2",
+				},
+			},
+			expectedEnabledFonts: []layerhub.EnabledFont{
+				{
+					ID:         "enabled_font_1",
+					CustomerID: "user_1",
+					FontID:     "font_1",
+				},
+			},
+		},
+		{
+			name:  "multiple result",
+			query: "user_1",
+			currentEnabledFonts: []*layerhub.EnabledFont{
+				{
+					ID:         "enabled_font_1",
+					CustomerID: "user_1",
+					FontID:     "font_1",
+				},
+				{
+					ID:         "enabled_font_2",
+					CustomerID: "user_1",
+					FontID:     "font_2",
+				},
+			},
+			expectedEnabledFonts: []layerhub.EnabledFont{
+				{
+					ID:         "enabled_font_1",
+					CustomerID: "user_1",
+					FontID:     "font_1",
+				},
+				{
+					ID:         "enabled_font_2",
+					CustomerID: "user_1",
+					FontID:     "font_2",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentEnabledFonts) > 0 {
+				err := db.BatchCreateEnabledFonts(context.TODO(), tc.currentEnabledFonts)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			enabledFonts, err := db.FindEnabledFonts(context.TODO(), tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(enabledFonts, tc.expectedEnabledFonts) {
+				t.Fatalf("mismatched find result:\ngot: %v\nwant: %v", enabledFonts, tc.expectedEnabledFonts)
+			}
+		})
+	}
+}
+
+func testBatchDeleteEnabledFonts(t *testing.T, newDB NewDB) {
+	testcases := []struct {
+		name                 string
+		deleteIDs            []string
+		currentEnabledFonts  []*layerhub.EnabledFont
+		expectedEnabledFonts []layerhub.EnabledFont
+	}{
+		{
+			name:      "enabled fonts found",
+			deleteIDs: []string{"enabled_font_1", "enabled_font_2"},
+			currentEnabledFonts: []*layerhub.EnabledFont{
+				{
+					ID:         "enabled_font_1",
+					CustomerID: "user_1",
+					FontID:     "font_1",
+				},
+				{
+					ID:         "enabled_font_2",
+					CustomerID: "user_1",
+					FontID:     "font_2",
+				},
+			},
+			expectedEnabledFonts: []layerhub.EnabledFont{},
+		},
+		{
+			name:      "enabled fonts not found",
+			deleteIDs: []string{"enabled_font_3"},
+			currentEnabledFonts: []*layerhub.EnabledFont{
+				{
+					ID:         "enabled_font_1",
+					CustomerID: "user_1",
+					FontID:     "font_1",
+				},
+				{
+					ID:         "enabled_font_2",
+					CustomerID: "user_1",
+					FontID:     "font_2",
+				},
+			},
+			expectedEnabledFonts: []layerhub.EnabledFont{
+				{
+					ID:         "enabled_font_1",
+					CustomerID: "user_1",
+					FontID:     "font_1",
+				},
+				{
+					ID:         "enabled_font_2",
+					CustomerID: "user_1",
+					FontID:     "font_2",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			if len(tc.currentEnabledFonts) > 0 {
+				err := db.BatchCreateEnabledFonts(context.TODO(), tc.currentEnabledFonts)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := db.BatchDeleteEnabledFonts(context.TODO(), tc.deleteIDs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fonts, err := db.FindEnabledFonts(context.TODO(), "user_1")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(fonts, tc.expectedEnabledFonts) {
+				t.Fatalf("mismatched find result:\ngot: %v\nwant: %v", fonts, tc.expectedEnabledFonts)
+			}
+		})
+	}
+}
+
+func testPutSubscriptionPlan(t *testing.T, newDB NewDB) {
+	testscases := []struct {
+		name                     string
+		newSubscriptionPlan      layerhub.SubscriptionPlan
+		updateBillings           []*layerhub.Billing
+		expectedSubscriptionPlan layerhub.SubscriptionPlan
+	}{
+		{
+			name: "new user",
+			newSubscriptionPlan: layerhub.SubscriptionPlan{
+				ID:                  "subscription_plan_1",
+				Name:                "Basic Plan",
+				Description:         "Basic Plan",
+				Provider:            layerhub.Paypal,
+				AutoBillOutstanding: true,
+				SetupFee:            "1",
+				Billing: []*layerhub.Billing{
+					{
+						ID:       "billing_1",
+						Interval: "MONTH",
+						Price:    "10",
+					},
+					{
+						ID:       "billing_2",
+						Interval: "YEAR",
+						Price:    "100",
+					},
+				},
+			},
+			expectedSubscriptionPlan: layerhub.SubscriptionPlan{
+				ID:                  "subscription_plan_1",
+				Name:                "Basic Plan",
+				Description:         "Basic Plan",
+				Provider:            layerhub.Paypal,
+				AutoBillOutstanding: true,
+				SetupFee:            "1",
+				Billing: []*layerhub.Billing{
+					{
+						ID:                 "billing_1",
+						Interval:           "MONTH",
+						Price:              "10",
+						SubscriptionPlanID: "subscription_plan_1",
+					},
+					{
+						ID:                 "billing_2",
+						Interval:           "YEAR",
+						Price:              "100",
+						SubscriptionPlanID: "subscription_plan_1",
+					},
+				},
+			},
+		},
+		{
+			name: "update billings",
+			newSubscriptionPlan: layerhub.SubscriptionPlan{
+				ID:                  "subscription_plan_1",
+				Name:                "Basic Plan",
+				Description:         "Basic Plan",
+				Provider:            layerhub.Paypal,
+				AutoBillOutstanding: true,
+				SetupFee:            "1",
+				Billing: []*layerhub.Billing{
+					{
+						ID:                 "billing_1",
+						Interval:           "MONTH",
+						Price:              "10",
+						SubscriptionPlanID: "subscription_plan_1",
+					},
+					{
+						ID:                 "billing_2",
+						Interval:           "YEAR",
+						Price:              "100",
+						SubscriptionPlanID: "subscription_plan_1",
+					},
+				},
+			},
+			updateBillings: []*layerhub.Billing{
+				{
+					ID:                 "billing_1",
+					Interval:           "MONTH",
+					Price:              "10",
+					SubscriptionPlanID: "subscription_plan_1",
+				},
+			},
+			expectedSubscriptionPlan: layerhub.SubscriptionPlan{
+				ID:                  "subscription_plan_1",
+				Name:                "Basic Plan",
+				Description:         "Basic Plan",
+				Provider:            layerhub.Paypal,
+				AutoBillOutstanding: true,
+				SetupFee:            "1",
+				Billing: []*layerhub.Billing{
+					{
+						ID:                 "billing_1",
+						Interval:           "MONTH",
+						Price:              "10",
+						SubscriptionPlanID: "subscription_plan_1",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testscases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			err := db.PutSubscriptionPlan(context.TODO(), &tc.newSubscriptionPlan)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(tc.updateBillings) > 0 {
+				tc.newSubscriptionPlan.Billing = tc.updateBillings
+				err := db.PutSubscriptionPlan(context.TODO(), &tc.newSubscriptionPlan)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			plans, err := db.FindSubscriptionPlans(context.TODO())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(plans) == 0 {
+				t.Fatal("user not found")
+			}
+
+			got := plans[0]
+			if reflect.DeepEqual(plans, tc.expectedSubscriptionPlan) {
+				t.Errorf("mismatched users:\ngot: %v\n want: %v", got, tc.expectedSubscriptionPlan)
+			}
+		})
+	}
+}
+
+func testPutMockupTemplate(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	placement := layerhub.MockupPlacement{{X: 10, Y: 10}, {X: 90, Y: 12}, {X: 88, Y: 80}, {X: 12, Y: 78}}
+	testscases := []struct {
+		name                   string
+		newMockupTemplate      layerhub.MockupTemplate
+		updatePhotoURL         string
+		expectedMockupTemplate layerhub.MockupTemplate
+	}{
+		{
+			name: "new mockup template",
+			newMockupTemplate: layerhub.MockupTemplate{
+				ID:        "mockup_template_1",
+				Name:      "T-Shirt",
+				PhotoURL:  "cloudfront.com/mockups/1.png",
+				Placement: placement,
+				CompanyID: "company_1",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			expectedMockupTemplate: layerhub.MockupTemplate{
+				ID:        "mockup_template_1",
+				Name:      "T-Shirt",
+				PhotoURL:  "cloudfront.com/mockups/1.png",
+				Placement: placement,
+				CompanyID: "company_1",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+		{
+			name: "update photo url",
+			newMockupTemplate: layerhub.MockupTemplate{
+				ID:        "mockup_template_1",
+				Name:      "T-Shirt",
+				PhotoURL:  "cloudfront.com/mockups/1.png",
+				Placement: placement,
+				CompanyID: "company_1",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			updatePhotoURL: "cloudfront.com/mockups/2.png",
+			expectedMockupTemplate: layerhub.MockupTemplate{
+				ID:        "mockup_template_1",
+				Name:      "T-Shirt",
+				PhotoURL:  "cloudfront.com/mockups/2.png",
+				Placement: placement,
+				CompanyID: "company_1",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+	}
+
+	for _, tc := range testscases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			err := db.PutMockupTemplate(context.TODO(), &tc.newMockupTemplate)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.updatePhotoURL != "" {
+				tc.newMockupTemplate.PhotoURL = tc.updatePhotoURL
+				err := db.PutMockupTemplate(context.TODO(), &tc.newMockupTemplate)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			mts, err := db.FindMockupTemplates(context.TODO(), &layerhub.Filter{ID: tc.newMockupTemplate.ID, Limit: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(mts) == 0 {
+				t.Fatal("mockup template not found")
+			}
+
+			got := mts[0]
+			if tc.expectedMockupTemplate != got {
+				t.Errorf("mismatched mockup templates:\ngot: %v\n want: %v", got, tc.expectedMockupTemplate)
+			}
+		})
+	}
+}
+
+func testPutMockup(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testscases := []struct {
+		name           string
+		currentMockups []layerhub.Mockup
+		query          *layerhub.Filter
+		expectedMockup layerhub.Mockup
+	}{
+		{
+			name: "new mockup",
+			currentMockups: []layerhub.Mockup{
+				{
+					ID:               "mockup_1",
+					DesignID:         "temp_1",
+					MockupTemplateID: "mockup_template_1",
+					URL:              "cloudfront.com/mockups/1.png",
+					DesignUpdatedAt:  now,
+					CreatedAt:        now,
+				},
+			},
+			query: &layerhub.Filter{DesignID: "temp_1", MockupTemplateID: "mockup_template_1"},
+			expectedMockup: layerhub.Mockup{
+				ID:               "mockup_1",
+				DesignID:         "temp_1",
+				MockupTemplateID: "mockup_template_1",
+				URL:              "cloudfront.com/mockups/1.png",
+				DesignUpdatedAt:  now,
+				CreatedAt:        now,
+			},
+		},
+		{
+			name: "regenerated mockup",
+			currentMockups: []layerhub.Mockup{
+				{
+					ID:               "mockup_1",
+					DesignID:         "temp_1",
+					MockupTemplateID: "mockup_template_1",
+					URL:              "cloudfront.com/mockups/1.png",
+					DesignUpdatedAt:  now,
+					CreatedAt:        now,
+				},
+				{
+					ID:               "mockup_1",
+					DesignID:         "temp_1",
+					MockupTemplateID: "mockup_template_1",
+					URL:              "cloudfront.com/mockups/2.png",
+					DesignUpdatedAt:  now.Add(time.Hour),
+					CreatedAt:        now.Add(time.Hour),
+				},
+			},
+			query: &layerhub.Filter{DesignID: "temp_1", MockupTemplateID: "mockup_template_1"},
+			expectedMockup: layerhub.Mockup{
+				ID:               "mockup_1",
+				DesignID:         "temp_1",
+				MockupTemplateID: "mockup_template_1",
+				URL:              "cloudfront.com/mockups/2.png",
+				DesignUpdatedAt:  now.Add(time.Hour),
+				CreatedAt:        now.Add(time.Hour),
+			},
+		},
+	}
+
+	for _, tc := range testscases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			for _, m := range tc.currentMockups {
+				err := db.PutMockup(context.TODO(), &m)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			mockups, err := db.FindMockups(context.TODO(), tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(mockups) != 1 {
+				t.Fatalf("expected one mockup, got %d", len(mockups))
+			}
+
+			got := mockups[0]
+			if tc.expectedMockup != got {
+				t.Errorf("mismatched mockups:\ngot: %v\n want: %v", got, tc.expectedMockup)
+			}
+		})
+	}
+}
+
+func testDeleteMockup(t *testing.T, newDB NewDB) {
+	db := newDB(t)
+	now := layerhub.Now()
+
+	mockups := []layerhub.Mockup{
+		{ID: "mockup_1", DesignID: "temp_1", MockupTemplateID: "mockup_template_1", URL: "cloudfront.com/mockups/1.png", DesignUpdatedAt: now, CreatedAt: now},
+		{ID: "mockup_2", DesignID: "temp_1", MockupTemplateID: "mockup_template_2", URL: "cloudfront.com/mockups/2.png", DesignUpdatedAt: now, CreatedAt: now},
+	}
+	for _, m := range mockups {
+		if err := db.PutMockup(context.TODO(), &m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := db.DeleteMockup(context.TODO(), "mockup_1"); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := db.FindMockups(context.TODO(), &layerhub.Filter{DesignID: "temp_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].ID != "mockup_2" {
+		t.Errorf("mockup not deleted:\ngot: %v", found)
+	}
+}
+
+func testPutOrder(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testscases := []struct {
+		name          string
+		currentOrders []layerhub.Order
+		expectedOrder layerhub.Order
+	}{
+		{
+			name: "new order",
+			currentOrders: []layerhub.Order{
+				{
+					ID:         "order_1",
+					Number:     "220101-ABCDEF",
+					Status:     layerhub.OrderDraft,
+					CustomerID: "customer_1",
+					CompanyID:  "company_1",
+					Items: []*layerhub.OrderItem{
+						{ID: "order_item_1", ProjectID: "proj_1", SKU: "MUG-11OZ", Quantity: 2, Format: layerhub.PrintPDF, DPI: 300},
+						{ID: "order_item_2", Position: 1, ProjectID: "proj_2", SKU: "TSHIRT-L", Quantity: 1, Format: layerhub.PrintPNG, DPI: 150},
+					},
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			expectedOrder: layerhub.Order{
+				ID:         "order_1",
+				Number:     "220101-ABCDEF",
+				Status:     layerhub.OrderDraft,
+				CustomerID: "customer_1",
+				CompanyID:  "company_1",
+				Items: []*layerhub.OrderItem{
+					{ID: "order_item_1", OrderID: "order_1", ProjectID: "proj_1", SKU: "MUG-11OZ", Quantity: 2, Format: layerhub.PrintPDF, DPI: 300},
+					{ID: "order_item_2", OrderID: "order_1", Position: 1, ProjectID: "proj_2", SKU: "TSHIRT-L", Quantity: 1, Format: layerhub.PrintPNG, DPI: 150},
+				},
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+		{
+			name: "submitted order with print files",
+			currentOrders: []layerhub.Order{
+				{
+					ID:         "order_1",
+					Number:     "220101-ABCDEF",
+					Status:     layerhub.OrderDraft,
+					CustomerID: "customer_1",
+					CompanyID:  "company_1",
+					Items: []*layerhub.OrderItem{
+						{ID: "order_item_1", ProjectID: "proj_1", SKU: "MUG-11OZ", Quantity: 2, Format: layerhub.PrintPDF, DPI: 300},
+					},
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				{
+					ID:          "order_1",
+					Number:      "220101-ABCDEF",
+					Status:      layerhub.OrderPrintFailed,
+					CustomerID:  "customer_1",
+					CompanyID:   "company_1",
+					SubmittedAt: &now,
+					Items: []*layerhub.OrderItem{
+						{
+							ID:            "order_item_1",
+							ProjectID:     "proj_1",
+							SKU:           "MUG-11OZ",
+							Quantity:      2,
+							Format:        layerhub.PrintPDF,
+							DPI:           300,
+							PrintStatus:   layerhub.PrintFailed,
+							PrintError:    "renderer: timeout",
+							PrintAttempts: 4,
+						},
+					},
+					CreatedAt: now,
+					UpdatedAt: now.Add(time.Hour),
+				},
+			},
+			expectedOrder: layerhub.Order{
+				ID:          "order_1",
+				Number:      "220101-ABCDEF",
+				Status:      layerhub.OrderPrintFailed,
+				CustomerID:  "customer_1",
+				CompanyID:   "company_1",
+				SubmittedAt: &now,
+				Items: []*layerhub.OrderItem{
+					{
+						ID:            "order_item_1",
+						OrderID:       "order_1",
+						ProjectID:     "proj_1",
+						SKU:           "MUG-11OZ",
+						Quantity:      2,
+						Format:        layerhub.PrintPDF,
+						DPI:           300,
+						PrintStatus:   layerhub.PrintFailed,
+						PrintError:    "renderer: timeout",
+						PrintAttempts: 4,
+					},
+				},
+				CreatedAt: now,
+				UpdatedAt: now.Add(time.Hour),
+			},
+		},
+	}
+
+	for _, tc := range testscases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			for _, o := range tc.currentOrders {
+				err := db.PutOrder(context.TODO(), &o)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			orders, err := db.FindOrders(context.TODO(), &layerhub.Filter{ID: tc.expectedOrder.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(orders) != 1 {
+				t.Fatalf("expected one order, got %d", len(orders))
+			}
+
+			if !reflect.DeepEqual(orders[0], tc.expectedOrder) {
+				t.Errorf("mismatched orders:\ngot: %v\n want: %v", orders[0], tc.expectedOrder)
+			}
+		})
+	}
+}
+
+func testClaimOrder(t *testing.T, newDB NewDB) {
+	db := newDB(t)
+	now := layerhub.Now()
+	lastHour := now.Add(-time.Hour)
+
+	order := layerhub.Order{
+		ID:          "order_1",
+		Number:      "220101-ABCDEF",
+		Status:      layerhub.OrderSubmitted,
+		CustomerID:  "customer_1",
+		CompanyID:   "company_1",
+		SubmittedAt: &lastHour,
+		Items: []*layerhub.OrderItem{
+			{ID: "order_item_1", ProjectID: "proj_1", SKU: "MUG-11OZ", Quantity: 2, Format: layerhub.PrintPDF, DPI: 300, PrintStatus: layerhub.PrintProcessing},
+		},
+		CreatedAt: lastHour,
+		UpdatedAt: lastHour,
+	}
+	if err := db.PutOrder(context.TODO(), &order); err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		name      string
+		status    layerhub.OrderStatus
+		updatedAt time.Time
+		claimed   bool
+	}{
+		{
+			name:      "other status",
+			status:    layerhub.OrderPrintFailed,
+			updatedAt: lastHour,
+			claimed:   false,
+		},
+		{
+			name:      "other update time",
+			status:    layerhub.OrderSubmitted,
+			updatedAt: now,
+			claimed:   false,
+		},
+		{
+			name:      "claimed",
+			status:    layerhub.OrderSubmitted,
+			updatedAt: lastHour,
+			claimed:   true,
+		},
+		{
+			name:      "already claimed",
+			status:    layerhub.OrderSubmitted,
+			updatedAt: lastHour,
+			claimed:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			claim := order
+			claim.UpdatedAt = now
+			claimed, err := db.ClaimOrder(context.TODO(), &claim, tc.status, tc.updatedAt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claimed != tc.claimed {
+				t.Fatalf("mismatched claim:\ngot: %v\nwant: %v", claimed, tc.claimed)
+			}
+		})
+	}
+
+	orders, err := db.FindOrders(context.TODO(), &layerhub.Filter{ID: "order_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 1 || !orders[0].UpdatedAt.Equal(now) || len(orders[0].Items) != 1 {
+		t.Fatalf("mismatched claimed order: %+v", orders)
+	}
+}
+
+func testDeleteOrder(t *testing.T, newDB NewDB) {
+	db := newDB(t)
+	now := layerhub.Now()
+
+	order := layerhub.Order{
+		ID:         "order_1",
+		Number:     "220101-ABCDEF",
+		Status:     layerhub.OrderDraft,
+		CustomerID: "customer_1",
+		CompanyID:  "company_1",
+		Items: []*layerhub.OrderItem{
+			{ID: "order_item_1", ProjectID: "proj_1", SKU: "MUG-11OZ", Quantity: 2, Format: layerhub.PrintPDF, DPI: 300},
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := db.PutOrder(context.TODO(), &order); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.DeleteOrder(context.TODO(), "order_1"); err != nil {
+		t.Fatal(err)
+	}
+
+	orders, err := db.FindOrders(context.TODO(), &layerhub.Filter{ID: "order_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 0 {
+		t.Fatalf("order not deleted:\ngot: %v", orders[0])
+	}
+
+	// The items are deleted with the order so their ids can be used again
+	order.Items = []*layerhub.OrderItem{}
+	if err := db.PutOrder(context.TODO(), &order); err != nil {
+		t.Fatal(err)
+	}
+	orders, err = db.FindOrders(context.TODO(), &layerhub.Filter{ID: "order_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 1 || len(orders[0].Items) != 0 {
+		t.Errorf("order items not deleted:\ngot: %v", orders)
+	}
+}
+
+func testPutProof(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	testscases := []struct {
+		name          string
+		currentProofs []layerhub.Proof
+		comments      []layerhub.ProofComment
+		expectedProof layerhub.Proof
+	}{
+		{
+			name: "new proof",
+			currentProofs: []layerhub.Proof{
+				{
+					ID:               "proof_1",
+					ProjectID:        "proj_1",
+					Version:          1,
+					Status:           layerhub.ProofPending,
+					Preview:          "cloudfront.com/previews/1.png",
+					ProjectUpdatedAt: now,
+					UserID:           "user_1",
+					CustomerID:       "customer_1",
+					CompanyID:        "company_1",
+					CreatedAt:        now,
+					UpdatedAt:        now,
+				},
+			},
+			expectedProof: layerhub.Proof{
+				ID:               "proof_1",
+				ProjectID:        "proj_1",
+				Version:          1,
+				Status:           layerhub.ProofPending,
+				Preview:          "cloudfront.com/previews/1.png",
+				ProjectUpdatedAt: now,
+				UserID:           "user_1",
+				CustomerID:       "customer_1",
+				CompanyID:        "company_1",
+				CreatedAt:        now,
+				UpdatedAt:        now,
+				Comments:         []*layerhub.ProofComment{},
+			},
+		},
+		{
+			name: "reviewed proof",
+			currentProofs: []layerhub.Proof{
+				{
+					ID:               "proof_1",
+					ProjectID:        "proj_1",
+					Version:          1,
+					Status:           layerhub.ProofPending,
+					Preview:          "cloudfront.com/previews/1.png",
+					ProjectUpdatedAt: now,
+					UserID:           "user_1",
+					CustomerID:       "customer_1",
+					CompanyID:        "company_1",
+					CreatedAt:        now,
+					UpdatedAt:        now,
+				},
+				{
+					ID:               "proof_1",
+					ProjectID:        "proj_1",
+					Version:          1,
+					Status:           layerhub.ProofChangesRequested,
+					Preview:          "cloudfront.com/previews/1.png",
+					ProjectUpdatedAt: now,
+					UserID:           "user_1",
+					CustomerID:       "customer_1",
+					CompanyID:        "company_1",
+					ReviewedAt:       &now,
+					CreatedAt:        now,
+					UpdatedAt:        now,
+				},
+			},
+			comments: []layerhub.ProofComment{
+				{ID: "proof_comment_1", ProofID: "proof_1", CustomerID: "customer_1", Body: "Bigger logo please", CreatedAt: now},
+			},
+			expectedProof: layerhub.Proof{
+				ID:               "proof_1",
+				ProjectID:        "proj_1",
+				Version:          1,
+				Status:           layerhub.ProofChangesRequested,
+				Preview:          "cloudfront.com/previews/1.png",
+				ProjectUpdatedAt: now,
+				UserID:           "user_1",
+				CustomerID:       "customer_1",
+				CompanyID:        "company_1",
+				ReviewedAt:       &now,
+				CreatedAt:        now,
+				UpdatedAt:        now,
+				Comments: []*layerhub.ProofComment{
+					{ID: "proof_comment_1", ProofID: "proof_1", CustomerID: "customer_1", Body: "Bigger logo please", CreatedAt: now},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testscases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			for _, p := range tc.currentProofs {
+				err := db.PutProof(context.TODO(), &p)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, c := range tc.comments {
+				err := db.PutProofComment(context.TODO(), &c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			proofs, err := db.FindProofs(context.TODO(), &layerhub.Filter{ProjectID: tc.expectedProof.ProjectID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(proofs) != 1 {
+				t.Fatalf("expected one proof, got %d", len(proofs))
+			}
+
+			if !reflect.DeepEqual(proofs[0], tc.expectedProof) {
+				t.Errorf("mismatched proofs:\ngot: %v\n want: %v", proofs[0], tc.expectedProof)
+			}
+		})
+	}
+}
+
+func testProofVersion(t *testing.T, newDB NewDB) {
+	db := newDB(t)
+	now := layerhub.Now()
+
+	proof := func(id, projectID string, version int) *layerhub.Proof {
+		return &layerhub.Proof{
+			ID:               id,
+			ProjectID:        projectID,
+			Version:          version,
+			Status:           layerhub.ProofPending,
+			Preview:          "cloudfront.com/previews/1.png",
+			ProjectUpdatedAt: now,
+			CustomerID:       "customer_1",
+			CompanyID:        "company_1",
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+	}
+
+	if err := db.PutProof(context.TODO(), proof("proof_1", "proj_1", 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.PutProof(context.TODO(), proof("proof_2", "proj_2", 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another proof of the project can't take the version
+	err := db.PutProof(context.TODO(), proof("proof_3", "proj_1", 1))
+	if !errors.Is(err, errors.KindConflict) {
+		t.Fatalf("got error %v, want a conflict", err)
+	}
+
+	// The proof holding the version is still updated
+	reviewed := proof("proof_1", "proj_1", 1)
+	reviewed.Status = layerhub.ProofApproved
+	if err := db.PutProof(context.TODO(), reviewed); err != nil {
+		t.Fatal(err)
+	}
+
+	proofs, err := db.FindProofs(context.TODO(), &layerhub.Filter{ProjectID: "proj_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proofs) != 1 || proofs[0].ID != "proof_1" || proofs[0].Status != layerhub.ProofApproved {
+		t.Fatalf("mismatched proofs:\ngot: %v", proofs)
+	}
+}
+
+func testDeleteProof(t *testing.T, newDB NewDB) {
+	db := newDB(t)
+	now := layerhub.Now()
+
+	proof := layerhub.Proof{
+		ID:               "proof_1",
+		ProjectID:        "proj_1",
+		Version:          1,
+		Status:           layerhub.ProofPending,
+		Preview:          "cloudfront.com/previews/1.png",
+		ProjectUpdatedAt: now,
+		CustomerID:       "customer_1",
+		CompanyID:        "company_1",
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := db.PutProof(context.TODO(), &proof); err != nil {
+		t.Fatal(err)
+	}
+	comment := layerhub.ProofComment{ID: "proof_comment_1", ProofID: "proof_1", CustomerID: "customer_1", Body: "Looks good", CreatedAt: now}
+	if err := db.PutProofComment(context.TODO(), &comment); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.DeleteProof(context.TODO(), "proof_1"); err != nil {
+		t.Fatal(err)
+	}
+
+	proofs, err := db.FindProofs(context.TODO(), &layerhub.Filter{ProjectID: "proj_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proofs) != 0 {
+		t.Fatalf("proof not deleted:\ngot: %v", proofs[0])
+	}
+
+	// The comments are deleted with the proof
+	if err := db.PutProof(context.TODO(), &proof); err != nil {
+		t.Fatal(err)
+	}
+	proofs, err = db.FindProofs(context.TODO(), &layerhub.Filter{ProjectID: "proj_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proofs) != 1 || len(proofs[0].Comments) != 0 {
+		t.Errorf("proof comments not deleted:\ngot: %v", proofs)
+	}
+}
+
+func testFindFolders(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	folders := []layerhub.Folder{
+		{
+			ID:        "folder_1",
+			Name:      "Brand",
+			CompanyID: "company_1",
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		{
+			ID:        "folder_2",
+			Name:      "Social",
+			ParentID:  "folder_1",
+			CompanyID: "company_1",
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		{
+			ID:         "folder_3",
+			Name:       "Drafts",
+			CompanyID:  "company_1",
+			CustomerID: "customer_1",
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		},
+	}
+
+	testcases := []struct {
+		name   string
+		filter *layerhub.Filter
+		want   []string
+	}{
+		{
+			name:   "top level",
+			filter: &layerhub.Filter{CompanyID: "company_1", FolderID: layerhub.RootFolder},
+			want:   []string{"folder_1", "folder_3"},
+		},
+		{
+			name:   "subfolders",
+			filter: &layerhub.Filter{CompanyID: "company_1", FolderID: "folder_1"},
+			want:   []string{"folder_2"},
+		},
+		{
+			name:   "customer",
+			filter: &layerhub.Filter{CompanyID: "company_1", CustomerID: "customer_1"},
+			want:   []string{"folder_3"},
+		},
+	}
+
+	db := newDB(t)
+	for _, f := range folders {
+		f := f
+		if err := db.PutFolder(context.TODO(), &f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			found, err := db.FindFolders(context.TODO(), tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ids := []string{}
+			for _, f := range found {
+				ids = append(ids, f.ID)
+			}
+			sort.Strings(ids)
+
+			if !reflect.DeepEqual(ids, tc.want) {
+				t.Fatalf("mismatched folders:\ngot: %v\nwant: %v", ids, tc.want)
+			}
+
+			count, err := db.CountFolders(context.TODO(), tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if count != len(tc.want) {
+				t.Fatalf("mismatched count:\ngot: %v\nwant: %v", count, len(tc.want))
+			}
+		})
+	}
+}
+
+func testTrash(t *testing.T, newDB NewDB) {
+	now := layerhub.Now()
+	lastWeek := now.Add(-7 * 24 * time.Hour)
+
+	// Every table has a live row, a row trashed now and a row trashed last
+	// week
+	db := newDB(t)
+	for i, deletedAt := range []*time.Time{nil, &now, &lastWeek} {
+		id := fmt.Sprintf("%d", i+1)
+		err := db.PutTemplate(context.TODO(), &layerhub.Template{
+			ID:        "template_" + id,
+			CompanyID: "company_1",
+			Frame:     layerhub.Frame{ID: "template_" + id, Width: 420, Height: 420},
+			CreatedAt: now,
+			UpdatedAt: now,
+			DeletedAt: deletedAt,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = db.PutProject(context.TODO(), &layerhub.Project{
+			ID:        "project_" + id,
+			CompanyID: "company_1",
+			Frame:     layerhub.Frame{ID: "project_" + id, Width: 420, Height: 420},
+			CreatedAt: now,
+			UpdatedAt: now,
+			DeletedAt: deletedAt,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = db.PutComponent(context.TODO(), &layerhub.Component{
+			ID:        "component_" + id,
+			CompanyID: "company_1",
+			CreatedAt: now,
+			UpdatedAt: now,
+			DeletedAt: deletedAt,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = db.PutUpload(context.TODO(), &layerhub.Upload{
+			ID:        "upload_" + id,
+			CompanyID: "company_1",
+			CreatedAt: now,
+			UpdatedAt: now,
+			DeletedAt: deletedAt,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testcases := []struct {
+		name   string
+		filter *layerhub.Filter
+		want   []string
+	}{
+		{
+			name:   "nil filter",
+			filter: nil,
+			want:   []string{"1"},
+		},
+		{
+			name:   "live",
+			filter: &layerhub.Filter{CompanyID: "company_1"},
+			want:   []string{"1"},
+		},
+		{
+			name:   "trashed",
+			filter: &layerhub.Filter{CompanyID: "company_1", Trashed: true},
+			want:   []string{"2", "3"},
+		},
+		{
+			name:   "with trashed",
+			filter: &layerhub.Filter{CompanyID: "company_1", WithTrashed: true},
+			want:   []string{"1", "2", "3"},
+		},
+		{
+			name:   "trashed before",
+			filter: &layerhub.Filter{TrashedBefore: now.Add(-24 * time.Hour)},
+			want:   []string{"3"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ids := map[string][]string{}
+			counts := map[string]int{}
+
+			templates, err := db.FindTemplates(context.TODO(), tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range templates {
+				ids["templates"] = append(ids["templates"], strings.TrimPrefix(r.ID, "template_"))
+			}
+			projects, err := db.FindProjects(context.TODO(), tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range projects {
+				ids["projects"] = append(ids["projects"], strings.TrimPrefix(r.ID, "project_"))
+			}
+			components, err := db.FindComponents(context.TODO(), tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range components {
+				ids["components"] = append(ids["components"], strings.TrimPrefix(r.ID, "component_"))
+			}
+			uploads, err := db.FindUploads(context.TODO(), tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range uploads {
+				ids["uploads"] = append(ids["uploads"], strings.TrimPrefix(r.ID, "upload_"))
+			}
+
+			if counts["templates"], err = db.CountTemplates(context.TODO(), tc.filter); err != nil {
+				t.Fatal(err)
+			}
+			if counts["projects"], err = db.CountProjects(context.TODO(), tc.filter); err != nil {
+				t.Fatal(err)
+			}
+			if counts["components"], err = db.CountComponents(context.TODO(), tc.filter); err != nil {
+				t.Fatal(err)
+			}
+			if counts["uploads"], err = db.CountUploads(context.TODO(), tc.filter); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, table := range []string{"templates", "projects", "components", "uploads"} {
+				got := ids[table]
+				sort.Strings(got)
+				if !reflect.DeepEqual(got, tc.want) {
+					t.Errorf("mismatched %s:\ngot: %v\nwant: %v", table, got, tc.want)
+				}
+				if counts[table] != len(tc.want) {
+					t.Errorf("mismatched %s count:\ngot: %v\nwant: %v", table, counts[table], len(tc.want))
+				}
+			}
+		})
+	}
+
+	// Looking a trashed row up by its regular or short ID only finds it in
+	// the trash
+	for _, id := range []string{"2", "3"} {
+		templates, err := db.FindTemplates(context.TODO(), &layerhub.Filter{RegularOrShortID: "template_" + id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		projects, err := db.FindProjects(context.TODO(), &layerhub.Filter{RegularOrShortID: "project_" + id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(templates) != 0 || len(projects) != 0 {
+			t.Fatalf("mismatched lookup of trashed row %s:\ngot: %v, %v\nwant: none", id, templates, projects)
+		}
+
+		templates, err = db.FindTemplates(context.TODO(), &layerhub.Filter{RegularOrShortID: "template_" + id, Trashed: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		projects, err = db.FindProjects(context.TODO(), &layerhub.Filter{RegularOrShortID: "project_" + id, Trashed: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(templates) != 1 || len(projects) != 1 {
+			t.Fatalf("mismatched trashed lookup of row %s:\ngot: %v, %v\nwant: one each", id, templates, projects)
+		}
+	}
+
+	// Restoring clears deleted_at
+	templates, err := db.FindTemplates(context.TODO(), &layerhub.Filter{ID: "template_2", Trashed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 || templates[0].DeletedAt == nil || !templates[0].DeletedAt.Equal(now) {
+		t.Fatalf("mismatched trashed template: %+v", templates)
+	}
+	templates[0].DeletedAt = nil
+	if err := db.PutTemplate(context.TODO(), &templates[0]); err != nil {
+		t.Fatal(err)
+	}
+	count, err := db.CountTemplates(context.TODO(), &layerhub.Filter{CompanyID: "company_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("mismatched templates after restore:\ngot: %v\nwant: 2", count)
+	}
+}
+
+func testWithTx(t *testing.T, newDB NewDB) {
+	errAbort := errors.Errorf("abort")
+	now := layerhub.Now()
+
+	// write puts a company and a template, the template is written with the
+	// store's own transaction which must join the unit of work
+	write := func(tx layerhub.DB) error {
+		company := &layerhub.Company{ID: "company_1", Name: "Fake company", CreatedAt: now, UpdatedAt: now}
+		if err := tx.PutCompany(context.TODO(), company); err != nil {
+			return err
+		}
+		template := &layerhub.Template{
+			ID:        "template_1",
+			Name:      "Fake design",
+			CompanyID: "company_1",
+			Tags:      []string{"birthday"},
+			Frame:     layerhub.Frame{ID: "template_1", Width: 420, Height: 420, UsedInTemplate: true},
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		return tx.PutTemplate(context.TODO(), template)
+	}
+
+	testcases := []struct {
+		name    string
+		fn      func(tx layerhub.DB) error
+		wantErr error
+		want    int
+	}{
+		{
+			name: "commit",
+			fn:   write,
+			want: 1,
+		},
+		{
+			name: "rollback",
+			fn: func(tx layerhub.DB) error {
+				if err := write(tx); err != nil {
+					return err
+				}
+				return errAbort
+			},
+			wantErr: errAbort,
+		},
+		{
+			name: "nested commit",
+			fn: func(tx layerhub.DB) error {
+				return tx.WithTx(context.TODO(), write)
+			},
+			want: 1,
+		},
+		{
+			name: "nested rollback",
+			fn: func(tx layerhub.DB) error {
+				if err := tx.WithTx(context.TODO(), write); err != nil {
+					return err
+				}
+				return errAbort
+			},
+			wantErr: errAbort,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newDB(t)
+
+			err := db.WithTx(context.TODO(), tc.fn)
+			if err != tc.wantErr {
+				t.Fatalf("mismatched error:\ngot: %v\nwant: %v", err, tc.wantErr)
+			}
+
+			companies, err := db.CountCompanies(context.TODO(), &layerhub.Filter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			templates, err := db.CountTemplates(context.TODO(), &layerhub.Filter{Tag: "birthday"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			frames, err := db.CountFrames(context.TODO(), &layerhub.Filter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if companies != tc.want || templates != tc.want || frames != tc.want {
+				t.Fatalf("mismatched rows:\ngot: %v companies, %v templates, %v frames\nwant: %v", companies, templates, frames, tc.want)
+			}
+		})
+	}
+}
+
+func testPutTenantJob(t *testing.T, newDB NewDB) {
+	db := newDB(t)
+	now := layerhub.Now()
+
+	job := layerhub.TenantJob{
+		ID:         "job_1",
+		Kind:       layerhub.TenantExportCustomer,
+		Status:     layerhub.TenantJobPending,
+		CompanyID:  "company_1",
+		CustomerID: "customer_1",
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := db.PutTenantJob(context.TODO(), &job); err != nil {
+		t.Fatal(err)
+	}
+
+	finished := now.Add(time.Minute)
+	job.Status = layerhub.TenantJobDone
+	job.Total = 10
+	job.Done = 10
+	job.ExportKey = "exports/job_1.zip"
+	job.UpdatedAt = finished
+	job.FinishedAt = &finished
+	if err := db.PutTenantJob(context.TODO(), &job); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := db.FindTenantJobs(context.TODO(), &layerhub.Filter{CustomerID: "customer_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("expected one job, got %d", len(jobs))
+	}
+
+	if !reflect.DeepEqual(jobs[0], job) {
+		t.Errorf("mismatched jobs:\ngot: %v\n want: %v", jobs[0], job)
+	}
+
+	for status, want := range map[layerhub.TenantJobStatus]int{layerhub.TenantJobDone: 1, layerhub.TenantJobRunning: 0} {
+		jobs, err := db.FindTenantJobs(context.TODO(), &layerhub.Filter{Status: string(status), UpdatedBefore: finished})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(jobs) != want {
+			t.Errorf("got %d %s jobs, want %d", len(jobs), status, want)
+		}
+	}
+}
+
+func testClaimTenantJob(t *testing.T, newDB NewDB) {
+	db := newDB(t)
+	now := layerhub.Now()
+	lastHour := now.Add(-time.Hour)
+
+	job := layerhub.TenantJob{
+		ID:        "job_1",
+		Kind:      layerhub.TenantDeleteCompany,
+		Status:    layerhub.TenantJobPending,
+		CompanyID: "company_1",
+		CreatedAt: lastHour,
+		UpdatedAt: lastHour,
+	}
+	if err := db.PutTenantJob(context.TODO(), &job); err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		name      string
+		status    layerhub.TenantJobStatus
+		updatedAt time.Time
+		claimed   bool
+	}{
+		{
+			name:      "other status",
+			status:    layerhub.TenantJobRunning,
+			updatedAt: lastHour,
+			claimed:   false,
+		},
+		{
+			name:      "other update time",
+			status:    layerhub.TenantJobPending,
+			updatedAt: now,
+			claimed:   false,
+		},
+		{
+			name:      "claimed",
+			status:    layerhub.TenantJobPending,
+			updatedAt: lastHour,
+			claimed:   true,
+		},
+		{
+			name:      "already claimed",
+			status:    layerhub.TenantJobPending,
+			updatedAt: lastHour,
+			claimed:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			claim := job
+			claim.Status = layerhub.TenantJobRunning
+			claim.UpdatedAt = now
+			claimed, err := db.ClaimTenantJob(context.TODO(), &claim, tc.status, tc.updatedAt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claimed != tc.claimed {
+				t.Fatalf("mismatched claim:\ngot: %v\nwant: %v", claimed, tc.claimed)
+			}
+		})
+	}
+
+	jobs, err := db.FindTenantJobs(context.TODO(), &layerhub.Filter{ID: "job_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].Status != layerhub.TenantJobRunning || !jobs[0].UpdatedAt.Equal(now) {
+		t.Fatalf("mismatched claimed job: %+v", jobs)
+	}
+}
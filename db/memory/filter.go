@@ -0,0 +1,339 @@
+package memory
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/echovl/orderflo-dev/layerhub"
+)
+
+// timeLayout is the layout of the time values of a cursor
+const timeLayout = "2006-01-02 15:04:05"
+
+// find returns the rows of the table that match the filter, sorted and
+// paginated like the SQL implementations do. rows is a map of row structs
+func (s *MemoryDB) find(table string, rows any, filter *layerhub.Filter) []reflect.Value {
+	if filter == nil {
+		filter = &layerhub.Filter{}
+	}
+
+	return paginate(s.filter(table, rows, filter), filter)
+}
+
+// count returns the number of rows of the table that match the filter,
+// pagination is ignored
+func (s *MemoryDB) count(table string, rows any, filter *layerhub.Filter) int {
+	if filter == nil {
+		filter = &layerhub.Filter{}
+	}
+	return len(s.filter(table, rows, filter.WithoutPagination()))
+}
+
+// filter returns the rows that match the filter in the sort order of the
+// filter, rows are sorted by id when the filter isn't sorted
+func (s *MemoryDB) filter(table string, rows any, filter *layerhub.Filter) []reflect.Value {
+	matched := []reflect.Value{}
+
+	iter := reflect.ValueOf(rows).MapRange()
+	for iter.Next() {
+		if s.match(table, iter.Value(), filter) {
+			matched = append(matched, iter.Value())
+		}
+	}
+
+	sortBy := sortColumn(filter.SortBy)
+	desc := filter.SortDesc && (filter.SortBy != "" || filter.Limit != 0 || filter.After != nil)
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := sortKey(matched[i], sortBy), sortKey(matched[j], sortBy)
+		if a == b {
+			a, b = sortKey(matched[i], "id"), sortKey(matched[j], "id")
+		}
+		if desc {
+			return a > b
+		}
+		return a < b
+	})
+
+	return matched
+}
+
+// paginate returns the page of the sorted rows
+func paginate(rows []reflect.Value, filter *layerhub.Filter) []reflect.Value {
+	if filter.After != nil {
+		sortBy := sortColumn(filter.SortBy)
+		page := []reflect.Value{}
+		for _, row := range rows {
+			value, id := sortKey(row, sortBy), sortKey(row, "id")
+			after := value > filter.After.Value || (value == filter.After.Value && id > filter.After.ID)
+			if sortBy == "id" {
+				after = id > filter.After.ID
+			}
+			if filter.SortDesc {
+				after = value < filter.After.Value || (value == filter.After.Value && id < filter.After.ID)
+				if sortBy == "id" {
+					after = id < filter.After.ID
+				}
+			}
+			if after {
+				page = append(page, row)
+			}
+		}
+		rows = page
+	} else if filter.Offset != 0 {
+		if filter.Offset >= len(rows) {
+			return nil
+		}
+		rows = rows[filter.Offset:]
+	}
+
+	if filter.Limit != 0 && filter.Limit < len(rows) {
+		rows = rows[:filter.Limit]
+	}
+
+	return rows
+}
+
+// match returns true if the row satisfies every condition of the filter, a
+// condition on a column the row doesn't have is never satisfied
+func (s *MemoryDB) match(table string, row reflect.Value, filter *layerhub.Filter) bool {
+	equals := []struct {
+		column string
+		value  string
+	}{
+		{"email", filter.Email},
+		{"postscript_name", filter.PostscriptName},
+		{"id", filter.ID},
+		{"short_id", filter.ShortID},
+		{"user_id", filter.UserID},
+		{"customer_id", filter.CustomerID},
+		{"company_id", filter.CompanyID},
+		{"source", string(filter.AuthSource)},
+		{"design_id", filter.DesignID},
+		{"mockup_template_id", filter.MockupTemplateID},
+		{"project_id", filter.ProjectID},
+		{"status", filter.Status},
+		{"api_token", filter.ApiToken},
+	}
+	for _, cond := range equals {
+		if cond.value != "" && !columnEquals(row, cond.column, cond.value) {
+			return false
+		}
+	}
+
+	flags := []struct {
+		column string
+		value  *bool
+	}{
+		{"public", filter.Public},
+		{"used_in_template", filter.UsedInTemplate},
+		{"published", filter.Published},
+	}
+	for _, cond := range flags {
+		if cond.value != nil && !columnEquals(row, cond.column, strconv.FormatBool(*cond.value)) {
+			return false
+		}
+	}
+
+	optionals := []struct {
+		column string
+		value  string
+	}{
+		{"user_id", filter.OptionalUserID},
+		{"customer_id", filter.OptionalCustomerID},
+		{"company_id", filter.OptionalCompanyID},
+	}
+	for _, cond := range optionals {
+		if cond.value != "" && !columnEquals(row, cond.column, cond.value) && !columnEquals(row, cond.column, "") {
+			return false
+		}
+	}
+
+	if filter.RegularOrShortID != "" && !columnEquals(row, "id", filter.RegularOrShortID) && !columnEquals(row, "short_id", filter.RegularOrShortID) {
+		return false
+	}
+
+	if filter.PublicOrCompanyID != "" && !columnEquals(row, "public", "true") && !columnEquals(row, "company_id", filter.PublicOrCompanyID) {
+		return false
+	}
+
+	if filter.FolderID != "" {
+		folderID := filter.FolderID
+		if folderID == layerhub.RootFolder {
+			folderID = ""
+		}
+		if !columnEquals(row, folderColumn(table), folderID) {
+			return false
+		}
+	}
+
+	id := sortKey(row, "id")
+
+	if filter.Tag != "" && !contains(s.templateTags[id], filter.Tag) {
+		return false
+	}
+	if filter.Color != "" && !contains(s.templateColors[id], filter.Color) {
+		return false
+	}
+	if filter.Orientation != "" || filter.License != "" {
+		metadata, ok := s.templateMetadata[id]
+		if !ok {
+			return false
+		}
+		if filter.Orientation != "" && metadata.Orientation != filter.Orientation {
+			return false
+		}
+		if filter.License != "" && metadata.License != filter.License {
+			return false
+		}
+	}
+	if filter.FrameWidth != 0 && filter.FrameHeight != 0 {
+		frame, ok := s.frames[id]
+		if !ok || math.Abs(frame.Width-filter.FrameWidth) >= layerhub.FrameSizeTolerance || math.Abs(frame.Height-filter.FrameHeight) >= layerhub.FrameSizeTolerance {
+			return false
+		}
+		if filter.FrameUnit != "" && frame.Unit != filter.FrameUnit {
+			return false
+		}
+	}
+
+	if table == "fonts" && filter.EnabledFonts != nil && s.fontEnabled(id, filter.OptionalCustomerID) != *filter.EnabledFonts {
+		return false
+	}
+
+	times := []struct {
+		column string
+		value  time.Time
+		after  bool
+	}{
+		{"created_at", filter.CreatedAfter, true},
+		{"created_at", filter.CreatedBefore, false},
+		{"updated_at", filter.UpdatedAfter, true},
+		{"updated_at", filter.UpdatedBefore, false},
+	}
+	for _, cond := range times {
+		if cond.value.IsZero() {
+			continue
+		}
+		v, ok := column(row, cond.column)
+		if !ok {
+			return false
+		}
+		t, ok := v.Interface().(time.Time)
+		if !ok || (cond.after && t.Before(cond.value)) || (!cond.after && t.After(cond.value)) {
+			return false
+		}
+	}
+
+	// Rows with a deleted_at column are in the trash while it's set
+	if v, ok := column(row, "deleted_at"); ok {
+		deletedAt, _ := v.Interface().(*time.Time)
+		trashed := filter.Trashed || !filter.TrashedBefore.IsZero()
+		if trashed && deletedAt == nil {
+			return false
+		}
+		if !trashed && !filter.WithTrashed && deletedAt != nil {
+			return false
+		}
+		if !filter.TrashedBefore.IsZero() && deletedAt.After(filter.TrashedBefore) {
+			return false
+		}
+	}
+
+	if len(filter.IDs) != 0 && !contains(filter.IDs, id) {
+		return false
+	}
+	if len(filter.CustomerIDs) != 0 && !contains(filter.CustomerIDs, sortKey(row, "customer_id")) {
+		return false
+	}
+
+	if filter.NamePrefix != "" {
+		v, ok := column(row, "name")
+		if !ok || !strings.HasPrefix(strings.ToLower(v.String()), strings.ToLower(filter.NamePrefix)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fontEnabled returns true if the customer enabled the font
+func (s *MemoryDB) fontEnabled(fontID, customerID string) bool {
+	for _, f := range s.enabledFonts {
+		if f.FontID == fontID && f.CustomerID == customerID {
+			return true
+		}
+	}
+	return false
+}
+
+// column returns the struct field mapped to the column, fields without a db
+// tag are mapped by their lowercase name
+func column(row reflect.Value, name string) (reflect.Value, bool) {
+	t := row.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("db")
+		if tag == name || (tag == "" && strings.ToLower(f.Name) == name) {
+			return row.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func columnEquals(row reflect.Value, name, value string) bool {
+	v, ok := column(row, name)
+	return ok && fmt.Sprint(v.Interface()) == value
+}
+
+// sortKey returns the value of the column as it's compared against a
+// cursor value
+func sortKey(row reflect.Value, name string) string {
+	v, ok := column(row, name)
+	if !ok {
+		return ""
+	}
+
+	switch fv := v.Interface().(type) {
+	case time.Time:
+		return fv.UTC().Format(timeLayout)
+	default:
+		return fmt.Sprint(fv)
+	}
+}
+
+// folderColumn returns the column of the folder of the table items, folders
+// are nested through their parent
+func folderColumn(table string) string {
+	switch table {
+	case "folders":
+		return "parent_id"
+	case "uploads":
+		return "folder"
+	default:
+		return "folder_id"
+	}
+}
+
+// sortColumn returns the column of a sort field, unknown fields sort by id
+func sortColumn(field layerhub.SortField) string {
+	switch field {
+	case layerhub.SortName, layerhub.SortCreatedAt, layerhub.SortUpdatedAt:
+		return string(field)
+	default:
+		return "id"
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+)
+
+// JSONDB stores the designs as JSON documents
+type JSONDB struct {
+	mu          sync.RWMutex
+	collections map[string]map[string][]byte
+}
+
+var _ layerhub.JSONDB = (*JSONDB)(nil)
+
+func NewJSONDB() layerhub.JSONDB {
+	return &JSONDB{collections: map[string]map[string][]byte{
+		"templates":  {},
+		"projects":   {},
+		"components": {},
+	}}
+}
+
+func (s *JSONDB) PutTemplate(ctx context.Context, template *layerhub.Template) error {
+	return s.put("templates", template.ID, template)
+}
+
+// FindTemplates returns the templates that match the ID of the filter, the
+// templates don't have a user so a filter by user matches none of them
+func (s *JSONDB) FindTemplates(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Template, error) {
+	templates := []layerhub.Template{}
+	if filter != nil && filter.UserID != "" {
+		return templates, nil
+	}
+
+	err := s.find("templates", filter, func(doc []byte) error {
+		var template layerhub.Template
+		if err := json.Unmarshal(doc, &template); err != nil {
+			return err
+		}
+		templates = append(templates, template)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+func (s *JSONDB) DeleteTemplate(ctx context.Context, id string) error {
+	return s.delete("templates", id)
+}
+
+func (s *JSONDB) PutProject(ctx context.Context, project *layerhub.Project) error {
+	return s.put("projects", project.ID, project)
+}
+
+// FindProjects returns the projects that match the ID of the filter, a
+// filter by user matches none of them
+func (s *JSONDB) FindProjects(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Project, error) {
+	projects := []layerhub.Project{}
+	if filter != nil && filter.UserID != "" {
+		return projects, nil
+	}
+
+	err := s.find("projects", filter, func(doc []byte) error {
+		var project layerhub.Project
+		if err := json.Unmarshal(doc, &project); err != nil {
+			return err
+		}
+		projects = append(projects, project)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+func (s *JSONDB) DeleteProject(ctx context.Context, id string) error {
+	return s.delete("projects", id)
+}
+
+func (s *JSONDB) PutComponent(ctx context.Context, component *layerhub.Component) error {
+	return s.put("components", component.ID, component)
+}
+
+// FindComponents returns the components that match the ID and the user of
+// the filter
+func (s *JSONDB) FindComponents(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Component, error) {
+	components := []layerhub.Component{}
+	err := s.find("components", filter, func(doc []byte) error {
+		var component layerhub.Component
+		if err := json.Unmarshal(doc, &component); err != nil {
+			return err
+		}
+		if filter != nil && filter.UserID != "" && filter.UserID != component.UserID {
+			return nil
+		}
+		components = append(components, component)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return components, nil
+}
+
+func (s *JSONDB) DeleteComponent(ctx context.Context, id string) error {
+	return s.delete("components", id)
+}
+
+func (s *JSONDB) Close(ctx context.Context) error {
+	return nil
+}
+
+func (s *JSONDB) put(collection, id string, v any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	s.collections[collection][id] = doc
+	return nil
+}
+
+// find calls fn with the documents of the collection that match the ID of
+// the filter, sorted by ID
+func (s *JSONDB) find(collection string, filter *layerhub.Filter, fn func(doc []byte) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := []string{}
+	for id := range s.collections[collection] {
+		if filter != nil && filter.ID != "" && filter.ID != id {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if err := fn(s.collections[collection][id]); err != nil {
+			return errors.E(errors.KindUnexpected, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *JSONDB) delete(collection, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.collections[collection], id)
+	return nil
+}
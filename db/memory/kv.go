@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/echovl/orderflo-dev/db"
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+type KeyValueDB struct {
+	mu    sync.Mutex
+	items map[string]item
+
+	// now returns the current time, it's replaced by tests
+	now func() time.Time
+}
+
+type item struct {
+	val []byte
+	// expiresAt is zero for the items that don't expire
+	expiresAt time.Time
+}
+
+var _ db.KeyValueDB = (*KeyValueDB)(nil)
+
+func NewKeyValueDB() db.KeyValueDB {
+	return &KeyValueDB{
+		items: map[string]item{},
+		now:   time.Now,
+	}
+}
+
+// Get returns the value of the key, it fails if the key doesn't exist or it
+// has expired
+func (kv *KeyValueDB) Get(ctx context.Context, key string) ([]byte, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	it, ok := kv.items[key]
+	if ok && kv.expired(it) {
+		delete(kv.items, key)
+		ok = false
+	}
+	if !ok {
+		return nil, errors.NotFound(fmt.Sprintf("key '%s' not found", key))
+	}
+
+	return append([]byte{}, it.val...), nil
+}
+
+// Set sets the value of the key, a zero expiration means the key doesn't
+// expire
+func (kv *KeyValueDB) Set(ctx context.Context, key string, val any, expiration time.Duration) error {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	it := item{}
+	switch v := val.(type) {
+	case []byte:
+		it.val = append([]byte{}, v...)
+	case string:
+		it.val = []byte(v)
+	default:
+		it.val = []byte(fmt.Sprint(v))
+	}
+
+	if expiration > 0 {
+		it.expiresAt = kv.now().Add(expiration)
+	}
+
+	kv.items[key] = it
+	return nil
+}
+
+func (kv *KeyValueDB) Del(ctx context.Context, keys ...string) error {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	for _, key := range keys {
+		delete(kv.items, key)
+	}
+	return nil
+}
+
+func (kv *KeyValueDB) Close(ctx context.Context) error {
+	return nil
+}
+
+func (kv *KeyValueDB) expired(it item) bool {
+	return !it.expiresAt.IsZero() && !kv.now().Before(it.expiresAt)
+}
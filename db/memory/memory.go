@@ -0,0 +1,933 @@
+// Package memory implements layerhub.DB, layerhub.JSONDB and db.KeyValueDB
+// in memory. It's the reference implementation used by tests, rows are
+// stored the way the SQL implementations store them
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+)
+
+type MemoryDB struct {
+	mu sync.RWMutex
+
+	// inTx is set on the copy handed to a WithTx unit of work
+	inTx bool
+
+	*tables
+}
+
+// tables are the rows of the database, rows are values so a copy of the maps
+// is a snapshot of the database
+type tables struct {
+	users            map[string]layerhub.User
+	companies        map[string]layerhub.Company
+	customers        map[string]layerhub.Customer
+	fonts            map[string]layerhub.Font
+	templates        map[string]layerhub.Template
+	templateTags     map[string][]string
+	templateColors   map[string][]string
+	templateMetadata map[string]layerhub.Metadata
+	projects         map[string]layerhub.Project
+	frames           map[string]layerhub.Frame
+	components       map[string]layerhub.Component
+	uploads          map[string]layerhub.Upload
+	enabledFonts     map[string]layerhub.EnabledFont
+	plans            map[string]layerhub.SubscriptionPlan
+	mockupTemplates  map[string]layerhub.MockupTemplate
+	mockups          map[string]layerhub.Mockup
+	orders           map[string]layerhub.Order
+	proofs           map[string]layerhub.Proof
+	proofComments    map[string]layerhub.ProofComment
+	folders          map[string]layerhub.Folder
+	tenantJobs       map[string]layerhub.TenantJob
+}
+
+var _ layerhub.DB = (*MemoryDB)(nil)
+
+func New() layerhub.DB {
+	return &MemoryDB{tables: &tables{
+		users:            map[string]layerhub.User{},
+		companies:        map[string]layerhub.Company{},
+		customers:        map[string]layerhub.Customer{},
+		fonts:            map[string]layerhub.Font{},
+		templates:        map[string]layerhub.Template{},
+		templateTags:     map[string][]string{},
+		templateColors:   map[string][]string{},
+		templateMetadata: map[string]layerhub.Metadata{},
+		projects:         map[string]layerhub.Project{},
+		frames:           map[string]layerhub.Frame{},
+		components:       map[string]layerhub.Component{},
+		uploads:          map[string]layerhub.Upload{},
+		enabledFonts:     map[string]layerhub.EnabledFont{},
+		plans:            map[string]layerhub.SubscriptionPlan{},
+		mockupTemplates:  map[string]layerhub.MockupTemplate{},
+		mockups:          map[string]layerhub.Mockup{},
+		orders:           map[string]layerhub.Order{},
+		proofs:           map[string]layerhub.Proof{},
+		proofComments:    map[string]layerhub.ProofComment{},
+		folders:          map[string]layerhub.Folder{},
+		tenantJobs:       map[string]layerhub.TenantJob{},
+	}}
+}
+
+// WithTx runs fn on a snapshot of the tables, the snapshot replaces the tables
+// when fn succeeds. The database is locked while fn runs so fn must only use
+// the DB it's given, calls to WithTx on it join the same unit of work
+func (s *MemoryDB) WithTx(ctx context.Context, fn func(tx layerhub.DB) error) error {
+	if s.inTx {
+		return fn(s)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := &MemoryDB{inTx: true, tables: s.tables.clone()}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	s.tables = tx.tables
+	return nil
+}
+
+func (t *tables) clone() *tables {
+	c := &tables{
+		users:            make(map[string]layerhub.User, len(t.users)),
+		companies:        make(map[string]layerhub.Company, len(t.companies)),
+		customers:        make(map[string]layerhub.Customer, len(t.customers)),
+		fonts:            make(map[string]layerhub.Font, len(t.fonts)),
+		templates:        make(map[string]layerhub.Template, len(t.templates)),
+		templateTags:     make(map[string][]string, len(t.templateTags)),
+		templateColors:   make(map[string][]string, len(t.templateColors)),
+		templateMetadata: make(map[string]layerhub.Metadata, len(t.templateMetadata)),
+		projects:         make(map[string]layerhub.Project, len(t.projects)),
+		frames:           make(map[string]layerhub.Frame, len(t.frames)),
+		components:       make(map[string]layerhub.Component, len(t.components)),
+		uploads:          make(map[string]layerhub.Upload, len(t.uploads)),
+		enabledFonts:     make(map[string]layerhub.EnabledFont, len(t.enabledFonts)),
+		plans:            make(map[string]layerhub.SubscriptionPlan, len(t.plans)),
+		mockupTemplates:  make(map[string]layerhub.MockupTemplate, len(t.mockupTemplates)),
+		mockups:          make(map[string]layerhub.Mockup, len(t.mockups)),
+		orders:           make(map[string]layerhub.Order, len(t.orders)),
+		proofs:           make(map[string]layerhub.Proof, len(t.proofs)),
+		proofComments:    make(map[string]layerhub.ProofComment, len(t.proofComments)),
+		folders:          make(map[string]layerhub.Folder, len(t.folders)),
+		tenantJobs:       make(map[string]layerhub.TenantJob, len(t.tenantJobs)),
+	}
+	for k, v := range t.users {
+		c.users[k] = v
+	}
+	for k, v := range t.companies {
+		c.companies[k] = v
+	}
+	for k, v := range t.customers {
+		c.customers[k] = v
+	}
+	for k, v := range t.fonts {
+		c.fonts[k] = v
+	}
+	for k, v := range t.templates {
+		c.templates[k] = v
+	}
+	for k, v := range t.templateTags {
+		c.templateTags[k] = v
+	}
+	for k, v := range t.templateColors {
+		c.templateColors[k] = v
+	}
+	for k, v := range t.templateMetadata {
+		c.templateMetadata[k] = v
+	}
+	for k, v := range t.projects {
+		c.projects[k] = v
+	}
+	for k, v := range t.frames {
+		c.frames[k] = v
+	}
+	for k, v := range t.components {
+		c.components[k] = v
+	}
+	for k, v := range t.uploads {
+		c.uploads[k] = v
+	}
+	for k, v := range t.enabledFonts {
+		c.enabledFonts[k] = v
+	}
+	for k, v := range t.plans {
+		c.plans[k] = v
+	}
+	for k, v := range t.mockupTemplates {
+		c.mockupTemplates[k] = v
+	}
+	for k, v := range t.mockups {
+		c.mockups[k] = v
+	}
+	for k, v := range t.orders {
+		c.orders[k] = v
+	}
+	for k, v := range t.proofs {
+		c.proofs[k] = v
+	}
+	for k, v := range t.proofComments {
+		c.proofComments[k] = v
+	}
+	for k, v := range t.folders {
+		c.folders[k] = v
+	}
+	for k, v := range t.tenantJobs {
+		c.tenantJobs[k] = v
+	}
+
+	return c
+}
+
+func (s *MemoryDB) PutUser(ctx context.Context, user *layerhub.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[user.ID] = *user
+	return nil
+}
+
+func (s *MemoryDB) FindUsers(ctx context.Context, filter *layerhub.Filter) ([]layerhub.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := []layerhub.User{}
+	for _, row := range s.find("users", s.users, filter) {
+		users = append(users, row.Interface().(layerhub.User))
+	}
+
+	return users, nil
+}
+
+func (s *MemoryDB) DeleteUser(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.users, id)
+	return nil
+}
+
+func (s *MemoryDB) PutCompany(ctx context.Context, company *layerhub.Company) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.companies[company.ID] = *company
+	return nil
+}
+
+func (s *MemoryDB) FindCompanies(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Company, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	companies := []layerhub.Company{}
+	for _, row := range s.find("companies", s.companies, filter) {
+		companies = append(companies, row.Interface().(layerhub.Company))
+	}
+
+	return companies, nil
+}
+
+func (s *MemoryDB) CountCompanies(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.count("companies", s.companies, filter), nil
+}
+
+func (s *MemoryDB) DeleteCompany(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.companies, id)
+	return nil
+}
+
+func (s *MemoryDB) PutCustomer(ctx context.Context, customer *layerhub.Customer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.customers[customer.ID] = *customer
+	return nil
+}
+
+func (s *MemoryDB) FindCustomers(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Customer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	customers := []layerhub.Customer{}
+	for _, row := range s.find("customers", s.customers, filter) {
+		customers = append(customers, row.Interface().(layerhub.Customer))
+	}
+
+	return customers, nil
+}
+
+func (s *MemoryDB) CountCustomers(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.count("customers", s.customers, filter), nil
+}
+
+func (s *MemoryDB) DeleteCustomer(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.customers, id)
+	return nil
+}
+
+func (s *MemoryDB) BatchCreateFonts(ctx context.Context, fonts []layerhub.Font) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, font := range fonts {
+		s.fonts[font.ID] = font
+	}
+	return nil
+}
+
+func (s *MemoryDB) PutFont(ctx context.Context, font *layerhub.Font) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fonts[font.ID] = *font
+	return nil
+}
+
+func (s *MemoryDB) FindFonts(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Font, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fonts := []layerhub.Font{}
+	for _, row := range s.find("fonts", s.fonts, filter) {
+		fonts = append(fonts, row.Interface().(layerhub.Font))
+	}
+
+	return fonts, nil
+}
+
+func (s *MemoryDB) CountFonts(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.count("fonts", s.fonts, filter), nil
+}
+
+func (s *MemoryDB) DeleteFont(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.fonts, id)
+	return nil
+}
+
+func (s *MemoryDB) PutTemplate(ctx context.Context, template *layerhub.Template) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := *template
+	row.Description = ""
+	row.Tags = nil
+	row.Colors = nil
+	row.Layers = nil
+	row.Frame = layerhub.Frame{}
+	row.Metadata = layerhub.Metadata{}
+	s.templates[template.ID] = row
+
+	s.putFrame(&layerhub.Frame{
+		ID:             template.ID,
+		Name:           template.Frame.Name,
+		Width:          template.Frame.Width,
+		Height:         template.Frame.Height,
+		Unit:           template.Frame.Unit,
+		Bleed:          template.Frame.Bleed,
+		SafeMargin:     template.Frame.SafeMargin,
+		UsedInTemplate: true,
+	})
+
+	s.templateTags[template.ID] = append([]string{}, template.Tags...)
+	s.templateColors[template.ID] = append([]string{}, template.Colors...)
+	s.templateMetadata[template.ID] = layerhub.Metadata{
+		ID:          template.ID,
+		License:     template.Metadata.License,
+		Orientation: template.Metadata.Orientation,
+	}
+
+	return nil
+}
+
+func (s *MemoryDB) FindTemplates(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	templates := []layerhub.Template{}
+	for _, row := range s.find("templates", s.templates, filter) {
+		template := row.Interface().(layerhub.Template)
+		template.Frame = s.frames[template.ID]
+		template.Tags = append([]string{}, s.templateTags[template.ID]...)
+		template.Colors = append([]string{}, s.templateColors[template.ID]...)
+		template.Metadata = s.templateMetadata[template.ID]
+		templates = append(templates, template)
+	}
+
+	return templates, nil
+}
+
+func (s *MemoryDB) CountTemplates(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.count("templates", s.templates, filter), nil
+}
+
+func (s *MemoryDB) DeleteTemplate(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.templates, id)
+	delete(s.templateTags, id)
+	delete(s.templateColors, id)
+	delete(s.frames, id)
+	return nil
+}
+
+func (s *MemoryDB) PutProject(ctx context.Context, project *layerhub.Project) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := *project
+	row.Description = ""
+	row.Layers = nil
+	row.Frame = layerhub.Frame{}
+	s.projects[project.ID] = row
+
+	s.putFrame(&layerhub.Frame{
+		ID:             project.ID,
+		Name:           project.Frame.Name,
+		Width:          project.Frame.Width,
+		Height:         project.Frame.Height,
+		Unit:           project.Frame.Unit,
+		Bleed:          project.Frame.Bleed,
+		SafeMargin:     project.Frame.SafeMargin,
+		UsedInTemplate: true,
+	})
+
+	return nil
+}
+
+func (s *MemoryDB) FindProjects(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Project, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	projects := []layerhub.Project{}
+	for _, row := range s.find("projects", s.projects, filter) {
+		project := row.Interface().(layerhub.Project)
+		project.Frame = s.frames[project.ID]
+		projects = append(projects, project)
+	}
+
+	return projects, nil
+}
+
+func (s *MemoryDB) CountProjects(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.count("projects", s.projects, filter), nil
+}
+
+func (s *MemoryDB) DeleteProject(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.projects, id)
+	return nil
+}
+
+func (s *MemoryDB) PutFrame(ctx context.Context, frame *layerhub.Frame) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.putFrame(frame)
+	return nil
+}
+
+// putFrame upserts the frame, the owner and used_in_template of a frame are
+// set when it's created
+func (s *MemoryDB) putFrame(frame *layerhub.Frame) {
+	row := *frame
+	if old, ok := s.frames[frame.ID]; ok {
+		row.UsedInTemplate = old.UsedInTemplate
+		row.CustomerID = old.CustomerID
+		row.CompanyID = old.CompanyID
+	}
+	s.frames[frame.ID] = row
+}
+
+func (s *MemoryDB) FindFrames(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Frame, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	frames := []layerhub.Frame{}
+	for _, row := range s.find("frames", s.frames, filter) {
+		frames = append(frames, row.Interface().(layerhub.Frame))
+	}
+
+	return frames, nil
+}
+
+func (s *MemoryDB) CountFrames(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.count("frames", s.frames, filter), nil
+}
+
+func (s *MemoryDB) DeleteFrame(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.frames, id)
+	return nil
+}
+
+func (s *MemoryDB) PutComponent(ctx context.Context, component *layerhub.Component) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := *component
+	row.Layers = nil
+	row.Metadata = nil
+	s.components[component.ID] = row
+	return nil
+}
+
+func (s *MemoryDB) FindComponents(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Component, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	components := []layerhub.Component{}
+	for _, row := range s.find("components", s.components, filter) {
+		components = append(components, row.Interface().(layerhub.Component))
+	}
+
+	return components, nil
+}
+
+func (s *MemoryDB) CountComponents(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.count("components", s.components, filter), nil
+}
+
+func (s *MemoryDB) DeleteComponent(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.components, id)
+	return nil
+}
+
+func (s *MemoryDB) PutUpload(ctx context.Context, upload *layerhub.Upload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.uploads[upload.ID] = *upload
+	return nil
+}
+
+func (s *MemoryDB) FindUploads(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Upload, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	uploads := []layerhub.Upload{}
+	for _, row := range s.find("uploads", s.uploads, filter) {
+		uploads = append(uploads, row.Interface().(layerhub.Upload))
+	}
+
+	return uploads, nil
+}
+
+func (s *MemoryDB) CountUploads(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.count("uploads", s.uploads, filter), nil
+}
+
+func (s *MemoryDB) DeleteUpload(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.uploads, id)
+	return nil
+}
+
+func (s *MemoryDB) BatchCreateEnabledFonts(ctx context.Context, fonts []*layerhub.EnabledFont) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, font := range fonts {
+		s.enabledFonts[font.ID] = *font
+	}
+	return nil
+}
+
+func (s *MemoryDB) FindEnabledFonts(ctx context.Context, customerID string) ([]layerhub.EnabledFont, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fonts := []layerhub.EnabledFont{}
+	for _, row := range s.find("enabled_fonts", s.enabledFonts, &layerhub.Filter{CustomerID: customerID}) {
+		fonts = append(fonts, row.Interface().(layerhub.EnabledFont))
+	}
+
+	return fonts, nil
+}
+
+func (s *MemoryDB) BatchDeleteEnabledFonts(ctx context.Context, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		delete(s.enabledFonts, id)
+	}
+	return nil
+}
+
+func (s *MemoryDB) PutSubscriptionPlan(ctx context.Context, plan *layerhub.SubscriptionPlan) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := *plan
+	row.Billing = make([]*layerhub.Billing, len(plan.Billing))
+	for i, b := range plan.Billing {
+		billing := *b
+		billing.SubscriptionPlanID = plan.ID
+		row.Billing[i] = &billing
+	}
+	s.plans[plan.ID] = row
+
+	return nil
+}
+
+func (s *MemoryDB) FindSubscriptionPlans(ctx context.Context) ([]layerhub.SubscriptionPlan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	plans := []layerhub.SubscriptionPlan{}
+	for _, row := range s.find("subscription_plans", s.plans, nil) {
+		plan := row.Interface().(layerhub.SubscriptionPlan)
+		billings := make([]*layerhub.Billing, len(plan.Billing))
+		for i, b := range plan.Billing {
+			billing := *b
+			billings[i] = &billing
+		}
+		plan.Billing = billings
+		plans = append(plans, plan)
+	}
+
+	return plans, nil
+}
+
+func (s *MemoryDB) PutMockupTemplate(ctx context.Context, mt *layerhub.MockupTemplate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mockupTemplates[mt.ID] = *mt
+	return nil
+}
+
+func (s *MemoryDB) FindMockupTemplates(ctx context.Context, filter *layerhub.Filter) ([]layerhub.MockupTemplate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mts := []layerhub.MockupTemplate{}
+	for _, row := range s.find("mockup_templates", s.mockupTemplates, filter) {
+		mts = append(mts, row.Interface().(layerhub.MockupTemplate))
+	}
+
+	return mts, nil
+}
+
+func (s *MemoryDB) CountMockupTemplates(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.count("mockup_templates", s.mockupTemplates, filter), nil
+}
+
+func (s *MemoryDB) DeleteMockupTemplate(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.mockupTemplates, id)
+	for mockupID, mockup := range s.mockups {
+		if mockup.MockupTemplateID == id {
+			delete(s.mockups, mockupID)
+		}
+	}
+	return nil
+}
+
+// PutMockup upserts the mockup, there's one mockup for each design and
+// mockup template
+func (s *MemoryDB) PutMockup(ctx context.Context, mockup *layerhub.Mockup) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := *mockup
+	for id, old := range s.mockups {
+		if old.DesignID == mockup.DesignID && old.MockupTemplateID == mockup.MockupTemplateID {
+			row.ID = id
+			break
+		}
+	}
+	s.mockups[row.ID] = row
+
+	return nil
+}
+
+func (s *MemoryDB) FindMockups(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Mockup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mockups := []layerhub.Mockup{}
+	for _, row := range s.find("mockups", s.mockups, filter) {
+		mockups = append(mockups, row.Interface().(layerhub.Mockup))
+	}
+
+	return mockups, nil
+}
+
+func (s *MemoryDB) DeleteMockup(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.mockups, id)
+	return nil
+}
+
+func (s *MemoryDB) PutOrder(ctx context.Context, order *layerhub.Order) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := *order
+	row.Items = make([]*layerhub.OrderItem, len(order.Items))
+	for i, it := range order.Items {
+		item := *it
+		item.OrderID = order.ID
+		row.Items[i] = &item
+	}
+	sort.SliceStable(row.Items, func(i, j int) bool {
+		return row.Items[i].Position < row.Items[j].Position
+	})
+	s.orders[order.ID] = row
+
+	return nil
+}
+
+func (s *MemoryDB) ClaimOrder(ctx context.Context, order *layerhub.Order, status layerhub.OrderStatus, updatedAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, ok := s.orders[order.ID]
+	if !ok || row.Status != status || !row.UpdatedAt.Equal(updatedAt) {
+		return false, nil
+	}
+	row.Status = order.Status
+	row.UpdatedAt = order.UpdatedAt
+	s.orders[order.ID] = row
+
+	return true, nil
+}
+
+func (s *MemoryDB) FindOrders(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Order, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	orders := []layerhub.Order{}
+	for _, row := range s.find("orders", s.orders, filter) {
+		order := row.Interface().(layerhub.Order)
+		items := make([]*layerhub.OrderItem, len(order.Items))
+		for i, it := range order.Items {
+			item := *it
+			items[i] = &item
+		}
+		order.Items = items
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+func (s *MemoryDB) CountOrders(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.count("orders", s.orders, filter), nil
+}
+
+// DeleteOrder deletes the order, the items are stored in the order row
+func (s *MemoryDB) DeleteOrder(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.orders, id)
+	return nil
+}
+
+func (s *MemoryDB) PutProof(ctx context.Context, proof *layerhub.Proof) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, other := range s.proofs {
+		if other.ID != proof.ID && other.ProjectID == proof.ProjectID && other.Version == proof.Version {
+			return errors.Conflict(fmt.Sprintf("version %d of the proofs of project '%s' already exists", proof.Version, proof.ProjectID))
+		}
+	}
+
+	row := *proof
+	row.Comments = nil
+	row.Frame = layerhub.Frame{}
+	row.Layers = nil
+	s.proofs[proof.ID] = row
+
+	return nil
+}
+
+func (s *MemoryDB) FindProofs(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Proof, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	proofs := []layerhub.Proof{}
+	for _, row := range s.find("proofs", s.proofs, filter) {
+		proof := row.Interface().(layerhub.Proof)
+		proof.Comments = s.proofCommentsOf(proof.ID)
+		proofs = append(proofs, proof)
+	}
+
+	return proofs, nil
+}
+
+func (s *MemoryDB) PutProofComment(ctx context.Context, comment *layerhub.ProofComment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.proofComments[comment.ID] = *comment
+	return nil
+}
+
+func (s *MemoryDB) DeleteProof(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.proofs, id)
+	for commentID, comment := range s.proofComments {
+		if comment.ProofID == id {
+			delete(s.proofComments, commentID)
+		}
+	}
+	return nil
+}
+
+// proofCommentsOf returns the comments of the proof, oldest first
+func (s *MemoryDB) proofCommentsOf(proofID string) []*layerhub.ProofComment {
+	comments := []*layerhub.ProofComment{}
+	for _, c := range s.proofComments {
+		if c.ProofID == proofID {
+			comment := c
+			comments = append(comments, &comment)
+		}
+	}
+
+	sort.Slice(comments, func(i, j int) bool {
+		if !comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+		}
+		return comments[i].ID < comments[j].ID
+	})
+
+	return comments
+}
+
+func (s *MemoryDB) PutFolder(ctx context.Context, folder *layerhub.Folder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.folders[folder.ID] = *folder
+	return nil
+}
+
+func (s *MemoryDB) FindFolders(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Folder, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	folders := []layerhub.Folder{}
+	for _, row := range s.find("folders", s.folders, filter) {
+		folders = append(folders, row.Interface().(layerhub.Folder))
+	}
+
+	return folders, nil
+}
+
+func (s *MemoryDB) CountFolders(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.count("folders", s.folders, filter), nil
+}
+
+func (s *MemoryDB) DeleteFolder(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.folders, id)
+	return nil
+}
+
+func (s *MemoryDB) PutTenantJob(ctx context.Context, job *layerhub.TenantJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tenantJobs[job.ID] = *job
+	return nil
+}
+
+func (s *MemoryDB) ClaimTenantJob(ctx context.Context, job *layerhub.TenantJob, status layerhub.TenantJobStatus, updatedAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, ok := s.tenantJobs[job.ID]
+	if !ok || row.Status != status || !row.UpdatedAt.Equal(updatedAt) {
+		return false, nil
+	}
+	row.Status = job.Status
+	row.UpdatedAt = job.UpdatedAt
+	s.tenantJobs[job.ID] = row
+
+	return true, nil
+}
+
+func (s *MemoryDB) FindTenantJobs(ctx context.Context, filter *layerhub.Filter) ([]layerhub.TenantJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jobs := []layerhub.TenantJob{}
+	for _, row := range s.find("tenant_jobs", s.tenantJobs, filter) {
+		jobs = append(jobs, row.Interface().(layerhub.TenantJob))
+	}
+
+	return jobs, nil
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/echovl/orderflo-dev/db/dbtest"
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+)
+
+func TestMemoryDB(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) layerhub.DB { return New() })
+}
+
+func TestKeyValueDB(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	kv := NewKeyValueDB().(*KeyValueDB)
+	kv.now = func() time.Time { return now }
+
+	testcases := []struct {
+		name       string
+		key        string
+		val        any
+		expiration time.Duration
+		elapsed    time.Duration
+		expected   []byte
+		expiredErr bool
+	}{
+		{name: "bytes", key: "k1", val: []byte("v1"), expiration: time.Minute, elapsed: 30 * time.Second, expected: []byte("v1")},
+		{name: "string", key: "k2", val: "v2", expiration: time.Minute, expected: []byte("v2")},
+		{name: "no expiration", key: "k3", val: 3, elapsed: 24 * time.Hour, expected: []byte("3")},
+		{name: "expired", key: "k4", val: "v4", expiration: time.Minute, elapsed: time.Minute, expiredErr: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := kv.Set(ctx, tc.key, tc.val, tc.expiration)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			now = now.Add(tc.elapsed)
+
+			val, err := kv.Get(ctx, tc.key)
+			if tc.expiredErr {
+				if !errors.Is(err, errors.KindNotFound) {
+					t.Fatalf("expected not found error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(val, tc.expected) {
+				t.Errorf("mismatched value:\ngot: %s\nwant: %s", val, tc.expected)
+			}
+
+			if err := kv.Del(ctx, tc.key); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := kv.Get(ctx, tc.key); err == nil {
+				t.Errorf("expected an error after deleting '%s'", tc.key)
+			}
+		})
+	}
+}
@@ -79,6 +79,92 @@ func (s *MongoDB) FindTemplates(ctx context.Context, filter *layerhub.Filter) ([
 	return templates, nil
 }
 
+func (s *MongoDB) PutProject(ctx context.Context, project *layerhub.Project) error {
+	collection := s.client.Database(s.DB).Collection("projects")
+
+	filter := bson.M{"_id": project.ID}
+	query := bson.M{"$set": project}
+	opts := options.Update().SetUpsert(true)
+	_, err := collection.UpdateOne(ctx, filter, query, opts)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MongoDB) DeleteProject(ctx context.Context, id string) error {
+	collection := s.client.Database(s.DB).Collection("projects")
+
+	filter := bson.M{"_id": id}
+	_, err := collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MongoDB) FindProjects(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Project, error) {
+	collection := s.client.Database(s.DB).Collection("projects")
+
+	projects := []layerhub.Project{}
+	cursor, err := collection.Find(ctx, parseFilters(filter))
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	err = cursor.All(ctx, &projects)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return projects, nil
+}
+
+func (s *MongoDB) PutComponent(ctx context.Context, component *layerhub.Component) error {
+	collection := s.client.Database(s.DB).Collection("components")
+
+	filter := bson.M{"_id": component.ID}
+	query := bson.M{"$set": component}
+	opts := options.Update().SetUpsert(true)
+	_, err := collection.UpdateOne(ctx, filter, query, opts)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MongoDB) DeleteComponent(ctx context.Context, id string) error {
+	collection := s.client.Database(s.DB).Collection("components")
+
+	filter := bson.M{"_id": id}
+	_, err := collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MongoDB) FindComponents(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Component, error) {
+	collection := s.client.Database(s.DB).Collection("components")
+
+	components := []layerhub.Component{}
+	cursor, err := collection.Find(ctx, parseFilters(filter))
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	err = cursor.All(ctx, &components)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return components, nil
+}
+
 func (s *MongoDB) Close(ctx context.Context) error {
 	return s.client.Disconnect(ctx)
 }
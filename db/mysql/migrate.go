@@ -0,0 +1,36 @@
+package mysql
+
+import (
+	"database/sql"
+	"embed"
+
+	"github.com/echovl/orderflo-dev/db/schema"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	migratemysql "github.com/golang-migrate/migrate/v4/database/mysql"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrate applies the embedded migrations and returns the schema version
+func Migrate(conf *Config) (uint, error) {
+	// The migrations run several statements at once
+	cfg, err := mysqldriver.ParseDSN(conf.DSN)
+	if err != nil {
+		return 0, err
+	}
+	cfg.MultiStatements = true
+
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		return 0, err
+	}
+
+	driver, err := migratemysql.WithInstance(db, &migratemysql.Config{})
+	if err != nil {
+		db.Close()
+		return 0, err
+	}
+
+	return schema.Migrate(migrations, "migrations", "mysql", driver)
+}
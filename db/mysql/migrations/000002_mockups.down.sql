@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE
+  IF EXISTS mockup_templates;
+
+DROP TABLE
+  IF EXISTS mockups;
+
+COMMIT;
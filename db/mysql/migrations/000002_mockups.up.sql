@@ -0,0 +1,32 @@
+BEGIN;
+
+CREATE TABLE
+  IF NOT EXISTS mockup_templates (
+    id VARCHAR(50),
+    name VARCHAR(255) NOT NULL,
+    photo_url VARCHAR(1000) NOT NULL,
+    placement VARCHAR(1000) NOT NULL,
+    shading_url VARCHAR(1000) NOT NULL,
+    displacement_url VARCHAR(1000) NOT NULL,
+    displacement_strength FLOAT NOT NULL,
+    public BOOLEAN NOT NULL,
+    customer_id VARCHAR(50) NOT NULL,
+    company_id VARCHAR(50) NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (id)
+  );
+
+CREATE TABLE
+  IF NOT EXISTS mockups (
+    id VARCHAR(50),
+    design_id VARCHAR(255) NOT NULL,
+    mockup_template_id VARCHAR(50) NOT NULL,
+    url VARCHAR(1000) NOT NULL,
+    design_updated_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY (design_id, mockup_template_id)
+  );
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE frames DROP COLUMN safe_margin;
+
+COMMIT;
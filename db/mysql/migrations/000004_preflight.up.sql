@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE frames ADD COLUMN safe_margin FLOAT NOT NULL DEFAULT 0;
+
+COMMIT;
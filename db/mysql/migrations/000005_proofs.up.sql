@@ -0,0 +1,35 @@
+BEGIN;
+
+CREATE TABLE
+  IF NOT EXISTS proofs (
+    id VARCHAR(50),
+    project_id VARCHAR(255) NOT NULL,
+    version INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    preview VARCHAR(1000) NOT NULL,
+    project_updated_at DATETIME NOT NULL,
+    user_id VARCHAR(50) NOT NULL,
+    customer_id VARCHAR(50) NOT NULL,
+    company_id VARCHAR(50) NOT NULL,
+    reviewed_at DATETIME,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY (project_id, version)
+  );
+
+CREATE TABLE
+  IF NOT EXISTS proof_comments (
+    id VARCHAR(50),
+    proof_id VARCHAR(50) NOT NULL,
+    user_id VARCHAR(50) NOT NULL,
+    customer_id VARCHAR(50) NOT NULL,
+    body TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    KEY (proof_id)
+  );
+
+ALTER TABLE order_items ADD COLUMN proof_id VARCHAR(50) NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE projects DROP COLUMN template_id;
+ALTER TABLE projects DROP COLUMN template_updated_at;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE projects ADD COLUMN template_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE projects ADD COLUMN template_updated_at DATETIME;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE templates ADD COLUMN public BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE
+  IF EXISTS search_documents;
+
+DROP TABLE
+  IF EXISTS search_facets;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE users DROP COLUMN api_token;
+ALTER TABLE users DROP COLUMN plan_id;
+
+COMMIT;
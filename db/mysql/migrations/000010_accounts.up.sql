@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE users ADD COLUMN plan_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN api_token VARCHAR(255) NOT NULL DEFAULT '';
+
+COMMIT;
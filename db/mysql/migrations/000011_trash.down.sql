@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE uploads DROP COLUMN deleted_at;
+ALTER TABLE components DROP COLUMN deleted_at;
+ALTER TABLE projects DROP COLUMN deleted_at;
+ALTER TABLE templates DROP COLUMN deleted_at;
+
+COMMIT;
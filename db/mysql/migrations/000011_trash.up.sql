@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE templates ADD COLUMN deleted_at DATETIME;
+ALTER TABLE projects ADD COLUMN deleted_at DATETIME;
+ALTER TABLE components ADD COLUMN deleted_at DATETIME;
+ALTER TABLE uploads ADD COLUMN deleted_at DATETIME;
+
+COMMIT;
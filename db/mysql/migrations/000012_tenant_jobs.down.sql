@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS tenant_jobs;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+ALTER TABLE fonts DROP COLUMN no_subsetting;
+ALTER TABLE fonts DROP COLUMN embedding;
+ALTER TABLE fonts DROP COLUMN subsets;
+ALTER TABLE fonts DROP COLUMN num_glyphs;
+ALTER TABLE fonts DROP COLUMN axes;
+ALTER TABLE fonts DROP COLUMN italic;
+ALTER TABLE fonts DROP COLUMN width;
+ALTER TABLE fonts DROP COLUMN weight;
+ALTER TABLE fonts DROP COLUMN format;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+ALTER TABLE fonts ADD COLUMN format VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE fonts ADD COLUMN weight INT NOT NULL DEFAULT 400;
+ALTER TABLE fonts ADD COLUMN width INT NOT NULL DEFAULT 5;
+ALTER TABLE fonts ADD COLUMN italic BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE fonts ADD COLUMN axes VARCHAR(2000) NOT NULL DEFAULT '[]';
+ALTER TABLE fonts ADD COLUMN num_glyphs INT NOT NULL DEFAULT 0;
+ALTER TABLE fonts ADD COLUMN subsets VARCHAR(1000) NOT NULL DEFAULT '[]';
+ALTER TABLE fonts ADD COLUMN embedding VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE fonts ADD COLUMN no_subsetting BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
@@ -9,7 +9,7 @@ import (
 
 	"github.com/echovl/orderflo-dev/errors"
 	"github.com/echovl/orderflo-dev/layerhub"
-	_ "github.com/go-sql-driver/mysql"
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -18,6 +18,33 @@ type ExtContext interface {
 	sqlx.QueryerContext
 }
 
+// duplicateEntry is the error number of a write that breaks a unique key
+const duplicateEntry = 1062
+
+// conn runs the queries of the store, it's the database or the transaction of
+// a unit of work
+type conn interface {
+	ExtContext
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+}
+
+// txConn is a transaction of the statements written together by a method
+type txConn interface {
+	ExtContext
+	Commit() error
+	Rollback() error
+}
+
+// joinedTx is the transaction of a unit of work joined by a method, the unit
+// of work commits or rolls it back
+type joinedTx struct {
+	*sqlx.Tx
+}
+
+func (joinedTx) Commit() error   { return nil }
+func (joinedTx) Rollback() error { return nil }
+
 type Config struct {
 	DSN             string
 	ConnMaxIdleTime time.Duration
@@ -28,9 +55,22 @@ type Config struct {
 
 type MySQLDB struct {
 	db *sqlx.DB
+
+	// tx is the transaction of a unit of work started by WithTx, the
+	// statements of the copy handed to it run on it
+	tx *sqlx.Tx
 }
 
 func New(conf *Config) (layerhub.DB, error) {
+	db, err := open(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MySQLDB{db: db}, nil
+}
+
+func open(conf *Config) (*sqlx.DB, error) {
 	db, err := sqlx.Open("mysql", conf.DSN)
 	if err != nil {
 		return nil, err
@@ -54,7 +94,48 @@ func New(conf *Config) (layerhub.DB, error) {
 		return nil, err
 	}
 
-	return &MySQLDB{db}, nil
+	return db, nil
+}
+
+// WithTx runs fn in a transaction, it's committed when fn succeeds and rolled
+// back otherwise. The DB given to fn must be used for every statement of the
+// unit of work, calls to WithTx on it join the same transaction
+func (s *MySQLDB) WithTx(ctx context.Context, fn func(tx layerhub.DB) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(&MySQLDB{db: s.db, tx: tx}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MySQLDB) conn() conn {
+	if s.tx != nil {
+		return s.tx
+	}
+	return s.db
+}
+
+// begin starts the transaction of a method, inside a unit of work the method
+// joins its transaction instead
+func (s *MySQLDB) begin(ctx context.Context) (txConn, error) {
+	if s.tx != nil {
+		return joinedTx{s.tx}, nil
+	}
+	return s.db.BeginTxx(ctx, &sql.TxOptions{})
 }
 
 func (s *MySQLDB) PutUser(ctx context.Context, user *layerhub.User) error {
@@ -69,11 +150,13 @@ func (s *MySQLDB) PutUser(ctx context.Context, user *layerhub.User) error {
         phone_verified,
         role,
         password_hash,
+        plan_id,
+        api_token,
         source,
         company_id,
         created_at,
         updated_at
-    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
+    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
         first_name=VALUES(first_name),
         last_name=VALUES(last_name),
         email=VALUES(email),
@@ -83,10 +166,12 @@ func (s *MySQLDB) PutUser(ctx context.Context, user *layerhub.User) error {
         phone_verified=VALUES(phone_verified),
         role=VALUES(role),
         password_hash=VALUES(password_hash),
+        plan_id=VALUES(plan_id),
+        api_token=VALUES(api_token),
         updated_at=VALUES(updated_at)
     `
 
-	_, err := s.db.ExecContext(
+	_, err := s.conn().ExecContext(
 		ctx,
 		query,
 		user.ID,
@@ -99,6 +184,8 @@ func (s *MySQLDB) PutUser(ctx context.Context, user *layerhub.User) error {
 		user.PhoneVerified,
 		user.Role,
 		user.PasswordHash,
+		user.PlanID,
+		user.ApiToken,
 		user.Source,
 		user.CompanyID,
 		user.CreatedAt,
@@ -117,7 +204,7 @@ func (s *MySQLDB) FindUsers(ctx context.Context, filter *layerhub.Filter) ([]lay
 	where, args := filterToQuery("users", filter)
 	users := []layerhub.User{}
 
-	err := s.db.SelectContext(ctx, &users, query+where, args...)
+	err := s.conn().SelectContext(ctx, &users, query+where, args...)
 	if err != nil {
 		return nil, errors.E(errors.KindUnexpected, err)
 	}
@@ -125,8 +212,19 @@ func (s *MySQLDB) FindUsers(ctx context.Context, filter *layerhub.Filter) ([]lay
 	return users, nil
 }
 
+func (s *MySQLDB) DeleteUser(ctx context.Context, id string) error {
+	query := `DELETE FROM users WHERE id = ?`
+
+	_, err := s.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
 func (s *MySQLDB) BatchCreateFonts(ctx context.Context, fonts []layerhub.Font) error {
-	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	tx, err := s.begin(ctx)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
 	}
@@ -151,13 +249,22 @@ func (s *MySQLDB) BatchCreateFonts(ctx context.Context, fonts []layerhub.Font) e
             customer_id,
             company_id,
             category,
-            public
+            public,
+            format,
+            weight,
+            width,
+            italic,
+            axes,
+            num_glyphs,
+            subsets,
+            embedding,
+            no_subsetting
         ) VALUES `
 
 		args := []any{}
 		values := []string{}
 		for _, pf := range fonts[batchStart:batchEnd] {
-			values = append(values, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+			values = append(values, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 			args = append(
 				args,
 				pf.ID,
@@ -171,6 +278,15 @@ func (s *MySQLDB) BatchCreateFonts(ctx context.Context, fonts []layerhub.Font) e
 				pf.CompanyID,
 				pf.Category,
 				pf.Public,
+				pf.Format,
+				pf.Weight,
+				pf.Width,
+				pf.Italic,
+				pf.Axes,
+				pf.NumGlyphs,
+				pf.Subsets,
+				pf.Embedding,
+				pf.NoSubsetting,
 			)
 		}
 		query += strings.Join(values, ",")
@@ -207,7 +323,7 @@ func (s *MySQLDB) PutCustomer(ctx context.Context, customer *layerhub.Customer)
         updated_at=VALUES(updated_at)
     `
 
-	_, err := s.db.ExecContext(
+	_, err := s.conn().ExecContext(
 		ctx,
 		query,
 		customer.ID,
@@ -232,7 +348,7 @@ func (s *MySQLDB) FindCustomers(ctx context.Context, filter *layerhub.Filter) ([
 	where, args := filterToQuery("customers", filter)
 	customers := []layerhub.Customer{}
 
-	err := s.db.SelectContext(ctx, &customers, query+where, args...)
+	err := s.conn().SelectContext(ctx, &customers, query+where, args...)
 	if err != nil {
 		return nil, errors.E(errors.KindUnexpected, err)
 	}
@@ -245,7 +361,7 @@ func (s *MySQLDB) CountCustomers(ctx context.Context, filter *layerhub.Filter) (
 	where, args := filterToQuery("customers", filter)
 	count := []CountRow{}
 
-	err := s.db.SelectContext(ctx, &count, query+where, args...)
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
 	if err != nil {
 		return 0, errors.E(errors.KindUnexpected, err)
 	}
@@ -256,7 +372,7 @@ func (s *MySQLDB) CountCustomers(ctx context.Context, filter *layerhub.Filter) (
 func (s *MySQLDB) DeleteCustomer(ctx context.Context, id string) error {
 	query := `DELETE FROM customers WHERE id = ?`
 
-	_, err := s.db.ExecContext(ctx, query, id)
+	_, err := s.conn().ExecContext(ctx, query, id)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
 	}
@@ -275,7 +391,7 @@ func (s *MySQLDB) PutCompany(ctx context.Context, company *layerhub.Company) err
         updated_at=VALUES(updated_at)
     `
 
-	_, err := s.db.ExecContext(
+	_, err := s.conn().ExecContext(
 		ctx,
 		query,
 		company.ID,
@@ -295,7 +411,7 @@ func (s *MySQLDB) FindCompanies(ctx context.Context, filter *layerhub.Filter) ([
 	where, args := filterToQuery("companies", filter)
 	companies := []layerhub.Company{}
 
-	err := s.db.SelectContext(ctx, &companies, query+where, args...)
+	err := s.conn().SelectContext(ctx, &companies, query+where, args...)
 	if err != nil {
 		return nil, errors.E(errors.KindUnexpected, err)
 	}
@@ -308,7 +424,7 @@ func (s *MySQLDB) CountCompanies(ctx context.Context, filter *layerhub.Filter) (
 	where, args := filterToQuery("companies", filter)
 	count := []CountRow{}
 
-	err := s.db.SelectContext(ctx, &count, query+where, args...)
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
 	if err != nil {
 		return 0, errors.E(errors.KindUnexpected, err)
 	}
@@ -319,7 +435,7 @@ func (s *MySQLDB) CountCompanies(ctx context.Context, filter *layerhub.Filter) (
 func (s *MySQLDB) DeleteCompany(ctx context.Context, id string) error {
 	query := `DELETE FROM companies WHERE id = ?`
 
-	_, err := s.db.ExecContext(ctx, query, id)
+	_, err := s.conn().ExecContext(ctx, query, id)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
 	}
@@ -343,7 +459,7 @@ func (s *MySQLDB) FindFonts(ctx context.Context, filter *layerhub.Filter) ([]lay
 
 	fmt.Println(query+where, args)
 
-	err := s.db.SelectContext(ctx, &fonts, query+where, args...)
+	err := s.conn().SelectContext(ctx, &fonts, query+where, args...)
 	if err != nil {
 		return nil, errors.E(errors.KindUnexpected, err)
 	}
@@ -365,7 +481,7 @@ func (s *MySQLDB) CountFonts(ctx context.Context, filter *layerhub.Filter) (int,
 		args = append([]any{filter.OptionalCustomerID}, args...)
 	}
 
-	err := s.db.SelectContext(ctx, &count, query+where, args...)
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
 	if err != nil {
 		return 0, errors.E(errors.KindUnexpected, err)
 	}
@@ -385,17 +501,35 @@ func (s MySQLDB) PutFont(ctx context.Context, font *layerhub.Font) error {
         category,
         customer_id,
         company_id,
-        public
-    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
+        public,
+        format,
+        weight,
+        width,
+        italic,
+        axes,
+        num_glyphs,
+        subsets,
+        embedding,
+        no_subsetting
+    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
         full_name=VALUES(full_name),
         family=VALUES(family),
         postscript_name=VALUES(postscript_name),
         preview=VALUES(preview),
         style=VALUES(style),
         url=VALUES(url),
-        category=VALUES(category)
+        category=VALUES(category),
+        format=VALUES(format),
+        weight=VALUES(weight),
+        width=VALUES(width),
+        italic=VALUES(italic),
+        axes=VALUES(axes),
+        num_glyphs=VALUES(num_glyphs),
+        subsets=VALUES(subsets),
+        embedding=VALUES(embedding),
+        no_subsetting=VALUES(no_subsetting)
     `
-	_, err := s.db.ExecContext(
+	_, err := s.conn().ExecContext(
 		ctx,
 		query,
 		font.ID,
@@ -409,6 +543,15 @@ func (s MySQLDB) PutFont(ctx context.Context, font *layerhub.Font) error {
 		font.CustomerID,
 		font.CompanyID,
 		font.Public,
+		font.Format,
+		font.Weight,
+		font.Width,
+		font.Italic,
+		font.Axes,
+		font.NumGlyphs,
+		font.Subsets,
+		font.Embedding,
+		font.NoSubsetting,
 	)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
@@ -420,7 +563,7 @@ func (s MySQLDB) PutFont(ctx context.Context, font *layerhub.Font) error {
 func (s MySQLDB) DeleteFont(ctx context.Context, id string) error {
 	query := `DELETE FROM fonts WHERE id = ?`
 
-	_, err := s.db.ExecContext(ctx, query, id)
+	_, err := s.conn().ExecContext(ctx, query, id)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
 	}
@@ -429,7 +572,7 @@ func (s MySQLDB) DeleteFont(ctx context.Context, id string) error {
 }
 
 func (s *MySQLDB) PutTemplate(ctx context.Context, template *layerhub.Template) error {
-	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	tx, err := s.begin(ctx)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
 	}
@@ -441,18 +584,24 @@ func (s *MySQLDB) PutTemplate(ctx context.Context, template *layerhub.Template)
         name,
         type,
         published,
+        public,
+        folder_id,
         preview,
         customer_id,
         company_id,
         created_at,
-        updated_at
-    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
+        updated_at,
+        deleted_at
+    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
         short_id=VALUES(short_id),
         name=VALUES(name),
         type=VALUES(type),
         published=VALUES(published),
+        public=VALUES(public),
+        folder_id=VALUES(folder_id),
         preview=VALUES(preview),
-        updated_at=VALUES(updated_at)
+        updated_at=VALUES(updated_at),
+        deleted_at=VALUES(deleted_at)
     `
 
 	_, err = tx.ExecContext(
@@ -462,11 +611,14 @@ func (s *MySQLDB) PutTemplate(ctx context.Context, template *layerhub.Template)
 		template.Name,
 		template.Type,
 		template.Published,
+		template.Public,
+		template.FolderID,
 		template.Preview,
 		template.CustomerID,
 		template.CompanyID,
 		template.CreatedAt,
 		template.UpdatedAt,
+		template.DeletedAt,
 	)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
@@ -477,6 +629,9 @@ func (s *MySQLDB) PutTemplate(ctx context.Context, template *layerhub.Template)
 		Name:           template.Frame.Name,
 		Width:          template.Frame.Width,
 		Height:         template.Frame.Height,
+		Unit:           template.Frame.Unit,
+		Bleed:          template.Frame.Bleed,
+		SafeMargin:     template.Frame.SafeMargin,
 		UsedInTemplate: true,
 	})
 	if err != nil {
@@ -511,7 +666,7 @@ func (s *MySQLDB) FindTemplates(ctx context.Context, filter *layerhub.Filter) ([
 	where, args := filterToQuery("templates", filter)
 	templates := []layerhub.Template{}
 
-	err := s.db.SelectContext(ctx, &templates, query+where, args...)
+	err := s.conn().SelectContext(ctx, &templates, query+where, args...)
 	if err != nil {
 		return nil, errors.E(errors.KindUnexpected, err)
 	}
@@ -549,7 +704,7 @@ func (s *MySQLDB) CountTemplates(ctx context.Context, filter *layerhub.Filter) (
 	where, args := filterToQuery("templates", filter)
 	count := []CountRow{}
 
-	err := s.db.SelectContext(ctx, &count, query+where, args...)
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
 	if err != nil {
 		return 0, errors.E(errors.KindUnexpected, err)
 	}
@@ -558,7 +713,7 @@ func (s *MySQLDB) CountTemplates(ctx context.Context, filter *layerhub.Filter) (
 }
 
 func (s *MySQLDB) DeleteTemplate(ctx context.Context, id string) error {
-	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	tx, err := s.begin(ctx)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
 	}
@@ -606,16 +761,20 @@ func (s *MySQLDB) putFrame(ctx context.Context, ext ExtContext, frame *layerhub.
         width,
         height,
         unit,
+        bleed,
+        safe_margin,
         preview,
         used_in_template,
         customer_id,
         company_id
-    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
+    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
         name=VALUES(name),
         public=VALUES(public),
         width=VALUES(width),
         height=VALUES(height),
         unit=VALUES(unit),
+        bleed=VALUES(bleed),
+        safe_margin=VALUES(safe_margin),
         preview=VALUES(preview)
     `
 
@@ -628,6 +787,8 @@ func (s *MySQLDB) putFrame(ctx context.Context, ext ExtContext, frame *layerhub.
 		frame.Width,
 		frame.Height,
 		frame.Unit,
+		frame.Bleed,
+		frame.SafeMargin,
 		frame.Preview,
 		frame.UsedInTemplate,
 		frame.CustomerID,
@@ -645,7 +806,7 @@ func (s *MySQLDB) FindFrames(ctx context.Context, filter *layerhub.Filter) ([]la
 	where, args := filterToQuery("frames", filter)
 	frames := []layerhub.Frame{}
 
-	err := s.db.SelectContext(ctx, &frames, query+where, args...)
+	err := s.conn().SelectContext(ctx, &frames, query+where, args...)
 	if err != nil {
 		return nil, errors.E(errors.KindUnexpected, err)
 	}
@@ -658,7 +819,7 @@ func (s *MySQLDB) CountFrames(ctx context.Context, filter *layerhub.Filter) (int
 	where, args := filterToQuery("frames", filter)
 	count := []CountRow{}
 
-	err := s.db.SelectContext(ctx, &count, query+where, args...)
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
 	if err != nil {
 		return 0, errors.E(errors.KindUnexpected, err)
 	}
@@ -682,7 +843,7 @@ func (s *MySQLDB) deleteFrame(ctx context.Context, ext ExtContext, id string) er
 }
 
 func (s *MySQLDB) PutProject(ctx context.Context, project *layerhub.Project) error {
-	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	tx, err := s.begin(ctx)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
 	}
@@ -696,14 +857,20 @@ func (s *MySQLDB) PutProject(ctx context.Context, project *layerhub.Project) err
         preview,
         customer_id,
         company_id,
+        template_id,
+        template_updated_at,
+        folder_id,
         created_at,
-        updated_at
-    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
+        updated_at,
+        deleted_at
+    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
         short_id=VALUES(short_id),
         name=VALUES(name),
         type=VALUES(type),
         preview=VALUES(preview),
-        updated_at=VALUES(updated_at)
+        folder_id=VALUES(folder_id),
+        updated_at=VALUES(updated_at),
+        deleted_at=VALUES(deleted_at)
     `
 
 	_, err = tx.ExecContext(
@@ -716,8 +883,12 @@ func (s *MySQLDB) PutProject(ctx context.Context, project *layerhub.Project) err
 		project.Preview,
 		project.CustomerID,
 		project.CompanyID,
+		project.TemplateID,
+		project.TemplateUpdatedAt,
+		project.FolderID,
 		project.CreatedAt,
 		project.UpdatedAt,
+		project.DeletedAt,
 	)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
@@ -728,6 +899,9 @@ func (s *MySQLDB) PutProject(ctx context.Context, project *layerhub.Project) err
 		Name:           project.Frame.Name,
 		Width:          project.Frame.Width,
 		Height:         project.Frame.Height,
+		Unit:           project.Frame.Unit,
+		Bleed:          project.Frame.Bleed,
+		SafeMargin:     project.Frame.SafeMargin,
 		UsedInTemplate: true,
 	})
 	if err != nil {
@@ -747,7 +921,7 @@ func (s *MySQLDB) FindProjects(ctx context.Context, filter *layerhub.Filter) ([]
 	where, args := filterToQuery("projects", filter)
 	projects := []layerhub.Project{}
 
-	err := s.db.SelectContext(ctx, &projects, query+where, args...)
+	err := s.conn().SelectContext(ctx, &projects, query+where, args...)
 	if err != nil {
 		return nil, errors.E(errors.KindUnexpected, err)
 	}
@@ -772,7 +946,7 @@ func (s *MySQLDB) CountProjects(ctx context.Context, filter *layerhub.Filter) (i
 	where, args := filterToQuery("projects", filter)
 	count := []CountRow{}
 
-	err := s.db.SelectContext(ctx, &count, query+where, args...)
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
 	if err != nil {
 		return 0, errors.E(errors.KindUnexpected, err)
 	}
@@ -783,7 +957,7 @@ func (s *MySQLDB) CountProjects(ctx context.Context, filter *layerhub.Filter) (i
 func (s *MySQLDB) DeleteProject(ctx context.Context, id string) error {
 	query := `DELETE FROM projects WHERE id = ?`
 
-	_, err := s.db.ExecContext(ctx, query, id)
+	_, err := s.conn().ExecContext(ctx, query, id)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
 	}
@@ -800,15 +974,19 @@ func (s *MySQLDB) PutComponent(ctx context.Context, component *layerhub.Componen
         customer_id,
         company_id,
         user_id,
+        folder_id,
         created_at,
-        updated_at
-    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
+        updated_at,
+        deleted_at
+    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
         name=VALUES(name),
         preview=VALUES(preview),
-        updated_at=VALUES(updated_at)
+        folder_id=VALUES(folder_id),
+        updated_at=VALUES(updated_at),
+        deleted_at=VALUES(deleted_at)
     `
 
-	_, err := s.db.ExecContext(
+	_, err := s.conn().ExecContext(
 		ctx,
 		query,
 		component.ID,
@@ -818,8 +996,10 @@ func (s *MySQLDB) PutComponent(ctx context.Context, component *layerhub.Componen
 		component.CustomerID,
 		component.CompanyID,
 		component.UserID,
+		component.FolderID,
 		component.CreatedAt,
 		component.UpdatedAt,
+		component.DeletedAt,
 	)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
@@ -833,7 +1013,7 @@ func (s *MySQLDB) FindComponents(ctx context.Context, filter *layerhub.Filter) (
 	where, args := filterToQuery("components", filter)
 	components := []layerhub.Component{}
 
-	err := s.db.SelectContext(ctx, &components, query+where, args...)
+	err := s.conn().SelectContext(ctx, &components, query+where, args...)
 	if err != nil {
 		return nil, errors.E(errors.KindUnexpected, err)
 	}
@@ -846,7 +1026,7 @@ func (s *MySQLDB) CountComponents(ctx context.Context, filter *layerhub.Filter)
 	where, args := filterToQuery("components", filter)
 	count := []CountRow{}
 
-	err := s.db.SelectContext(ctx, &count, query+where, args...)
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
 	if err != nil {
 		return 0, errors.E(errors.KindUnexpected, err)
 	}
@@ -857,7 +1037,7 @@ func (s *MySQLDB) CountComponents(ctx context.Context, filter *layerhub.Filter)
 func (s *MySQLDB) DeleteComponent(ctx context.Context, id string) error {
 	query := `DELETE FROM components WHERE id = ?`
 
-	_, err := s.db.ExecContext(ctx, query, id)
+	_, err := s.conn().ExecContext(ctx, query, id)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
 	}
@@ -876,17 +1056,19 @@ func (s *MySQLDB) PutUpload(ctx context.Context, upload *layerhub.Upload) error
         company_id,
         customer_id,
         created_at,
-        updated_at
-    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
+        updated_at,
+        deleted_at
+    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
         name=VALUES(name),
         content_type=VALUES(content_type),
         folder=VALUES(folder),
         type=VALUES(type),
         url=VALUES(url),
-        updated_at=VALUES(updated_at)
+        updated_at=VALUES(updated_at),
+        deleted_at=VALUES(deleted_at)
     `
 
-	_, err := s.db.ExecContext(
+	_, err := s.conn().ExecContext(
 		ctx,
 		query,
 		upload.ID,
@@ -899,6 +1081,7 @@ func (s *MySQLDB) PutUpload(ctx context.Context, upload *layerhub.Upload) error
 		upload.CustomerID,
 		upload.CreatedAt,
 		upload.UpdatedAt,
+		upload.DeletedAt,
 	)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
@@ -912,7 +1095,7 @@ func (s *MySQLDB) FindUploads(ctx context.Context, filter *layerhub.Filter) ([]l
 	where, args := filterToQuery("uploads", filter)
 	uploads := []layerhub.Upload{}
 
-	err := s.db.SelectContext(ctx, &uploads, query+where, args...)
+	err := s.conn().SelectContext(ctx, &uploads, query+where, args...)
 	if err != nil {
 		return nil, errors.E(errors.KindUnexpected, err)
 	}
@@ -925,7 +1108,7 @@ func (s *MySQLDB) CountUploads(ctx context.Context, filter *layerhub.Filter) (in
 	where, args := filterToQuery("uploads", filter)
 	count := []CountRow{}
 
-	err := s.db.SelectContext(ctx, &count, query+where, args...)
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
 	if err != nil {
 		return 0, errors.E(errors.KindUnexpected, err)
 	}
@@ -936,7 +1119,7 @@ func (s *MySQLDB) CountUploads(ctx context.Context, filter *layerhub.Filter) (in
 func (s *MySQLDB) DeleteUpload(ctx context.Context, id string) error {
 	query := `DELETE FROM uploads WHERE id = ?`
 
-	_, err := s.db.ExecContext(ctx, query, id)
+	_, err := s.conn().ExecContext(ctx, query, id)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
 	}
@@ -951,7 +1134,7 @@ func (s *MySQLDB) FindEnabledFonts(ctx context.Context, customerID string) ([]la
 	})
 	fonts := []layerhub.EnabledFont{}
 
-	err := s.db.SelectContext(ctx, &fonts, query+where, args...)
+	err := s.conn().SelectContext(ctx, &fonts, query+where, args...)
 	if err != nil {
 		return nil, errors.E(errors.KindUnexpected, err)
 	}
@@ -960,7 +1143,7 @@ func (s *MySQLDB) FindEnabledFonts(ctx context.Context, customerID string) ([]la
 }
 
 func (s *MySQLDB) BatchCreateEnabledFonts(ctx context.Context, fonts []*layerhub.EnabledFont) error {
-	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	tx, err := s.begin(ctx)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
 	}
@@ -1018,7 +1201,7 @@ func (s *MySQLDB) BatchDeleteEnabledFonts(ctx context.Context, ids []string) err
 	if len(ids) != 0 {
 		query := fmt.Sprintf("DELETE FROM enabled_fonts WHERE id IN (%s)", strings.Join(args, ","))
 
-		_, err := s.db.ExecContext(ctx, query, values...)
+		_, err := s.conn().ExecContext(ctx, query, values...)
 		if err != nil {
 			return errors.E(errors.KindUnexpected, err)
 		}
@@ -1028,7 +1211,7 @@ func (s *MySQLDB) BatchDeleteEnabledFonts(ctx context.Context, ids []string) err
 }
 
 func (s *MySQLDB) PutSubscriptionPlan(ctx context.Context, plan *layerhub.SubscriptionPlan) error {
-	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	tx, err := s.begin(ctx)
 	if err != nil {
 		return errors.E(errors.KindUnexpected, err)
 	}
@@ -1087,7 +1270,7 @@ func (s *MySQLDB) FindSubscriptionPlans(ctx context.Context) ([]layerhub.Subscri
 	query := `SELECT * FROM subscription_plans`
 	plans := []layerhub.SubscriptionPlan{}
 
-	err := s.db.SelectContext(ctx, &plans, query)
+	err := s.conn().SelectContext(ctx, &plans, query)
 	if err != nil {
 		return nil, errors.E(errors.KindUnexpected, err)
 	}
@@ -1103,6 +1286,659 @@ func (s *MySQLDB) FindSubscriptionPlans(ctx context.Context) ([]layerhub.Subscri
 	return plans, nil
 }
 
+func (s *MySQLDB) PutMockupTemplate(ctx context.Context, mt *layerhub.MockupTemplate) error {
+	query := `INSERT INTO mockup_templates (
+        id,
+        name,
+        photo_url,
+        placement,
+        shading_url,
+        displacement_url,
+        displacement_strength,
+        public,
+        customer_id,
+        company_id,
+        created_at,
+        updated_at
+    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
+        name=VALUES(name),
+        photo_url=VALUES(photo_url),
+        placement=VALUES(placement),
+        shading_url=VALUES(shading_url),
+        displacement_url=VALUES(displacement_url),
+        displacement_strength=VALUES(displacement_strength),
+        public=VALUES(public),
+        updated_at=VALUES(updated_at)
+    `
+
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		mt.ID,
+		mt.Name,
+		mt.PhotoURL,
+		mt.Placement,
+		mt.ShadingURL,
+		mt.DisplacementURL,
+		mt.DisplacementStrength,
+		mt.Public,
+		mt.CustomerID,
+		mt.CompanyID,
+		mt.CreatedAt,
+		mt.UpdatedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MySQLDB) FindMockupTemplates(ctx context.Context, filter *layerhub.Filter) ([]layerhub.MockupTemplate, error) {
+	query := `SELECT * FROM mockup_templates `
+	where, args := filterToQuery("mockup_templates", filter)
+	mts := []layerhub.MockupTemplate{}
+
+	err := s.conn().SelectContext(ctx, &mts, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return mts, nil
+}
+
+func (s *MySQLDB) CountMockupTemplates(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	query := `SELECT COUNT(*) AS count FROM mockup_templates `
+	where, args := filterToQuery("mockup_templates", filter)
+	count := []CountRow{}
+
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
+	if err != nil {
+		return 0, errors.E(errors.KindUnexpected, err)
+	}
+
+	return count[0].Count, nil
+}
+
+func (s *MySQLDB) DeleteMockupTemplate(ctx context.Context, id string) error {
+	tx, err := s.begin(ctx)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM mockup_templates WHERE id = ?`, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM mockups WHERE mockup_template_id = ?`, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MySQLDB) PutMockup(ctx context.Context, mockup *layerhub.Mockup) error {
+	query := `INSERT INTO mockups (
+        id,
+        design_id,
+        mockup_template_id,
+        url,
+        design_updated_at,
+        created_at
+    ) VALUES (?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
+        url=VALUES(url),
+        design_updated_at=VALUES(design_updated_at),
+        created_at=VALUES(created_at)
+    `
+
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		mockup.ID,
+		mockup.DesignID,
+		mockup.MockupTemplateID,
+		mockup.URL,
+		mockup.DesignUpdatedAt,
+		mockup.CreatedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MySQLDB) FindMockups(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Mockup, error) {
+	query := `SELECT * FROM mockups `
+	where, args := filterToQuery("mockups", filter)
+	mockups := []layerhub.Mockup{}
+
+	err := s.conn().SelectContext(ctx, &mockups, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return mockups, nil
+}
+
+func (s *MySQLDB) DeleteMockup(ctx context.Context, id string) error {
+	query := `DELETE FROM mockups WHERE id = ?`
+
+	_, err := s.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MySQLDB) PutOrder(ctx context.Context, order *layerhub.Order) error {
+	tx, err := s.begin(ctx)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO orders (
+        id,
+        number,
+        status,
+        customer_id,
+        company_id,
+        submitted_at,
+        created_at,
+        updated_at
+    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
+        status=VALUES(status),
+        submitted_at=VALUES(submitted_at),
+        updated_at=VALUES(updated_at)
+    `
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		order.ID,
+		order.Number,
+		order.Status,
+		order.CustomerID,
+		order.CompanyID,
+		order.SubmittedAt,
+		order.CreatedAt,
+		order.UpdatedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = s.putOrderItems(ctx, tx, order)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MySQLDB) ClaimOrder(ctx context.Context, order *layerhub.Order, status layerhub.OrderStatus, updatedAt time.Time) (bool, error) {
+	query := `UPDATE orders SET status = ?, updated_at = ? WHERE id = ? AND status = ? AND updated_at = ?`
+
+	res, err := s.conn().ExecContext(ctx, query, order.Status, order.UpdatedAt, order.ID, status, updatedAt)
+	if err != nil {
+		return false, errors.E(errors.KindUnexpected, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.E(errors.KindUnexpected, err)
+	}
+
+	return n == 1, nil
+}
+
+func (s *MySQLDB) FindOrders(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Order, error) {
+	query := `SELECT * FROM orders `
+	where, args := filterToQuery("orders", filter)
+	orders := []layerhub.Order{}
+
+	err := s.conn().SelectContext(ctx, &orders, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	for i, order := range orders {
+		items, err := s.getOrderItems(ctx, order.ID)
+		if err != nil {
+			return nil, err
+		}
+		orders[i].Items = items
+	}
+
+	return orders, nil
+}
+
+func (s *MySQLDB) CountOrders(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	query := `SELECT COUNT(*) AS count FROM orders `
+	where, args := filterToQuery("orders", filter)
+	count := []CountRow{}
+
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
+	if err != nil {
+		return 0, errors.E(errors.KindUnexpected, err)
+	}
+
+	return count[0].Count, nil
+}
+
+func (s *MySQLDB) DeleteOrder(ctx context.Context, id string) error {
+	tx, err := s.begin(ctx)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM orders WHERE id = ?`, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM order_items WHERE order_id = ?`, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MySQLDB) putOrderItems(ctx context.Context, ext ExtContext, order *layerhub.Order) error {
+	delQuery := `DELETE FROM order_items WHERE order_id = ?`
+	_, err := ext.ExecContext(ctx, delQuery, order.ID)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO order_items (
+        id,
+        order_id,
+        position,
+        project_id,
+        proof_id,
+        sku,
+        quantity,
+        format,
+        dpi,
+        print_file_url,
+        print_status,
+        print_error,
+        print_attempts
+    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	for _, item := range order.Items {
+		_, err := ext.ExecContext(
+			ctx,
+			query,
+			item.ID,
+			order.ID,
+			item.Position,
+			item.ProjectID,
+			item.ProofID,
+			item.SKU,
+			item.Quantity,
+			item.Format,
+			item.DPI,
+			item.PrintFileURL,
+			item.PrintStatus,
+			item.PrintError,
+			item.PrintAttempts,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *MySQLDB) getOrderItems(ctx context.Context, orderID string) ([]*layerhub.OrderItem, error) {
+	query := `SELECT * FROM order_items WHERE order_id = ? ORDER BY position`
+	items := []*layerhub.OrderItem{}
+
+	err := s.conn().SelectContext(ctx, &items, query, orderID)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return items, nil
+}
+
+// PutProof inserts new proofs instead of upserting them, an upsert would
+// update the proof holding the same version of the project
+func (s *MySQLDB) PutProof(ctx context.Context, proof *layerhub.Proof) error {
+	tx, err := s.begin(ctx)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	var count int
+	err = tx.QueryRowxContext(ctx, `SELECT COUNT(*) FROM proofs WHERE id = ? FOR UPDATE`, proof.ID).Scan(&count)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	if count > 0 {
+		_, err = tx.ExecContext(
+			ctx,
+			`UPDATE proofs SET status = ?, reviewed_at = ?, updated_at = ? WHERE id = ?`,
+			proof.Status,
+			proof.ReviewedAt,
+			proof.UpdatedAt,
+			proof.ID,
+		)
+		if err != nil {
+			return errors.E(errors.KindUnexpected, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return errors.E(errors.KindUnexpected, err)
+		}
+		return nil
+	}
+
+	query := `INSERT INTO proofs (
+        id,
+        project_id,
+        version,
+        status,
+        preview,
+        project_updated_at,
+        user_id,
+        customer_id,
+        company_id,
+        reviewed_at,
+        created_at,
+        updated_at
+    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		proof.ID,
+		proof.ProjectID,
+		proof.Version,
+		proof.Status,
+		proof.Preview,
+		proof.ProjectUpdatedAt,
+		proof.UserID,
+		proof.CustomerID,
+		proof.CompanyID,
+		proof.ReviewedAt,
+		proof.CreatedAt,
+		proof.UpdatedAt,
+	)
+	if mysqlErr, ok := err.(*mysqldriver.MySQLError); ok && mysqlErr.Number == duplicateEntry {
+		return errors.Conflict(fmt.Sprintf("version %d of the proofs of project '%s' already exists", proof.Version, proof.ProjectID))
+	}
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MySQLDB) FindProofs(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Proof, error) {
+	query := `SELECT * FROM proofs `
+	where, args := filterToQuery("proofs", filter)
+	proofs := []layerhub.Proof{}
+
+	err := s.conn().SelectContext(ctx, &proofs, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	for i, proof := range proofs {
+		comments, err := s.getProofComments(ctx, proof.ID)
+		if err != nil {
+			return nil, err
+		}
+		proofs[i].Comments = comments
+	}
+
+	return proofs, nil
+}
+
+func (s *MySQLDB) PutProofComment(ctx context.Context, comment *layerhub.ProofComment) error {
+	query := `INSERT INTO proof_comments (
+        id,
+        proof_id,
+        user_id,
+        customer_id,
+        body,
+        created_at
+    ) VALUES (?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
+        body=VALUES(body)
+    `
+
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		comment.ID,
+		comment.ProofID,
+		comment.UserID,
+		comment.CustomerID,
+		comment.Body,
+		comment.CreatedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MySQLDB) DeleteProof(ctx context.Context, id string) error {
+	tx, err := s.begin(ctx)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM proofs WHERE id = ?`, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM proof_comments WHERE proof_id = ?`, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MySQLDB) getProofComments(ctx context.Context, proofID string) ([]*layerhub.ProofComment, error) {
+	query := `SELECT * FROM proof_comments WHERE proof_id = ? ORDER BY created_at, id`
+	comments := []*layerhub.ProofComment{}
+
+	err := s.conn().SelectContext(ctx, &comments, query, proofID)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return comments, nil
+}
+
+func (s *MySQLDB) PutFolder(ctx context.Context, folder *layerhub.Folder) error {
+	query := `INSERT INTO folders (
+        id,
+        name,
+        parent_id,
+        company_id,
+        customer_id,
+        created_at,
+        updated_at
+    ) VALUES (?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE 
+        name=VALUES(name),
+        parent_id=VALUES(parent_id),
+        updated_at=VALUES(updated_at)
+    `
+
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		folder.ID,
+		folder.Name,
+		folder.ParentID,
+		folder.CompanyID,
+		folder.CustomerID,
+		folder.CreatedAt,
+		folder.UpdatedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MySQLDB) FindFolders(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Folder, error) {
+	query := `SELECT * FROM folders `
+	where, args := filterToQuery("folders", filter)
+	folders := []layerhub.Folder{}
+
+	err := s.conn().SelectContext(ctx, &folders, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return folders, nil
+}
+
+func (s *MySQLDB) CountFolders(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	query := `SELECT COUNT(*) AS count FROM folders `
+	where, args := filterToQuery("folders", filter)
+	count := []CountRow{}
+
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
+	if err != nil {
+		return 0, errors.E(errors.KindUnexpected, err)
+	}
+
+	return count[0].Count, nil
+}
+
+func (s *MySQLDB) DeleteFolder(ctx context.Context, id string) error {
+	query := `DELETE FROM folders WHERE id = ?`
+
+	_, err := s.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MySQLDB) PutTenantJob(ctx context.Context, job *layerhub.TenantJob) error {
+	query := `INSERT INTO tenant_jobs (
+        id,
+        kind,
+        status,
+        company_id,
+        customer_id,
+        total,
+        done,
+        error,
+        export_key,
+        created_at,
+        updated_at,
+        finished_at
+    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE
+        status=VALUES(status),
+        total=VALUES(total),
+        done=VALUES(done),
+        error=VALUES(error),
+        export_key=VALUES(export_key),
+        updated_at=VALUES(updated_at),
+        finished_at=VALUES(finished_at)
+    `
+
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		job.ID,
+		job.Kind,
+		job.Status,
+		job.CompanyID,
+		job.CustomerID,
+		job.Total,
+		job.Done,
+		job.Error,
+		job.ExportKey,
+		job.CreatedAt,
+		job.UpdatedAt,
+		job.FinishedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MySQLDB) ClaimTenantJob(ctx context.Context, job *layerhub.TenantJob, status layerhub.TenantJobStatus, updatedAt time.Time) (bool, error) {
+	query := `UPDATE tenant_jobs SET status = ?, updated_at = ? WHERE id = ? AND status = ? AND updated_at = ?`
+
+	res, err := s.conn().ExecContext(ctx, query, job.Status, job.UpdatedAt, job.ID, status, updatedAt)
+	if err != nil {
+		return false, errors.E(errors.KindUnexpected, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.E(errors.KindUnexpected, err)
+	}
+
+	return n == 1, nil
+}
+
+func (s *MySQLDB) FindTenantJobs(ctx context.Context, filter *layerhub.Filter) ([]layerhub.TenantJob, error) {
+	query := `SELECT * FROM tenant_jobs `
+	where, args := filterToQuery("tenant_jobs", filter)
+	jobs := []layerhub.TenantJob{}
+
+	err := s.conn().SelectContext(ctx, &jobs, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return jobs, nil
+}
+
 func (s *MySQLDB) deleteTemplateTags(ctx context.Context, ext ExtContext, templateID string) error {
 	delQuery := `DELETE FROM template_tags WHERE template_id = ?`
 	_, err := ext.ExecContext(ctx, delQuery, templateID)
@@ -1182,13 +2018,13 @@ func (s *MySQLDB) putTemplateColors(ctx context.Context, ext ExtContext, templat
 func (s *MySQLDB) getTemplateMetadata(ctx context.Context, templateID string) (layerhub.Metadata, error) {
 	var metadata layerhub.Metadata
 	query := `SELECT * FROM template_metadata WHERE id = ?`
-	err := s.db.GetContext(ctx, &metadata, query, templateID)
+	err := s.conn().GetContext(ctx, &metadata, query, templateID)
 	return metadata, err
 }
 
 func (s *MySQLDB) getTemplateTags(ctx context.Context, templateID string) ([]string, error) {
 	query := `SELECT * FROM template_tags WHERE template_id = ? ORDER BY position`
-	rows, err := s.db.QueryxContext(ctx, query, templateID)
+	rows, err := s.conn().QueryxContext(ctx, query, templateID)
 	if err != nil {
 		return nil, err
 	}
@@ -1213,7 +2049,7 @@ func (s *MySQLDB) getTemplateTags(ctx context.Context, templateID string) ([]str
 
 func (s *MySQLDB) getTemplateColors(ctx context.Context, templateID string) ([]string, error) {
 	query := `SELECT * FROM template_colors WHERE template_id = ? ORDER BY position`
-	rows, err := s.db.QueryxContext(ctx, query, templateID)
+	rows, err := s.conn().QueryxContext(ctx, query, templateID)
 	if err != nil {
 		return nil, err
 	}
@@ -1240,7 +2076,7 @@ func (s *MySQLDB) getSubscriptionPlanBillings(ctx context.Context, planID string
 	query := `SELECT * FROM subscription_plan_billings WHERE subscription_plan_id = ?`
 	billings := []*layerhub.Billing{}
 
-	err := s.db.SelectContext(ctx, &billings, query, planID)
+	err := s.conn().SelectContext(ctx, &billings, query, planID)
 	if err != nil {
 		return nil, err
 	}
@@ -1289,6 +2125,11 @@ func filterToQuery(table string, filter *layerhub.Filter) (string, []any) {
 	args := []any{}
 	conds := []string{}
 
+	// Rows in the trash are only listed when the filter asks for them
+	if filter == nil && hasTrash(table) {
+		filter = &layerhub.Filter{}
+	}
+
 	if filter != nil {
 		if filter.Email != "" {
 			conds = append(conds, fmt.Sprintf("%s.email = ?", table))
@@ -1307,7 +2148,7 @@ func filterToQuery(table string, filter *layerhub.Filter) (string, []any) {
 			args = append(args, filter.ShortID)
 		}
 		if filter.RegularOrShortID != "" {
-			conds = append(conds, fmt.Sprintf("%s.id = ? OR %s.short_id = ?", table, table))
+			conds = append(conds, fmt.Sprintf("(%s.id = ? OR %s.short_id = ?)", table, table))
 			args = append(args, filter.RegularOrShortID, filter.RegularOrShortID)
 		}
 		if filter.UserID != "" {
@@ -1334,10 +2175,62 @@ func filterToQuery(table string, filter *layerhub.Filter) (string, []any) {
 			conds = append(conds, fmt.Sprintf("%s.used_in_template = ?", table))
 			args = append(args, *filter.UsedInTemplate)
 		}
+		if filter.DesignID != "" {
+			conds = append(conds, fmt.Sprintf("%s.design_id = ?", table))
+			args = append(args, filter.DesignID)
+		}
+		if filter.MockupTemplateID != "" {
+			conds = append(conds, fmt.Sprintf("%s.mockup_template_id = ?", table))
+			args = append(args, filter.MockupTemplateID)
+		}
+		if filter.ProjectID != "" {
+			conds = append(conds, fmt.Sprintf("%s.project_id = ?", table))
+			args = append(args, filter.ProjectID)
+		}
+		if filter.Status != "" {
+			conds = append(conds, fmt.Sprintf("%s.status = ?", table))
+			args = append(args, filter.Status)
+		}
 		if filter.ApiToken != "" {
 			conds = append(conds, fmt.Sprintf("%s.api_token = ?", table))
 			args = append(args, filter.ApiToken)
 		}
+		if filter.FolderID != "" {
+			folderID := filter.FolderID
+			if folderID == layerhub.RootFolder {
+				folderID = ""
+			}
+			conds = append(conds, fmt.Sprintf("%s.%s = ?", table, folderColumn(table)))
+			args = append(args, folderID)
+		}
+		if filter.Published != nil {
+			conds = append(conds, fmt.Sprintf("%s.published = ?", table))
+			args = append(args, *filter.Published)
+		}
+		if filter.Tag != "" {
+			conds = append(conds, fmt.Sprintf("EXISTS (SELECT 1 FROM template_tags WHERE template_tags.template_id = %s.id AND template_tags.tag = ?)", table))
+			args = append(args, filter.Tag)
+		}
+		if filter.Color != "" {
+			conds = append(conds, fmt.Sprintf("EXISTS (SELECT 1 FROM template_colors WHERE template_colors.template_id = %s.id AND template_colors.color = ?)", table))
+			args = append(args, filter.Color)
+		}
+		if filter.Orientation != "" {
+			conds = append(conds, fmt.Sprintf("EXISTS (SELECT 1 FROM template_metadata WHERE template_metadata.id = %s.id AND template_metadata.orientation = ?)", table))
+			args = append(args, filter.Orientation)
+		}
+		if filter.License != "" {
+			conds = append(conds, fmt.Sprintf("EXISTS (SELECT 1 FROM template_metadata WHERE template_metadata.id = %s.id AND template_metadata.license = ?)", table))
+			args = append(args, filter.License)
+		}
+		if filter.FrameWidth != 0 && filter.FrameHeight != 0 {
+			conds = append(conds, fmt.Sprintf("EXISTS (SELECT 1 FROM frames WHERE frames.id = %s.id AND ABS(frames.width - ?) < ? AND ABS(frames.height - ?) < ? AND (frames.unit = ? OR ? = ''))", table))
+			args = append(args, filter.FrameWidth, layerhub.FrameSizeTolerance, filter.FrameHeight, layerhub.FrameSizeTolerance, filter.FrameUnit, filter.FrameUnit)
+		}
+		if filter.PublicOrCompanyID != "" {
+			conds = append(conds, fmt.Sprintf("(%s.public = TRUE OR %s.company_id = ?)", table, table))
+			args = append(args, filter.PublicOrCompanyID)
+		}
 		if filter.EnabledFonts != nil && *filter.EnabledFonts == false {
 			conds = append(conds, "enabled_fonts.id IS NULL")
 		}
@@ -1354,15 +2247,87 @@ func filterToQuery(table string, filter *layerhub.Filter) (string, []any) {
 			args = append(args, filter.OptionalCompanyID)
 		}
 
+		if !filter.CreatedAfter.IsZero() {
+			conds = append(conds, fmt.Sprintf("%s.created_at >= ?", table))
+			args = append(args, filter.CreatedAfter)
+		}
+		if !filter.CreatedBefore.IsZero() {
+			conds = append(conds, fmt.Sprintf("%s.created_at <= ?", table))
+			args = append(args, filter.CreatedBefore)
+		}
+		if !filter.UpdatedAfter.IsZero() {
+			conds = append(conds, fmt.Sprintf("%s.updated_at >= ?", table))
+			args = append(args, filter.UpdatedAfter)
+		}
+		if !filter.UpdatedBefore.IsZero() {
+			conds = append(conds, fmt.Sprintf("%s.updated_at <= ?", table))
+			args = append(args, filter.UpdatedBefore)
+		}
+		if len(filter.IDs) != 0 {
+			conds = append(conds, fmt.Sprintf("%s.id IN (?%s)", table, strings.Repeat(", ?", len(filter.IDs)-1)))
+			for _, id := range filter.IDs {
+				args = append(args, id)
+			}
+		}
+		if len(filter.CustomerIDs) != 0 {
+			conds = append(conds, fmt.Sprintf("%s.customer_id IN (?%s)", table, strings.Repeat(", ?", len(filter.CustomerIDs)-1)))
+			for _, id := range filter.CustomerIDs {
+				args = append(args, id)
+			}
+		}
+		if filter.NamePrefix != "" {
+			conds = append(conds, fmt.Sprintf("%s.name LIKE ?", table))
+			args = append(args, escapeLike(filter.NamePrefix)+"%")
+		}
+		if hasTrash(table) {
+			if filter.Trashed || !filter.TrashedBefore.IsZero() {
+				conds = append(conds, fmt.Sprintf("%s.deleted_at IS NOT NULL", table))
+			} else if !filter.WithTrashed {
+				conds = append(conds, fmt.Sprintf("%s.deleted_at IS NULL", table))
+			}
+			if !filter.TrashedBefore.IsZero() {
+				conds = append(conds, fmt.Sprintf("%s.deleted_at <= ?", table))
+				args = append(args, filter.TrashedBefore)
+			}
+		}
+
+		sortBy := sortColumn(filter.SortBy)
+		if filter.After != nil {
+			op := ">"
+			if filter.SortDesc {
+				op = "<"
+			}
+			if sortBy == "id" {
+				conds = append(conds, fmt.Sprintf("%s.id %s ?", table, op))
+				args = append(args, filter.After.ID)
+			} else {
+				conds = append(conds, fmt.Sprintf("(%s.%s %s ? OR (%s.%s = ? AND %s.id %s ?))", table, sortBy, op, table, sortBy, table, op))
+				args = append(args, filter.After.Value, filter.After.Value, filter.After.ID)
+			}
+		}
+
 		if len(conds) != 0 {
 			query += "WHERE " + strings.Join(conds, " AND ") + " "
 		}
 
+		// Pages are always sorted, the id breaks ties so the order is stable
+		if filter.SortBy != "" || filter.Limit != 0 || filter.After != nil {
+			dir := "ASC"
+			if filter.SortDesc {
+				dir = "DESC"
+			}
+			if sortBy == "id" {
+				query += fmt.Sprintf("ORDER BY %s.id %s ", table, dir)
+			} else {
+				query += fmt.Sprintf("ORDER BY %s.%s %s, %s.id %s ", table, sortBy, dir, table, dir)
+			}
+		}
+
 		if filter.Limit != 0 {
 			query += "LIMIT ? "
 			args = append(args, filter.Limit)
 		}
-		if filter.Offset != 0 {
+		if filter.Offset != 0 && filter.After == nil {
 			query += "OFFSET ? "
 			args = append(args, filter.Offset)
 		}
@@ -1371,6 +2336,43 @@ func filterToQuery(table string, filter *layerhub.Filter) (string, []any) {
 	return query, args
 }
 
+// hasTrash returns true if the rows of the table are moved to the trash
+// before they're deleted
+func hasTrash(table string) bool {
+	switch table {
+	case "templates", "projects", "components", "uploads":
+		return true
+	}
+	return false
+}
+
+// folderColumn returns the column of the folder of the table items, folders
+// are nested through their parent
+func folderColumn(table string) string {
+	switch table {
+	case "folders":
+		return "parent_id"
+	case "uploads":
+		return "folder"
+	default:
+		return "folder_id"
+	}
+}
+
+// sortColumn returns the column of a sort field, unknown fields sort by id
+func sortColumn(field layerhub.SortField) string {
+	switch field {
+	case layerhub.SortName, layerhub.SortCreatedAt, layerhub.SortUpdatedAt:
+		return string(field)
+	default:
+		return "id"
+	}
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 type CountRow struct {
 	Count int `db:"count"`
 }
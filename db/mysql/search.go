@@ -0,0 +1,249 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+	"github.com/jmoiron/sqlx"
+)
+
+const facetSize = 20
+
+// MySQLSearchIndex is a search index backed by a FULLTEXT index, facets are
+// counted from the search_facets table
+type MySQLSearchIndex struct {
+	db *sqlx.DB
+}
+
+var _ layerhub.SearchIndex = (*MySQLSearchIndex)(nil)
+
+func NewSearchIndex(conf *Config) (layerhub.SearchIndex, error) {
+	db, err := open(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MySQLSearchIndex{db}, nil
+}
+
+func (s *MySQLSearchIndex) IndexDocument(ctx context.Context, doc *layerhub.SearchDocument) error {
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO search_documents (
+        id,
+        kind,
+        company_id,
+        customer_id,
+        public,
+        name,
+        description,
+        tags,
+        colors,
+        content,
+        width,
+        height,
+        unit
+    ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE
+        company_id=VALUES(company_id),
+        customer_id=VALUES(customer_id),
+        public=VALUES(public),
+        name=VALUES(name),
+        description=VALUES(description),
+        tags=VALUES(tags),
+        colors=VALUES(colors),
+        content=VALUES(content),
+        width=VALUES(width),
+        height=VALUES(height),
+        unit=VALUES(unit)
+    `
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		doc.ID,
+		doc.Kind,
+		doc.CompanyID,
+		doc.CustomerID,
+		doc.Public,
+		doc.Name,
+		doc.Description,
+		strings.Join(doc.Tags, " "),
+		strings.Join(doc.Colors, " "),
+		strings.Join(doc.Text, "\n"),
+		doc.Width,
+		doc.Height,
+		doc.Unit,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM search_facets WHERE kind = ? AND document_id = ?`, doc.Kind, doc.ID)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	facets := map[string][]string{
+		layerhub.FacetTags:   doc.Tags,
+		layerhub.FacetColors: doc.Colors,
+	}
+	if size := doc.Size(); size != "" {
+		facets[layerhub.FacetSize] = []string{size}
+	}
+
+	for facet, values := range facets {
+		for _, value := range values {
+			_, err = tx.ExecContext(
+				ctx,
+				`INSERT IGNORE INTO search_facets (kind, document_id, facet, value) VALUES (?, ?, ?, ?)`,
+				doc.Kind,
+				doc.ID,
+				facet,
+				value,
+			)
+			if err != nil {
+				return errors.E(errors.KindUnexpected, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MySQLSearchIndex) DeleteDocument(ctx context.Context, kind layerhub.SearchKind, id string) error {
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM search_documents WHERE kind = ? AND id = ?`, kind, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM search_facets WHERE kind = ? AND document_id = ?`, kind, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *MySQLSearchIndex) Search(ctx context.Context, q *layerhub.SearchQuery) (*layerhub.SearchResult, error) {
+	where, args := searchToQuery(q)
+
+	order := ` ORDER BY search_documents.name, search_documents.id`
+	orderArgs := []any{}
+	if q.Text != "" {
+		order = ` ORDER BY MATCH(search_documents.name, search_documents.description, search_documents.tags, search_documents.colors, search_documents.content) AGAINST (? IN NATURAL LANGUAGE MODE) DESC, search_documents.id`
+		orderArgs = append(orderArgs, q.Text)
+	}
+
+	ids := []string{}
+	query := `SELECT search_documents.id FROM search_documents ` + where + order + ` LIMIT ? OFFSET ?`
+	err := s.db.SelectContext(ctx, &ids, query, append(append(args, orderArgs...), q.Limit, q.Offset)...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	count := []CountRow{}
+	query = `SELECT COUNT(*) AS count FROM search_documents ` + where
+	err = s.db.SelectContext(ctx, &count, query, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	rows := []struct {
+		Facet string `db:"facet"`
+		Value string `db:"value"`
+		Count int    `db:"count"`
+	}{}
+	query = `SELECT search_facets.facet, search_facets.value, COUNT(*) AS count FROM search_facets
+        JOIN search_documents ON search_documents.kind = search_facets.kind AND search_documents.id = search_facets.document_id ` +
+		where + ` GROUP BY search_facets.facet, search_facets.value`
+	err = s.db.SelectContext(ctx, &rows, query, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	facets := map[string][]layerhub.SearchFacet{}
+	for _, row := range rows {
+		facets[row.Facet] = append(facets[row.Facet], layerhub.SearchFacet{Value: row.Value, Count: row.Count})
+	}
+	for name, terms := range facets {
+		sort.Slice(terms, func(i, j int) bool {
+			if terms[i].Count != terms[j].Count {
+				return terms[i].Count > terms[j].Count
+			}
+			return terms[i].Value < terms[j].Value
+		})
+		if len(terms) > facetSize {
+			facets[name] = terms[:facetSize]
+		}
+	}
+
+	return &layerhub.SearchResult{
+		IDs:    ids,
+		Total:  count[0].Count,
+		Facets: facets,
+	}, nil
+}
+
+func searchToQuery(q *layerhub.SearchQuery) (string, []any) {
+	conds := []string{"search_documents.kind = ?"}
+	args := []any{q.Kind}
+
+	if q.CustomerID != "" {
+		conds = append(conds, "((search_documents.company_id = ? AND (search_documents.customer_id = ? OR search_documents.customer_id = '')) OR search_documents.public = TRUE)")
+		args = append(args, q.CompanyID, q.CustomerID)
+	} else {
+		conds = append(conds, "(search_documents.company_id = ? OR search_documents.public = TRUE)")
+		args = append(args, q.CompanyID)
+	}
+
+	if q.Text != "" {
+		conds = append(conds, "MATCH(search_documents.name, search_documents.description, search_documents.tags, search_documents.colors, search_documents.content) AGAINST (? IN NATURAL LANGUAGE MODE)")
+		args = append(args, q.Text)
+	}
+
+	for _, tag := range q.Tags {
+		conds = append(conds, "EXISTS (SELECT 1 FROM search_facets f WHERE f.kind = search_documents.kind AND f.document_id = search_documents.id AND f.facet = 'tags' AND f.value = ?)")
+		args = append(args, tag)
+	}
+
+	for _, color := range q.Colors {
+		conds = append(conds, "EXISTS (SELECT 1 FROM search_facets f WHERE f.kind = search_documents.kind AND f.document_id = search_documents.id AND f.facet = 'colors' AND f.value = ?)")
+		args = append(args, color)
+	}
+
+	// The sizes are single precision floats, they're matched within a
+	// tolerance
+	if q.Width != 0 {
+		conds = append(conds, "ABS(search_documents.width - ?) < ?")
+		args = append(args, q.Width, layerhub.FrameSizeTolerance)
+	}
+
+	if q.Height != 0 {
+		conds = append(conds, "ABS(search_documents.height - ?) < ?")
+		args = append(args, q.Height, layerhub.FrameSizeTolerance)
+	}
+
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
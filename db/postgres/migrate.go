@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"database/sql"
+	"embed"
+
+	"github.com/echovl/orderflo-dev/db/schema"
+	migratepostgres "github.com/golang-migrate/migrate/v4/database/postgres"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrate applies the embedded migrations and returns the schema version
+func Migrate(conf *Config) (uint, error) {
+	dsn, err := sessionDSN(conf.DSN)
+	if err != nil {
+		return 0, err
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return 0, err
+	}
+
+	driver, err := migratepostgres.WithInstance(db, &migratepostgres.Config{})
+	if err != nil {
+		db.Close()
+		return 0, err
+	}
+
+	return schema.Migrate(migrations, "migrations", "postgres", driver)
+}
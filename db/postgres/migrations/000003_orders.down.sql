@@ -0,0 +1,7 @@
+DROP TABLE
+  IF EXISTS order_items;
+
+DROP TABLE
+  IF EXISTS orders;
+
+ALTER TABLE frames DROP COLUMN bleed;
@@ -0,0 +1 @@
+ALTER TABLE frames DROP COLUMN safe_margin;
@@ -0,0 +1 @@
+ALTER TABLE frames ADD COLUMN safe_margin FLOAT NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+DROP TABLE
+  IF EXISTS proofs;
+
+DROP TABLE
+  IF EXISTS proof_comments;
+
+ALTER TABLE order_items DROP COLUMN proof_id;
@@ -0,0 +1,2 @@
+ALTER TABLE projects DROP COLUMN template_id;
+ALTER TABLE projects DROP COLUMN template_updated_at;
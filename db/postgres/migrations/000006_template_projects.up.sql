@@ -0,0 +1,2 @@
+ALTER TABLE projects ADD COLUMN template_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE projects ADD COLUMN template_updated_at TIMESTAMPTZ;
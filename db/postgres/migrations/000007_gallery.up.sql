@@ -0,0 +1 @@
+ALTER TABLE templates ADD COLUMN public BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,5 @@
+DROP TABLE
+  IF EXISTS search_documents;
+
+DROP TABLE
+  IF EXISTS search_facets;
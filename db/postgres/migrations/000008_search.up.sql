@@ -0,0 +1,28 @@
+-- search_documents is kept for parity with the other backends, deployments on
+-- PostgreSQL use the embedded search index
+CREATE TABLE
+  IF NOT EXISTS search_documents (
+    id VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    company_id VARCHAR(255) NOT NULL,
+    customer_id VARCHAR(255) NOT NULL,
+    public BOOLEAN NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    tags TEXT NOT NULL,
+    colors TEXT NOT NULL,
+    content TEXT NOT NULL,
+    width FLOAT NOT NULL,
+    height FLOAT NOT NULL,
+    unit VARCHAR(10) NOT NULL,
+    PRIMARY KEY (kind, id)
+  );
+
+CREATE TABLE
+  IF NOT EXISTS search_facets (
+    kind VARCHAR(20) NOT NULL,
+    document_id VARCHAR(255) NOT NULL,
+    facet VARCHAR(20) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    PRIMARY KEY (kind, document_id, facet, value)
+  );
@@ -0,0 +1,6 @@
+ALTER TABLE components DROP COLUMN folder_id;
+ALTER TABLE projects DROP COLUMN folder_id;
+ALTER TABLE templates DROP COLUMN folder_id;
+
+DROP TABLE
+  IF EXISTS folders;
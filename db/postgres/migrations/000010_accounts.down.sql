@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN api_token;
+ALTER TABLE users DROP COLUMN plan_id;
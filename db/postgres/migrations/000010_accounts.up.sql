@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN plan_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN api_token VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE templates ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE projects ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE components ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE uploads ADD COLUMN deleted_at TIMESTAMPTZ;
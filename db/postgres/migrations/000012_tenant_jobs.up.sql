@@ -0,0 +1,19 @@
+CREATE TABLE
+  IF NOT EXISTS tenant_jobs (
+    id VARCHAR(50),
+    kind VARCHAR(50) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    company_id VARCHAR(255) NOT NULL,
+    customer_id VARCHAR(255) NOT NULL,
+    total INT NOT NULL,
+    done INT NOT NULL,
+    error TEXT NOT NULL,
+    export_key VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ,
+    PRIMARY KEY (id)
+  );
+
+CREATE INDEX IF NOT EXISTS tenant_jobs_company_id ON tenant_jobs (company_id);
+CREATE INDEX IF NOT EXISTS tenant_jobs_customer_id ON tenant_jobs (customer_id);
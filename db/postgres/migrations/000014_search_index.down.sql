@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS search_facets_facet_value;
+DROP INDEX IF EXISTS search_documents_company_id;
+DROP INDEX IF EXISTS search_documents_document;
+ALTER TABLE search_documents DROP COLUMN document;
@@ -0,0 +1,11 @@
+-- search_documents backs the search index shared by the instances, the text
+-- is matched against a generated tsvector
+ALTER TABLE search_documents ADD COLUMN document tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', name), 'A') ||
+    setweight(to_tsvector('simple', tags || ' ' || colors), 'B') ||
+    setweight(to_tsvector('simple', description || ' ' || content), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS search_documents_document ON search_documents USING GIN (document);
+CREATE INDEX IF NOT EXISTS search_documents_company_id ON search_documents (company_id);
+CREATE INDEX IF NOT EXISTS search_facets_facet_value ON search_facets (facet, value);
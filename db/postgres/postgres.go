@@ -0,0 +1,2232 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ExtContext interface {
+	sqlx.ExecerContext
+	sqlx.QueryerContext
+}
+
+// uniqueViolation is the SQLSTATE of a write that breaks a unique constraint
+const uniqueViolation = "23505"
+
+// conn runs the queries of the store, it's the database or the transaction of
+// a unit of work
+type conn interface {
+	ExtContext
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+}
+
+// txConn is a transaction of the statements written together by a method
+type txConn interface {
+	ExtContext
+	Commit() error
+	Rollback() error
+}
+
+// joinedTx is the transaction of a unit of work joined by a method, the unit
+// of work commits or rolls it back
+type joinedTx struct {
+	*sqlx.Tx
+}
+
+func (joinedTx) Commit() error   { return nil }
+func (joinedTx) Rollback() error { return nil }
+
+type Config struct {
+	DSN             string
+	ConnMaxIdleTime time.Duration
+	ConnMaxLifetime time.Duration
+	MaxIdleConns    int
+	MaxOpenConns    int
+}
+
+// PostgresDB is a layerhub.DB stored in PostgreSQL, the schema is created by
+// the migrations in db/postgres/migrations. Template tags and colors are
+// stored as arrays in the templates table
+type PostgresDB struct {
+	db *sqlx.DB
+
+	// tx is the transaction of a unit of work started by WithTx, the
+	// statements of the copy handed to it run on it
+	tx *sqlx.Tx
+}
+
+func New(conf *Config) (layerhub.DB, error) {
+	db, err := open(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostgresDB{db: db}, nil
+}
+
+func open(conf *Config) (*sqlx.DB, error) {
+	dsn, err := sessionDSN(conf.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sqlx.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.ConnMaxIdleTime != 0 {
+		db.SetConnMaxIdleTime(conf.ConnMaxIdleTime)
+	}
+	if conf.ConnMaxLifetime != 0 {
+		db.SetConnMaxLifetime(conf.ConnMaxLifetime)
+	}
+	if conf.MaxIdleConns != 0 {
+		db.SetMaxIdleConns(conf.MaxIdleConns)
+	}
+	if conf.MaxOpenConns != 0 {
+		db.SetMaxOpenConns(conf.MaxOpenConns)
+	}
+
+	err = db.Ping()
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// sessionDSN returns the DSN in key/value form with the session time zone set
+// to UTC, times are read back in UTC like they're written
+func sessionDSN(dsn string) (string, error) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		var err error
+		dsn, err = pq.ParseURL(dsn)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return strings.TrimSpace(dsn + " timezone=UTC"), nil
+}
+
+// WithTx runs fn in a transaction, it's committed when fn succeeds and rolled
+// back otherwise. The DB given to fn must be used for every statement of the
+// unit of work, calls to WithTx on it join the same transaction
+func (s *PostgresDB) WithTx(ctx context.Context, fn func(tx layerhub.DB) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(&PostgresDB{db: s.db, tx: tx}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) conn() conn {
+	if s.tx != nil {
+		return s.tx
+	}
+	return s.db
+}
+
+// begin starts the transaction of a method, inside a unit of work the method
+// joins its transaction instead
+func (s *PostgresDB) begin(ctx context.Context) (txConn, error) {
+	if s.tx != nil {
+		return joinedTx{s.tx}, nil
+	}
+	return s.db.BeginTxx(ctx, &sql.TxOptions{})
+}
+
+func (s *PostgresDB) PutUser(ctx context.Context, user *layerhub.User) error {
+	query := `INSERT INTO users (
+        id,
+        first_name,
+        last_name,
+        email,
+        phone,
+        avatar,
+        email_verified,
+        phone_verified,
+        role,
+        password_hash,
+        plan_id,
+        api_token,
+        source,
+        company_id,
+        created_at,
+        updated_at
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16) ON CONFLICT (id) DO UPDATE SET
+        first_name=excluded.first_name,
+        last_name=excluded.last_name,
+        email=excluded.email,
+        phone=excluded.phone,
+        avatar=excluded.avatar,
+        email_verified=excluded.email_verified,
+        phone_verified=excluded.phone_verified,
+        role=excluded.role,
+        password_hash=excluded.password_hash,
+        plan_id=excluded.plan_id,
+        api_token=excluded.api_token,
+        updated_at=excluded.updated_at
+    `
+
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		user.ID,
+		user.FirstName,
+		user.LastName,
+		user.Email,
+		user.Phone,
+		user.Avatar,
+		user.EmailVerified,
+		user.PhoneVerified,
+		user.Role,
+		user.PasswordHash,
+		user.PlanID,
+		user.ApiToken,
+		user.Source,
+		user.CompanyID,
+		user.CreatedAt,
+		user.UpdatedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) FindUsers(ctx context.Context, filter *layerhub.Filter) ([]layerhub.User, error) {
+	query := `SELECT * FROM users `
+
+	where, args := filterToQuery("users", filter)
+	users := []layerhub.User{}
+
+	err := s.conn().SelectContext(ctx, &users, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return users, nil
+}
+
+func (s *PostgresDB) DeleteUser(ctx context.Context, id string) error {
+	query := `DELETE FROM users WHERE id = $1`
+
+	_, err := s.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) BatchCreateFonts(ctx context.Context, fonts []layerhub.Font) error {
+	tx, err := s.begin(ctx)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	batchSize := 500
+
+	for batchStart := 0; batchStart < len(fonts); batchStart += batchSize {
+		batchEnd := batchStart + batchSize
+		if batchEnd >= len(fonts) {
+			batchEnd = len(fonts)
+		}
+
+		query := `INSERT INTO fonts (
+            id,
+            family,
+            full_name,
+            postscript_name,
+            preview,
+            style,
+            url,
+            customer_id,
+            company_id,
+            category,
+            public,
+            format,
+            weight,
+            width,
+            italic,
+            axes,
+            num_glyphs,
+            subsets,
+            embedding,
+            no_subsetting
+        ) VALUES `
+
+		args := []any{}
+		values := []string{}
+		for _, pf := range fonts[batchStart:batchEnd] {
+			values = append(values, placeholders(len(args), 20))
+			args = append(
+				args,
+				pf.ID,
+				pf.Family,
+				pf.FullName,
+				pf.PostscriptName,
+				pf.Preview,
+				pf.Style,
+				pf.URL,
+				pf.CustomerID,
+				pf.CompanyID,
+				pf.Category,
+				pf.Public,
+				pf.Format,
+				pf.Weight,
+				pf.Width,
+				pf.Italic,
+				pf.Axes,
+				pf.NumGlyphs,
+				pf.Subsets,
+				pf.Embedding,
+				pf.NoSubsetting,
+			)
+		}
+		query += strings.Join(values, ",")
+
+		_, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return errors.E(errors.KindUnexpected, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) PutCustomer(ctx context.Context, customer *layerhub.Customer) error {
+	query := `INSERT INTO customers (
+        id,
+        first_name,
+        last_name,
+        email,
+        password_hash,
+        company_id,
+        source,
+        created_at,
+        updated_at
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT (id) DO UPDATE SET
+        first_name=excluded.first_name,
+        last_name=excluded.last_name,
+        email=excluded.email,
+        updated_at=excluded.updated_at
+    `
+
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		customer.ID,
+		customer.FirstName,
+		customer.LastName,
+		customer.Email,
+		customer.PasswordHash,
+		customer.CompanyID,
+		customer.Source,
+		customer.CreatedAt,
+		customer.UpdatedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) FindCustomers(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Customer, error) {
+	query := `SELECT * FROM customers `
+	where, args := filterToQuery("customers", filter)
+	customers := []layerhub.Customer{}
+
+	err := s.conn().SelectContext(ctx, &customers, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return customers, nil
+}
+
+func (s *PostgresDB) CountCustomers(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	query := `SELECT COUNT(*) AS count FROM customers `
+	where, args := filterToQuery("customers", filter)
+	count := []CountRow{}
+
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
+	if err != nil {
+		return 0, errors.E(errors.KindUnexpected, err)
+	}
+
+	return count[0].Count, nil
+}
+
+func (s *PostgresDB) DeleteCustomer(ctx context.Context, id string) error {
+	query := `DELETE FROM customers WHERE id = $1`
+
+	_, err := s.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) PutCompany(ctx context.Context, company *layerhub.Company) error {
+	query := `INSERT INTO companies (
+        id,
+        name,
+        created_at,
+        updated_at
+    ) VALUES ($1, $2, $3, $4) ON CONFLICT (id) DO UPDATE SET
+        name=excluded.name,
+        updated_at=excluded.updated_at
+    `
+
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		company.ID,
+		company.Name,
+		company.CreatedAt,
+		company.UpdatedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) FindCompanies(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Company, error) {
+	query := `SELECT * FROM companies `
+	where, args := filterToQuery("companies", filter)
+	companies := []layerhub.Company{}
+
+	err := s.conn().SelectContext(ctx, &companies, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return companies, nil
+}
+
+func (s *PostgresDB) CountCompanies(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	query := `SELECT COUNT(*) AS count FROM companies `
+	where, args := filterToQuery("companies", filter)
+	count := []CountRow{}
+
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
+	if err != nil {
+		return 0, errors.E(errors.KindUnexpected, err)
+	}
+
+	return count[0].Count, nil
+}
+
+func (s *PostgresDB) DeleteCompany(ctx context.Context, id string) error {
+	query := `DELETE FROM companies WHERE id = $1`
+
+	_, err := s.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) FindFonts(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Font, error) {
+	query := `SELECT fonts.* FROM fonts `
+	args := []any{}
+	fonts := []layerhub.Font{}
+
+	if filter != nil && filter.EnabledFonts != nil {
+		if *filter.EnabledFonts {
+			query += "INNER JOIN enabled_fonts ON fonts.id = enabled_fonts.font_id AND enabled_fonts.customer_id = $1 "
+		} else {
+			query += "LEFT JOIN enabled_fonts ON fonts.id = enabled_fonts.font_id AND enabled_fonts.customer_id = $1 "
+		}
+		args = append(args, filter.OptionalCustomerID)
+	}
+
+	where, args := filterToQuery("fonts", filter, args...)
+
+	err := s.conn().SelectContext(ctx, &fonts, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return fonts, nil
+}
+
+func (s *PostgresDB) CountFonts(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	query := `SELECT COUNT(*) AS count FROM fonts `
+	args := []any{}
+	count := []CountRow{}
+
+	if filter != nil && filter.EnabledFonts != nil {
+		if *filter.EnabledFonts {
+			query += "INNER JOIN enabled_fonts ON fonts.id = enabled_fonts.font_id AND enabled_fonts.customer_id = $1 "
+		} else {
+			query += "LEFT JOIN enabled_fonts ON fonts.id = enabled_fonts.font_id AND enabled_fonts.customer_id = $1 "
+		}
+		args = append(args, filter.OptionalCustomerID)
+	}
+
+	where, args := filterToQuery("fonts", filter, args...)
+
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
+	if err != nil {
+		return 0, errors.E(errors.KindUnexpected, err)
+	}
+
+	return count[0].Count, nil
+}
+
+func (s PostgresDB) PutFont(ctx context.Context, font *layerhub.Font) error {
+	query := `INSERT INTO fonts (
+        id,
+        full_name,
+        family,
+        postscript_name,
+        preview,
+        style,
+        url,
+        category,
+        customer_id,
+        company_id,
+        public,
+        format,
+        weight,
+        width,
+        italic,
+        axes,
+        num_glyphs,
+        subsets,
+        embedding,
+        no_subsetting
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20) ON CONFLICT (id) DO UPDATE SET
+        full_name=excluded.full_name,
+        family=excluded.family,
+        postscript_name=excluded.postscript_name,
+        preview=excluded.preview,
+        style=excluded.style,
+        url=excluded.url,
+        category=excluded.category,
+        format=excluded.format,
+        weight=excluded.weight,
+        width=excluded.width,
+        italic=excluded.italic,
+        axes=excluded.axes,
+        num_glyphs=excluded.num_glyphs,
+        subsets=excluded.subsets,
+        embedding=excluded.embedding,
+        no_subsetting=excluded.no_subsetting
+    `
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		font.ID,
+		font.FullName,
+		font.Family,
+		font.PostscriptName,
+		font.Preview,
+		font.Style,
+		font.URL,
+		font.Category,
+		font.CustomerID,
+		font.CompanyID,
+		font.Public,
+		font.Format,
+		font.Weight,
+		font.Width,
+		font.Italic,
+		font.Axes,
+		font.NumGlyphs,
+		font.Subsets,
+		font.Embedding,
+		font.NoSubsetting,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s PostgresDB) DeleteFont(ctx context.Context, id string) error {
+	query := `DELETE FROM fonts WHERE id = $1`
+
+	_, err := s.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) PutTemplate(ctx context.Context, template *layerhub.Template) error {
+	tx, err := s.begin(ctx)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO templates (
+        id,
+        short_id,
+        name,
+        type,
+        published,
+        public,
+        folder_id,
+        tags,
+        colors,
+        preview,
+        customer_id,
+        company_id,
+        created_at,
+        updated_at,
+        deleted_at
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) ON CONFLICT (id) DO UPDATE SET
+        short_id=excluded.short_id,
+        name=excluded.name,
+        type=excluded.type,
+        published=excluded.published,
+        public=excluded.public,
+        folder_id=excluded.folder_id,
+        tags=excluded.tags,
+        colors=excluded.colors,
+        preview=excluded.preview,
+        updated_at=excluded.updated_at,
+        deleted_at=excluded.deleted_at
+    `
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		template.ID,
+		template.ShortID,
+		template.Name,
+		template.Type,
+		template.Published,
+		template.Public,
+		template.FolderID,
+		stringArray(template.Tags),
+		stringArray(template.Colors),
+		template.Preview,
+		template.CustomerID,
+		template.CompanyID,
+		template.CreatedAt,
+		template.UpdatedAt,
+		template.DeletedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = s.putFrame(ctx, tx, &layerhub.Frame{
+		ID:             template.ID,
+		Name:           template.Frame.Name,
+		Width:          template.Frame.Width,
+		Height:         template.Frame.Height,
+		Unit:           template.Frame.Unit,
+		Bleed:          template.Frame.Bleed,
+		SafeMargin:     template.Frame.SafeMargin,
+		UsedInTemplate: true,
+	})
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = s.putTemplateMetadata(ctx, tx, template)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) FindTemplates(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Template, error) {
+	query := `SELECT * FROM templates `
+	where, args := filterToQuery("templates", filter)
+	rows := []templateRow{}
+
+	err := s.conn().SelectContext(ctx, &rows, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	templates := make([]layerhub.Template, len(rows))
+	for i, row := range rows {
+		frames, err := s.FindFrames(ctx, &layerhub.Filter{ID: row.ID})
+		if err != nil {
+			return nil, err
+		}
+		metadata, err := s.getTemplateMetadata(ctx, row.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		templates[i] = row.Template
+		if len(frames) > 0 {
+			templates[i].Frame = frames[0]
+		}
+		templates[i].Tags = append([]string{}, row.Tags...)
+		templates[i].Colors = append([]string{}, row.Colors...)
+		templates[i].Metadata = metadata
+	}
+
+	return templates, nil
+}
+
+func (s *PostgresDB) CountTemplates(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	query := `SELECT COUNT(*) AS count FROM templates `
+	where, args := filterToQuery("templates", filter)
+	count := []CountRow{}
+
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
+	if err != nil {
+		return 0, errors.E(errors.KindUnexpected, err)
+	}
+
+	return count[0].Count, nil
+}
+
+func (s *PostgresDB) DeleteTemplate(ctx context.Context, id string) error {
+	tx, err := s.begin(ctx)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	query := `DELETE FROM templates WHERE id = $1`
+
+	_, err = tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = s.deleteFrame(ctx, tx, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) PutFrame(ctx context.Context, frame *layerhub.Frame) error {
+	return s.putFrame(ctx, s.db, frame)
+}
+
+func (s *PostgresDB) putFrame(ctx context.Context, ext ExtContext, frame *layerhub.Frame) error {
+	query := `INSERT INTO frames (
+        id,
+        name,
+        public,
+        width,
+        height,
+        unit,
+        bleed,
+        safe_margin,
+        preview,
+        used_in_template,
+        customer_id,
+        company_id
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) ON CONFLICT (id) DO UPDATE SET
+        name=excluded.name,
+        public=excluded.public,
+        width=excluded.width,
+        height=excluded.height,
+        unit=excluded.unit,
+        bleed=excluded.bleed,
+        safe_margin=excluded.safe_margin,
+        preview=excluded.preview
+    `
+
+	_, err := ext.ExecContext(
+		ctx,
+		query,
+		frame.ID,
+		frame.Name,
+		frame.Public,
+		frame.Width,
+		frame.Height,
+		frame.Unit,
+		frame.Bleed,
+		frame.SafeMargin,
+		frame.Preview,
+		frame.UsedInTemplate,
+		frame.CustomerID,
+		frame.CompanyID,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) FindFrames(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Frame, error) {
+	query := `SELECT * FROM frames `
+	where, args := filterToQuery("frames", filter)
+	frames := []layerhub.Frame{}
+
+	err := s.conn().SelectContext(ctx, &frames, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return frames, nil
+}
+
+func (s *PostgresDB) CountFrames(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	query := `SELECT COUNT(*) AS count FROM frames `
+	where, args := filterToQuery("frames", filter)
+	count := []CountRow{}
+
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
+	if err != nil {
+		return 0, errors.E(errors.KindUnexpected, err)
+	}
+
+	return count[0].Count, nil
+}
+
+func (s *PostgresDB) DeleteFrame(ctx context.Context, id string) error {
+	return s.deleteFrame(ctx, s.db, id)
+}
+
+func (s *PostgresDB) deleteFrame(ctx context.Context, ext ExtContext, id string) error {
+	query := `DELETE FROM frames WHERE id = $1`
+
+	_, err := ext.ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) PutProject(ctx context.Context, project *layerhub.Project) error {
+	tx, err := s.begin(ctx)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO projects (
+        id,
+        short_id,
+        name,
+        type,
+        preview,
+        customer_id,
+        company_id,
+        template_id,
+        template_updated_at,
+        folder_id,
+        created_at,
+        updated_at,
+        deleted_at
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) ON CONFLICT (id) DO UPDATE SET
+        short_id=excluded.short_id,
+        name=excluded.name,
+        type=excluded.type,
+        preview=excluded.preview,
+        folder_id=excluded.folder_id,
+        updated_at=excluded.updated_at,
+        deleted_at=excluded.deleted_at
+    `
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		project.ID,
+		project.ShortID,
+		project.Name,
+		project.Type,
+		project.Preview,
+		project.CustomerID,
+		project.CompanyID,
+		project.TemplateID,
+		project.TemplateUpdatedAt,
+		project.FolderID,
+		project.CreatedAt,
+		project.UpdatedAt,
+		project.DeletedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = s.putFrame(ctx, tx, &layerhub.Frame{
+		ID:             project.ID,
+		Name:           project.Frame.Name,
+		Width:          project.Frame.Width,
+		Height:         project.Frame.Height,
+		Unit:           project.Frame.Unit,
+		Bleed:          project.Frame.Bleed,
+		SafeMargin:     project.Frame.SafeMargin,
+		UsedInTemplate: true,
+	})
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) FindProjects(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Project, error) {
+	query := `SELECT * FROM projects `
+	where, args := filterToQuery("projects", filter)
+	projects := []layerhub.Project{}
+
+	err := s.conn().SelectContext(ctx, &projects, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	for i, p := range projects {
+		frames, err := s.FindFrames(ctx, &layerhub.Filter{ID: p.ID})
+		if err != nil {
+			return nil, err
+		}
+		if len(frames) == 0 {
+			continue
+		}
+
+		projects[i].Frame = frames[0]
+	}
+
+	return projects, nil
+}
+
+func (s *PostgresDB) CountProjects(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	query := `SELECT COUNT(*) AS count FROM projects `
+	where, args := filterToQuery("projects", filter)
+	count := []CountRow{}
+
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
+	if err != nil {
+		return 0, errors.E(errors.KindUnexpected, err)
+	}
+
+	return count[0].Count, nil
+}
+
+func (s *PostgresDB) DeleteProject(ctx context.Context, id string) error {
+	query := `DELETE FROM projects WHERE id = $1`
+
+	_, err := s.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) PutComponent(ctx context.Context, component *layerhub.Component) error {
+	query := `INSERT INTO components (
+        id,
+        name,
+        preview,
+        public,
+        customer_id,
+        company_id,
+        user_id,
+        folder_id,
+        created_at,
+        updated_at,
+        deleted_at
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) ON CONFLICT (id) DO UPDATE SET
+        name=excluded.name,
+        preview=excluded.preview,
+        folder_id=excluded.folder_id,
+        updated_at=excluded.updated_at,
+        deleted_at=excluded.deleted_at
+    `
+
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		component.ID,
+		component.Name,
+		component.Preview,
+		component.Public,
+		component.CustomerID,
+		component.CompanyID,
+		component.UserID,
+		component.FolderID,
+		component.CreatedAt,
+		component.UpdatedAt,
+		component.DeletedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) FindComponents(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Component, error) {
+	query := `SELECT * FROM components `
+	where, args := filterToQuery("components", filter)
+	components := []layerhub.Component{}
+
+	err := s.conn().SelectContext(ctx, &components, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return components, nil
+}
+
+func (s *PostgresDB) CountComponents(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	query := `SELECT COUNT(*) AS count FROM components `
+	where, args := filterToQuery("components", filter)
+	count := []CountRow{}
+
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
+	if err != nil {
+		return 0, errors.E(errors.KindUnexpected, err)
+	}
+
+	return count[0].Count, nil
+}
+
+func (s *PostgresDB) DeleteComponent(ctx context.Context, id string) error {
+	query := `DELETE FROM components WHERE id = $1`
+
+	_, err := s.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) PutUpload(ctx context.Context, upload *layerhub.Upload) error {
+	query := `INSERT INTO uploads (
+        id,
+        name,
+        content_type,
+        folder,
+        type,
+        url,
+        company_id,
+        customer_id,
+        created_at,
+        updated_at,
+        deleted_at
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) ON CONFLICT (id) DO UPDATE SET
+        name=excluded.name,
+        content_type=excluded.content_type,
+        folder=excluded.folder,
+        type=excluded.type,
+        url=excluded.url,
+        updated_at=excluded.updated_at,
+        deleted_at=excluded.deleted_at
+    `
+
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		upload.ID,
+		upload.Name,
+		upload.ContentType,
+		upload.Folder,
+		upload.Type,
+		upload.URL,
+		upload.CompanyID,
+		upload.CustomerID,
+		upload.CreatedAt,
+		upload.UpdatedAt,
+		upload.DeletedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) FindUploads(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Upload, error) {
+	query := `SELECT * FROM uploads `
+	where, args := filterToQuery("uploads", filter)
+	uploads := []layerhub.Upload{}
+
+	err := s.conn().SelectContext(ctx, &uploads, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return uploads, nil
+}
+
+func (s *PostgresDB) CountUploads(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	query := `SELECT COUNT(*) AS count FROM uploads `
+	where, args := filterToQuery("uploads", filter)
+	count := []CountRow{}
+
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
+	if err != nil {
+		return 0, errors.E(errors.KindUnexpected, err)
+	}
+
+	return count[0].Count, nil
+}
+
+func (s *PostgresDB) DeleteUpload(ctx context.Context, id string) error {
+	query := `DELETE FROM uploads WHERE id = $1`
+
+	_, err := s.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) FindEnabledFonts(ctx context.Context, customerID string) ([]layerhub.EnabledFont, error) {
+	query := `SELECT * FROM enabled_fonts `
+	where, args := filterToQuery("enabled_fonts", &layerhub.Filter{
+		CustomerID: customerID,
+	})
+	fonts := []layerhub.EnabledFont{}
+
+	err := s.conn().SelectContext(ctx, &fonts, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return fonts, nil
+}
+
+func (s *PostgresDB) BatchCreateEnabledFonts(ctx context.Context, fonts []*layerhub.EnabledFont) error {
+	tx, err := s.begin(ctx)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	batchSize := 500
+
+	for batchStart := 0; batchStart < len(fonts); batchStart += batchSize {
+		batchEnd := batchStart + batchSize
+		if batchEnd >= len(fonts) {
+			batchEnd = len(fonts)
+		}
+
+		query := `INSERT INTO enabled_fonts (
+            id,
+            customer_id,
+            font_id
+        ) VALUES `
+
+		args := []any{}
+		values := []string{}
+		for _, f := range fonts[batchStart:batchEnd] {
+			values = append(values, placeholders(len(args), 3))
+			args = append(
+				args,
+				f.ID,
+				f.CustomerID,
+				f.FontID,
+			)
+		}
+		query += strings.Join(values, ",")
+
+		_, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return errors.E(errors.KindUnexpected, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) BatchDeleteEnabledFonts(ctx context.Context, ids []string) error {
+	if len(ids) != 0 {
+		query := `DELETE FROM enabled_fonts WHERE id = ANY($1)`
+
+		_, err := s.conn().ExecContext(ctx, query, pq.Array(ids))
+		if err != nil {
+			return errors.E(errors.KindUnexpected, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) PutSubscriptionPlan(ctx context.Context, plan *layerhub.SubscriptionPlan) error {
+	tx, err := s.begin(ctx)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO subscription_plans (
+        id,
+        provider,
+        external_id,
+        name,
+        description,
+        external_product_id,
+        auto_bill_outstanding,
+        setup_fee,
+        max_templates
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT (id) DO UPDATE SET
+        name=excluded.name,
+        description=excluded.description,
+        external_product_id=excluded.external_product_id,
+        auto_bill_outstanding=excluded.auto_bill_outstanding,
+        setup_fee=excluded.setup_fee,
+        max_templates=excluded.max_templates
+    `
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		plan.ID,
+		plan.Provider,
+		plan.ExternalID,
+		plan.Name,
+		plan.Description,
+		plan.ExternalProductID,
+		plan.AutoBillOutstanding,
+		plan.SetupFee,
+		plan.MaxTemplates,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = s.putSubscriptionPlanBillings(ctx, tx, plan)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) FindSubscriptionPlans(ctx context.Context) ([]layerhub.SubscriptionPlan, error) {
+	query := `SELECT * FROM subscription_plans`
+	plans := []layerhub.SubscriptionPlan{}
+
+	err := s.conn().SelectContext(ctx, &plans, query)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	for i, plan := range plans {
+		billings, err := s.getSubscriptionPlanBillings(ctx, plan.ID)
+		if err != nil {
+			return nil, errors.E(errors.KindUnexpected, err)
+		}
+		plans[i].Billing = billings
+	}
+
+	return plans, nil
+}
+
+func (s *PostgresDB) PutMockupTemplate(ctx context.Context, mt *layerhub.MockupTemplate) error {
+	query := `INSERT INTO mockup_templates (
+        id,
+        name,
+        photo_url,
+        placement,
+        shading_url,
+        displacement_url,
+        displacement_strength,
+        public,
+        customer_id,
+        company_id,
+        created_at,
+        updated_at
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) ON CONFLICT (id) DO UPDATE SET
+        name=excluded.name,
+        photo_url=excluded.photo_url,
+        placement=excluded.placement,
+        shading_url=excluded.shading_url,
+        displacement_url=excluded.displacement_url,
+        displacement_strength=excluded.displacement_strength,
+        public=excluded.public,
+        updated_at=excluded.updated_at
+    `
+
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		mt.ID,
+		mt.Name,
+		mt.PhotoURL,
+		mt.Placement,
+		mt.ShadingURL,
+		mt.DisplacementURL,
+		mt.DisplacementStrength,
+		mt.Public,
+		mt.CustomerID,
+		mt.CompanyID,
+		mt.CreatedAt,
+		mt.UpdatedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) FindMockupTemplates(ctx context.Context, filter *layerhub.Filter) ([]layerhub.MockupTemplate, error) {
+	query := `SELECT * FROM mockup_templates `
+	where, args := filterToQuery("mockup_templates", filter)
+	mts := []layerhub.MockupTemplate{}
+
+	err := s.conn().SelectContext(ctx, &mts, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return mts, nil
+}
+
+func (s *PostgresDB) CountMockupTemplates(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	query := `SELECT COUNT(*) AS count FROM mockup_templates `
+	where, args := filterToQuery("mockup_templates", filter)
+	count := []CountRow{}
+
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
+	if err != nil {
+		return 0, errors.E(errors.KindUnexpected, err)
+	}
+
+	return count[0].Count, nil
+}
+
+func (s *PostgresDB) DeleteMockupTemplate(ctx context.Context, id string) error {
+	tx, err := s.begin(ctx)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM mockup_templates WHERE id = $1`, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM mockups WHERE mockup_template_id = $1`, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) PutMockup(ctx context.Context, mockup *layerhub.Mockup) error {
+	query := `INSERT INTO mockups (
+        id,
+        design_id,
+        mockup_template_id,
+        url,
+        design_updated_at,
+        created_at
+    ) VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (design_id, mockup_template_id) DO UPDATE SET
+        url=excluded.url,
+        design_updated_at=excluded.design_updated_at,
+        created_at=excluded.created_at
+    `
+
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		mockup.ID,
+		mockup.DesignID,
+		mockup.MockupTemplateID,
+		mockup.URL,
+		mockup.DesignUpdatedAt,
+		mockup.CreatedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) FindMockups(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Mockup, error) {
+	query := `SELECT * FROM mockups `
+	where, args := filterToQuery("mockups", filter)
+	mockups := []layerhub.Mockup{}
+
+	err := s.conn().SelectContext(ctx, &mockups, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return mockups, nil
+}
+
+func (s *PostgresDB) DeleteMockup(ctx context.Context, id string) error {
+	query := `DELETE FROM mockups WHERE id = $1`
+
+	_, err := s.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) PutOrder(ctx context.Context, order *layerhub.Order) error {
+	tx, err := s.begin(ctx)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO orders (
+        id,
+        number,
+        status,
+        customer_id,
+        company_id,
+        submitted_at,
+        created_at,
+        updated_at
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (id) DO UPDATE SET
+        status=excluded.status,
+        submitted_at=excluded.submitted_at,
+        updated_at=excluded.updated_at
+    `
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		order.ID,
+		order.Number,
+		order.Status,
+		order.CustomerID,
+		order.CompanyID,
+		order.SubmittedAt,
+		order.CreatedAt,
+		order.UpdatedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = s.putOrderItems(ctx, tx, order)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) ClaimOrder(ctx context.Context, order *layerhub.Order, status layerhub.OrderStatus, updatedAt time.Time) (bool, error) {
+	query := `UPDATE orders SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4 AND updated_at = $5`
+
+	res, err := s.conn().ExecContext(ctx, query, order.Status, order.UpdatedAt, order.ID, status, updatedAt)
+	if err != nil {
+		return false, errors.E(errors.KindUnexpected, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.E(errors.KindUnexpected, err)
+	}
+
+	return n == 1, nil
+}
+
+func (s *PostgresDB) FindOrders(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Order, error) {
+	query := `SELECT * FROM orders `
+	where, args := filterToQuery("orders", filter)
+	orders := []layerhub.Order{}
+
+	err := s.conn().SelectContext(ctx, &orders, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	for i, order := range orders {
+		items, err := s.getOrderItems(ctx, order.ID)
+		if err != nil {
+			return nil, err
+		}
+		orders[i].Items = items
+	}
+
+	return orders, nil
+}
+
+func (s *PostgresDB) CountOrders(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	query := `SELECT COUNT(*) AS count FROM orders `
+	where, args := filterToQuery("orders", filter)
+	count := []CountRow{}
+
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
+	if err != nil {
+		return 0, errors.E(errors.KindUnexpected, err)
+	}
+
+	return count[0].Count, nil
+}
+
+func (s *PostgresDB) DeleteOrder(ctx context.Context, id string) error {
+	tx, err := s.begin(ctx)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM orders WHERE id = $1`, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM order_items WHERE order_id = $1`, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) putOrderItems(ctx context.Context, ext ExtContext, order *layerhub.Order) error {
+	delQuery := `DELETE FROM order_items WHERE order_id = $1`
+	_, err := ext.ExecContext(ctx, delQuery, order.ID)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO order_items (
+        id,
+        order_id,
+        position,
+        project_id,
+        proof_id,
+        sku,
+        quantity,
+        format,
+        dpi,
+        print_file_url,
+        print_status,
+        print_error,
+        print_attempts
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+	for _, item := range order.Items {
+		_, err := ext.ExecContext(
+			ctx,
+			query,
+			item.ID,
+			order.ID,
+			item.Position,
+			item.ProjectID,
+			item.ProofID,
+			item.SKU,
+			item.Quantity,
+			item.Format,
+			item.DPI,
+			item.PrintFileURL,
+			item.PrintStatus,
+			item.PrintError,
+			item.PrintAttempts,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) getOrderItems(ctx context.Context, orderID string) ([]*layerhub.OrderItem, error) {
+	query := `SELECT * FROM order_items WHERE order_id = $1 ORDER BY position`
+	items := []*layerhub.OrderItem{}
+
+	err := s.conn().SelectContext(ctx, &items, query, orderID)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return items, nil
+}
+
+func (s *PostgresDB) PutProof(ctx context.Context, proof *layerhub.Proof) error {
+	query := `INSERT INTO proofs (
+        id,
+        project_id,
+        version,
+        status,
+        preview,
+        project_updated_at,
+        user_id,
+        customer_id,
+        company_id,
+        reviewed_at,
+        created_at,
+        updated_at
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) ON CONFLICT (id) DO UPDATE SET
+        status=excluded.status,
+        reviewed_at=excluded.reviewed_at,
+        updated_at=excluded.updated_at
+    `
+
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		proof.ID,
+		proof.ProjectID,
+		proof.Version,
+		proof.Status,
+		proof.Preview,
+		proof.ProjectUpdatedAt,
+		proof.UserID,
+		proof.CustomerID,
+		proof.CompanyID,
+		proof.ReviewedAt,
+		proof.CreatedAt,
+		proof.UpdatedAt,
+	)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolation {
+		return errors.Conflict(fmt.Sprintf("version %d of the proofs of project '%s' already exists", proof.Version, proof.ProjectID))
+	}
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) FindProofs(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Proof, error) {
+	query := `SELECT * FROM proofs `
+	where, args := filterToQuery("proofs", filter)
+	proofs := []layerhub.Proof{}
+
+	err := s.conn().SelectContext(ctx, &proofs, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	for i, proof := range proofs {
+		comments, err := s.getProofComments(ctx, proof.ID)
+		if err != nil {
+			return nil, err
+		}
+		proofs[i].Comments = comments
+	}
+
+	return proofs, nil
+}
+
+func (s *PostgresDB) PutProofComment(ctx context.Context, comment *layerhub.ProofComment) error {
+	query := `INSERT INTO proof_comments (
+        id,
+        proof_id,
+        user_id,
+        customer_id,
+        body,
+        created_at
+    ) VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (id) DO UPDATE SET
+        body=excluded.body
+    `
+
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		comment.ID,
+		comment.ProofID,
+		comment.UserID,
+		comment.CustomerID,
+		comment.Body,
+		comment.CreatedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) DeleteProof(ctx context.Context, id string) error {
+	tx, err := s.begin(ctx)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM proofs WHERE id = $1`, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM proof_comments WHERE proof_id = $1`, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) getProofComments(ctx context.Context, proofID string) ([]*layerhub.ProofComment, error) {
+	query := `SELECT * FROM proof_comments WHERE proof_id = $1 ORDER BY created_at, id`
+	comments := []*layerhub.ProofComment{}
+
+	err := s.conn().SelectContext(ctx, &comments, query, proofID)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return comments, nil
+}
+
+func (s *PostgresDB) PutFolder(ctx context.Context, folder *layerhub.Folder) error {
+	query := `INSERT INTO folders (
+        id,
+        name,
+        parent_id,
+        company_id,
+        customer_id,
+        created_at,
+        updated_at
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (id) DO UPDATE SET
+        name=excluded.name,
+        parent_id=excluded.parent_id,
+        updated_at=excluded.updated_at
+    `
+
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		folder.ID,
+		folder.Name,
+		folder.ParentID,
+		folder.CompanyID,
+		folder.CustomerID,
+		folder.CreatedAt,
+		folder.UpdatedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) FindFolders(ctx context.Context, filter *layerhub.Filter) ([]layerhub.Folder, error) {
+	query := `SELECT * FROM folders `
+	where, args := filterToQuery("folders", filter)
+	folders := []layerhub.Folder{}
+
+	err := s.conn().SelectContext(ctx, &folders, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return folders, nil
+}
+
+func (s *PostgresDB) CountFolders(ctx context.Context, filter *layerhub.Filter) (int, error) {
+	query := `SELECT COUNT(*) AS count FROM folders `
+	where, args := filterToQuery("folders", filter)
+	count := []CountRow{}
+
+	err := s.conn().SelectContext(ctx, &count, query+where, args...)
+	if err != nil {
+		return 0, errors.E(errors.KindUnexpected, err)
+	}
+
+	return count[0].Count, nil
+}
+
+func (s *PostgresDB) DeleteFolder(ctx context.Context, id string) error {
+	query := `DELETE FROM folders WHERE id = $1`
+
+	_, err := s.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) PutTenantJob(ctx context.Context, job *layerhub.TenantJob) error {
+	query := `INSERT INTO tenant_jobs (
+        id,
+        kind,
+        status,
+        company_id,
+        customer_id,
+        total,
+        done,
+        error,
+        export_key,
+        created_at,
+        updated_at,
+        finished_at
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) ON CONFLICT (id) DO UPDATE SET
+        status=excluded.status,
+        total=excluded.total,
+        done=excluded.done,
+        error=excluded.error,
+        export_key=excluded.export_key,
+        updated_at=excluded.updated_at,
+        finished_at=excluded.finished_at
+    `
+
+	_, err := s.conn().ExecContext(
+		ctx,
+		query,
+		job.ID,
+		job.Kind,
+		job.Status,
+		job.CompanyID,
+		job.CustomerID,
+		job.Total,
+		job.Done,
+		job.Error,
+		job.ExportKey,
+		job.CreatedAt,
+		job.UpdatedAt,
+		job.FinishedAt,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresDB) ClaimTenantJob(ctx context.Context, job *layerhub.TenantJob, status layerhub.TenantJobStatus, updatedAt time.Time) (bool, error) {
+	query := `UPDATE tenant_jobs SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4 AND updated_at = $5`
+
+	res, err := s.conn().ExecContext(ctx, query, job.Status, job.UpdatedAt, job.ID, status, updatedAt)
+	if err != nil {
+		return false, errors.E(errors.KindUnexpected, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.E(errors.KindUnexpected, err)
+	}
+
+	return n == 1, nil
+}
+
+func (s *PostgresDB) FindTenantJobs(ctx context.Context, filter *layerhub.Filter) ([]layerhub.TenantJob, error) {
+	query := `SELECT * FROM tenant_jobs `
+	where, args := filterToQuery("tenant_jobs", filter)
+	jobs := []layerhub.TenantJob{}
+
+	err := s.conn().SelectContext(ctx, &jobs, query+where, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	return jobs, nil
+}
+
+func (s *PostgresDB) putTemplateMetadata(ctx context.Context, ext ExtContext, template *layerhub.Template) error {
+	query := `INSERT INTO template_metadata (
+        id,
+        license,
+        orientation
+    ) VALUES ($1, $2, $3) ON CONFLICT (id) DO UPDATE SET
+        license=excluded.license,
+        orientation=excluded.orientation
+    `
+
+	_, err := ext.ExecContext(
+		ctx,
+		query,
+		template.ID,
+		template.Metadata.License,
+		template.Metadata.Orientation,
+	)
+	return err
+}
+
+func (s *PostgresDB) getTemplateMetadata(ctx context.Context, templateID string) (layerhub.Metadata, error) {
+	var metadata layerhub.Metadata
+	query := `SELECT * FROM template_metadata WHERE id = $1`
+	err := s.conn().GetContext(ctx, &metadata, query, templateID)
+	return metadata, err
+}
+
+func (s *PostgresDB) getSubscriptionPlanBillings(ctx context.Context, planID string) ([]*layerhub.Billing, error) {
+	query := `SELECT * FROM subscription_plan_billings WHERE subscription_plan_id = $1`
+	billings := []*layerhub.Billing{}
+
+	err := s.conn().SelectContext(ctx, &billings, query, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	return billings, nil
+}
+
+func (s *PostgresDB) putSubscriptionPlanBillings(ctx context.Context, ext ExtContext, plan *layerhub.SubscriptionPlan) error {
+	delQuery := `DELETE FROM subscription_plan_billings WHERE subscription_plan_id = $1`
+
+	_, err := ext.ExecContext(
+		ctx,
+		delQuery,
+		plan.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	insertQuery := `INSERT INTO subscription_plan_billings (
+        id,
+        "interval",
+        price,
+        subscription_plan_id
+    ) VALUES ($1, $2, $3, $4)`
+
+	for _, b := range plan.Billing {
+		_, err := ext.ExecContext(
+			ctx,
+			insertQuery,
+			b.ID,
+			b.Interval,
+			b.Price,
+			plan.ID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// filterToQuery returns the clauses of the filter, its placeholders are
+// numbered after the args already bound by the query
+func filterToQuery(table string, filter *layerhub.Filter, args ...any) (string, []any) {
+	query := ""
+	args = append([]any{}, args...)
+	conds := []string{}
+
+	bind := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	// Rows in the trash are only listed when the filter asks for them
+	if filter == nil && hasTrash(table) {
+		filter = &layerhub.Filter{}
+	}
+
+	if filter != nil {
+		if filter.Email != "" {
+			conds = append(conds, fmt.Sprintf("%s.email = %s", table, bind(filter.Email)))
+		}
+		if filter.PostscriptName != "" {
+			conds = append(conds, fmt.Sprintf("%s.postscript_name = %s", table, bind(filter.PostscriptName)))
+		}
+		if filter.ID != "" {
+			conds = append(conds, fmt.Sprintf("%s.id = %s", table, bind(filter.ID)))
+		}
+		if filter.ShortID != "" {
+			conds = append(conds, fmt.Sprintf("%s.short_id = %s", table, bind(filter.ShortID)))
+		}
+		if filter.RegularOrShortID != "" {
+			id := bind(filter.RegularOrShortID)
+			conds = append(conds, fmt.Sprintf("(%s.id = %s OR %s.short_id = %s)", table, id, table, id))
+		}
+		if filter.UserID != "" {
+			conds = append(conds, fmt.Sprintf("%s.user_id = %s", table, bind(filter.UserID)))
+		}
+		if filter.CustomerID != "" {
+			conds = append(conds, fmt.Sprintf("%s.customer_id = %s", table, bind(filter.CustomerID)))
+		}
+		if filter.CompanyID != "" {
+			conds = append(conds, fmt.Sprintf("%s.company_id = %s", table, bind(filter.CompanyID)))
+		}
+		if filter.AuthSource != "" {
+			conds = append(conds, fmt.Sprintf("%s.source = %s", table, bind(filter.AuthSource)))
+		}
+		if filter.Public != nil {
+			conds = append(conds, fmt.Sprintf("%s.public = %s", table, bind(*filter.Public)))
+		}
+		if filter.UsedInTemplate != nil {
+			conds = append(conds, fmt.Sprintf("%s.used_in_template = %s", table, bind(*filter.UsedInTemplate)))
+		}
+		if filter.DesignID != "" {
+			conds = append(conds, fmt.Sprintf("%s.design_id = %s", table, bind(filter.DesignID)))
+		}
+		if filter.MockupTemplateID != "" {
+			conds = append(conds, fmt.Sprintf("%s.mockup_template_id = %s", table, bind(filter.MockupTemplateID)))
+		}
+		if filter.ProjectID != "" {
+			conds = append(conds, fmt.Sprintf("%s.project_id = %s", table, bind(filter.ProjectID)))
+		}
+		if filter.Status != "" {
+			conds = append(conds, fmt.Sprintf("%s.status = %s", table, bind(filter.Status)))
+		}
+		if filter.ApiToken != "" {
+			conds = append(conds, fmt.Sprintf("%s.api_token = %s", table, bind(filter.ApiToken)))
+		}
+		if filter.FolderID != "" {
+			folderID := filter.FolderID
+			if folderID == layerhub.RootFolder {
+				folderID = ""
+			}
+			conds = append(conds, fmt.Sprintf("%s.%s = %s", table, folderColumn(table), bind(folderID)))
+		}
+		if filter.Published != nil {
+			conds = append(conds, fmt.Sprintf("%s.published = %s", table, bind(*filter.Published)))
+		}
+		if filter.Tag != "" {
+			conds = append(conds, fmt.Sprintf("%s = ANY(%s.tags)", bind(filter.Tag), table))
+		}
+		if filter.Color != "" {
+			conds = append(conds, fmt.Sprintf("%s = ANY(%s.colors)", bind(filter.Color), table))
+		}
+		if filter.Orientation != "" {
+			conds = append(conds, fmt.Sprintf("EXISTS (SELECT 1 FROM template_metadata WHERE template_metadata.id = %s.id AND template_metadata.orientation = %s)", table, bind(filter.Orientation)))
+		}
+		if filter.License != "" {
+			conds = append(conds, fmt.Sprintf("EXISTS (SELECT 1 FROM template_metadata WHERE template_metadata.id = %s.id AND template_metadata.license = %s)", table, bind(filter.License)))
+		}
+		if filter.FrameWidth != 0 && filter.FrameHeight != 0 {
+			unit := bind(filter.FrameUnit)
+			conds = append(conds, fmt.Sprintf("EXISTS (SELECT 1 FROM frames WHERE frames.id = %s.id AND ABS(frames.width - %s) < %s AND ABS(frames.height - %s) < %s AND (frames.unit = %s OR %s = ''))", table, bind(filter.FrameWidth), bind(layerhub.FrameSizeTolerance), bind(filter.FrameHeight), bind(layerhub.FrameSizeTolerance), unit, unit))
+		}
+		if filter.PublicOrCompanyID != "" {
+			conds = append(conds, fmt.Sprintf("(%s.public = TRUE OR %s.company_id = %s)", table, table, bind(filter.PublicOrCompanyID)))
+		}
+		if filter.EnabledFonts != nil && *filter.EnabledFonts == false {
+			conds = append(conds, "enabled_fonts.id IS NULL")
+		}
+		if filter.OptionalUserID != "" {
+			conds = append(conds, fmt.Sprintf("(%s.user_id = %s OR %s.user_id = '')", table, bind(filter.OptionalUserID), table))
+		}
+		if filter.OptionalCustomerID != "" {
+			conds = append(conds, fmt.Sprintf("(%s.customer_id = %s OR %s.customer_id = '')", table, bind(filter.OptionalCustomerID), table))
+		}
+		if filter.OptionalCompanyID != "" {
+			conds = append(conds, fmt.Sprintf("(%s.company_id = %s OR %s.company_id = '')", table, bind(filter.OptionalCompanyID), table))
+		}
+
+		if !filter.CreatedAfter.IsZero() {
+			conds = append(conds, fmt.Sprintf("%s.created_at >= %s", table, bind(filter.CreatedAfter)))
+		}
+		if !filter.CreatedBefore.IsZero() {
+			conds = append(conds, fmt.Sprintf("%s.created_at <= %s", table, bind(filter.CreatedBefore)))
+		}
+		if !filter.UpdatedAfter.IsZero() {
+			conds = append(conds, fmt.Sprintf("%s.updated_at >= %s", table, bind(filter.UpdatedAfter)))
+		}
+		if !filter.UpdatedBefore.IsZero() {
+			conds = append(conds, fmt.Sprintf("%s.updated_at <= %s", table, bind(filter.UpdatedBefore)))
+		}
+		if len(filter.IDs) != 0 {
+			conds = append(conds, fmt.Sprintf("%s.id = ANY(%s)", table, bind(pq.StringArray(filter.IDs))))
+		}
+		if len(filter.CustomerIDs) != 0 {
+			conds = append(conds, fmt.Sprintf("%s.customer_id = ANY(%s)", table, bind(pq.StringArray(filter.CustomerIDs))))
+		}
+		if filter.NamePrefix != "" {
+			conds = append(conds, fmt.Sprintf("%s.name ILIKE %s", table, bind(escapeLike(filter.NamePrefix)+"%")))
+		}
+		if hasTrash(table) {
+			if filter.Trashed || !filter.TrashedBefore.IsZero() {
+				conds = append(conds, fmt.Sprintf("%s.deleted_at IS NOT NULL", table))
+			} else if !filter.WithTrashed {
+				conds = append(conds, fmt.Sprintf("%s.deleted_at IS NULL", table))
+			}
+			if !filter.TrashedBefore.IsZero() {
+				conds = append(conds, fmt.Sprintf("%s.deleted_at <= %s", table, bind(filter.TrashedBefore)))
+			}
+		}
+
+		sortBy := sortColumn(filter.SortBy)
+		if filter.After != nil {
+			op := ">"
+			if filter.SortDesc {
+				op = "<"
+			}
+			if sortBy == "id" {
+				conds = append(conds, fmt.Sprintf("%s.id %s %s", table, op, bind(filter.After.ID)))
+			} else {
+				// The session is in UTC, cursor times are compared as UTC
+				col := fmt.Sprintf("%s.%s", table, sortBy)
+				value := bind(filter.After.Value)
+				conds = append(conds, fmt.Sprintf("(%s %s %s OR (%s = %s AND %s.id %s %s))", col, op, value, col, value, table, op, bind(filter.After.ID)))
+			}
+		}
+
+		if len(conds) != 0 {
+			query += "WHERE " + strings.Join(conds, " AND ") + " "
+		}
+
+		// PostgreSQL returns rows in no particular order, they're always
+		// sorted so they're listed in the same order as the other backends
+		dir := "ASC"
+		if filter.SortDesc {
+			dir = "DESC"
+		}
+		if sortBy == "id" {
+			query += fmt.Sprintf("ORDER BY %s.id %s ", table, dir)
+		} else {
+			query += fmt.Sprintf("ORDER BY %s.%s %s, %s.id %s ", table, sortBy, dir, table, dir)
+		}
+
+		if filter.Limit != 0 {
+			query += fmt.Sprintf("LIMIT %s ", bind(filter.Limit))
+		}
+		if filter.Offset != 0 && filter.After == nil {
+			query += fmt.Sprintf("OFFSET %s ", bind(filter.Offset))
+		}
+	}
+
+	return query, args
+}
+
+// hasTrash returns true if the rows of the table are moved to the trash
+// before they're deleted
+func hasTrash(table string) bool {
+	switch table {
+	case "templates", "projects", "components", "uploads":
+		return true
+	}
+	return false
+}
+
+// folderColumn returns the column of the folder of the table items, folders
+// are nested through their parent
+func folderColumn(table string) string {
+	switch table {
+	case "folders":
+		return "parent_id"
+	case "uploads":
+		return "folder"
+	default:
+		return "folder_id"
+	}
+}
+
+// sortColumn returns the column of a sort field, unknown fields sort by id
+func sortColumn(field layerhub.SortField) string {
+	switch field {
+	case layerhub.SortName, layerhub.SortCreatedAt, layerhub.SortUpdatedAt:
+		return string(field)
+	default:
+		return "id"
+	}
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// templateRow is a row of the templates table, tags and colors are arrays
+type templateRow struct {
+	layerhub.Template
+	Tags   pq.StringArray `db:"tags"`
+	Colors pq.StringArray `db:"colors"`
+}
+
+// stringArray returns the array value of a slice, nil slices are stored as
+// empty arrays
+func stringArray(values []string) pq.StringArray {
+	if values == nil {
+		return pq.StringArray{}
+	}
+	return pq.StringArray(values)
+}
+
+// placeholders returns the placeholders of a row of n values, numbered after
+// the values already bound
+func placeholders(bound, n int) string {
+	params := make([]string, n)
+	for i := range params {
+		params[i] = fmt.Sprintf("$%d", bound+i+1)
+	}
+	return "(" + strings.Join(params, ", ") + ")"
+}
+
+type CountRow struct {
+	Count int `db:"count"`
+}
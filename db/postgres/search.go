@@ -0,0 +1,268 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+	"github.com/jmoiron/sqlx"
+)
+
+const facetSize = 20
+
+// textQuery is the tsquery of a search text, any of its words matches like
+// the other indexes do
+const textQuery = `replace(plainto_tsquery('simple', %s)::text, '&', '|')::tsquery`
+
+// PostgresSearchIndex is a search index backed by the tsvector of the
+// search_documents table, facets are counted from the search_facets table.
+// It's shared by every instance using the database
+type PostgresSearchIndex struct {
+	db *sqlx.DB
+}
+
+var _ layerhub.SearchIndex = (*PostgresSearchIndex)(nil)
+
+func NewSearchIndex(conf *Config) (*PostgresSearchIndex, error) {
+	db, err := open(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostgresSearchIndex{db}, nil
+}
+
+// Empty reports whether the index has no documents, it's filled from the
+// database before serving
+func (s *PostgresSearchIndex) Empty(ctx context.Context) (bool, error) {
+	count := []CountRow{}
+	err := s.db.SelectContext(ctx, &count, `SELECT COUNT(*) AS count FROM (SELECT 1 FROM search_documents LIMIT 1) AS documents`)
+	if err != nil {
+		return false, errors.E(errors.KindUnexpected, err)
+	}
+
+	return count[0].Count == 0, nil
+}
+
+func (s *PostgresSearchIndex) IndexDocument(ctx context.Context, doc *layerhub.SearchDocument) error {
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO search_documents (
+        id,
+        kind,
+        company_id,
+        customer_id,
+        public,
+        name,
+        description,
+        tags,
+        colors,
+        content,
+        width,
+        height,
+        unit
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) ON CONFLICT (kind, id) DO UPDATE SET
+        company_id=excluded.company_id,
+        customer_id=excluded.customer_id,
+        public=excluded.public,
+        name=excluded.name,
+        description=excluded.description,
+        tags=excluded.tags,
+        colors=excluded.colors,
+        content=excluded.content,
+        width=excluded.width,
+        height=excluded.height,
+        unit=excluded.unit
+    `
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		doc.ID,
+		doc.Kind,
+		doc.CompanyID,
+		doc.CustomerID,
+		doc.Public,
+		doc.Name,
+		doc.Description,
+		strings.Join(doc.Tags, " "),
+		strings.Join(doc.Colors, " "),
+		strings.Join(doc.Text, "\n"),
+		doc.Width,
+		doc.Height,
+		doc.Unit,
+	)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM search_facets WHERE kind = $1 AND document_id = $2`, doc.Kind, doc.ID)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	facets := map[string][]string{
+		layerhub.FacetTags:   doc.Tags,
+		layerhub.FacetColors: doc.Colors,
+	}
+	if size := doc.Size(); size != "" {
+		facets[layerhub.FacetSize] = []string{size}
+	}
+
+	for facet, values := range facets {
+		for _, value := range values {
+			_, err = tx.ExecContext(
+				ctx,
+				`INSERT INTO search_facets (kind, document_id, facet, value) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`,
+				doc.Kind,
+				doc.ID,
+				facet,
+				value,
+			)
+			if err != nil {
+				return errors.E(errors.KindUnexpected, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresSearchIndex) DeleteDocument(ctx context.Context, kind layerhub.SearchKind, id string) error {
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM search_documents WHERE kind = $1 AND id = $2`, kind, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM search_facets WHERE kind = $1 AND document_id = $2`, kind, id)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresSearchIndex) Search(ctx context.Context, q *layerhub.SearchQuery) (*layerhub.SearchResult, error) {
+	where, args := searchToQuery(q)
+
+	bound := append([]any{}, args...)
+	bind := func(v any) string {
+		bound = append(bound, v)
+		return fmt.Sprintf("$%d", len(bound))
+	}
+
+	order := ` ORDER BY search_documents.name, search_documents.id`
+	if q.Text != "" {
+		order = fmt.Sprintf(` ORDER BY ts_rank(search_documents.document, `+textQuery+`) DESC, search_documents.id`, bind(q.Text))
+	}
+	limit := fmt.Sprintf(` LIMIT %s OFFSET %s`, bind(q.Limit), bind(q.Offset))
+
+	ids := []string{}
+	query := `SELECT search_documents.id FROM search_documents ` + where + order + limit
+	err := s.db.SelectContext(ctx, &ids, query, bound...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	count := []CountRow{}
+	query = `SELECT COUNT(*) AS count FROM search_documents ` + where
+	err = s.db.SelectContext(ctx, &count, query, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	rows := []struct {
+		Facet string `db:"facet"`
+		Value string `db:"value"`
+		Count int    `db:"count"`
+	}{}
+	query = `SELECT search_facets.facet, search_facets.value, COUNT(*) AS count FROM search_facets
+        JOIN search_documents ON search_documents.kind = search_facets.kind AND search_documents.id = search_facets.document_id ` +
+		where + ` GROUP BY search_facets.facet, search_facets.value`
+	err = s.db.SelectContext(ctx, &rows, query, args...)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	facets := map[string][]layerhub.SearchFacet{}
+	for _, row := range rows {
+		facets[row.Facet] = append(facets[row.Facet], layerhub.SearchFacet{Value: row.Value, Count: row.Count})
+	}
+	for name, terms := range facets {
+		sort.Slice(terms, func(i, j int) bool {
+			if terms[i].Count != terms[j].Count {
+				return terms[i].Count > terms[j].Count
+			}
+			return terms[i].Value < terms[j].Value
+		})
+		if len(terms) > facetSize {
+			facets[name] = terms[:facetSize]
+		}
+	}
+
+	return &layerhub.SearchResult{
+		IDs:    ids,
+		Total:  count[0].Count,
+		Facets: facets,
+	}, nil
+}
+
+func searchToQuery(q *layerhub.SearchQuery) (string, []any) {
+	args := []any{}
+	bind := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conds := []string{"search_documents.kind = " + bind(q.Kind)}
+
+	if q.CustomerID != "" {
+		conds = append(conds, fmt.Sprintf("((search_documents.company_id = %s AND (search_documents.customer_id = %s OR search_documents.customer_id = '')) OR search_documents.public = TRUE)", bind(q.CompanyID), bind(q.CustomerID)))
+	} else {
+		conds = append(conds, fmt.Sprintf("(search_documents.company_id = %s OR search_documents.public = TRUE)", bind(q.CompanyID)))
+	}
+
+	if q.Text != "" {
+		conds = append(conds, fmt.Sprintf("search_documents.document @@ "+textQuery, bind(q.Text)))
+	}
+
+	for _, tag := range q.Tags {
+		conds = append(conds, fmt.Sprintf("EXISTS (SELECT 1 FROM search_facets f WHERE f.kind = search_documents.kind AND f.document_id = search_documents.id AND f.facet = 'tags' AND f.value = %s)", bind(tag)))
+	}
+
+	for _, color := range q.Colors {
+		conds = append(conds, fmt.Sprintf("EXISTS (SELECT 1 FROM search_facets f WHERE f.kind = search_documents.kind AND f.document_id = search_documents.id AND f.facet = 'colors' AND f.value = %s)", bind(color)))
+	}
+
+	if q.Width != 0 {
+		conds = append(conds, fmt.Sprintf("ABS(search_documents.width - %s) < %s", bind(q.Width), bind(layerhub.FrameSizeTolerance)))
+	}
+
+	if q.Height != 0 {
+		conds = append(conds, fmt.Sprintf("ABS(search_documents.height - %s) < %s", bind(q.Height), bind(layerhub.FrameSizeTolerance)))
+	}
+
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
@@ -0,0 +1,112 @@
+// Package schema applies the migrations embedded by the SQL databases
+package schema
+
+import (
+	stderrors "errors"
+	"io/fs"
+	"time"
+
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+const (
+	// lockRetries is the number of times an instance tries to take the
+	// migration lock, drivers like MySQL give up waiting on it after a few
+	// seconds while another instance migrates
+	lockRetries = 30
+	lockBackoff = 2 * time.Second
+
+	// lockTimeout is how long drivers like PostgreSQL, which wait on the
+	// lock until it's free, wait on each try
+	lockTimeout = time.Minute
+)
+
+// Migrate applies the migrations in dir of fsys that are newer than the
+// schema version of the database and returns the resulting version. The
+// driver holds a database lock while migrating so instances starting together
+// apply them once, the others wait for the lock. A dirty schema, left by a
+// failed migration, or a schema newer than the migrations of this build is an
+// error. The driver is closed on return
+func Migrate(fsys fs.FS, dir string, driverName string, driver database.Driver) (uint, error) {
+	src, err := iofs.New(fsys, dir)
+	if err != nil {
+		driver.Close()
+		return 0, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, driverName, driver)
+	if err != nil {
+		driver.Close()
+		return 0, err
+	}
+	defer m.Close()
+	m.LockTimeout = lockTimeout
+
+	latest, err := lastVersion(src)
+	if err != nil {
+		return 0, err
+	}
+
+	// The schema is dirty while another instance migrates it, so it's only
+	// checked by Up once the lock is taken
+	version, _, err := m.Version()
+	if err != nil && err != migrate.ErrNilVersion {
+		return 0, err
+	}
+	if version > latest {
+		return version, errors.Errorf("schema version %d is newer than the migrations of this build, the latest is %d", version, latest)
+	}
+
+	for i := 0; ; i++ {
+		err = m.Up()
+		if err == migrate.ErrLockTimeout {
+			// The driver still waits on the lock after the timeout, the
+			// lock is released once it's taken so the next try can take it
+			driver.Unlock()
+		} else if err != database.ErrLocked {
+			break
+		}
+		if i == lockRetries {
+			break
+		}
+		time.Sleep(lockBackoff)
+	}
+
+	var dirty migrate.ErrDirty
+	if stderrors.As(err, &dirty) {
+		return uint(dirty.Version), errors.Errorf("schema version %d is dirty, the failed migration must be fixed by hand", dirty.Version)
+	}
+	if err != nil && err != migrate.ErrNoChange {
+		return 0, err
+	}
+
+	version, _, err = m.Version()
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// lastVersion returns the version of the last migration of src
+func lastVersion(src source.Driver) (uint, error) {
+	version, err := src.First()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		next, err := src.Next(version)
+		if stderrors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
@@ -0,0 +1,29 @@
+package sqlite
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+
+	"github.com/echovl/orderflo-dev/db/schema"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrate applies the embedded migrations and returns the schema version
+func Migrate(conf *Config) (uint, error) {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_busy_timeout=5000", conf.Path))
+	if err != nil {
+		return 0, err
+	}
+
+	driver, err := sqlite3.WithInstance(db, &sqlite3.Config{})
+	if err != nil {
+		db.Close()
+		return 0, err
+	}
+
+	return schema.Migrate(migrations, "migrations", "sqlite3", driver)
+}
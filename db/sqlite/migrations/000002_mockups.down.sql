@@ -0,0 +1,5 @@
+DROP TABLE
+  IF EXISTS mockup_templates;
+
+DROP TABLE
+  IF EXISTS mockups;
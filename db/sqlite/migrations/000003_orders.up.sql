@@ -0,0 +1,34 @@
+ALTER TABLE frames ADD COLUMN bleed FLOAT NOT NULL DEFAULT 0;
+
+CREATE TABLE
+  IF NOT EXISTS orders (
+    id VARCHAR(50),
+    number VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    customer_id VARCHAR(50) NOT NULL,
+    company_id VARCHAR(50) NOT NULL,
+    submitted_at DATETIME,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (number)
+  );
+
+CREATE TABLE
+  IF NOT EXISTS order_items (
+    id VARCHAR(50),
+    order_id VARCHAR(50) NOT NULL,
+    position INT NOT NULL,
+    project_id VARCHAR(255) NOT NULL,
+    sku VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    dpi INT NOT NULL,
+    print_file_url VARCHAR(1000) NOT NULL,
+    print_status VARCHAR(20) NOT NULL,
+    print_error TEXT NOT NULL,
+    print_attempts INT NOT NULL,
+    PRIMARY KEY (id)
+  );
+
+CREATE INDEX IF NOT EXISTS order_items_order_id ON order_items (order_id);
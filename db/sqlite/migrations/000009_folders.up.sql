@@ -0,0 +1,17 @@
+CREATE TABLE
+  IF NOT EXISTS folders (
+    id VARCHAR(50),
+    name VARCHAR(255) NOT NULL,
+    parent_id VARCHAR(50) NOT NULL,
+    company_id VARCHAR(255) NOT NULL,
+    customer_id VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (id)
+  );
+
+CREATE INDEX IF NOT EXISTS folders_parent_id ON folders (parent_id);
+
+ALTER TABLE templates ADD COLUMN folder_id VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE projects ADD COLUMN folder_id VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE components ADD COLUMN folder_id VARCHAR(50) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE uploads DROP COLUMN deleted_at;
+ALTER TABLE components DROP COLUMN deleted_at;
+ALTER TABLE projects DROP COLUMN deleted_at;
+ALTER TABLE templates DROP COLUMN deleted_at;
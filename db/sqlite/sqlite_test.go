@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/echovl/orderflo-dev/db/dbtest"
+	"github.com/echovl/orderflo-dev/layerhub"
+)
+
+func TestSQLite(t *testing.T) {
+	dbtest.Run(t, newTestDB)
+}
+
+func TestColumns(t *testing.T) {
+	db := newTestDB(t)
+
+	dbtest.RunColumns(t, func(t *testing.T, table string) []string {
+		columns := []string{}
+		if err := db.(*SQLiteDB).db.Select(&columns, "SELECT name FROM pragma_table_info(?)", table); err != nil {
+			t.Fatal(err)
+		}
+		return columns
+	})
+}
+
+func TestMigrate(t *testing.T) {
+	conf := &Config{Path: filepath.Join(t.TempDir(), "layerhub.db")}
+
+	version, err := Migrate(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Migrating again is a no-op
+	again, err := Migrate(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != version {
+		t.Fatalf("mismatched version:\ngot: %v\nwant: %v", again, version)
+	}
+
+	db, err := New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.(*SQLiteDB).db.Close()
+
+	// A schema newer than the migrations comes from a newer build
+	if _, err := db.(*SQLiteDB).db.Exec("UPDATE schema_migrations SET version = ?", version+1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Migrate(conf); err == nil {
+		t.Fatal("expected an error with a newer schema")
+	}
+
+	// A dirty schema is left by a failed migration
+	if _, err := db.(*SQLiteDB).db.Exec("UPDATE schema_migrations SET version = ?, dirty = 1", version); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Migrate(conf); err == nil || !strings.Contains(err.Error(), "dirty") {
+		t.Fatalf("expected an error with a dirty schema, got %v", err)
+	}
+}
+
+func TestFilterToQuery(t *testing.T) {
+	trashed := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	testcases := []struct {
+		name  string
+		query *layerhub.Filter
+		where string
+		args  []any
+	}{
+		{
+			name:  "always sorted",
+			query: &layerhub.Filter{CompanyID: "company_1"},
+			where: "WHERE templates.company_id = ? AND templates.deleted_at IS NULL ORDER BY templates.id ASC ",
+			args:  []any{"company_1"},
+		},
+		{
+			name:  "offset without limit",
+			query: &layerhub.Filter{Offset: 20},
+			where: "WHERE templates.deleted_at IS NULL ORDER BY templates.id ASC LIMIT -1 OFFSET ? ",
+			args:  []any{20},
+		},
+		{
+			name:  "time cursor",
+			query: &layerhub.Filter{SortBy: layerhub.SortCreatedAt, After: &layerhub.Cursor{Value: "2022-06-01 00:00:00", ID: "template_2"}},
+			where: "WHERE templates.deleted_at IS NULL AND (datetime(templates.created_at) > ? OR (datetime(templates.created_at) = ? AND templates.id > ?)) ORDER BY templates.created_at ASC, templates.id ASC ",
+			args:  []any{"2022-06-01 00:00:00", "2022-06-01 00:00:00", "template_2"},
+		},
+		{
+			name:  "prefix",
+			query: &layerhub.Filter{NamePrefix: "50%_"},
+			where: `WHERE templates.name LIKE ? ESCAPE '\' AND templates.deleted_at IS NULL ORDER BY templates.id ASC `,
+			args:  []any{`50\%\_%`},
+		},
+		{
+			name:  "trashed before",
+			query: &layerhub.Filter{TrashedBefore: trashed},
+			where: "WHERE templates.deleted_at IS NOT NULL AND templates.deleted_at <= ? ORDER BY templates.id ASC ",
+			args:  []any{trashed},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			where, args := filterToQuery("templates", tc.query)
+			if where != tc.where {
+				t.Errorf("mismatched query:\ngot: %s\nwant: %s", where, tc.where)
+			}
+			if !reflect.DeepEqual(args, tc.args) {
+				t.Errorf("mismatched args:\ngot: %v\nwant: %v", args, tc.args)
+			}
+		})
+	}
+}
+
+// newTestDB returns a migrated database in a temporary file
+func newTestDB(t *testing.T) layerhub.DB {
+	conf := &Config{Path: filepath.Join(t.TempDir(), "layerhub.db")}
+
+	if _, err := Migrate(conf); err != nil {
+		t.Fatalf("could not migrate: %s", err)
+	}
+
+	db, err := New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.(*SQLiteDB).db.Close() })
+
+	return db
+}
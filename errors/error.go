@@ -13,6 +13,9 @@ const (
 	KindNotFound
 	KindAuthentication
 	KindAuthorization
+	// KindConflict is a write that clashes with a concurrent one, it can be
+	// retried
+	KindConflict
 )
 
 type Error struct {
@@ -56,6 +59,11 @@ func Authorization(args ...any) error {
 	return E(args...)
 }
 
+func Conflict(args ...any) error {
+	args = append(args, KindConflict)
+	return E(args...)
+}
+
 func Unexpected(args ...any) error {
 	args = append(args, KindUnexpected)
 	return E(args...)
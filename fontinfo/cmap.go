@@ -0,0 +1,232 @@
+package fontinfo
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// runeRange is an inclusive range of code points
+type runeRange struct {
+	lo, hi rune
+}
+
+// coverage is the sorted, non overlapping ranges of code points mapped to a
+// glyph by the font
+type coverage []runeRange
+
+// count returns the number of code points of r covered by the font
+func (c coverage) count(r runeRange) int {
+	i := sort.Search(len(c), func(i int) bool { return c[i].hi >= r.lo })
+
+	n := 0
+	for ; i < len(c) && c[i].lo <= r.hi; i++ {
+		lo, hi := c[i].lo, c[i].hi
+		if lo < r.lo {
+			lo = r.lo
+		}
+		if hi > r.hi {
+			hi = r.hi
+		}
+		n += int(hi-lo) + 1
+	}
+
+	return n
+}
+
+func (c coverage) len() int {
+	n := 0
+	for _, r := range c {
+		n += int(r.hi-r.lo) + 1
+	}
+	return n
+}
+
+// subset is a script a font supports when it covers at least ratio of the
+// code points of its ranges
+type subset struct {
+	name   string
+	ranges []runeRange
+	ratio  float64
+}
+
+// subsets are named like the subsets of Google Fonts
+var subsets = []subset{
+	{"latin", []runeRange{{0x20, 0x7e}}, 0.9},
+	{"latin-ext", []runeRange{{0x100, 0x17f}}, 0.75},
+	{"vietnamese", []runeRange{{0x1ea0, 0x1ef9}}, 0.9},
+	{"greek", []runeRange{{0x391, 0x3a1}, {0x3a3, 0x3a9}, {0x3b1, 0x3c9}}, 0.9},
+	{"cyrillic", []runeRange{{0x410, 0x44f}}, 0.9},
+	{"cyrillic-ext", []runeRange{{0x460, 0x52f}}, 0.5},
+	{"hebrew", []runeRange{{0x5d0, 0x5ea}}, 0.9},
+	{"arabic", []runeRange{{0x621, 0x64a}}, 0.9},
+	{"devanagari", []runeRange{{0x905, 0x939}}, 0.9},
+	{"thai", []runeRange{{0xe01, 0xe3a}}, 0.9},
+	{"japanese", []runeRange{{0x3041, 0x3096}, {0x30a1, 0x30fa}}, 0.9},
+	// Most Korean fonts only cover the 2350 syllables of KS X 1001
+	{"korean", []runeRange{{0xac00, 0xd7a3}}, 0.2},
+}
+
+// subsets returns the names of the scripts covered by the font
+func (c coverage) subsets() []string {
+	names := []string{}
+	for _, s := range subsets {
+		total, covered := 0, 0
+		for _, r := range s.ranges {
+			total += int(r.hi-r.lo) + 1
+			covered += c.count(r)
+		}
+		if float64(covered) >= s.ratio*float64(total) {
+			names = append(names, s.name)
+		}
+	}
+	return names
+}
+
+// readCmap returns the code points mapped by the Unicode subtable of the
+// cmap table. Subtables of formats 4 and 12 are read, fonts without either
+// cover no code points
+func readCmap(cmap []byte) coverage {
+	if len(cmap) < 4 {
+		return nil
+	}
+
+	// The full repertoire subtables are preferred over the BMP ones
+	var bmp, full []byte
+	numTables := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < numTables; i++ {
+		record := 4 + i*8
+		if record+8 > len(cmap) {
+			break
+		}
+		platformID := binary.BigEndian.Uint16(cmap[record:])
+		encodingID := binary.BigEndian.Uint16(cmap[record+2:])
+		offset := int(binary.BigEndian.Uint32(cmap[record+4:]))
+		if offset < 0 || offset+2 > len(cmap) {
+			continue
+		}
+
+		unicode := platformID == 0 || (platformID == 3 && (encodingID == 1 || encodingID == 10))
+		if !unicode {
+			continue
+		}
+
+		switch binary.BigEndian.Uint16(cmap[offset:]) {
+		case 4:
+			if bmp == nil {
+				bmp = cmap[offset:]
+			}
+		case 12:
+			if full == nil {
+				full = cmap[offset:]
+			}
+		}
+	}
+
+	if full != nil {
+		return readCmap12(full)
+	}
+	if bmp != nil {
+		return readCmap4(bmp)
+	}
+	return nil
+}
+
+func readCmap4(sub []byte) coverage {
+	if len(sub) < 14 {
+		return nil
+	}
+
+	segCount := int(binary.BigEndian.Uint16(sub[6:])) / 2
+	endCodes := 14
+	startCodes := endCodes + segCount*2 + 2
+	idDeltas := startCodes + segCount*2
+	idRangeOffsets := idDeltas + segCount*2
+	if idRangeOffsets+segCount*2 > len(sub) {
+		return nil
+	}
+
+	var cov coverage
+	add := func(r rune) {
+		if n := len(cov); n != 0 && cov[n-1].hi == r-1 {
+			cov[n-1].hi = r
+			return
+		}
+		cov = append(cov, runeRange{r, r})
+	}
+
+	for i := 0; i < segCount; i++ {
+		end := int(binary.BigEndian.Uint16(sub[endCodes+i*2:]))
+		start := int(binary.BigEndian.Uint16(sub[startCodes+i*2:]))
+		delta := int(binary.BigEndian.Uint16(sub[idDeltas+i*2:]))
+		rangeOffset := int(binary.BigEndian.Uint16(sub[idRangeOffsets+i*2:]))
+
+		for c := start; c <= end && c != 0xffff; c++ {
+			glyph := 0
+			if rangeOffset == 0 {
+				glyph = (c + delta) & 0xffff
+			} else {
+				pos := idRangeOffsets + i*2 + rangeOffset + (c-start)*2
+				if pos+2 > len(sub) {
+					break
+				}
+				if glyph = int(binary.BigEndian.Uint16(sub[pos:])); glyph != 0 {
+					glyph = (glyph + delta) & 0xffff
+				}
+			}
+			if glyph != 0 {
+				add(rune(c))
+			}
+		}
+	}
+
+	return cov.normalize()
+}
+
+func readCmap12(sub []byte) coverage {
+	if len(sub) < 16 {
+		return nil
+	}
+
+	numGroups := int(binary.BigEndian.Uint32(sub[12:]))
+	if numGroups < 0 || 16+numGroups*12 > len(sub) {
+		return nil
+	}
+
+	cov := make(coverage, 0, numGroups)
+	for i := 0; i < numGroups; i++ {
+		group := sub[16+i*12:]
+		start := rune(binary.BigEndian.Uint32(group))
+		end := rune(binary.BigEndian.Uint32(group[4:]))
+		startGlyph := binary.BigEndian.Uint32(group[8:])
+		if end < start || end > 0x10ffff {
+			continue
+		}
+		// The first code point of a group starting at glyph 0 is unmapped
+		if startGlyph == 0 {
+			start++
+		}
+		if start <= end {
+			cov = append(cov, runeRange{start, end})
+		}
+	}
+
+	return cov.normalize()
+}
+
+// normalize sorts the ranges and merges the ones that overlap or touch
+func (c coverage) normalize() coverage {
+	sort.Slice(c, func(i, j int) bool { return c[i].lo < c[j].lo })
+
+	merged := coverage{}
+	for _, r := range c {
+		if n := len(merged); n != 0 && r.lo <= merged[n-1].hi+1 {
+			if r.hi > merged[n-1].hi {
+				merged[n-1].hi = r.hi
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	return merged
+}
@@ -0,0 +1,300 @@
+// Package fontinfo reads the metadata of TrueType and OpenType fonts, and of
+// their WOFF and WOFF2 encodings, from the font tables. Glyph outlines aren't
+// parsed
+package fontinfo
+
+import (
+	"encoding/binary"
+	"errors"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// MaxSize is the largest font accepted, compressed fonts declaring a larger
+// size are rejected before they are decompressed
+const MaxSize = 32 << 20
+
+type Format string
+
+const (
+	FormatTrueType Format = "truetype"
+	FormatOpenType Format = "opentype"
+	FormatWOFF     Format = "woff"
+	FormatWOFF2    Format = "woff2"
+)
+
+// Embedding is the license of the font for embedding it in documents, from
+// the most to the least permissive
+type Embedding string
+
+const (
+	EmbeddingInstallable  Embedding = "installable"
+	EmbeddingEditable     Embedding = "editable"
+	EmbeddingPreviewPrint Embedding = "preview_print"
+	EmbeddingRestricted   Embedding = "restricted"
+)
+
+// Categories are the ones of Google Fonts
+const (
+	CategorySerif       = "serif"
+	CategorySansSerif   = "sans-serif"
+	CategoryMonospace   = "monospace"
+	CategoryHandwriting = "handwriting"
+	CategoryDisplay     = "display"
+)
+
+// Axis is a design axis of a variable font
+type Axis struct {
+	Tag     string  `json:"tag"`
+	Name    string  `json:"name"`
+	Min     float64 `json:"min"`
+	Default float64 `json:"default"`
+	Max     float64 `json:"max"`
+}
+
+type Info struct {
+	Format Format
+
+	// Family and Subfamily are the typographic names when the font has
+	// them, like "Roboto" and "Medium Italic"
+	Family         string
+	Subfamily      string
+	FullName       string
+	PostscriptName string
+	Version        string
+	Designer       string
+	License        string
+	LicenseURL     string
+
+	// Weight is from 100 to 900 and Width from 1, ultra-condensed, to 9,
+	// ultra-expanded
+	Weight int
+	Width  int
+	Italic bool
+
+	// Category is guessed from the PANOSE classification, it's empty if
+	// the font isn't classified
+	Category string
+
+	// Axes is empty for static fonts
+	Axes []Axis
+
+	NumGlyphs int
+
+	// CodePoints is the number of characters mapped to a glyph, Subsets are
+	// the scripts they cover
+	CodePoints int
+	Subsets    []string
+
+	Embedding    Embedding
+	NoSubsetting bool
+	BitmapOnly   bool
+}
+
+// Parse reads the metadata of the font, tables missing from the font leave
+// their fields unset
+func Parse(data []byte) (*Info, error) {
+	format, tables, err := readTables(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if tables["name"] == nil {
+		return nil, errors.New("fontinfo: font has no name table")
+	}
+
+	info := &Info{
+		Format:    format,
+		Embedding: EmbeddingInstallable,
+		Weight:    400,
+		Width:     5,
+	}
+
+	names := readNames(tables["name"])
+	info.Family = firstName(names, 16, 1)
+	info.Subfamily = firstName(names, 17, 2)
+	info.FullName = names[4]
+	info.PostscriptName = names[6]
+	info.Version = names[5]
+	info.Designer = names[9]
+	info.License = names[13]
+	info.LicenseURL = names[14]
+
+	if os2 := tables["OS/2"]; len(os2) >= 64 {
+		info.Weight = int(binary.BigEndian.Uint16(os2[4:]))
+		info.Width = int(binary.BigEndian.Uint16(os2[6:]))
+
+		fsType := binary.BigEndian.Uint16(os2[8:])
+		switch {
+		// The least restrictive license applies when several are set
+		case fsType&0x8 != 0:
+			info.Embedding = EmbeddingEditable
+		case fsType&0x4 != 0:
+			info.Embedding = EmbeddingPreviewPrint
+		case fsType&0x2 != 0:
+			info.Embedding = EmbeddingRestricted
+		}
+		info.NoSubsetting = fsType&0x100 != 0
+		info.BitmapOnly = fsType&0x200 != 0
+
+		info.Category = category(os2[32:42])
+
+		// The italic and oblique bits
+		fsSelection := binary.BigEndian.Uint16(os2[62:])
+		info.Italic = fsSelection&0x201 != 0
+	}
+
+	if head := tables["head"]; len(head) >= 46 {
+		info.Italic = info.Italic || binary.BigEndian.Uint16(head[44:])&0x2 != 0
+	}
+
+	if post := tables["post"]; len(post) >= 16 && binary.BigEndian.Uint32(post[12:]) != 0 {
+		info.Category = CategoryMonospace
+	}
+
+	if maxp := tables["maxp"]; len(maxp) >= 6 {
+		info.NumGlyphs = int(binary.BigEndian.Uint16(maxp[4:]))
+	}
+
+	info.Axes = readAxes(tables["fvar"], names)
+
+	cov := readCmap(tables["cmap"])
+	info.CodePoints = cov.len()
+	info.Subsets = cov.subsets()
+
+	return info, nil
+}
+
+// readNames returns the English names of the font by name ID, Windows names
+// are preferred over the Unicode and Macintosh ones
+func readNames(table []byte) map[int]string {
+	names := map[int]string{}
+	if len(table) < 6 {
+		return names
+	}
+
+	count := int(binary.BigEndian.Uint16(table[2:]))
+	storage := int(binary.BigEndian.Uint16(table[4:]))
+
+	// rank of the record of each name, lower is better
+	ranks := map[int]int{}
+	for i := 0; i < count; i++ {
+		record := 6 + i*12
+		if record+12 > len(table) {
+			break
+		}
+		platformID := binary.BigEndian.Uint16(table[record:])
+		encodingID := binary.BigEndian.Uint16(table[record+2:])
+		languageID := binary.BigEndian.Uint16(table[record+4:])
+		nameID := int(binary.BigEndian.Uint16(table[record+6:]))
+		length := int(binary.BigEndian.Uint16(table[record+8:]))
+		offset := storage + int(binary.BigEndian.Uint16(table[record+10:]))
+		if offset+length > len(table) {
+			continue
+		}
+
+		rank := -1
+		switch {
+		case platformID == 3 && (encodingID == 1 || encodingID == 10) && languageID == 0x409:
+			rank = 0
+		case platformID == 3 && (encodingID == 1 || encodingID == 10):
+			rank = 1
+		case platformID == 0:
+			rank = 2
+		case platformID == 1 && encodingID == 0 && languageID == 0:
+			rank = 3
+		}
+		if best, ok := ranks[nameID]; rank < 0 || (ok && best <= rank) {
+			continue
+		}
+
+		raw := table[offset : offset+length]
+		var name string
+		if platformID == 1 {
+			decoded, err := charmap.Macintosh.NewDecoder().Bytes(raw)
+			if err != nil {
+				continue
+			}
+			name = string(decoded)
+		} else {
+			name = decodeUTF16(raw)
+		}
+
+		ranks[nameID] = rank
+		names[nameID] = name
+	}
+
+	return names
+}
+
+func firstName(names map[int]string, ids ...int) string {
+	for _, id := range ids {
+		if name := names[id]; name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+func decodeUTF16(b []byte) string {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = binary.BigEndian.Uint16(b[i*2:])
+	}
+	return string(utf16.Decode(units))
+}
+
+func readAxes(fvar []byte, names map[int]string) []Axis {
+	axes := []Axis{}
+	if len(fvar) < 16 {
+		return axes
+	}
+
+	offset := int(binary.BigEndian.Uint16(fvar[4:]))
+	count := int(binary.BigEndian.Uint16(fvar[8:]))
+	size := int(binary.BigEndian.Uint16(fvar[10:]))
+	if size < 20 {
+		return axes
+	}
+
+	fixed := func(b []byte) float64 {
+		return float64(int32(binary.BigEndian.Uint32(b))) / 65536
+	}
+
+	for i := 0; i < count; i++ {
+		record := offset + i*size
+		if record+20 > len(fvar) {
+			break
+		}
+		r := fvar[record:]
+		axes = append(axes, Axis{
+			Tag:     string(r[:4]),
+			Min:     fixed(r[4:]),
+			Default: fixed(r[8:]),
+			Max:     fixed(r[12:]),
+			Name:    names[int(binary.BigEndian.Uint16(r[18:]))],
+		})
+	}
+
+	return axes
+}
+
+// category returns the category of the PANOSE classification, monospace
+// fonts are found with the post table instead
+func category(panose []byte) string {
+	switch panose[0] {
+	case 2:
+		// Latin text, the serif style tells serif and sans serif fonts apart
+		if style := panose[1]; style >= 11 && style <= 15 {
+			return CategorySansSerif
+		} else if style >= 2 {
+			return CategorySerif
+		}
+	case 3:
+		return CategoryHandwriting
+	case 4:
+		return CategoryDisplay
+	}
+	return ""
+}
@@ -0,0 +1,384 @@
+package fontinfo
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestParse(t *testing.T) {
+	regular := &Info{
+		Format:         FormatTrueType,
+		Family:         "Go",
+		Subfamily:      "Regular",
+		FullName:       "Go Regular",
+		PostscriptName: "GoRegular",
+		Weight:         400,
+		Width:          5,
+		Category:       CategorySansSerif,
+		Axes:           []Axis{},
+		Subsets:        []string{"latin", "latin-ext", "greek", "cyrillic"},
+		Embedding:      EmbeddingInstallable,
+	}
+
+	testcases := []struct {
+		name string
+		data []byte
+		want *Info
+	}{
+		{"truetype", goregular.TTF, regular},
+		{"woff", toWOFF(t, goregular.TTF), withFormat(regular, FormatWOFF)},
+		{"woff2", toWOFF2(t, goregular.TTF), withFormat(regular, FormatWOFF2)},
+		{"italic", goitalic.TTF, &Info{
+			Format:         FormatTrueType,
+			Family:         "Go",
+			Subfamily:      "Italic",
+			FullName:       "Go Italic",
+			PostscriptName: "Go-Italic",
+			Weight:         400,
+			Width:          5,
+			Italic:         true,
+			Category:       CategorySansSerif,
+			Axes:           []Axis{},
+			Subsets:        []string{"latin", "latin-ext", "greek", "cyrillic"},
+			Embedding:      EmbeddingInstallable,
+		}},
+		{"monospace", gomono.TTF, &Info{
+			Format:         FormatTrueType,
+			Family:         "Go Mono",
+			Subfamily:      "Regular",
+			FullName:       "Go Mono",
+			PostscriptName: "GoMono",
+			Weight:         400,
+			Width:          5,
+			Category:       CategoryMonospace,
+			Axes:           []Axis{},
+			Subsets:        []string{"latin", "latin-ext", "greek", "cyrillic"},
+			Embedding:      EmbeddingInstallable,
+		}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			info, err := Parse(tc.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if info.NumGlyphs == 0 || info.CodePoints == 0 || info.Version == "" {
+				t.Errorf("missing glyph coverage or version: %+v", info)
+			}
+			// The counts and the version change with each release of the fonts
+			info.NumGlyphs, info.CodePoints, info.Version, info.Designer = 0, 0, "", ""
+			info.License, info.LicenseURL = "", ""
+
+			if !reflect.DeepEqual(info, tc.want) {
+				t.Errorf("mismatched info:\ngot: %+v\nwant: %+v", info, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseVariable(t *testing.T) {
+	tables := readAllTables(t, goregular.TTF)
+
+	// Typographic names, a variable weight axis and a restricted license
+	tables["name"] = nameTable(map[int]string{
+		1:   "Go Light",
+		2:   "Regular",
+		4:   "Go Light",
+		6:   "GoLight",
+		16:  "Go",
+		17:  "Light",
+		256: "Weight",
+	})
+	fvar := make([]byte, 16+20)
+	binary.BigEndian.PutUint16(fvar[0:], 1)
+	binary.BigEndian.PutUint16(fvar[4:], 16)
+	binary.BigEndian.PutUint16(fvar[8:], 1)
+	binary.BigEndian.PutUint16(fvar[10:], 20)
+	copy(fvar[16:], "wght")
+	binary.BigEndian.PutUint32(fvar[20:], 100<<16)
+	binary.BigEndian.PutUint32(fvar[24:], 300<<16)
+	binary.BigEndian.PutUint32(fvar[28:], 900<<16)
+	binary.BigEndian.PutUint16(fvar[34:], 256)
+	tables["fvar"] = fvar
+
+	os2 := append([]byte{}, tables["OS/2"]...)
+	binary.BigEndian.PutUint16(os2[4:], 300)
+	binary.BigEndian.PutUint16(os2[8:], 0x2|0x100)
+	tables["OS/2"] = os2
+
+	for _, data := range [][]byte{buildSFNT(tables), toWOFF2(t, buildSFNT(tables))} {
+		info, err := Parse(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if info.Family != "Go" || info.Subfamily != "Light" || info.FullName != "Go Light" || info.PostscriptName != "GoLight" {
+			t.Errorf("typographic names weren't preferred: %+v", info)
+		}
+		if info.Weight != 300 {
+			t.Errorf("got weight %d, want 300", info.Weight)
+		}
+		want := []Axis{{Tag: "wght", Name: "Weight", Min: 100, Default: 300, Max: 900}}
+		if !reflect.DeepEqual(info.Axes, want) {
+			t.Errorf("mismatched axes:\ngot: %+v\nwant: %+v", info.Axes, want)
+		}
+		if info.Embedding != EmbeddingRestricted || !info.NoSubsetting || info.BitmapOnly {
+			t.Errorf("mismatched embedding: %s, no subsetting %t, bitmap only %t", info.Embedding, info.NoSubsetting, info.BitmapOnly)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	woff := toWOFF(t, goregular.TTF)
+	// The declared size of the first table is larger than the limit
+	binary.BigEndian.PutUint32(woff[44+12:], MaxSize+1)
+
+	testcases := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"unknown format", []byte("%PDF-1.7")},
+		{"truncated", goregular.TTF[:100]},
+		{"truncated woff2", toWOFF2(t, goregular.TTF)[:200]},
+		{"large woff table", woff},
+		{"no name table", buildSFNT(map[string][]byte{"head": make([]byte, 54)})},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Parse(tc.data); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestReadBase128(t *testing.T) {
+	testcases := []struct {
+		data  []byte
+		value uint32
+		n     int
+		err   bool
+	}{
+		{[]byte{0x3f}, 63, 1, false},
+		{[]byte{0x81, 0x00}, 128, 2, false},
+		{[]byte{0x8f, 0xff, 0xff, 0xff, 0x7f}, 0xffffffff, 5, false},
+		{[]byte{0x80, 0x01}, 0, 0, true},
+		{[]byte{0x90, 0x80, 0x80, 0x80, 0x00}, 0, 0, true},
+		{[]byte{0x81, 0x81, 0x81, 0x81, 0x81, 0x01}, 0, 0, true},
+		{[]byte{0x81}, 0, 0, true},
+	}
+
+	for _, tc := range testcases {
+		value, n, err := readBase128(tc.data)
+		if tc.err {
+			if err == nil {
+				t.Errorf("% x: expected error", tc.data)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("% x: %s", tc.data, err)
+			continue
+		}
+		if value != tc.value || n != tc.n {
+			t.Errorf("% x: got %d in %d bytes, want %d in %d bytes", tc.data, value, n, tc.value, tc.n)
+		}
+	}
+}
+
+func withFormat(info *Info, format Format) *Info {
+	i := *info
+	i.Format = format
+	return &i
+}
+
+// readAllTables returns every table of the TrueType font
+func readAllTables(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	tables := map[string][]byte{}
+	for i := 0; i < numTables; i++ {
+		record := data[12+i*16:]
+		offset := binary.BigEndian.Uint32(record[8:])
+		length := binary.BigEndian.Uint32(record[12:])
+		tables[string(record[:4])] = data[offset : offset+length]
+	}
+
+	return tables
+}
+
+func sortedTags(tables map[string][]byte) []string {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// buildSFNT returns a TrueType font with the tables, checksums are left
+// empty
+func buildSFNT(tables map[string][]byte) []byte {
+	tags := sortedTags(tables)
+
+	header := make([]byte, 12+len(tags)*16)
+	binary.BigEndian.PutUint32(header, 0x00010000)
+	binary.BigEndian.PutUint16(header[4:], uint16(len(tags)))
+
+	body := []byte{}
+	for i, tag := range tags {
+		record := header[12+i*16:]
+		copy(record, tag)
+		binary.BigEndian.PutUint32(record[8:], uint32(len(header)+len(body)))
+		binary.BigEndian.PutUint32(record[12:], uint32(len(tables[tag])))
+		body = append(body, tables[tag]...)
+		for len(body)%4 != 0 {
+			body = append(body, 0)
+		}
+	}
+
+	return append(header, body...)
+}
+
+// toWOFF encodes the TrueType font as a WOFF font
+func toWOFF(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	tables := readAllTables(t, data)
+	tags := sortedTags(tables)
+
+	header := make([]byte, 44+len(tags)*20)
+	copy(header, "wOFF")
+	binary.BigEndian.PutUint32(header[4:], 0x00010000)
+	binary.BigEndian.PutUint16(header[12:], uint16(len(tags)))
+
+	body := []byte{}
+	for i, tag := range tags {
+		compressed := &bytes.Buffer{}
+		zw := zlib.NewWriter(compressed)
+		if _, err := zw.Write(tables[tag]); err != nil {
+			t.Fatal(err)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Tables that don't get smaller are stored as is
+		table := compressed.Bytes()
+		if len(table) >= len(tables[tag]) {
+			table = tables[tag]
+		}
+
+		entry := header[44+i*20:]
+		copy(entry, tag)
+		binary.BigEndian.PutUint32(entry[4:], uint32(len(header)+len(body)))
+		binary.BigEndian.PutUint32(entry[8:], uint32(len(table)))
+		binary.BigEndian.PutUint32(entry[12:], uint32(len(tables[tag])))
+		body = append(body, table...)
+		for len(body)%4 != 0 {
+			body = append(body, 0)
+		}
+	}
+	binary.BigEndian.PutUint32(header[8:], uint32(len(header)+len(body)))
+
+	return append(header, body...)
+}
+
+// toWOFF2 encodes the TrueType font as a WOFF2 font without transforming its
+// tables, every tag is written in full
+func toWOFF2(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	tables := readAllTables(t, data)
+	tags := sortedTags(tables)
+
+	directory := []byte{}
+	stream := []byte{}
+	for _, tag := range tags {
+		flags := byte(0x3f)
+		// The null transform of glyf and loca is version 3
+		if tag == "glyf" || tag == "loca" {
+			flags |= 3 << 6
+		}
+		directory = append(directory, flags)
+		directory = append(directory, tag...)
+		directory = append(directory, base128(uint32(len(tables[tag])))...)
+		stream = append(stream, tables[tag]...)
+	}
+
+	compressed := &bytes.Buffer{}
+	bw := brotli.NewWriter(compressed)
+	if _, err := bw.Write(stream); err != nil {
+		t.Fatal(err)
+	}
+	if err := bw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	header := make([]byte, 48)
+	copy(header, "wOF2")
+	binary.BigEndian.PutUint32(header[4:], 0x00010000)
+	binary.BigEndian.PutUint16(header[12:], uint16(len(tags)))
+	binary.BigEndian.PutUint32(header[16:], uint32(len(data)))
+	binary.BigEndian.PutUint32(header[20:], uint32(compressed.Len()))
+
+	out := append(header, directory...)
+	out = append(out, compressed.Bytes()...)
+	binary.BigEndian.PutUint32(out[8:], uint32(len(out)))
+
+	return out
+}
+
+func base128(v uint32) []byte {
+	out := []byte{byte(v & 0x7f)}
+	for v >>= 7; v != 0; v >>= 7 {
+		out = append([]byte{byte(v&0x7f) | 0x80}, out...)
+	}
+	return out
+}
+
+// nameTable returns a name table with Windows English records of the names
+func nameTable(names map[int]string) []byte {
+	ids := make([]int, 0, len(names))
+	for id := range names {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	records := make([]byte, 6+len(ids)*12)
+	binary.BigEndian.PutUint16(records[2:], uint16(len(ids)))
+	binary.BigEndian.PutUint16(records[4:], uint16(len(records)))
+
+	storage := []byte{}
+	for i, id := range ids {
+		encoded := []byte{}
+		for _, r := range names[id] {
+			encoded = append(encoded, byte(r>>8), byte(r))
+		}
+
+		record := records[6+i*12:]
+		binary.BigEndian.PutUint16(record[0:], 3)
+		binary.BigEndian.PutUint16(record[2:], 1)
+		binary.BigEndian.PutUint16(record[4:], 0x409)
+		binary.BigEndian.PutUint16(record[6:], uint16(id))
+		binary.BigEndian.PutUint16(record[8:], uint16(len(encoded)))
+		binary.BigEndian.PutUint16(record[10:], uint16(len(storage)))
+		storage = append(storage, encoded...)
+	}
+
+	return append(records, storage...)
+}
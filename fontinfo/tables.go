@@ -0,0 +1,277 @@
+package fontinfo
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/andybalholm/brotli"
+)
+
+var errTruncated = errors.New("fontinfo: truncated font")
+
+// metadataTables are the tables read by Parse, the other tables of WOFF
+// fonts aren't decompressed
+var metadataTables = map[string]bool{
+	"name": true,
+	"OS/2": true,
+	"head": true,
+	"post": true,
+	"maxp": true,
+	"fvar": true,
+	"cmap": true,
+}
+
+// woff2Tags are the known tags of the WOFF2 table directory, in the order of
+// their index
+var woff2Tags = [63]string{
+	"cmap", "head", "hhea", "hmtx", "maxp", "name", "OS/2", "post", "cvt ",
+	"fpgm", "glyf", "loca", "prep", "CFF ", "VORG", "EBDT", "EBLC", "gasp",
+	"hdmx", "kern", "LTSH", "PCLT", "VDMX", "vhea", "vmtx", "BASE", "GDEF",
+	"GPOS", "GSUB", "EBSC", "JSTF", "MATH", "CBDT", "CBLC", "COLR", "CPAL",
+	"SVG ", "sbix", "acnt", "avar", "bdat", "bloc", "bsln", "cvar", "fdsc",
+	"feat", "fmtx", "fvar", "gvar", "hsty", "just", "lcar", "mort", "morx",
+	"opbd", "prop", "trak", "Zapf", "Silf", "Glat", "Gloc", "Feat", "Sill",
+}
+
+// readTables returns the format of the font and its metadata tables. The
+// first font of collections is read
+func readTables(data []byte) (Format, map[string][]byte, error) {
+	if len(data) < 4 {
+		return "", nil, errTruncated
+	}
+
+	switch string(data[:4]) {
+	case "\x00\x01\x00\x00", "true":
+		tables, err := readSFNT(data, 0)
+		return FormatTrueType, tables, err
+	case "OTTO":
+		tables, err := readSFNT(data, 0)
+		return FormatOpenType, tables, err
+	case "ttcf":
+		if len(data) < 16 {
+			return "", nil, errTruncated
+		}
+		if binary.BigEndian.Uint32(data[8:]) == 0 {
+			return "", nil, errors.New("fontinfo: empty font collection")
+		}
+		offset := int(binary.BigEndian.Uint32(data[12:]))
+		if offset+4 > len(data) {
+			return "", nil, errTruncated
+		}
+		format := FormatTrueType
+		if string(data[offset:offset+4]) == "OTTO" {
+			format = FormatOpenType
+		}
+		tables, err := readSFNT(data, offset)
+		return format, tables, err
+	case "wOFF":
+		tables, err := readWOFF(data)
+		return FormatWOFF, tables, err
+	case "wOF2":
+		tables, err := readWOFF2(data)
+		return FormatWOFF2, tables, err
+	}
+
+	return "", nil, errors.New("fontinfo: unsupported font format")
+}
+
+// readSFNT reads the table directory at offset, table offsets are relative
+// to the start of data
+func readSFNT(data []byte, offset int) (map[string][]byte, error) {
+	if offset+12 > len(data) {
+		return nil, errTruncated
+	}
+
+	numTables := int(binary.BigEndian.Uint16(data[offset+4:]))
+	if offset+12+numTables*16 > len(data) {
+		return nil, errTruncated
+	}
+
+	tables := map[string][]byte{}
+	for i := 0; i < numTables; i++ {
+		record := data[offset+12+i*16:]
+		tag := string(record[:4])
+		if !metadataTables[tag] {
+			continue
+		}
+
+		start := int(binary.BigEndian.Uint32(record[8:]))
+		length := int(binary.BigEndian.Uint32(record[12:]))
+		if start < 0 || length < 0 || start+length > len(data) {
+			return nil, fmt.Errorf("fontinfo: table '%s' is out of bounds", tag)
+		}
+		tables[tag] = data[start : start+length]
+	}
+
+	return tables, nil
+}
+
+func readWOFF(data []byte) (map[string][]byte, error) {
+	const headerSize, entrySize = 44, 20
+	if len(data) < headerSize {
+		return nil, errTruncated
+	}
+
+	numTables := int(binary.BigEndian.Uint16(data[12:]))
+	if headerSize+numTables*entrySize > len(data) {
+		return nil, errTruncated
+	}
+
+	tables := map[string][]byte{}
+	for i := 0; i < numTables; i++ {
+		entry := data[headerSize+i*entrySize:]
+		tag := string(entry[:4])
+		if !metadataTables[tag] {
+			continue
+		}
+
+		offset := int(binary.BigEndian.Uint32(entry[4:]))
+		compLength := int(binary.BigEndian.Uint32(entry[8:]))
+		origLength := int(binary.BigEndian.Uint32(entry[12:]))
+		if offset < 0 || compLength < 0 || offset+compLength > len(data) {
+			return nil, fmt.Errorf("fontinfo: table '%s' is out of bounds", tag)
+		}
+		if compLength > origLength {
+			return nil, fmt.Errorf("fontinfo: table '%s' is larger compressed", tag)
+		}
+		if origLength > MaxSize {
+			return nil, fmt.Errorf("fontinfo: table '%s' is larger than %d bytes", tag, MaxSize)
+		}
+
+		table := data[offset : offset+compLength]
+		if compLength < origLength {
+			zr, err := zlib.NewReader(bytes.NewReader(table))
+			if err != nil {
+				return nil, fmt.Errorf("fontinfo: table '%s': %s", tag, err)
+			}
+			table = make([]byte, origLength)
+			if _, err := io.ReadFull(zr, table); err != nil {
+				return nil, fmt.Errorf("fontinfo: table '%s': %s", tag, err)
+			}
+		}
+		tables[tag] = table
+	}
+
+	return tables, nil
+}
+
+func readWOFF2(data []byte) (map[string][]byte, error) {
+	const headerSize = 48
+	if len(data) < headerSize {
+		return nil, errTruncated
+	}
+
+	if string(data[4:8]) == "ttcf" {
+		return nil, errors.New("fontinfo: WOFF2 font collections aren't supported")
+	}
+
+	numTables := int(binary.BigEndian.Uint16(data[12:]))
+	sfntSize := binary.BigEndian.Uint32(data[16:])
+	compressedSize := int(binary.BigEndian.Uint32(data[20:]))
+	if sfntSize > MaxSize {
+		return nil, fmt.Errorf("fontinfo: font is larger than %d bytes", MaxSize)
+	}
+
+	type entry struct {
+		tag    string
+		length int
+	}
+
+	// Tables are stored one after the other in the decompressed stream,
+	// transformed tables have the size of their transformed data
+	entries := make([]entry, 0, numTables)
+	pos := headerSize
+	for i := 0; i < numTables; i++ {
+		if pos >= len(data) {
+			return nil, errTruncated
+		}
+		flags := data[pos]
+		pos++
+
+		var tag string
+		if index := flags & 0x3f; index == 0x3f {
+			if pos+4 > len(data) {
+				return nil, errTruncated
+			}
+			tag = string(data[pos : pos+4])
+			pos += 4
+		} else {
+			tag = woff2Tags[index]
+		}
+
+		length, n, err := readBase128(data[pos:])
+		if err != nil {
+			return nil, err
+		}
+		pos += n
+
+		version := flags >> 6
+		transformed := version != 0
+		if tag == "glyf" || tag == "loca" {
+			transformed = version != 3
+		}
+		if transformed {
+			length, n, err = readBase128(data[pos:])
+			if err != nil {
+				return nil, err
+			}
+			pos += n
+		}
+
+		entries = append(entries, entry{tag, int(length)})
+	}
+
+	if compressedSize < 0 || pos+compressedSize > len(data) {
+		return nil, errTruncated
+	}
+
+	stream, err := io.ReadAll(io.LimitReader(brotli.NewReader(bytes.NewReader(data[pos:pos+compressedSize])), MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("fontinfo: decompressing: %s", err)
+	}
+	if len(stream) > MaxSize {
+		return nil, fmt.Errorf("fontinfo: font is larger than %d bytes", MaxSize)
+	}
+
+	tables := map[string][]byte{}
+	offset := 0
+	for _, e := range entries {
+		if offset+e.length > len(stream) {
+			return nil, fmt.Errorf("fontinfo: table '%s' is out of bounds", e.tag)
+		}
+		if metadataTables[e.tag] {
+			tables[e.tag] = stream[offset : offset+e.length]
+		}
+		offset += e.length
+	}
+
+	return tables, nil
+}
+
+// readBase128 reads a WOFF2 UIntBase128 and returns it with the number of
+// bytes read
+func readBase128(data []byte) (uint32, int, error) {
+	var value uint32
+	for i := 0; i < 5; i++ {
+		if i >= len(data) {
+			return 0, 0, errTruncated
+		}
+		b := data[i]
+		if i == 0 && b == 0x80 {
+			return 0, 0, errors.New("fontinfo: invalid UIntBase128 with leading zeros")
+		}
+		if value&0xfe000000 != 0 {
+			return 0, 0, errors.New("fontinfo: UIntBase128 overflows")
+		}
+		value = value<<7 | uint32(b&0x7f)
+		if b&0x80 == 0 {
+			return value, i + 1, nil
+		}
+	}
+
+	return 0, 0, errors.New("fontinfo: UIntBase128 is longer than 5 bytes")
+}
@@ -1,7 +1,9 @@
 package http
 
 import (
+	"crypto/subtle"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/echovl/orderflo-dev/assign"
@@ -46,6 +48,21 @@ func (s *Server) requireUserSession(c *fiber.Ctx) error {
 	return c.Next()
 }
 
+// requireMetricsToken only lets the operators holding the metrics token
+// through, the metrics aren't scoped to a company so sessions aren't enough
+func (s *Server) requireMetricsToken(c *fiber.Ctx) error {
+	if s.metricsToken == "" {
+		return errors.NotFound("metrics are disabled")
+	}
+
+	token := strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.metricsToken)) != 1 {
+		return errors.Authentication("invalid metrics token")
+	}
+
+	return c.Next()
+}
+
 func (s *Server) requireCustomerSession(c *fiber.Ctx) error {
 	session, err := s.getSession(c)
 	if err != nil {
@@ -21,6 +21,10 @@ func (s *Server) handleCreateComponent(c *fiber.Ctx) error {
 		Component *layerhub.Component `json:"component"`
 	}
 
+	if err := layerhub.ValidateDesignJSON(c.Body()); err != nil {
+		return err
+	}
+
 	var req request
 	if err := s.requestParser(c, &req); err != nil {
 		return errors.E(errors.KindValidation, err)
@@ -56,6 +60,10 @@ func (s *Server) handleUpdateComponent(c *fiber.Ctx) error {
 		Component *layerhub.Component `json:"component"`
 	}
 
+	if err := layerhub.ValidateDesignJSON(c.Body()); err != nil {
+		return err
+	}
+
 	var req request
 	if err := s.requestParser(c, &req); err != nil {
 		return errors.E(errors.KindValidation, err)
@@ -119,14 +127,15 @@ func (s *Server) handleGetComponent(c *fiber.Ctx) error {
 
 func (s *Server) handleListComponent(c *fiber.Ctx) error {
 	type request struct {
+		listParams
 		CustomerID string `query:"customer_id"`
-		Limit      int    `query:"limit"`
-		Offset     int    `query:"offset"`
+		FolderID   string `query:"folder_id"`
 	}
 
 	type response struct {
 		Components []layerhub.Component `json:"components"`
 		Total      int                  `json:"total"`
+		NextCursor string               `json:"next_cursor,omitempty"`
 	}
 
 	var req request
@@ -138,24 +147,23 @@ func (s *Server) handleListComponent(c *fiber.Ctx) error {
 	filter := &layerhub.Filter{
 		OptionalCustomerID: req.CustomerID,
 		OptionalCompanyID:  session.Company.ID,
-		Limit:              req.Limit,
-		Offset:             req.Offset,
+		FolderID:           req.FolderID,
 	}
 
 	if session.Customer != nil {
 		filter.OptionalCustomerID = session.Customer.ID
 	}
 
-	components, count, err := s.Core.FindComponents(c.Context(), &layerhub.Filter{
-		OptionalCompanyID: session.Company.ID,
-		Limit:             req.Limit,
-		Offset:            req.Offset,
-	})
+	if err := req.apply(filter, designFields...); err != nil {
+		return err
+	}
+
+	components, count, err := s.Core.FindComponents(c.Context(), filter)
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(response{components, count})
+	return c.JSON(response{components, count, layerhub.NextCursor(filter, components)})
 }
 
 func (s *Server) handleDeleteComponent(c *fiber.Ctx) error {
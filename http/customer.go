@@ -95,13 +95,13 @@ func (s *Server) handleGetCustomer(c *fiber.Ctx) error {
 
 func (s *Server) handleListCustomers(c *fiber.Ctx) error {
 	type request struct {
-		Limit  int `query:"limit"`
-		Offset int `query:"offset"`
+		listParams
 	}
 
 	type response struct {
-		Customers []layerhub.Customer `json:"customers"`
-		Total     int                 `json:"total"`
+		Customers  []layerhub.Customer `json:"customers"`
+		Total      int                 `json:"total"`
+		NextCursor string              `json:"next_cursor,omitempty"`
 	}
 
 	var req request
@@ -110,21 +110,26 @@ func (s *Server) handleListCustomers(c *fiber.Ctx) error {
 	}
 
 	session, _ := s.getSession(c)
-	customers, count, err := s.Core.FindCustomers(c.Context(), &layerhub.Filter{
+	filter := &layerhub.Filter{
 		CompanyID: session.Company.ID,
-		Limit:     req.Limit,
-		Offset:    req.Offset,
-	})
+	}
+
+	if err := req.apply(filter, layerhub.SortCreatedAt, layerhub.SortUpdatedAt); err != nil {
+		return err
+	}
+
+	customers, count, err := s.Core.FindCustomers(c.Context(), filter)
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(response{Customers: customers, Total: count})
+	return c.JSON(response{Customers: customers, Total: count, NextCursor: layerhub.NextCursor(filter, customers)})
 }
 
 func (s *Server) handleDeleteCustomer(c *fiber.Ctx) error {
 	type response struct {
-		Customer *layerhub.Customer `json:"customer"`
+		Customer *layerhub.Customer  `json:"customer"`
+		Job      *layerhub.TenantJob `json:"job"`
 	}
 
 	session, _ := s.getSession(c)
@@ -142,10 +147,10 @@ func (s *Server) handleDeleteCustomer(c *fiber.Ctx) error {
 		return errors.NotFound(fmt.Sprintf("customer '%s' not found", id))
 	}
 
-	err = s.Core.DeleteCustomer(c.Context(), id)
+	job, err := s.Core.DeleteCustomer(c.Context(), customer)
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(response{customer})
+	return c.JSON(response{customer, job})
 }
@@ -0,0 +1,198 @@
+package http
+
+import (
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+	"github.com/gofiber/fiber/v2"
+)
+
+// sessionFolder returns the folder of the request if it belongs to the session
+func (s *Server) sessionFolder(c *fiber.Ctx) (*layerhub.Folder, error) {
+	session, _ := s.getSession(c)
+	folder, err := s.Core.GetFolder(c.Context(), c.Params("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	if folder.CompanyID != session.Company.ID {
+		return nil, errors.Authorization(folder.ID)
+	}
+
+	if session.Customer != nil && folder.CustomerID != session.Customer.ID {
+		return nil, errors.Authorization(folder.ID)
+	}
+
+	return folder, nil
+}
+
+func (s *Server) handleListFolders(c *fiber.Ctx) error {
+	type request struct {
+		listParams
+		ParentID string `query:"parent_id"`
+	}
+
+	type response struct {
+		Folders    []layerhub.Folder `json:"folders"`
+		Total      int               `json:"total"`
+		NextCursor string            `json:"next_cursor,omitempty"`
+	}
+
+	var req request
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	filter := &layerhub.Filter{
+		CompanyID: session.Company.ID,
+		FolderID:  req.ParentID,
+	}
+
+	if session.Customer != nil {
+		filter.CustomerID = session.Customer.ID
+	}
+
+	if err := req.apply(filter, designFields...); err != nil {
+		return err
+	}
+
+	folders, count, err := s.Core.FindFolders(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{folders, count, layerhub.NextCursor(filter, folders)})
+}
+
+func (s *Server) handleGetFolder(c *fiber.Ctx) error {
+	type response struct {
+		Folder *layerhub.Folder `json:"folder"`
+	}
+
+	folder, err := s.sessionFolder(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{folder})
+}
+
+func (s *Server) handleCreateFolder(c *fiber.Ctx) error {
+	type request struct {
+		Name     string `json:"name" validate:"required"`
+		ParentID string `json:"parent_id"`
+	}
+
+	type response struct {
+		Folder *layerhub.Folder `json:"folder"`
+	}
+
+	var req request
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	folder := layerhub.NewFolder()
+	folder.Name = req.Name
+	folder.ParentID = req.ParentID
+	folder.CompanyID = session.Company.ID
+
+	if session.Customer != nil {
+		folder.CustomerID = session.Customer.ID
+	}
+
+	err := s.Core.PutFolder(c.Context(), folder)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{folder})
+}
+
+// handleUpdateFolder renames the folder and moves it to another parent, an
+// empty parent_id moves the folder to the top level
+func (s *Server) handleUpdateFolder(c *fiber.Ctx) error {
+	type request struct {
+		Name     *string `json:"name"`
+		ParentID *string `json:"parent_id"`
+	}
+
+	type response struct {
+		Folder *layerhub.Folder `json:"folder"`
+	}
+
+	var req request
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	folder, err := s.sessionFolder(c)
+	if err != nil {
+		return err
+	}
+
+	if req.Name != nil {
+		if err := s.Core.RenameFolder(c.Context(), folder, *req.Name); err != nil {
+			return err
+		}
+	}
+
+	if req.ParentID != nil && *req.ParentID != folder.ParentID {
+		if err := s.Core.MoveFolder(c.Context(), folder, *req.ParentID); err != nil {
+			return err
+		}
+	}
+
+	return c.JSON(response{folder})
+}
+
+// handleDeleteFolder deletes the folder with its subfolders and contents
+func (s *Server) handleDeleteFolder(c *fiber.Ctx) error {
+	type response struct {
+		Folder *layerhub.Folder `json:"folder"`
+	}
+
+	folder, err := s.sessionFolder(c)
+	if err != nil {
+		return err
+	}
+
+	err = s.Core.DeleteFolder(c.Context(), folder)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{folder})
+}
+
+// handleMoveToFolder moves templates, projects, components and uploads to the
+// folder, the root folder moves them out of any folder
+func (s *Server) handleMoveToFolder(c *fiber.Ctx) error {
+	type response struct {
+		Items *layerhub.FolderItems `json:"items"`
+	}
+
+	var req layerhub.FolderItems
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	folderID := c.Params("id")
+	if folderID == layerhub.RootFolder {
+		folderID = ""
+	}
+
+	session, _ := s.getSession(c)
+	customerID := ""
+	if session.Customer != nil {
+		customerID = session.Customer.ID
+	}
+
+	err := s.Core.MoveToFolder(c.Context(), session.Company.ID, customerID, folderID, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{&req})
+}
@@ -169,16 +169,16 @@ func (s *Server) handleGetFont(c *fiber.Ctx) error {
 
 func (s *Server) handleListFonts(c *fiber.Ctx) error {
 	type request struct {
+		listParams
 		CustomerID     string `query:"customer_id"`
 		PostscriptName string `query:"postscript_name"`
 		Enabled        *bool  `query:"enabled"`
-		Limit          int    `query:"limit"`
-		Offset         int    `query:"offset"`
 	}
 
 	type response struct {
-		Fonts []layerhub.Font `json:"fonts"`
-		Total int             `json:"total"`
+		Fonts      []layerhub.Font `json:"fonts"`
+		Total      int             `json:"total"`
+		NextCursor string          `json:"next_cursor,omitempty"`
 	}
 
 	var req request
@@ -192,20 +192,22 @@ func (s *Server) handleListFonts(c *fiber.Ctx) error {
 		OptionalCompanyID:  session.Company.ID,
 		PostscriptName:     req.PostscriptName,
 		EnabledFonts:       req.Enabled,
-		Limit:              req.Limit,
-		Offset:             req.Offset,
 	}
 
 	if session.Customer != nil {
 		filter.OptionalCustomerID = session.Customer.ID
 	}
 
+	if err := req.apply(filter); err != nil {
+		return err
+	}
+
 	fonts, count, err := s.Core.FindFonts(c.Context(), filter)
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(response{Fonts: fonts, Total: count})
+	return c.JSON(response{Fonts: fonts, Total: count, NextCursor: layerhub.NextCursor(filter, fonts)})
 }
 
 func (s *Server) handleDeleteFont(c *fiber.Ctx) error {
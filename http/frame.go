@@ -15,6 +15,8 @@ func (s *Server) handleCreateFrame(c *fiber.Ctx) error {
 		Width      float64            `json:"width"`
 		Height     float64            `json:"height"`
 		Unit       layerhub.FrameUnit `json:"unit" validate:"oneof=cm px in"`
+		Bleed      float64            `json:"bleed" validate:"min=0"`
+		SafeMargin float64            `json:"safe_margin" validate:"min=0"`
 		CustomerID string             `json:"customer_id"`
 		CompanyID  string             `json:"company_id"`
 	}
@@ -34,6 +36,8 @@ func (s *Server) handleCreateFrame(c *fiber.Ctx) error {
 	frame.Width = req.Width
 	frame.Height = req.Height
 	frame.Unit = req.Unit
+	frame.Bleed = req.Bleed
+	frame.SafeMargin = req.SafeMargin
 	frame.CompanyID = session.Company.ID
 
 	if session.Customer != nil {
@@ -50,10 +54,12 @@ func (s *Server) handleCreateFrame(c *fiber.Ctx) error {
 
 func (s *Server) handleUpdateFrame(c *fiber.Ctx) error {
 	type request struct {
-		Name   *string             `json:"name"`
-		Width  *float64            `json:"width"`
-		Height *float64            `json:"height"`
-		Unit   *layerhub.FrameUnit `json:"unit"`
+		Name       *string             `json:"name"`
+		Width      *float64            `json:"width"`
+		Height     *float64            `json:"height"`
+		Unit       *layerhub.FrameUnit `json:"unit"`
+		Bleed      *float64            `json:"bleed" validate:"omitempty,min=0"`
+		SafeMargin *float64            `json:"safe_margin" validate:"omitempty,min=0"`
 	}
 
 	type response struct {
@@ -119,14 +125,14 @@ func (s *Server) handleGetFrame(c *fiber.Ctx) error {
 
 func (s *Server) handleListFrames(c *fiber.Ctx) error {
 	type request struct {
+		listParams
 		CustomerID string `query:"customer_id"`
-		Limit      int    `query:"limit"`
-		Offset     int    `query:"offset"`
 	}
 
 	type response struct {
-		Frames []layerhub.Frame `json:"frames"`
-		Total  int              `json:"total"`
+		Frames     []layerhub.Frame `json:"frames"`
+		Total      int              `json:"total"`
+		NextCursor string           `json:"next_cursor,omitempty"`
 	}
 
 	var req request
@@ -139,20 +145,22 @@ func (s *Server) handleListFrames(c *fiber.Ctx) error {
 		OptionalCustomerID: req.CustomerID,
 		OptionalCompanyID:  session.Company.ID,
 		UsedInTemplate:     ptr.Bool(false),
-		Limit:              req.Limit,
-		Offset:             req.Offset,
 	}
 
 	if session.Customer != nil {
 		filter.OptionalCustomerID = session.Customer.ID
 	}
 
+	if err := req.apply(filter, layerhub.SortName); err != nil {
+		return err
+	}
+
 	frames, count, err := s.Core.FindFrames(c.Context(), filter)
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(response{frames, count})
+	return c.JSON(response{frames, count, layerhub.NextCursor(filter, frames)})
 }
 func (s *Server) handleDeleteFrame(c *fiber.Ctx) error {
 	type response struct {
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (s *Server) handlePublishTemplate(c *fiber.Ctx) error {
+	type request struct {
+		Public bool `json:"public"`
+	}
+
+	type response struct {
+		Template  *layerhub.Template        `json:"template"`
+		Preflight *layerhub.PreflightReport `json:"preflight"`
+	}
+
+	var req request
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	template, err := s.Core.GetTemplate(c.Context(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	if template.CompanyID != session.Company.ID {
+		return errors.Authorization(template.ID)
+	}
+
+	if session.Customer != nil && template.CustomerID != session.Customer.ID {
+		return errors.Authorization(template.ID)
+	}
+
+	report, err := s.Core.PublishTemplate(c.Context(), template, req.Public)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{template, report})
+}
+
+func (s *Server) handleUnpublishTemplate(c *fiber.Ctx) error {
+	type response struct {
+		Template *layerhub.Template `json:"template"`
+	}
+
+	session, _ := s.getSession(c)
+	template, err := s.Core.GetTemplate(c.Context(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	if template.CompanyID != session.Company.ID {
+		return errors.Authorization(template.ID)
+	}
+
+	if session.Customer != nil && template.CustomerID != session.Customer.ID {
+		return errors.Authorization(template.ID)
+	}
+
+	if err := s.Core.UnpublishTemplate(c.Context(), template); err != nil {
+		return err
+	}
+
+	return c.JSON(response{template})
+}
+
+// handleListGallery lists the published templates of the company and the
+// public templates of every company
+func (s *Server) handleListGallery(c *fiber.Ctx) error {
+	type request struct {
+		listParams
+		Tag         string             `query:"tag"`
+		Color       string             `query:"color"`
+		Orientation string             `query:"orientation"`
+		License     string             `query:"license"`
+		Width       float64            `query:"width"`
+		Height      float64            `query:"height"`
+		Unit        layerhub.FrameUnit `query:"unit"`
+	}
+
+	type response struct {
+		Templates  []layerhub.Template `json:"templates"`
+		Total      int                 `json:"total"`
+		NextCursor string              `json:"next_cursor,omitempty"`
+	}
+
+	var req request
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	filter := &layerhub.Filter{
+		Tag:         req.Tag,
+		Color:       req.Color,
+		Orientation: req.Orientation,
+		License:     req.License,
+		FrameWidth:  req.Width,
+		FrameHeight: req.Height,
+		FrameUnit:   req.Unit,
+	}
+
+	if err := req.apply(filter, designFields...); err != nil {
+		return err
+	}
+
+	templates, count, err := s.Core.FindGalleryTemplates(c.Context(), session.Company.ID, filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{templates, count, layerhub.NextCursor(filter, templates)})
+}
@@ -0,0 +1,11 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// handleDesignCacheStats returns the hit and miss counters of the design
+// cache, they count the designs of every company so only operators see them
+func (s *Server) handleDesignCacheStats(c *fiber.Ctx) error {
+	return c.JSON(s.Core.DesignCacheStats())
+}
@@ -0,0 +1,267 @@
+package http
+
+import (
+	"github.com/echovl/orderflo-dev/assign"
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (s *Server) handleCreateMockupTemplate(c *fiber.Ctx) error {
+	type request struct {
+		Name                 string                   `json:"name"`
+		PhotoURL             string                   `json:"photo_url" validate:"required,url"`
+		Placement            layerhub.MockupPlacement `json:"placement" validate:"required"`
+		ShadingURL           string                   `json:"shading_url" validate:"omitempty,url"`
+		DisplacementURL      string                   `json:"displacement_url" validate:"omitempty,url"`
+		DisplacementStrength float64                  `json:"displacement_strength" validate:"min=0"`
+		Public               bool                     `json:"public"`
+	}
+
+	type response struct {
+		MockupTemplate *layerhub.MockupTemplate `json:"mockup_template"`
+	}
+
+	var req request
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	mt := layerhub.NewMockupTemplate()
+	mt.Name = req.Name
+	mt.PhotoURL = req.PhotoURL
+	mt.Placement = req.Placement
+	mt.ShadingURL = req.ShadingURL
+	mt.DisplacementURL = req.DisplacementURL
+	mt.DisplacementStrength = req.DisplacementStrength
+	mt.Public = req.Public
+	mt.CompanyID = session.Company.ID
+
+	if session.Customer != nil {
+		mt.CustomerID = session.Customer.ID
+	}
+
+	err := s.Core.PutMockupTemplate(c.Context(), mt)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{mt})
+}
+
+func (s *Server) handleUpdateMockupTemplate(c *fiber.Ctx) error {
+	type request struct {
+		Name                 *string                   `json:"name"`
+		PhotoURL             *string                   `json:"photo_url" validate:"omitempty,url"`
+		Placement            *layerhub.MockupPlacement `json:"placement"`
+		ShadingURL           *string                   `json:"shading_url" validate:"omitempty,url"`
+		DisplacementURL      *string                   `json:"displacement_url" validate:"omitempty,url"`
+		DisplacementStrength *float64                  `json:"displacement_strength" validate:"omitempty,min=0"`
+		Public               *bool                     `json:"public"`
+	}
+
+	type response struct {
+		MockupTemplate *layerhub.MockupTemplate `json:"mockup_template"`
+	}
+
+	var req request
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	id := c.Params("id")
+	mt, err := s.Core.GetMockupTemplate(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	if mt.CompanyID != session.Company.ID {
+		return errors.Authorization(mt.ID)
+	}
+
+	if session.Customer != nil && mt.CustomerID != session.Customer.ID {
+		return errors.Authorization(mt.ID)
+	}
+
+	if err := assign.Structs(mt, req); err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	mt.UpdatedAt = layerhub.Now()
+
+	err = s.Core.PutMockupTemplate(c.Context(), mt)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{mt})
+}
+
+func (s *Server) handleGetMockupTemplate(c *fiber.Ctx) error {
+	type response struct {
+		MockupTemplate *layerhub.MockupTemplate `json:"mockup_template"`
+	}
+
+	session, _ := s.getSession(c)
+	mt, err := s.sessionMockupTemplate(c, session, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{mt})
+}
+
+func (s *Server) handleListMockupTemplates(c *fiber.Ctx) error {
+	type request struct {
+		listParams
+		CustomerID string `query:"customer_id"`
+	}
+
+	type response struct {
+		MockupTemplates []layerhub.MockupTemplate `json:"mockup_templates"`
+		Total           int                       `json:"total"`
+		NextCursor      string                    `json:"next_cursor,omitempty"`
+	}
+
+	var req request
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	filter := &layerhub.Filter{
+		OptionalCustomerID: req.CustomerID,
+		OptionalCompanyID:  session.Company.ID,
+	}
+
+	if session.Customer != nil {
+		filter.OptionalCustomerID = session.Customer.ID
+	}
+
+	if err := req.apply(filter, designFields...); err != nil {
+		return err
+	}
+
+	mts, count, err := s.Core.FindMockupTemplates(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{mts, count, layerhub.NextCursor(filter, mts)})
+}
+
+func (s *Server) handleDeleteMockupTemplate(c *fiber.Ctx) error {
+	type response struct {
+		MockupTemplate *layerhub.MockupTemplate `json:"mockup_template"`
+	}
+
+	session, _ := s.getSession(c)
+	id := c.Params("id")
+	mt, err := s.Core.GetMockupTemplate(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	if mt.CompanyID != session.Company.ID {
+		return errors.Authorization(mt.ID)
+	}
+
+	if session.Customer != nil && mt.CustomerID != session.Customer.ID {
+		return errors.Authorization(mt.ID)
+	}
+
+	err = s.Core.DeleteMockupTemplate(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{mt})
+}
+
+func (s *Server) handleGetTemplateMockup(c *fiber.Ctx) error {
+	type response struct {
+		Mockup *layerhub.Mockup `json:"mockup"`
+	}
+
+	session, _ := s.getSession(c)
+	template, err := s.Core.GetTemplate(c.Context(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	if !template.Public {
+		if template.CompanyID != session.Company.ID {
+			return errors.Authorization(template.ID)
+		}
+
+		if session.Customer != nil && template.CustomerID != session.Customer.ID {
+			return errors.Authorization(template.ID)
+		}
+	}
+
+	mt, err := s.sessionMockupTemplate(c, session, c.Params("mockup_id"))
+	if err != nil {
+		return err
+	}
+
+	mockup, err := s.Core.TemplateMockup(c.Context(), template, mt)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{mockup})
+}
+
+func (s *Server) handleGetProjectMockup(c *fiber.Ctx) error {
+	type response struct {
+		Mockup *layerhub.Mockup `json:"mockup"`
+	}
+
+	session, _ := s.getSession(c)
+	project, err := s.Core.GetProject(c.Context(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	if project.CompanyID != session.Company.ID {
+		return errors.Authorization(project.ID)
+	}
+
+	if session.Customer != nil && project.CustomerID != session.Customer.ID {
+		return errors.Authorization(project.ID)
+	}
+
+	mt, err := s.sessionMockupTemplate(c, session, c.Params("mockup_id"))
+	if err != nil {
+		return err
+	}
+
+	mockup, err := s.Core.ProjectMockup(c.Context(), project, mt)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{mockup})
+}
+
+// sessionMockupTemplate returns the mockup template if the session is allowed
+// to use it, company wide mockup templates are available to every customer
+func (s *Server) sessionMockupTemplate(c *fiber.Ctx, session *Session, id string) (*layerhub.MockupTemplate, error) {
+	mt, err := s.Core.GetMockupTemplate(c.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !mt.Public {
+		if mt.CompanyID != session.Company.ID {
+			return nil, errors.Authorization(mt.ID)
+		}
+
+		if session.Customer != nil && mt.CustomerID != "" && mt.CustomerID != session.Customer.ID {
+			return nil, errors.Authorization(mt.ID)
+		}
+	}
+
+	return mt, nil
+}
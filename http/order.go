@@ -0,0 +1,181 @@
+package http
+
+import (
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (s *Server) handleCreateOrder(c *fiber.Ctx) error {
+	type item struct {
+		ProjectID string               `json:"project_id" validate:"required"`
+		SKU       string               `json:"sku" validate:"required"`
+		Quantity  int                  `json:"quantity" validate:"omitempty,min=1"`
+		Format    layerhub.PrintFormat `json:"format" validate:"omitempty,oneof=pdf png"`
+		DPI       int                  `json:"dpi" validate:"omitempty,min=72,max=1200"`
+	}
+
+	type request struct {
+		CustomerID string `json:"customer_id"`
+		Items      []item `json:"items" validate:"required,min=1,dive"`
+		Submit     bool   `json:"submit"`
+	}
+
+	type response struct {
+		Order *layerhub.Order `json:"order"`
+	}
+
+	var req request
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	order := layerhub.NewOrder()
+	order.CompanyID = session.Company.ID
+	order.CustomerID = req.CustomerID
+
+	if session.Customer != nil {
+		order.CustomerID = session.Customer.ID
+	}
+
+	for _, it := range req.Items {
+		item := layerhub.NewOrderItem()
+		item.ProjectID = it.ProjectID
+		item.SKU = it.SKU
+		if it.Quantity != 0 {
+			item.Quantity = it.Quantity
+		}
+		if it.Format != "" {
+			item.Format = it.Format
+		}
+		if it.DPI != 0 {
+			item.DPI = it.DPI
+		}
+		order.Items = append(order.Items, item)
+	}
+
+	err := s.Core.PutOrder(c.Context(), order)
+	if err != nil {
+		return err
+	}
+
+	if req.Submit {
+		err := s.Core.SubmitOrder(c.Context(), order)
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.JSON(response{order})
+}
+
+func (s *Server) handleGetOrder(c *fiber.Ctx) error {
+	type response struct {
+		Order *layerhub.Order `json:"order"`
+	}
+
+	session, _ := s.getSession(c)
+	order, err := s.sessionOrder(c, session, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{order})
+}
+
+func (s *Server) handleListOrders(c *fiber.Ctx) error {
+	type request struct {
+		listParams
+		CustomerID string `query:"customer_id"`
+	}
+
+	type response struct {
+		Orders     []layerhub.Order `json:"orders"`
+		Total      int              `json:"total"`
+		NextCursor string           `json:"next_cursor,omitempty"`
+	}
+
+	var req request
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	filter := &layerhub.Filter{
+		CustomerID: req.CustomerID,
+		CompanyID:  session.Company.ID,
+	}
+
+	if session.Customer != nil {
+		filter.CustomerID = session.Customer.ID
+	}
+
+	if err := req.apply(filter, layerhub.SortCreatedAt, layerhub.SortUpdatedAt); err != nil {
+		return err
+	}
+
+	orders, count, err := s.Core.FindOrders(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{orders, count, layerhub.NextCursor(filter, orders)})
+}
+
+func (s *Server) handleSubmitOrder(c *fiber.Ctx) error {
+	type response struct {
+		Order *layerhub.Order `json:"order"`
+	}
+
+	session, _ := s.getSession(c)
+	order, err := s.sessionOrder(c, session, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	err = s.Core.SubmitOrder(c.Context(), order)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{order})
+}
+
+func (s *Server) handleRetryPrintFiles(c *fiber.Ctx) error {
+	type response struct {
+		Order *layerhub.Order `json:"order"`
+	}
+
+	session, _ := s.getSession(c)
+	order, err := s.sessionOrder(c, session, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	err = s.Core.RetryPrintFiles(c.Context(), order)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{order})
+}
+
+// sessionOrder returns the order if it belongs to the session's company and
+// customer
+func (s *Server) sessionOrder(c *fiber.Ctx, session *Session, id string) (*layerhub.Order, error) {
+	order, err := s.Core.GetOrder(c.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.CompanyID != session.Company.ID {
+		return nil, errors.Authorization(order.ID)
+	}
+
+	if session.Customer != nil && order.CustomerID != session.Customer.ID {
+		return nil, errors.Authorization(order.ID)
+	}
+
+	return order, nil
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+)
+
+// listParams are the sorting, filtering and pagination params accepted by
+// every list handler. Sort is a field name, prefixed with '-' for descending
+// order, and times are RFC 3339
+type listParams struct {
+	Limit         int      `query:"limit"`
+	Offset        int      `query:"offset"`
+	Cursor        string   `query:"cursor"`
+	Sort          string   `query:"sort"`
+	IDs           []string `query:"id"`
+	NamePrefix    string   `query:"name_prefix"`
+	CreatedAfter  string   `query:"created_after"`
+	CreatedBefore string   `query:"created_before"`
+	UpdatedAfter  string   `query:"updated_after"`
+	UpdatedBefore string   `query:"updated_before"`
+}
+
+// apply sets the params on the filter, fields are the ones the items can be
+// sorted and filtered by besides the id. The name_prefix param needs
+// layerhub.SortName and the time params need the sort field of their time
+func (p *listParams) apply(filter *layerhub.Filter, fields ...layerhub.SortField) error {
+	allowed := func(field layerhub.SortField) bool {
+		for _, f := range fields {
+			if f == field {
+				return true
+			}
+		}
+		return false
+	}
+
+	filter.Limit = p.Limit
+	filter.Offset = p.Offset
+	filter.IDs = p.IDs
+
+	if p.NamePrefix != "" && !allowed(layerhub.SortName) {
+		return errors.Validation("can't filter by 'name_prefix'")
+	}
+	filter.NamePrefix = p.NamePrefix
+
+	if p.Cursor != "" {
+		cursor, err := layerhub.DecodeCursor(p.Cursor)
+		if err != nil {
+			return err
+		}
+		filter.After = cursor
+	}
+
+	sortBy, err := layerhub.ParseSortField(strings.TrimPrefix(p.Sort, "-"))
+	if err != nil {
+		return err
+	}
+	if sortBy != "" && sortBy != layerhub.SortID && !allowed(sortBy) {
+		return errors.Validation(fmt.Sprintf("can't sort by '%s'", sortBy))
+	}
+	filter.SortBy = sortBy
+	filter.SortDesc = strings.HasPrefix(p.Sort, "-")
+
+	times := []struct {
+		name  string
+		field layerhub.SortField
+		value string
+		dst   *time.Time
+	}{
+		{"created_after", layerhub.SortCreatedAt, p.CreatedAfter, &filter.CreatedAfter},
+		{"created_before", layerhub.SortCreatedAt, p.CreatedBefore, &filter.CreatedBefore},
+		{"updated_after", layerhub.SortUpdatedAt, p.UpdatedAfter, &filter.UpdatedAfter},
+		{"updated_before", layerhub.SortUpdatedAt, p.UpdatedBefore, &filter.UpdatedBefore},
+	}
+	for _, t := range times {
+		if t.value == "" {
+			continue
+		}
+		if !allowed(t.field) {
+			return errors.Validation(fmt.Sprintf("can't filter by '%s'", t.name))
+		}
+		parsed, err := time.Parse(time.RFC3339, t.value)
+		if err != nil {
+			return errors.Validation(fmt.Sprintf("'%s' must be an RFC 3339 time", t.name))
+		}
+		*t.dst = parsed.UTC()
+	}
+
+	return nil
+}
+
+// designFields are the list fields of the items with a name and timestamps
+var designFields = []layerhub.SortField{layerhub.SortName, layerhub.SortCreatedAt, layerhub.SortUpdatedAt}
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (s *Server) handlePreflightTemplate(c *fiber.Ctx) error {
+	type response struct {
+		Preflight *layerhub.PreflightReport `json:"preflight"`
+	}
+
+	session, _ := s.getSession(c)
+	template, err := s.Core.GetTemplate(c.Context(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	if template.CompanyID != session.Company.ID {
+		return errors.Authorization(template.ID)
+	}
+
+	if session.Customer != nil && template.CustomerID != session.Customer.ID {
+		return errors.Authorization(template.ID)
+	}
+
+	report, err := s.Core.PreflightTemplate(c.Context(), template)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{report})
+}
+
+func (s *Server) handlePreflightProject(c *fiber.Ctx) error {
+	type response struct {
+		Preflight *layerhub.PreflightReport `json:"preflight"`
+	}
+
+	session, _ := s.getSession(c)
+	project, err := s.Core.GetProject(c.Context(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	if project.CompanyID != session.Company.ID {
+		return errors.Authorization(project.ID)
+	}
+
+	if session.Customer != nil && project.CustomerID != session.Customer.ID {
+		return errors.Authorization(project.ID)
+	}
+
+	report, err := s.Core.PreflightProject(c.Context(), project)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{report})
+}
@@ -11,14 +11,15 @@ import (
 
 func (s *Server) handleListProject(c *fiber.Ctx) error {
 	type request struct {
+		listParams
 		CustomerID string `query:"customer_id"`
-		Limit      int    `query:"limit"`
-		Offset     int    `query:"offset"`
+		FolderID   string `query:"folder_id"`
 	}
 
 	type response struct {
-		Projects []layerhub.Project `json:"projects"`
-		Total    int                `json:"total"`
+		Projects   []layerhub.Project `json:"projects"`
+		Total      int                `json:"total"`
+		NextCursor string             `json:"next_cursor,omitempty"`
 	}
 
 	var req request
@@ -30,20 +31,23 @@ func (s *Server) handleListProject(c *fiber.Ctx) error {
 	filter := &layerhub.Filter{
 		CustomerID: req.CustomerID,
 		CompanyID:  session.Company.ID,
-		Limit:      req.Limit,
-		Offset:     req.Offset,
+		FolderID:   req.FolderID,
 	}
 
 	if session.Customer != nil {
 		filter.CustomerID = session.Customer.ID
 	}
 
+	if err := req.apply(filter, designFields...); err != nil {
+		return err
+	}
+
 	projects, count, err := s.Core.FindProjects(c.Context(), filter)
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(response{projects, count})
+	return c.JSON(response{projects, count, layerhub.NextCursor(filter, projects)})
 }
 
 func (s *Server) handleGetProject(c *fiber.Ctx) error {
@@ -83,6 +87,10 @@ func (s *Server) handleCreateProject(c *fiber.Ctx) error {
 		Project *layerhub.Project `json:"project"`
 	}
 
+	if err := layerhub.ValidateDesignJSON(c.Body()); err != nil {
+		return err
+	}
+
 	var req request
 	if err := s.requestParser(c, &req); err != nil {
 		return errors.E(errors.KindValidation, err)
@@ -125,6 +133,10 @@ func (s *Server) handleUpdateProject(c *fiber.Ctx) error {
 		Project *layerhub.Project `json:"project"`
 	}
 
+	if err := layerhub.ValidateDesignJSON(c.Body()); err != nil {
+		return err
+	}
+
 	var req request
 	if err := s.requestParser(c, &req); err != nil {
 		return errors.E(errors.KindValidation, err)
@@ -0,0 +1,216 @@
+package http
+
+import (
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (s *Server) handleCreateProof(c *fiber.Ctx) error {
+	type response struct {
+		Proof *layerhub.Proof `json:"proof"`
+	}
+
+	session, _ := s.getSession(c)
+	project, err := s.Core.GetProject(c.Context(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	if project.CompanyID != session.Company.ID {
+		return errors.Authorization(project.ID)
+	}
+
+	userID := ""
+	if session.User != nil {
+		userID = session.User.ID
+	}
+
+	proof, err := s.Core.CreateProof(c.Context(), project, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{proof})
+}
+
+func (s *Server) handleListProofs(c *fiber.Ctx) error {
+	type response struct {
+		Proofs []layerhub.Proof `json:"proofs"`
+	}
+
+	session, _ := s.getSession(c)
+	project, err := s.Core.GetProject(c.Context(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	if project.CompanyID != session.Company.ID {
+		return errors.Authorization(project.ID)
+	}
+
+	if session.Customer != nil && project.CustomerID != session.Customer.ID {
+		return errors.Authorization(project.ID)
+	}
+
+	proofs, err := s.Core.FindProjectProofs(c.Context(), project.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{proofs})
+}
+
+func (s *Server) handleGetProof(c *fiber.Ctx) error {
+	type response struct {
+		Proof *layerhub.Proof `json:"proof"`
+	}
+
+	session, _ := s.getSession(c)
+	proof, err := s.sessionProof(c, session, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{proof})
+}
+
+func (s *Server) handleApproveProof(c *fiber.Ctx) error {
+	type request struct {
+		Comment string `json:"comment"`
+	}
+
+	type response struct {
+		Proof *layerhub.Proof `json:"proof"`
+	}
+
+	var req request
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	proof, err := s.reviewableProof(c, session, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	var comment *layerhub.ProofComment
+	if req.Comment != "" {
+		comment = sessionProofComment(session, req.Comment)
+	}
+
+	err = s.Core.ApproveProof(c.Context(), proof, comment)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{proof})
+}
+
+func (s *Server) handleRequestProofChanges(c *fiber.Ctx) error {
+	type request struct {
+		Comment string `json:"comment" validate:"required"`
+	}
+
+	type response struct {
+		Proof *layerhub.Proof `json:"proof"`
+	}
+
+	var req request
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	proof, err := s.reviewableProof(c, session, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	err = s.Core.RequestProofChanges(c.Context(), proof, sessionProofComment(session, req.Comment))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{proof})
+}
+
+func (s *Server) handleCreateProofComment(c *fiber.Ctx) error {
+	type request struct {
+		Body string `json:"body" validate:"required"`
+	}
+
+	type response struct {
+		Comment *layerhub.ProofComment `json:"comment"`
+	}
+
+	var req request
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	proof, err := s.sessionProof(c, session, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	comment := sessionProofComment(session, req.Body)
+	err = s.Core.AddProofComment(c.Context(), proof, comment)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{comment})
+}
+
+// sessionProof returns the proof if it belongs to the session's company and
+// customer
+func (s *Server) sessionProof(c *fiber.Ctx, session *Session, id string) (*layerhub.Proof, error) {
+	proof, err := s.Core.GetProof(c.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if proof.CompanyID != session.Company.ID {
+		return nil, errors.Authorization(proof.ID)
+	}
+
+	if session.Customer != nil && proof.CustomerID != session.Customer.ID {
+		return nil, errors.Authorization(proof.ID)
+	}
+
+	return proof, nil
+}
+
+// reviewableProof returns the proof if the session can review it, proofs of
+// customer projects are reviewed by the customer and proofs of company
+// projects by the company users
+func (s *Server) reviewableProof(c *fiber.Ctx, session *Session, id string) (*layerhub.Proof, error) {
+	proof, err := s.sessionProof(c, session, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Customer == nil && proof.CustomerID != "" {
+		return nil, errors.Authorization(proof.ID)
+	}
+
+	return proof, nil
+}
+
+// sessionProofComment returns a comment authored by the session's customer or
+// user
+func sessionProofComment(session *Session, body string) *layerhub.ProofComment {
+	comment := layerhub.NewProofComment()
+	comment.Body = body
+
+	if session.Customer != nil {
+		comment.CustomerID = session.Customer.ID
+	} else if session.User != nil {
+		comment.UserID = session.User.ID
+	}
+
+	return comment
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+	"github.com/gofiber/fiber/v2"
+)
+
+type resizeRequest struct {
+	FrameID  string                  `json:"frame_id"`
+	Frame    *layerhub.Frame         `json:"frame"`
+	Strategy layerhub.ResizeStrategy `json:"strategy"`
+}
+
+func (s *Server) handleResizeTemplate(c *fiber.Ctx) error {
+	type response struct {
+		Template layerhub.Design `json:"template"`
+	}
+
+	var req resizeRequest
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	template, err := s.Core.GetTemplate(c.Context(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	if template.CompanyID != session.Company.ID {
+		return errors.Authorization(template.ID)
+	}
+
+	if session.Customer != nil && template.CustomerID != session.Customer.ID {
+		return errors.Authorization(template.ID)
+	}
+
+	frame, err := s.resizeFrame(c, req)
+	if err != nil {
+		return err
+	}
+
+	resized, err := s.Core.ResizeDesign(c.Context(), template, frame, req.Strategy)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{resized})
+}
+
+func (s *Server) handleResizeProject(c *fiber.Ctx) error {
+	type response struct {
+		Project layerhub.Design `json:"project"`
+	}
+
+	var req resizeRequest
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	project, err := s.Core.GetProject(c.Context(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	if project.CompanyID != session.Company.ID {
+		return errors.Authorization(project.ID)
+	}
+
+	if session.Customer != nil && project.CustomerID != session.Customer.ID {
+		return errors.Authorization(project.ID)
+	}
+
+	frame, err := s.resizeFrame(c, req)
+	if err != nil {
+		return err
+	}
+
+	resized, err := s.Core.ResizeDesign(c.Context(), project, frame, req.Strategy)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{resized})
+}
+
+// resizeFrame returns the target frame of a resize, either a saved frame the
+// session can see or the dimensions sent in the request
+func (s *Server) resizeFrame(c *fiber.Ctx, req resizeRequest) (layerhub.Frame, error) {
+	if req.FrameID == "" {
+		if req.Frame == nil {
+			return layerhub.Frame{}, errors.Validation("frame_id or frame is required")
+		}
+		return *req.Frame, nil
+	}
+
+	session, _ := s.getSession(c)
+	frame, err := s.Core.GetFrame(c.Context(), req.FrameID)
+	if err != nil {
+		return layerhub.Frame{}, err
+	}
+
+	if !frame.Public {
+		if frame.CompanyID != session.Company.ID {
+			return layerhub.Frame{}, errors.Authorization(frame.ID)
+		}
+
+		if session.Customer != nil && frame.CustomerID != session.Customer.ID {
+			return layerhub.Frame{}, errors.Authorization(frame.ID)
+		}
+	}
+
+	return *frame, nil
+}
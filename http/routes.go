@@ -13,20 +13,39 @@ func (s *Server) initRoutes() {
 	editor := s.App.Group("/editor")
 	root := s.App.Group("/")
 
-	root.Get("/:id", s.handleRenderDesign)
 	root.Get("/health", s.handleCheckHealth)
+	root.Get("/metrics/design-cache", s.requireMetricsToken, s.handleDesignCacheStats)
+	root.Get("/:id", s.handleRenderDesign)
 
 	editor.Post("/auth/signup", s.handleCustomerSignUp)
 	editor.Post("/auth/signin", s.handleCustomerSignIn)
 
 	editor.Get("/customers/me", s.requireCustomerSession, s.handleCurrentCustomer)
 	editor.Put("/customers/:id", s.requireCustomerSession, s.handleUpdateCustomer)
+	editor.Delete("/customers/:id", s.requireCustomerSession, s.handleDeleteCustomer)
+	editor.Post("/customers/:id/export", s.requireCustomerSession, s.handleExportCustomer)
+
+	editor.Get("/jobs/:id", s.requireCustomerSession, s.handleGetTenantJob)
+	editor.Get("/jobs/:id/export", s.requireCustomerSession, s.handleDownloadTenantExport)
 
 	editor.Get("/projects", s.requireCustomerSession, s.handleListProject)
 	editor.Get("/projects/:id", s.requireCustomerSession, s.handleGetProject)
 	editor.Post("/projects", s.requireCustomerSession, s.handleCreateProject)
 	editor.Put("/projects/:id", s.requireCustomerSession, s.handleUpdateProject)
 	editor.Delete("/projects/:id", s.requireCustomerSession, s.handleDeleteProject)
+	editor.Get("/projects/:id/preflight", s.requireCustomerSession, s.handlePreflightProject)
+	editor.Post("/projects/:id/resize", s.requireCustomerSession, s.handleResizeProject)
+	editor.Get("/projects/:id/proofs", s.requireCustomerSession, s.handleListProofs)
+
+	editor.Get("/gallery", s.requireCustomerSession, s.handleListGallery)
+	editor.Get("/search/templates", s.requireCustomerSession, s.handleSearchTemplates)
+	editor.Get("/search/uploads", s.requireCustomerSession, s.handleSearchUploads)
+	editor.Post("/templates/:id/use", s.requireCustomerSession, s.handleUseTemplate)
+
+	editor.Get("/proofs/:id", s.requireCustomerSession, s.handleGetProof)
+	editor.Post("/proofs/:id/approve", s.requireCustomerSession, s.handleApproveProof)
+	editor.Post("/proofs/:id/request-changes", s.requireCustomerSession, s.handleRequestProofChanges)
+	editor.Post("/proofs/:id/comments", s.requireCustomerSession, s.handleCreateProofComment)
 
 	editor.Get("/fonts", s.requireCustomerSession, s.handleListFonts)
 	editor.Get("/fonts/:id", s.requireCustomerSession, s.handleGetFont)
@@ -36,10 +55,22 @@ func (s *Server) initRoutes() {
 	editor.Post("/fonts/enable", s.requireCustomerSession, s.handleEnableFonts)
 	editor.Post("/fonts/disable", s.requireCustomerSession, s.handleDisableFonts)
 
+	editor.Get("/folders", s.requireCustomerSession, s.handleListFolders)
+	editor.Get("/folders/:id", s.requireCustomerSession, s.handleGetFolder)
+	editor.Post("/folders", s.requireCustomerSession, s.handleCreateFolder)
+	editor.Put("/folders/:id", s.requireCustomerSession, s.handleUpdateFolder)
+	editor.Delete("/folders/:id", s.requireCustomerSession, s.handleDeleteFolder)
+	editor.Post("/folders/:id/items", s.requireCustomerSession, s.handleMoveToFolder)
+
 	editor.Post("/uploads", s.requireCustomerSession, s.handleCreateSignedURL)
 	editor.Put("/uploads", s.requireCustomerSession, s.handleCreateUpload)
 	editor.Get("/uploads", s.requireCustomerSession, s.handleListUpload)
 	editor.Delete("/uploads/:id", s.requireCustomerSession, s.handleDeleteUpload)
+	editor.Post("/uploads/:id/restore", s.requireCustomerSession, s.handleRestoreUpload)
+
+	editor.Get("/trash/projects", s.requireCustomerSession, s.handleListTrashedProjects)
+	editor.Get("/trash/uploads", s.requireCustomerSession, s.handleListTrashedUploads)
+	editor.Post("/projects/:id/restore", s.requireCustomerSession, s.handleRestoreProject)
 
 	editor.Get("/frames", s.requireCustomerSession, s.handleListFrames)
 	editor.Get("/frames/:id", s.requireCustomerSession, s.handleGetFrame)
@@ -47,6 +78,15 @@ func (s *Server) initRoutes() {
 	editor.Put("/frames/:id", s.requireCustomerSession, s.handleUpdateFrame)
 	editor.Delete("/frames/:id", s.requireCustomerSession, s.handleDeleteFrame)
 
+	editor.Get("/mockups", s.requireCustomerSession, s.handleListMockupTemplates)
+	editor.Get("/mockups/:id", s.requireCustomerSession, s.handleGetMockupTemplate)
+	editor.Get("/projects/:id/mockups/:mockup_id", s.requireCustomerSession, s.handleGetProjectMockup)
+
+	editor.Get("/orders", s.requireCustomerSession, s.handleListOrders)
+	editor.Get("/orders/:id", s.requireCustomerSession, s.handleGetOrder)
+	editor.Post("/orders", s.requireCustomerSession, s.handleCreateOrder)
+	editor.Post("/orders/:id/submit", s.requireCustomerSession, s.handleSubmitOrder)
+
 	editor.Get("/resources/pixabay/images", s.requireCustomerSession, s.handleFetchPixabayImages)
 	editor.Get("/resources/pixabay/videos", s.requireCustomerSession, s.handleFetchPixabayVideos)
 	editor.Get("/resources/pexels/images", s.requireCustomerSession, s.handleFetchPexelsImages)
@@ -65,11 +105,16 @@ func (s *Server) initRoutes() {
 
 	web.Get("/companies/:id", s.requireUserSession, s.handleGetCompany)
 	web.Put("/companies/:id", s.requireUserSession, s.handleUpdateCompany)
+	web.Delete("/companies/:id", s.requireUserSession, s.handleDeleteCompany)
 
 	web.Get("/customers", s.requireUserSession, s.handleListCustomers)
 	web.Get("/customers/:id", s.requireUserSession, s.handleGetCustomer)
 	web.Put("/customers/:id", s.requireUserSession, s.handleUpdateCustomer)
 	web.Delete("/customers/:id", s.requireUserSession, s.handleDeleteCustomer)
+	web.Post("/customers/:id/export", s.requireUserSession, s.handleExportCustomer)
+
+	web.Get("/jobs/:id", s.requireUserSession, s.handleGetTenantJob)
+	web.Get("/jobs/:id/export", s.requireUserSession, s.handleDownloadTenantExport)
 
 	web.Get("/frames", s.requireUserSession, s.handleListFrames)
 	web.Get("/frames/:id", s.requireUserSession, s.handleGetFrame)
@@ -82,6 +127,16 @@ func (s *Server) initRoutes() {
 	web.Post("/templates", s.requireUserSession, s.handleCreateTemplate)
 	web.Put("/templates/:id", s.requireUserSession, s.handleUpdateTemplate)
 	web.Delete("/templates/:id", s.requireUserSession, s.handleDeleteTemplate)
+	web.Get("/templates/:id/preflight", s.requireUserSession, s.handlePreflightTemplate)
+	web.Get("/templates/:id/params", s.requireUserSession, s.handleGetTemplateParams)
+	web.Post("/templates/:id/resize", s.requireUserSession, s.handleResizeTemplate)
+	web.Post("/templates/:id/publish", s.requireUserSession, s.handlePublishTemplate)
+	web.Post("/templates/:id/unpublish", s.requireUserSession, s.handleUnpublishTemplate)
+	web.Post("/templates/:id/restore", s.requireUserSession, s.handleRestoreTemplate)
+	web.Get("/gallery", s.requireUserSession, s.handleListGallery)
+	web.Get("/search/templates", s.requireUserSession, s.handleSearchTemplates)
+	web.Get("/search/components", s.requireUserSession, s.handleSearchComponents)
+	web.Get("/search/uploads", s.requireUserSession, s.handleSearchUploads)
 
 	web.Get("/render/:id", s.handleRenderDesign)
 
@@ -90,17 +145,55 @@ func (s *Server) initRoutes() {
 	web.Post("/projects", s.requireUserSession, s.handleCreateProject)
 	web.Put("/projects/:id", s.requireUserSession, s.handleUpdateProject)
 	web.Delete("/projects/:id", s.requireUserSession, s.handleDeleteProject)
+	web.Get("/projects/:id/preflight", s.requireUserSession, s.handlePreflightProject)
+	web.Post("/projects/:id/resize", s.requireUserSession, s.handleResizeProject)
+	web.Get("/projects/:id/proofs", s.requireUserSession, s.handleListProofs)
+	web.Post("/projects/:id/proofs", s.requireUserSession, s.handleCreateProof)
+	web.Post("/projects/:id/restore", s.requireUserSession, s.handleRestoreProject)
+
+	web.Get("/proofs/:id", s.requireUserSession, s.handleGetProof)
+	web.Post("/proofs/:id/approve", s.requireUserSession, s.handleApproveProof)
+	web.Post("/proofs/:id/request-changes", s.requireUserSession, s.handleRequestProofChanges)
+	web.Post("/proofs/:id/comments", s.requireUserSession, s.handleCreateProofComment)
 
 	web.Get("/components", s.requireUserSession, s.handleListComponent)
 	web.Get("/components/:id", s.requireUserSession, s.handleGetComponent)
 	web.Post("/components", s.requireUserSession, s.handleCreateComponent)
 	web.Put("/components/:id", s.requireUserSession, s.handleUpdateComponent)
 	web.Delete("/components/:id", s.requireUserSession, s.handleDeleteComponent)
+	web.Post("/components/:id/restore", s.requireUserSession, s.handleRestoreComponent)
+
+	web.Get("/mockups", s.requireUserSession, s.handleListMockupTemplates)
+	web.Get("/mockups/:id", s.requireUserSession, s.handleGetMockupTemplate)
+	web.Post("/mockups", s.requireUserSession, s.handleCreateMockupTemplate)
+	web.Put("/mockups/:id", s.requireUserSession, s.handleUpdateMockupTemplate)
+	web.Delete("/mockups/:id", s.requireUserSession, s.handleDeleteMockupTemplate)
+	web.Get("/templates/:id/mockups/:mockup_id", s.requireUserSession, s.handleGetTemplateMockup)
+	web.Get("/projects/:id/mockups/:mockup_id", s.requireUserSession, s.handleGetProjectMockup)
+
+	web.Get("/orders", s.requireUserSession, s.handleListOrders)
+	web.Get("/orders/:id", s.requireUserSession, s.handleGetOrder)
+	web.Post("/orders", s.requireUserSession, s.handleCreateOrder)
+	web.Post("/orders/:id/submit", s.requireUserSession, s.handleSubmitOrder)
+	web.Post("/orders/:id/print-files/retry", s.requireUserSession, s.handleRetryPrintFiles)
+
+	web.Get("/folders", s.requireUserSession, s.handleListFolders)
+	web.Get("/folders/:id", s.requireUserSession, s.handleGetFolder)
+	web.Post("/folders", s.requireUserSession, s.handleCreateFolder)
+	web.Put("/folders/:id", s.requireUserSession, s.handleUpdateFolder)
+	web.Delete("/folders/:id", s.requireUserSession, s.handleDeleteFolder)
+	web.Post("/folders/:id/items", s.requireUserSession, s.handleMoveToFolder)
 
 	web.Post("/uploads", s.requireUserSession, s.handleCreateSignedURL)
 	web.Put("/uploads", s.requireUserSession, s.handleCreateUpload)
 	web.Get("/uploads", s.requireUserSession, s.handleListUpload)
 	web.Delete("/uploads/:id", s.requireUserSession, s.handleDeleteUpload)
+	web.Post("/uploads/:id/restore", s.requireUserSession, s.handleRestoreUpload)
+
+	web.Get("/trash/templates", s.requireUserSession, s.handleListTrashedTemplates)
+	web.Get("/trash/projects", s.requireUserSession, s.handleListTrashedProjects)
+	web.Get("/trash/components", s.requireUserSession, s.handleListTrashedComponents)
+	web.Get("/trash/uploads", s.requireUserSession, s.handleListTrashedUploads)
 
 	web.Get("/resources/pixabay/images", s.handleFetchPixabayImages)
 	web.Get("/resources/pixabay/videos", s.handleFetchPixabayVideos)
@@ -0,0 +1,857 @@
+package http
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/echovl/orderflo-dev/layerhub"
+	"github.com/echovl/orderflo-dev/testhelpers/fakes"
+	"github.com/gofiber/fiber/v2"
+)
+
+// testLayers is a design with a text param
+var testLayers = []map[string]any{
+	{"id": "title", "type": "DynamicText", "text": "Hello {{name}}", "keyValues": []map[string]any{{"key": "name", "value": "Jane"}}},
+}
+
+var testFrame = map[string]any{"width": 800, "height": 600, "unit": "px"}
+
+// TestRoutes goes through every route of the server with each design
+// storage
+func TestRoutes(t *testing.T) {
+	storages := []layerhub.DesignStorage{layerhub.DesignStorageUploader, layerhub.DesignStorageJSONDB}
+	for _, storage := range storages {
+		t.Run(string(storage), func(t *testing.T) { testRoutes(t, storage) })
+	}
+}
+
+// testRoutes goes through every route of the server, each step uses the
+// items created by the previous ones
+func testRoutes(t *testing.T, storage layerhub.DesignStorage) {
+	sv, uploader := setupTestServer(t, storage)
+
+	var mu sync.Mutex
+	covered := map[string]bool{}
+	sv.App.Use(func(c *fiber.Ctx) error {
+		err := c.Next()
+		mu.Lock()
+		covered[c.Method()+" "+c.Route().Path] = true
+		mu.Unlock()
+		return err
+	})
+	sv.initRoutes()
+
+	user := &testClient{app: sv.App}
+	customer := &testClient{app: sv.App}
+	anonymous := &testClient{app: sv.App}
+
+	var (
+		companyID, customerID, templateID, projectID, proofID, orderID     string
+		frameID, folderID, uploadID, fontID, mockupTemplateID, componentID string
+	)
+
+	t.Run("user auth", func(t *testing.T) {
+		var signUp struct {
+			User User `json:"user"`
+		}
+		anonymous.do(t, http.MethodPost, "/web/auth/signup", map[string]any{
+			"first_name":   "Jane",
+			"email":        "jane@layerhub.io",
+			"company_name": "Layerhub",
+			"password":     "password123",
+		}, http.StatusOK, &signUp)
+		companyID = signUp.User.CompanyID
+
+		anonymous.do(t, http.MethodPost, "/web/auth/signin", map[string]any{
+			"email":    "jane@layerhub.io",
+			"password": "wrong-password",
+		}, http.StatusBadRequest, nil)
+
+		var signIn struct {
+			CSRFToken string `json:"csrf_token"`
+		}
+		user.do(t, http.MethodPost, "/web/auth/signin", map[string]any{
+			"email":    "jane@layerhub.io",
+			"password": "password123",
+		}, http.StatusOK, &signIn)
+		user.csrfToken = signIn.CSRFToken
+
+		var csrf struct {
+			CSRFToken string `json:"csrf_token"`
+		}
+		user.do(t, http.MethodGet, "/web/auth/csrf", nil, http.StatusOK, &csrf)
+		if csrf.CSRFToken != signIn.CSRFToken {
+			t.Errorf("mismatched csrf token: got %s, want %s", csrf.CSRFToken, signIn.CSRFToken)
+		}
+
+		var me struct {
+			User User `json:"user"`
+		}
+		user.do(t, http.MethodPut, "/web/auth/profile", map[string]any{"last_name": "Doe"}, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/auth/me", nil, http.StatusOK, &me)
+		if me.User.LastName != "Doe" {
+			t.Errorf("profile wasn't updated: %+v", me.User)
+		}
+
+		anonymous.do(t, http.MethodGet, "/web/auth/me", nil, http.StatusUnauthorized, nil)
+		anonymous.do(t, http.MethodGet, "/web/auth/signin/github", nil, http.StatusFound, nil)
+		anonymous.do(t, http.MethodGet, "/web/auth/signin/google", nil, http.StatusFound, nil)
+		anonymous.do(t, http.MethodGet, "/web/auth/callback/github?code=x&state=y", nil, http.StatusUnauthorized, nil)
+		anonymous.do(t, http.MethodGet, "/web/auth/callback/google?code=x&state=y", nil, http.StatusUnauthorized, nil)
+	})
+
+	t.Run("companies", func(t *testing.T) {
+		var resp struct {
+			Company layerhub.Company `json:"company"`
+		}
+		user.do(t, http.MethodPut, "/web/companies/"+companyID, map[string]any{"name": "Layerhub Inc"}, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/companies/"+companyID, nil, http.StatusOK, &resp)
+		if resp.Company.Name != "Layerhub Inc" {
+			t.Errorf("company wasn't updated: %+v", resp.Company)
+		}
+	})
+
+	t.Run("customer auth", func(t *testing.T) {
+		var signUp struct {
+			Customer layerhub.Customer `json:"customer"`
+		}
+		anonymous.do(t, http.MethodPost, "/editor/auth/signup", map[string]any{
+			"first_name": "John",
+			"email":      "john@mail.com",
+			"password":   "password123",
+			"company_id": companyID,
+		}, http.StatusOK, &signUp)
+		customerID = signUp.Customer.ID
+
+		var signIn struct {
+			CSRFToken string `json:"csrf_token"`
+		}
+		customer.do(t, http.MethodPost, "/editor/auth/signin", map[string]any{
+			"email":    "john@mail.com",
+			"password": "password123",
+		}, http.StatusOK, &signIn)
+		customer.csrfToken = signIn.CSRFToken
+
+		var me struct {
+			Customer layerhub.Customer `json:"customer"`
+		}
+		customer.do(t, http.MethodPut, "/editor/customers/"+customerID, map[string]any{"last_name": "Smith"}, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/customers/me", nil, http.StatusOK, &me)
+		if me.Customer.ID != customerID {
+			t.Errorf("mismatched customer: got %s, want %s", me.Customer.ID, customerID)
+		}
+
+		// Customer sessions can't use the web routes
+		customer.do(t, http.MethodGet, "/web/auth/me", nil, http.StatusUnauthorized, nil)
+	})
+
+	t.Run("customers", func(t *testing.T) {
+		var list struct {
+			Customers []layerhub.Customer `json:"customers"`
+			Total     int                 `json:"total"`
+		}
+		user.do(t, http.MethodGet, "/web/customers", nil, http.StatusOK, &list)
+		if list.Total != 1 {
+			t.Errorf("got %d customers, want 1", list.Total)
+		}
+		user.do(t, http.MethodGet, "/web/customers?sort=-created_at&created_after=2020-01-01T00:00:00Z", nil, http.StatusOK, &list)
+		if list.Total != 1 {
+			t.Errorf("got %d customers created after 2020, want 1", list.Total)
+		}
+
+		// customers have no name column
+		user.do(t, http.MethodGet, "/web/customers?sort=name", nil, http.StatusBadRequest, nil)
+		user.do(t, http.MethodGet, "/web/customers?name_prefix=Jo", nil, http.StatusBadRequest, nil)
+
+		user.do(t, http.MethodPut, "/web/customers/"+customerID, map[string]any{"first_name": "Johnny"}, http.StatusOK, nil)
+
+		var resp struct {
+			Customer layerhub.Customer `json:"customer"`
+		}
+		user.do(t, http.MethodGet, "/web/customers/"+customerID, nil, http.StatusOK, &resp)
+		if resp.Customer.FirstName != "Johnny" {
+			t.Errorf("customer wasn't updated: %+v", resp.Customer)
+		}
+	})
+
+	t.Run("frames", func(t *testing.T) {
+		var resp struct {
+			Frame layerhub.Frame `json:"frame"`
+		}
+		user.do(t, http.MethodPost, "/web/frames", map[string]any{"name": "Poster", "width": 40, "height": 60, "unit": "cm"}, http.StatusOK, &resp)
+		frameID = resp.Frame.ID
+
+		user.do(t, http.MethodPut, "/web/frames/"+frameID, map[string]any{"name": "Large poster"}, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/frames/"+frameID, nil, http.StatusOK, &resp)
+		if resp.Frame.Name != "Large poster" {
+			t.Errorf("frame wasn't updated: %+v", resp.Frame)
+		}
+		user.do(t, http.MethodGet, "/web/frames", nil, http.StatusOK, nil)
+
+		var customerFrame struct {
+			Frame layerhub.Frame `json:"frame"`
+		}
+		customer.do(t, http.MethodPost, "/editor/frames", map[string]any{"name": "Card", "width": 85, "height": 55, "unit": "px"}, http.StatusOK, &customerFrame)
+		customer.do(t, http.MethodPut, "/editor/frames/"+customerFrame.Frame.ID, map[string]any{"name": "Business card"}, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/frames/"+customerFrame.Frame.ID, nil, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/frames", nil, http.StatusOK, nil)
+		customer.do(t, http.MethodDelete, "/editor/frames/"+customerFrame.Frame.ID, nil, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/frames/"+customerFrame.Frame.ID, nil, http.StatusNotFound, nil)
+	})
+
+	t.Run("templates", func(t *testing.T) {
+		var resp struct {
+			Template layerhub.Template `json:"template"`
+		}
+		user.do(t, http.MethodPost, "/web/templates", map[string]any{
+			"name":     "Greeting",
+			"layers":   testLayers,
+			"frame":    testFrame,
+			"tags":     []string{"card"},
+			"metadata": map[string]any{"orientation": "landscape"},
+		}, http.StatusOK, &resp)
+		templateID = resp.Template.ID
+
+		user.do(t, http.MethodPut, "/web/templates/"+templateID, map[string]any{"name": "Greeting card"}, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/templates/"+templateID, nil, http.StatusOK, &resp)
+		if resp.Template.Name != "Greeting card" || len(resp.Template.Layers) != 1 {
+			t.Errorf("template wasn't updated: %+v", resp.Template)
+		}
+
+		var list struct {
+			Templates []layerhub.Template `json:"templates"`
+			Total     int                 `json:"total"`
+		}
+		user.do(t, http.MethodGet, "/web/templates?sort=-created_at&limit=10", nil, http.StatusOK, &list)
+		if list.Total != 1 {
+			t.Errorf("got %d templates, want 1", list.Total)
+		}
+
+		var params struct {
+			Params []layerhub.Param `json:"params"`
+		}
+		user.do(t, http.MethodGet, "/web/templates/"+templateID+"/params", nil, http.StatusOK, &params)
+		user.do(t, http.MethodGet, "/web/templates/"+templateID+"/preflight", nil, http.StatusOK, nil)
+		user.do(t, http.MethodPost, "/web/templates/"+templateID+"/resize", map[string]any{"frame_id": frameID}, http.StatusOK, nil)
+		user.do(t, http.MethodPost, "/web/templates/"+templateID+"/publish", map[string]any{}, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/gallery", nil, http.StatusOK, &list)
+		if list.Total != 1 {
+			t.Errorf("got %d gallery templates, want 1", list.Total)
+		}
+		customer.do(t, http.MethodGet, "/editor/gallery?tag=card", nil, http.StatusOK, &list)
+		if list.Total != 1 {
+			t.Errorf("got %d gallery templates for the customer, want 1", list.Total)
+		}
+
+		user.do(t, http.MethodPost, "/web/templates/"+templateID+"/unpublish", map[string]any{}, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/gallery", nil, http.StatusOK, &list)
+		if list.Total != 0 {
+			t.Errorf("got %d gallery templates after unpublishing, want 0", list.Total)
+		}
+		user.do(t, http.MethodPost, "/web/templates/"+templateID+"/publish", map[string]any{}, http.StatusOK, nil)
+	})
+
+	t.Run("design cache", func(t *testing.T) {
+		var before, after layerhub.DesignCacheStats
+		operator := &testClient{app: sv.App, token: testMetricsToken}
+		anonymous.do(t, http.MethodGet, "/metrics/design-cache", nil, http.StatusUnauthorized, nil)
+		customer.do(t, http.MethodGet, "/metrics/design-cache", nil, http.StatusUnauthorized, nil)
+		// Company owners only see their own company
+		user.do(t, http.MethodGet, "/metrics/design-cache", nil, http.StatusUnauthorized, nil)
+		(&testClient{app: sv.App, token: "wrong"}).do(t, http.MethodGet, "/metrics/design-cache", nil, http.StatusUnauthorized, nil)
+		operator.do(t, http.MethodGet, "/metrics/design-cache", nil, http.StatusOK, &before)
+		user.do(t, http.MethodGet, "/web/templates/"+templateID, nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/templates/"+templateID, nil, http.StatusOK, nil)
+		operator.do(t, http.MethodGet, "/metrics/design-cache", nil, http.StatusOK, &after)
+		if after.LocalHits <= before.LocalHits {
+			t.Errorf("reading a template twice didn't hit the cache: %+v, %+v", before, after)
+		}
+	})
+
+	t.Run("render", func(t *testing.T) {
+		anonymous.do(t, http.MethodGet, "/web/render/"+templateID+"?name=John", nil, http.StatusOK, nil)
+		anonymous.do(t, http.MethodGet, "/"+templateID, nil, http.StatusOK, nil)
+		anonymous.do(t, http.MethodGet, "/missing", nil, http.StatusNotFound, nil)
+	})
+
+	t.Run("projects", func(t *testing.T) {
+		var resp struct {
+			Project layerhub.Project `json:"project"`
+		}
+		customer.do(t, http.MethodPost, "/editor/templates/"+templateID+"/use", map[string]any{"params": map[string]any{"name": "John"}}, http.StatusOK, &resp)
+		projectID = resp.Project.ID
+		if resp.Project.TemplateID != templateID {
+			t.Errorf("mismatched template: got %s, want %s", resp.Project.TemplateID, templateID)
+		}
+
+		customer.do(t, http.MethodPut, "/editor/projects/"+projectID, map[string]any{"name": "My card"}, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/projects/"+projectID, nil, http.StatusOK, &resp)
+		if resp.Project.Name != "My card" {
+			t.Errorf("project wasn't updated: %+v", resp.Project)
+		}
+		customer.do(t, http.MethodGet, "/editor/projects", nil, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/projects/"+projectID+"/preflight", nil, http.StatusOK, nil)
+		customer.do(t, http.MethodPost, "/editor/projects/"+projectID+"/resize", map[string]any{"frame": map[string]any{"width": 400, "height": 300, "unit": "px"}}, http.StatusOK, nil)
+
+		var created struct {
+			Project layerhub.Project `json:"project"`
+		}
+		customer.do(t, http.MethodPost, "/editor/projects", map[string]any{"name": "Blank", "layers": testLayers, "frame": testFrame}, http.StatusOK, &created)
+		customer.do(t, http.MethodDelete, "/editor/projects/"+created.Project.ID, nil, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/projects/"+created.Project.ID, nil, http.StatusNotFound, nil)
+
+		user.do(t, http.MethodPost, "/web/projects", map[string]any{"name": "Draft", "layers": testLayers, "frame": testFrame}, http.StatusOK, &created)
+		user.do(t, http.MethodPut, "/web/projects/"+created.Project.ID, map[string]any{"name": "Draft v2"}, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/projects/"+created.Project.ID, nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/projects", nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/projects/"+created.Project.ID+"/preflight", nil, http.StatusOK, nil)
+		user.do(t, http.MethodPost, "/web/projects/"+created.Project.ID+"/resize", map[string]any{"frame_id": frameID}, http.StatusOK, nil)
+		user.do(t, http.MethodDelete, "/web/projects/"+created.Project.ID, nil, http.StatusOK, nil)
+
+		// Another customer's session can't read the project
+		customer.do(t, http.MethodGet, "/editor/projects/"+created.Project.ID, nil, http.StatusNotFound, nil)
+	})
+
+	t.Run("proofs", func(t *testing.T) {
+		var resp struct {
+			Proof layerhub.Proof `json:"proof"`
+		}
+		user.do(t, http.MethodPost, "/web/projects/"+projectID+"/proofs", map[string]any{}, http.StatusOK, &resp)
+		proofID = resp.Proof.ID
+
+		user.do(t, http.MethodPost, "/web/proofs/"+proofID+"/comments", map[string]any{"body": "Please review"}, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/proofs/"+proofID, nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/projects/"+projectID+"/proofs", nil, http.StatusOK, nil)
+
+		customer.do(t, http.MethodGet, "/editor/projects/"+projectID+"/proofs", nil, http.StatusOK, nil)
+		customer.do(t, http.MethodPost, "/editor/proofs/"+proofID+"/comments", map[string]any{"body": "Looks good"}, http.StatusOK, nil)
+		customer.do(t, http.MethodPost, "/editor/proofs/"+proofID+"/request-changes", map[string]any{"comment": "Bigger title"}, http.StatusOK, nil)
+
+		user.do(t, http.MethodPost, "/web/projects/"+projectID+"/proofs", map[string]any{}, http.StatusOK, &resp)
+		proofID = resp.Proof.ID
+		customer.do(t, http.MethodPost, "/editor/proofs/"+proofID+"/approve", map[string]any{}, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/proofs/"+proofID, nil, http.StatusOK, &resp)
+		if resp.Proof.Status != layerhub.ProofApproved {
+			t.Errorf("proof wasn't approved: %s", resp.Proof.Status)
+		}
+
+		// Proofs of customer projects are only reviewed by the customer
+		user.do(t, http.MethodPost, "/web/projects/"+projectID+"/proofs", map[string]any{}, http.StatusOK, &resp)
+		user.do(t, http.MethodPost, "/web/proofs/"+resp.Proof.ID+"/approve", map[string]any{}, http.StatusUnauthorized, nil)
+		user.do(t, http.MethodPost, "/web/proofs/"+resp.Proof.ID+"/request-changes", map[string]any{"comment": "Bigger title"}, http.StatusUnauthorized, nil)
+		customer.do(t, http.MethodPost, "/editor/proofs/"+resp.Proof.ID+"/approve", map[string]any{}, http.StatusOK, nil)
+
+		// Proofs of company projects are reviewed by the company users
+		var created struct {
+			Project layerhub.Project `json:"project"`
+		}
+		user.do(t, http.MethodPost, "/web/projects", map[string]any{"name": "Flyer", "layers": testLayers, "frame": testFrame}, http.StatusOK, &created)
+		user.do(t, http.MethodPost, "/web/projects/"+created.Project.ID+"/proofs", map[string]any{}, http.StatusOK, &resp)
+		user.do(t, http.MethodPost, "/web/proofs/"+resp.Proof.ID+"/request-changes", map[string]any{"comment": "Darker background"}, http.StatusOK, nil)
+		user.do(t, http.MethodPost, "/web/projects/"+created.Project.ID+"/proofs", map[string]any{}, http.StatusOK, &resp)
+		user.do(t, http.MethodPost, "/web/proofs/"+resp.Proof.ID+"/approve", map[string]any{}, http.StatusOK, &resp)
+		if resp.Proof.Status != layerhub.ProofApproved {
+			t.Errorf("company proof wasn't approved: %s", resp.Proof.Status)
+		}
+	})
+
+	t.Run("orders", func(t *testing.T) {
+		var resp struct {
+			Order layerhub.Order `json:"order"`
+		}
+		customer.do(t, http.MethodPost, "/editor/orders", map[string]any{
+			"items": []map[string]any{{"project_id": projectID, "sku": "card-100"}},
+		}, http.StatusOK, &resp)
+		customer.do(t, http.MethodPost, "/editor/orders/"+resp.Order.ID+"/submit", map[string]any{}, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/orders/"+resp.Order.ID, nil, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/orders", nil, http.StatusOK, nil)
+
+		user.do(t, http.MethodPost, "/web/orders", map[string]any{
+			"customer_id": customerID,
+			"items":       []map[string]any{{"project_id": projectID, "sku": "card-200"}},
+		}, http.StatusOK, &resp)
+		orderID = resp.Order.ID
+		user.do(t, http.MethodPost, "/web/orders/"+orderID+"/submit", map[string]any{}, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/orders/"+orderID, nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/orders", nil, http.StatusOK, nil)
+		// Print files are generated when the order is submitted
+		user.do(t, http.MethodPost, "/web/orders/"+orderID+"/print-files/retry", map[string]any{}, http.StatusBadRequest, nil)
+	})
+
+	t.Run("mockups", func(t *testing.T) {
+		photoURL, err := (&fakes.Renderer{Uploader: uploader}).Render(context.Background(), nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var resp struct {
+			MockupTemplate layerhub.MockupTemplate `json:"mockup_template"`
+		}
+		user.do(t, http.MethodPost, "/web/mockups", map[string]any{
+			"name":      "Mug",
+			"photo_url": photoURL,
+			"placement": []map[string]float64{{"x": 0, "y": 0}, {"x": 16, "y": 0}, {"x": 16, "y": 16}, {"x": 0, "y": 16}},
+		}, http.StatusOK, &resp)
+		mockupTemplateID = resp.MockupTemplate.ID
+
+		user.do(t, http.MethodPut, "/web/mockups/"+mockupTemplateID, map[string]any{"name": "Big mug"}, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/mockups/"+mockupTemplateID, nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/mockups", nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/templates/"+templateID+"/mockups/"+mockupTemplateID, nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/projects/"+projectID+"/mockups/"+mockupTemplateID, nil, http.StatusOK, nil)
+
+		customer.do(t, http.MethodGet, "/editor/mockups", nil, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/mockups/"+mockupTemplateID, nil, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/projects/"+projectID+"/mockups/"+mockupTemplateID, nil, http.StatusOK, nil)
+	})
+
+	t.Run("components", func(t *testing.T) {
+		var resp struct {
+			Component layerhub.Component `json:"component"`
+		}
+		user.do(t, http.MethodPost, "/web/components", map[string]any{"name": "Logo", "layers": testLayers}, http.StatusOK, &resp)
+		componentID = resp.Component.ID
+
+		user.do(t, http.MethodPut, "/web/components/"+componentID, map[string]any{"name": "Logo v2"}, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/components/"+componentID, nil, http.StatusOK, &resp)
+		if resp.Component.Name != "Logo v2" {
+			t.Errorf("component wasn't updated: %+v", resp.Component)
+		}
+		user.do(t, http.MethodGet, "/web/components", nil, http.StatusOK, nil)
+
+		var deleted struct {
+			Component layerhub.Component `json:"component"`
+		}
+		user.do(t, http.MethodPost, "/web/components", map[string]any{"name": "Badge", "layers": testLayers}, http.StatusOK, &deleted)
+		user.do(t, http.MethodDelete, "/web/components/"+deleted.Component.ID, nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/components/"+deleted.Component.ID, nil, http.StatusNotFound, nil)
+	})
+
+	t.Run("uploads", func(t *testing.T) {
+		var signed struct {
+			URL string `json:"url"`
+		}
+		user.do(t, http.MethodPost, "/web/uploads", map[string]any{"filename": "photo.png"}, http.StatusOK, &signed)
+		if signed.URL == "" {
+			t.Error("empty signed url")
+		}
+
+		var resp struct {
+			Upload layerhub.Upload `json:"upload"`
+		}
+		user.do(t, http.MethodPut, "/web/uploads", map[string]any{"filename": "photo.png"}, http.StatusOK, &resp)
+		uploadID = resp.Upload.ID
+		user.do(t, http.MethodGet, "/web/uploads", nil, http.StatusOK, nil)
+
+		var deleted struct {
+			Upload layerhub.Upload `json:"upload"`
+		}
+		user.do(t, http.MethodPut, "/web/uploads", map[string]any{"filename": "draft.png"}, http.StatusOK, &deleted)
+		user.do(t, http.MethodDelete, "/web/uploads/"+deleted.Upload.ID, nil, http.StatusOK, nil)
+
+		customer.do(t, http.MethodPost, "/editor/uploads", map[string]any{"filename": "logo.png"}, http.StatusOK, nil)
+		customer.do(t, http.MethodPut, "/editor/uploads", map[string]any{"filename": "logo.png"}, http.StatusOK, &resp)
+		customer.do(t, http.MethodGet, "/editor/uploads", nil, http.StatusOK, nil)
+		customer.do(t, http.MethodDelete, "/editor/uploads/"+resp.Upload.ID, nil, http.StatusOK, nil)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		var templates struct {
+			Templates []layerhub.Template `json:"templates"`
+			Total     int                 `json:"total"`
+		}
+		user.do(t, http.MethodGet, "/web/search/templates?query=greeting", nil, http.StatusOK, &templates)
+		// The template and its resized copy
+		if templates.Total != 2 || len(templates.Templates) != 2 {
+			t.Errorf("got %d templates of %d, want 2", len(templates.Templates), templates.Total)
+		}
+		user.do(t, http.MethodGet, "/web/search/templates?query=greeting&limit=1", nil, http.StatusOK, &templates)
+		if len(templates.Templates) != 1 {
+			t.Errorf("got %d templates with limit 1, want 1", len(templates.Templates))
+		}
+		user.do(t, http.MethodGet, "/web/search/templates?query=greeting&limit=1000", nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/search/templates?query=greeting&offset=-1", nil, http.StatusBadRequest, nil)
+		user.do(t, http.MethodGet, "/web/search/templates?query=greeting&limit=-1", nil, http.StatusBadRequest, nil)
+		user.do(t, http.MethodGet, "/web/search/components?query=logo", nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/search/uploads?query=photo", nil, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/search/templates?query=greeting", nil, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/search/uploads?query=logo", nil, http.StatusOK, nil)
+
+		// Reindexing replaces the documents of the index
+		indexed, err := sv.Core.Reindex(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if indexed < 4 {
+			t.Errorf("got %d indexed documents, want at least 4", indexed)
+		}
+		user.do(t, http.MethodGet, "/web/search/templates?query=greeting", nil, http.StatusOK, &templates)
+		if templates.Total != 2 {
+			t.Errorf("got %d templates after reindexing, want 2", templates.Total)
+		}
+	})
+
+	t.Run("folders", func(t *testing.T) {
+		var resp struct {
+			Folder layerhub.Folder `json:"folder"`
+		}
+		user.do(t, http.MethodPost, "/web/folders", map[string]any{"name": "Cards"}, http.StatusOK, &resp)
+		folderID = resp.Folder.ID
+
+		user.do(t, http.MethodPut, "/web/folders/"+folderID, map[string]any{"name": "Greeting cards"}, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/folders/"+folderID, nil, http.StatusOK, &resp)
+		if resp.Folder.Name != "Greeting cards" {
+			t.Errorf("folder wasn't renamed: %+v", resp.Folder)
+		}
+		user.do(t, http.MethodPost, "/web/folders/"+folderID+"/items", map[string]any{
+			"templates":  []string{templateID},
+			"components": []string{componentID},
+			"uploads":    []string{uploadID},
+		}, http.StatusOK, nil)
+
+		var templates struct {
+			Total int `json:"total"`
+		}
+		user.do(t, http.MethodGet, "/web/templates?folder_id="+folderID, nil, http.StatusOK, &templates)
+		if templates.Total != 1 {
+			t.Errorf("got %d templates in the folder, want 1", templates.Total)
+		}
+		user.do(t, http.MethodPost, "/web/folders/root/items", map[string]any{"templates": []string{templateID}}, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/folders", nil, http.StatusOK, nil)
+
+		var customerFolder struct {
+			Folder layerhub.Folder `json:"folder"`
+		}
+		customer.do(t, http.MethodPost, "/editor/folders", map[string]any{"name": "Mine"}, http.StatusOK, &customerFolder)
+		customer.do(t, http.MethodPut, "/editor/folders/"+customerFolder.Folder.ID, map[string]any{"parent_id": folderID}, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/folders/"+customerFolder.Folder.ID, nil, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/folders?parent_id="+folderID, nil, http.StatusOK, nil)
+		customer.do(t, http.MethodPost, "/editor/folders/"+customerFolder.Folder.ID+"/items", map[string]any{"projects": []string{projectID}}, http.StatusOK, nil)
+		customer.do(t, http.MethodPost, "/editor/folders/root/items", map[string]any{"projects": []string{projectID}}, http.StatusOK, nil)
+		customer.do(t, http.MethodDelete, "/editor/folders/"+customerFolder.Folder.ID, nil, http.StatusOK, nil)
+
+		// The component and the upload are moved to the trash with the folder
+		user.do(t, http.MethodDelete, "/web/folders/"+folderID, nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/components/"+componentID, nil, http.StatusNotFound, nil)
+		user.do(t, http.MethodGet, "/web/templates/"+templateID, nil, http.StatusOK, nil)
+	})
+
+	t.Run("fonts", func(t *testing.T) {
+		var resp struct {
+			Font layerhub.Font `json:"font"`
+		}
+		user.do(t, http.MethodPost, "/web/fonts", map[string]any{
+			"family":    "Roboto",
+			"full_name": "Roboto Regular",
+			"style":     "Regular",
+		}, http.StatusOK, &resp)
+		fontID = resp.Font.ID
+
+		// Font files on internal addresses aren't downloaded
+		user.do(t, http.MethodPost, "/web/fonts", map[string]any{
+			"family": "Internal",
+			"url":    "http://127.0.0.1:1/font.ttf",
+		}, http.StatusBadRequest, nil)
+
+		user.do(t, http.MethodPut, "/web/fonts/"+fontID, map[string]any{"style": "Bold"}, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/fonts/"+fontID, nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/fonts", nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/fonts?created_after=2020-01-01T00:00:00Z", nil, http.StatusBadRequest, nil)
+		user.do(t, http.MethodPost, "/web/fonts/enable", map[string]any{"font_ids": []string{fontID}, "customer_id": customerID}, http.StatusOK, nil)
+
+		var fonts struct {
+			Total int `json:"total"`
+		}
+		customer.do(t, http.MethodGet, "/editor/fonts?enabled=true", nil, http.StatusOK, &fonts)
+		if fonts.Total != 1 {
+			t.Errorf("got %d enabled fonts, want 1", fonts.Total)
+		}
+		user.do(t, http.MethodPost, "/web/fonts/disable", map[string]any{"font_ids": []string{fontID}, "customer_id": customerID}, http.StatusOK, nil)
+
+		var customerFont struct {
+			Font layerhub.Font `json:"font"`
+		}
+		customer.do(t, http.MethodPost, "/editor/fonts", map[string]any{"family": "Lato", "full_name": "Lato Regular"}, http.StatusOK, &customerFont)
+		customer.do(t, http.MethodPut, "/editor/fonts/"+customerFont.Font.ID, map[string]any{"style": "Italic"}, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/fonts/"+customerFont.Font.ID, nil, http.StatusOK, nil)
+		customer.do(t, http.MethodPost, "/editor/fonts/enable", map[string]any{"font_ids": []string{customerFont.Font.ID}, "customer_id": customerID}, http.StatusOK, nil)
+		customer.do(t, http.MethodPost, "/editor/fonts/disable", map[string]any{"font_ids": []string{customerFont.Font.ID}, "customer_id": customerID}, http.StatusOK, nil)
+		customer.do(t, http.MethodDelete, "/editor/fonts/"+customerFont.Font.ID, nil, http.StatusOK, nil)
+		user.do(t, http.MethodDelete, "/web/fonts/"+fontID, nil, http.StatusOK, nil)
+	})
+
+	t.Run("resources", func(t *testing.T) {
+		var images struct {
+			Images []struct {
+				ID int `json:"id"`
+			} `json:"images"`
+			Total int `json:"total"`
+		}
+		user.do(t, http.MethodGet, "/web/resources/pixabay/images?query=cat&page=1&per_page=1", nil, http.StatusOK, &images)
+		if images.Total != 2 || len(images.Images) != 1 {
+			t.Errorf("got %d of %d images, want 1 of 2", len(images.Images), images.Total)
+		}
+		anonymous.do(t, http.MethodGet, "/web/resources/pixabay/videos?query=cat&page=1&per_page=5", nil, http.StatusOK, nil)
+		anonymous.do(t, http.MethodGet, "/web/resources/pexels/images?query=cat&page=1&per_page=5", nil, http.StatusOK, nil)
+		anonymous.do(t, http.MethodGet, "/web/resources/pexels/videos?query=cat&page=1&per_page=5", nil, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/resources/pixabay/images?query=cat&page=1&per_page=5", nil, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/resources/pixabay/videos?query=cat&page=1&per_page=5", nil, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/resources/pexels/images?query=cat&page=1&per_page=5", nil, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/resources/pexels/videos?query=cat&page=1&per_page=5", nil, http.StatusOK, nil)
+	})
+
+	t.Run("trash", func(t *testing.T) {
+		var templates struct {
+			Templates []layerhub.Template `json:"templates"`
+			Total     int                 `json:"total"`
+		}
+		user.do(t, http.MethodDelete, "/web/templates/"+templateID, nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/templates/"+templateID, nil, http.StatusNotFound, nil)
+		user.do(t, http.MethodGet, "/web/trash/templates", nil, http.StatusOK, &templates)
+		if templates.Total != 1 || templates.Templates[0].ID != templateID || templates.Templates[0].DeletedAt == nil {
+			t.Errorf("template isn't in the trash: %+v", templates)
+		}
+		user.do(t, http.MethodPost, "/web/templates/"+templateID+"/restore", map[string]any{}, http.StatusOK, nil)
+		user.do(t, http.MethodPost, "/web/templates/"+templateID+"/restore", map[string]any{}, http.StatusNotFound, nil)
+		user.do(t, http.MethodGet, "/web/templates/"+templateID, nil, http.StatusOK, nil)
+
+		var projects struct {
+			Projects []layerhub.Project `json:"projects"`
+		}
+		user.do(t, http.MethodDelete, "/web/projects/"+projectID, nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/trash/projects", nil, http.StatusOK, nil)
+		user.do(t, http.MethodPost, "/web/projects/"+projectID+"/restore", map[string]any{}, http.StatusOK, nil)
+		customer.do(t, http.MethodDelete, "/editor/projects/"+projectID, nil, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/trash/projects", nil, http.StatusOK, &projects)
+		found := false
+		for _, p := range projects.Projects {
+			found = found || p.ID == projectID
+		}
+		if !found {
+			t.Errorf("project %s isn't in the trash: %+v", projectID, projects)
+		}
+		customer.do(t, http.MethodPost, "/editor/projects/"+projectID+"/restore", map[string]any{}, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/projects/"+projectID, nil, http.StatusOK, nil)
+
+		// The component and the upload of the deleted folder are restored
+		// outside of any folder
+		var component struct {
+			Component layerhub.Component `json:"component"`
+		}
+		user.do(t, http.MethodGet, "/web/trash/components", nil, http.StatusOK, nil)
+		user.do(t, http.MethodPost, "/web/components/"+componentID+"/restore", map[string]any{}, http.StatusOK, &component)
+		if component.Component.FolderID != "" {
+			t.Errorf("component was restored to the deleted folder %s", component.Component.FolderID)
+		}
+		user.do(t, http.MethodGet, "/web/components/"+componentID, nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/trash/uploads", nil, http.StatusOK, nil)
+		user.do(t, http.MethodPost, "/web/uploads/"+uploadID+"/restore", map[string]any{}, http.StatusOK, nil)
+
+		var upload struct {
+			Upload layerhub.Upload `json:"upload"`
+		}
+		customer.do(t, http.MethodPut, "/editor/uploads", map[string]any{"filename": "scan.png"}, http.StatusOK, &upload)
+		customer.do(t, http.MethodDelete, "/editor/uploads/"+upload.Upload.ID, nil, http.StatusOK, nil)
+		customer.do(t, http.MethodGet, "/editor/trash/uploads", nil, http.StatusOK, nil)
+		customer.do(t, http.MethodPost, "/editor/uploads/"+upload.Upload.ID+"/restore", map[string]any{}, http.StatusOK, nil)
+
+		// The items deleted by the other tests are purged
+		purged, err := sv.Core.PurgeTrash(context.Background(), layerhub.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if purged == 0 {
+			t.Error("nothing was purged")
+		}
+		for _, kind := range []string{"templates", "projects", "components", "uploads"} {
+			var trash struct {
+				Total int `json:"total"`
+			}
+			user.do(t, http.MethodGet, "/web/trash/"+kind, nil, http.StatusOK, &trash)
+			if trash.Total != 0 {
+				t.Errorf("%d %s left in the trash", trash.Total, kind)
+			}
+		}
+	})
+
+	t.Run("exports", func(t *testing.T) {
+		var resp struct {
+			Job layerhub.TenantJob `json:"job"`
+		}
+		customer.do(t, http.MethodPost, "/editor/customers/"+customerID+"/export", map[string]any{}, http.StatusOK, &resp)
+		job := waitTenantJob(t, customer, "/editor/jobs/"+resp.Job.ID)
+		if job.Status != layerhub.TenantJobDone || job.Done != job.Total {
+			t.Fatalf("export didn't finish: %+v", job)
+		}
+		customer.do(t, http.MethodGet, "/editor/jobs/"+job.ID+"/export", nil, http.StatusFound, nil)
+
+		user.do(t, http.MethodPost, "/web/customers/"+customerID+"/export", map[string]any{}, http.StatusOK, &resp)
+		job = waitTenantJob(t, user, "/web/jobs/"+resp.Job.ID)
+		user.do(t, http.MethodGet, "/web/jobs/"+job.ID+"/export", nil, http.StatusFound, nil)
+
+		// The key of the ZIP isn't part of the response
+		job, err := sv.Core.GetTenantJob(context.Background(), job.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exportURL, err := sv.Core.TenantExportURL(context.Background(), job)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exportResp, err := http.Get(exportURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(exportResp.Body)
+		exportResp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files := map[string]bool{}
+		for _, f := range zr.File {
+			files[f.Name] = true
+		}
+		for _, name := range []string{"customer.json", "projects.json", "orders.json", "proofs.json", "uploads.json"} {
+			if !files[name] {
+				t.Errorf("%s is missing from the export", name)
+			}
+		}
+	})
+
+	t.Run("deletes", func(t *testing.T) {
+		user.do(t, http.MethodDelete, "/web/mockups/"+mockupTemplateID, nil, http.StatusOK, nil)
+		user.do(t, http.MethodDelete, "/web/frames/"+frameID, nil, http.StatusOK, nil)
+		user.do(t, http.MethodDelete, "/web/templates/"+templateID, nil, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/templates/"+templateID, nil, http.StatusNotFound, nil)
+
+		var resp struct {
+			Job layerhub.TenantJob `json:"job"`
+		}
+		user.do(t, http.MethodDelete, "/web/customers/"+customerID, nil, http.StatusOK, &resp)
+		job := waitTenantJob(t, user, "/web/jobs/"+resp.Job.ID)
+		if job.Status != layerhub.TenantJobDone {
+			t.Fatalf("customer wasn't deleted: %+v", job)
+		}
+		user.do(t, http.MethodGet, "/web/customers/"+customerID, nil, http.StatusNotFound, nil)
+		// The rows of the customer are deleted with it
+		user.do(t, http.MethodGet, "/web/projects/"+projectID, nil, http.StatusNotFound, nil)
+		user.do(t, http.MethodGet, "/web/orders/"+orderID, nil, http.StatusNotFound, nil)
+		user.do(t, http.MethodGet, "/web/proofs/"+proofID, nil, http.StatusNotFound, nil)
+	})
+
+	t.Run("company deletes", func(t *testing.T) {
+		other := &testClient{app: sv.App}
+		var signUp struct {
+			Customer layerhub.Customer `json:"customer"`
+		}
+		anonymous.do(t, http.MethodPost, "/editor/auth/signup", map[string]any{
+			"first_name": "Mary",
+			"email":      "mary@mail.com",
+			"password":   "password123",
+			"company_id": companyID,
+		}, http.StatusOK, &signUp)
+
+		var signIn struct {
+			CSRFToken string `json:"csrf_token"`
+		}
+		other.do(t, http.MethodPost, "/editor/auth/signin", map[string]any{
+			"email":    "mary@mail.com",
+			"password": "password123",
+		}, http.StatusOK, &signIn)
+		other.csrfToken = signIn.CSRFToken
+
+		var resp struct {
+			Job layerhub.TenantJob `json:"job"`
+		}
+		other.do(t, http.MethodDelete, "/editor/customers/"+signUp.Customer.ID, nil, http.StatusOK, &resp)
+		waitTenantJob(t, other, "/editor/jobs/"+resp.Job.ID)
+		user.do(t, http.MethodGet, "/web/customers/"+signUp.Customer.ID, nil, http.StatusNotFound, nil)
+
+		var component struct {
+			Component layerhub.Component `json:"component"`
+		}
+		user.do(t, http.MethodGet, "/web/components/"+componentID, nil, http.StatusOK, &component)
+
+		user.do(t, http.MethodDelete, "/web/companies/"+companyID, nil, http.StatusOK, &resp)
+		job := waitTenantJob(t, user, "/web/jobs/"+resp.Job.ID)
+		if job.Status != layerhub.TenantJobDone || job.Done != job.Total {
+			t.Fatalf("company wasn't deleted: %+v", job)
+		}
+		user.do(t, http.MethodGet, "/web/companies/"+companyID, nil, http.StatusNotFound, nil)
+		user.do(t, http.MethodGet, "/web/components/"+componentID, nil, http.StatusNotFound, nil)
+		for _, key := range uploader.Files() {
+			if strings.HasSuffix(component.Component.Preview, "/"+key) {
+				t.Errorf("preview %s of the component wasn't deleted", key)
+			}
+		}
+	})
+
+	t.Run("sign out", func(t *testing.T) {
+		user.do(t, http.MethodPost, "/web/auth/signout", map[string]any{}, http.StatusOK, nil)
+		user.do(t, http.MethodGet, "/web/auth/me", nil, http.StatusUnauthorized, nil)
+	})
+
+	t.Run("design storage", func(t *testing.T) {
+		if storage != layerhub.DesignStorageJSONDB {
+			t.Skip("designs are files of the uploader")
+		}
+		// Proof snapshots are always files of the uploader
+		for _, key := range uploader.Files() {
+			for _, id := range []string{templateID, projectID, componentID} {
+				if key == id+".layerhub" {
+					t.Errorf("design %s was uploaded", key)
+				}
+			}
+		}
+	})
+
+	t.Run("every route", func(t *testing.T) {
+		missing := []string{}
+		for _, routes := range sv.App.Stack() {
+			for _, route := range routes {
+				key := route.Method + " " + route.Path
+				// The health check needs a kafka broker
+				if route.Method == http.MethodHead || route.Path == "/" || route.Path == "/health" || covered[key] {
+					continue
+				}
+				missing = append(missing, key)
+			}
+		}
+		sort.Strings(missing)
+
+		if len(missing) != 0 {
+			t.Errorf("%d routes weren't tested:\n%s", len(missing), strings.Join(missing, "\n"))
+		}
+	})
+}
+
+// waitTenantJob polls the job of url until it's finished
+func waitTenantJob(t *testing.T, client *testClient, url string) *layerhub.TenantJob {
+	t.Helper()
+
+	var resp struct {
+		Job layerhub.TenantJob `json:"job"`
+	}
+	for i := 0; i < 100; i++ {
+		client.do(t, http.MethodGet, url, nil, http.StatusOK, &resp)
+		if resp.Job.Status == layerhub.TenantJobDone || resp.Job.Status == layerhub.TenantJobFailed {
+			return &resp.Job
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	t.Fatalf("job %s didn't finish: %+v", resp.Job.ID, resp.Job)
+	return nil
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+	"github.com/gofiber/fiber/v2"
+)
+
+type searchRequest struct {
+	Query  string   `query:"q"`
+	Tags   []string `query:"tag"`
+	Colors []string `query:"color"`
+	Width  float64  `query:"width"`
+	Height float64  `query:"height"`
+	Limit  int      `query:"limit"`
+	Offset int      `query:"offset"`
+}
+
+// searchQuery returns the query of a search request scoped to the session
+func (s *Server) searchQuery(c *fiber.Ctx) (*layerhub.SearchQuery, error) {
+	var req searchRequest
+	if err := s.requestParser(c, &req); err != nil {
+		return nil, errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	query := &layerhub.SearchQuery{
+		Text:      req.Query,
+		CompanyID: session.Company.ID,
+		Tags:      req.Tags,
+		Colors:    req.Colors,
+		Width:     req.Width,
+		Height:    req.Height,
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+	}
+
+	if session.Customer != nil {
+		query.CustomerID = session.Customer.ID
+	}
+
+	return query, nil
+}
+
+func (s *Server) handleSearchTemplates(c *fiber.Ctx) error {
+	type response struct {
+		Templates []layerhub.Template               `json:"templates"`
+		Total     int                               `json:"total"`
+		Facets    map[string][]layerhub.SearchFacet `json:"facets"`
+	}
+
+	query, err := s.searchQuery(c)
+	if err != nil {
+		return err
+	}
+
+	templates, result, err := s.Core.SearchTemplates(c.Context(), query)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{templates, result.Total, result.Facets})
+}
+
+func (s *Server) handleSearchComponents(c *fiber.Ctx) error {
+	type response struct {
+		Components []layerhub.Component              `json:"components"`
+		Total      int                               `json:"total"`
+		Facets     map[string][]layerhub.SearchFacet `json:"facets"`
+	}
+
+	query, err := s.searchQuery(c)
+	if err != nil {
+		return err
+	}
+
+	comps, result, err := s.Core.SearchComponents(c.Context(), query)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{comps, result.Total, result.Facets})
+}
+
+func (s *Server) handleSearchUploads(c *fiber.Ctx) error {
+	type response struct {
+		Uploads []layerhub.Upload                 `json:"uploads"`
+		Total   int                               `json:"total"`
+		Facets  map[string][]layerhub.SearchFacet `json:"facets"`
+	}
+
+	query, err := s.searchQuery(c)
+	if err != nil {
+		return err
+	}
+
+	uploads, result, err := s.Core.SearchUploads(c.Context(), query)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{uploads, result.Total, result.Facets})
+}
@@ -24,14 +24,18 @@ type Config struct {
 
 	Core      *layerhub.Core
 	SessionDB db.KeyValueDB
+	// MetricsToken is the bearer token of the operator routes, like the
+	// metrics, they're disabled when it's empty
+	MetricsToken string
 }
 
 // Server manages the HTTP implementation of this API
 type Server struct {
-	App       *fiber.App
-	Core      *layerhub.Core
-	validate  *validator.Validate
-	sessionDB db.KeyValueDB
+	App          *fiber.App
+	Core         *layerhub.Core
+	validate     *validator.Validate
+	sessionDB    db.KeyValueDB
+	metricsToken string
 }
 
 // NewServer creates a new server instance
@@ -47,9 +51,10 @@ func NewServer(conf Config) *Server {
 	})
 
 	srv := &Server{
-		Core:      conf.Core,
-		sessionDB: conf.SessionDB,
-		validate:  validate,
+		Core:         conf.Core,
+		sessionDB:    conf.SessionDB,
+		validate:     validate,
+		metricsToken: conf.MetricsToken,
 	}
 
 	srv.App = fiber.New(fiber.Config{
@@ -58,6 +63,9 @@ func NewServer(conf Config) *Server {
 		WriteTimeout:          conf.WriteTimeout,
 		IdleTimeout:           conf.IdleTimeout,
 		DisableStartupMessage: true,
+		// Params and body values outlive the handlers, the in-memory
+		// databases keep them
+		Immutable: true,
 	})
 
 	return srv
@@ -115,6 +123,9 @@ func (s *Server) errorHandler(c *fiber.Ctx, err error) error {
 	case errors.Is(err, errors.KindAuthorization):
 		code = http.StatusUnauthorized
 		message = "You are not authorized to perform this action"
+	case errors.Is(err, errors.KindConflict):
+		code = http.StatusConflict
+		message = err.Error()
 	default:
 		// Unexpected error
 		if e, ok := err.(*fiber.Error); ok {
@@ -3,31 +3,143 @@ package http
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"testing"
+	"time"
 
-	"github.com/gofiber/fiber/v2"
+	"github.com/echovl/orderflo-dev/cloud/github"
+	"github.com/echovl/orderflo-dev/cloud/google"
+	"github.com/echovl/orderflo-dev/db/memory"
+	"github.com/echovl/orderflo-dev/feeds"
 	"github.com/echovl/orderflo-dev/layerhub"
+	"github.com/echovl/orderflo-dev/search/bleve"
+	"github.com/echovl/orderflo-dev/testhelpers/fakes"
+	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 )
 
-func setupTestServer(t *testing.T) *Server {
-	logger, _ := zap.NewProduction()
-	defer logger.Sync()
-	return NewServer(Config{
+// setupTestServer returns a server backed by in-memory databases and fake
+// services, the uploaded files are served by a local HTTP server
+func setupTestServer(t *testing.T, storage layerhub.DesignStorage) (*Server, *fakes.Uploader) {
+	uploader := fakes.NewUploader()
+	files := httptest.NewServer(uploader)
+	t.Cleanup(files.Close)
+	uploader.BaseURL = files.URL
+
+	index, err := bleve.New(filepath.Join(t.TempDir(), "index"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { index.Close() })
+
+	sv := NewServer(Config{
 		Core: layerhub.New(layerhub.CoreConfig{
-			Logger:          logger,
-			Uploader:        nil,
-			Pixabay:         nil,
-			Pexels:          nil,
-			PaymentProvider: nil,
-			Renderer:        nil,
+			Logger:   zap.NewNop(),
+			DB:       memory.New(),
+			JSONDB:   memory.NewJSONDB(),
+			Uploader: uploader,
+			Pixabay: &fakes.MediaFeed{
+				Domain: "pixabay.com",
+				Images: []feeds.Image{{ID: 1, Src: "https://pixabay.com/1.png"}, {ID: 2, Src: "https://pixabay.com/2.png"}},
+				Videos: []feeds.Video{{ID: 1, PreviewURL: "https://pixabay.com/1.jpg"}},
+			},
+			Pexels: &fakes.MediaFeed{
+				Domain: "pexels.com",
+				Images: []feeds.Image{{ID: 1, Src: "https://pexels.com/1.png"}},
+				Videos: []feeds.Video{{ID: 1, PreviewURL: "https://pexels.com/1.jpg"}},
+			},
+			PaymentProvider: fakes.NewPaymentProvider(),
+			Renderer:        &fakes.Renderer{Uploader: uploader},
+			GithubClient:    github.NewClient(github.Config{ClientID: "github", RedirectURI: "http://localhost/web/auth/callback/github"}),
+			GoogleClient:    google.NewClient(google.Config{ClientID: "google", RedirectURI: "http://localhost/web/auth/callback/google"}),
+			SearchIndex:     index,
+			DesignStorage:   storage,
+			DesignCache: layerhub.DesignCacheConfig{
+				Size:       100,
+				KeyValueDB: memory.NewKeyValueDB(),
+				TTL:        time.Hour,
+			},
 		}),
+		SessionDB:    memory.NewKeyValueDB(),
+		MetricsToken: testMetricsToken,
 	})
+
+	return sv, uploader
+}
+
+const testMetricsToken = "metrics-token"
+
+// testClient sends requests to a test server, it keeps the session cookie
+// and the csrf token of the last sign in
+type testClient struct {
+	app       *fiber.App
+	session   string
+	csrfToken string
+	// token is sent as the bearer token of the requests
+	token string
+}
+
+// do sends the request and decodes the JSON response into out, it fails the
+// test if the response status isn't status
+func (c *testClient) do(t *testing.T, method, url string, body any, status int, out any) {
+	t.Helper()
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, url, reqBody)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.session != "" {
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: c.session})
+	}
+	if c.csrfToken != "" {
+		req.Header.Set(csrfHeaderName, c.csrfToken)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == sessionCookieName {
+			c.session = cookie.Value
+		}
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != status {
+		t.Fatalf("%s %s: got status %d, want %d: %s", method, url, resp.StatusCode, status, data)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			t.Fatalf("%s %s: decoding response: %s", method, url, err)
+		}
+	}
 }
 
 func TestRequestParser(t *testing.T) {
-	sv := setupTestServer(t)
+	sv, _ := setupTestServer(t, layerhub.DesignStorageUploader)
 
 	type request struct {
 		BodyField  int    `json:"bf" validate:"max=20"`
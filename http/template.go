@@ -12,15 +12,16 @@ import (
 
 func (s *Server) handleListTemplate(c *fiber.Ctx) error {
 	type request struct {
+		listParams
 		CustomerID string `query:"customer_id"`
+		FolderID   string `query:"folder_id"`
 		CompanyID  string `query:"company_id"`
-		Limit      int    `query:"limit"`
-		Offset     int    `query:"offset"`
 	}
 
 	type response struct {
-		Templates []layerhub.Template `json:"templates"`
-		Total     int                 `json:"total"`
+		Templates  []layerhub.Template `json:"templates"`
+		Total      int                 `json:"total"`
+		NextCursor string              `json:"next_cursor,omitempty"`
 	}
 
 	var req request
@@ -31,21 +32,24 @@ func (s *Server) handleListTemplate(c *fiber.Ctx) error {
 	session, _ := s.getSession(c)
 	filter := &layerhub.Filter{
 		OptionalCustomerID: req.CustomerID,
-		OptionalCompanyID:  session.Company.ID,
-		Limit:              req.Limit,
-		Offset:             req.Offset,
+		CompanyID:          session.Company.ID,
+		FolderID:           req.FolderID,
 	}
 
 	if session.Customer != nil {
 		filter.OptionalCustomerID = session.Customer.ID
 	}
 
+	if err := req.apply(filter, designFields...); err != nil {
+		return err
+	}
+
 	templates, count, err := s.Core.FindTemplates(c.Context(), filter)
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(response{templates, count})
+	return c.JSON(response{templates, count, layerhub.NextCursor(filter, templates)})
 }
 
 func (s *Server) handleGetTemplate(c *fiber.Ctx) error {
@@ -73,6 +77,73 @@ func (s *Server) handleGetTemplate(c *fiber.Ctx) error {
 	return c.JSON(response{template})
 }
 
+func (s *Server) handleGetTemplateParams(c *fiber.Ctx) error {
+	type response struct {
+		Params []*layerhub.Param `json:"params"`
+	}
+
+	session, _ := s.getSession(c)
+	template, err := s.Core.GetTemplate(c.Context(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	if !template.Public {
+		if template.CompanyID != session.Company.ID {
+			return errors.Authorization(template.ID)
+		}
+
+		if session.Customer != nil && template.CustomerID != session.Customer.ID {
+			return errors.Authorization(template.ID)
+		}
+	}
+
+	return c.JSON(response{layerhub.DesignParams(template.Layers).Params})
+}
+
+// handleUseTemplate starts a project from a template, customers can use the
+// published templates of their company and the public ones
+func (s *Server) handleUseTemplate(c *fiber.Ctx) error {
+	type request struct {
+		Params map[string]any `json:"params"`
+	}
+
+	type response struct {
+		Project *layerhub.Project `json:"project"`
+	}
+
+	var req request
+	if err := s.requestParser(c, &req); err != nil {
+		return errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	template, err := s.Core.GetTemplate(c.Context(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	owner := template.CompanyID == session.Company.ID &&
+		(session.Customer == nil || template.CustomerID == session.Customer.ID)
+	visible := template.Published && (template.Public || template.CompanyID == session.Company.ID)
+
+	if !owner && !visible {
+		return errors.Authorization(template.ID)
+	}
+
+	var customerID string
+	if session.Customer != nil {
+		customerID = session.Customer.ID
+	}
+
+	project, err := s.Core.UseTemplate(c.Context(), template, session.Company.ID, customerID, req.Params)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{project})
+}
+
 func (s *Server) handleRenderTemplate(c *fiber.Ctx) error {
 	id := c.Params("id")
 
@@ -121,6 +192,10 @@ func (s *Server) handleCreateTemplate(c *fiber.Ctx) error {
 		Template *layerhub.Template `json:"template"`
 	}
 
+	if err := layerhub.ValidateDesignJSON(c.Body()); err != nil {
+		return err
+	}
+
 	var req request
 	if err := s.requestParser(c, &req); err != nil {
 		return errors.E(errors.KindValidation, err)
@@ -168,6 +243,10 @@ func (s *Server) handleUpdateTemplate(c *fiber.Ctx) error {
 		Template *layerhub.Template `json:"template"`
 	}
 
+	if err := layerhub.ValidateDesignJSON(c.Body()); err != nil {
+		return err
+	}
+
 	var req request
 	if err := s.requestParser(c, &req); err != nil {
 		return errors.E(errors.KindValidation, err)
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (s *Server) handleDeleteCompany(c *fiber.Ctx) error {
+	type response struct {
+		Job *layerhub.TenantJob `json:"job"`
+	}
+
+	session, _ := s.getSession(c)
+	id := string(c.Params("id"))
+	company, err := s.Core.GetCompany(c.Context(), id)
+	if errors.Is(err, errors.KindNotFound) {
+		return errors.NotFound(fmt.Sprintf("company '%s' not found", id))
+	} else if err != nil {
+		return err
+	}
+
+	if company.ID != session.Company.ID {
+		return errors.NotFound(fmt.Sprintf("company '%s' not found", id))
+	}
+
+	if session.User.Role != layerhub.UserRoleOwner {
+		return errors.Authorization(company.ID)
+	}
+
+	job, err := s.Core.DeleteCompany(c.Context(), company)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{job})
+}
+
+func (s *Server) handleExportCustomer(c *fiber.Ctx) error {
+	type response struct {
+		Job *layerhub.TenantJob `json:"job"`
+	}
+
+	session, _ := s.getSession(c)
+	id := string(c.Params("id"))
+	customer, err := s.Core.GetCustomer(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	if customer.CompanyID != session.Company.ID {
+		return errors.NotFound(fmt.Sprintf("customer '%s' not found", id))
+	}
+
+	if session.Customer != nil && (customer.ID != session.Customer.ID) {
+		return errors.NotFound(fmt.Sprintf("customer '%s' not found", id))
+	}
+
+	job, err := s.Core.ExportCustomer(c.Context(), customer)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{job})
+}
+
+func (s *Server) handleGetTenantJob(c *fiber.Ctx) error {
+	type response struct {
+		Job *layerhub.TenantJob `json:"job"`
+	}
+
+	job, err := s.getTenantJob(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{job})
+}
+
+func (s *Server) handleDownloadTenantExport(c *fiber.Ctx) error {
+	job, err := s.getTenantJob(c)
+	if err != nil {
+		return err
+	}
+
+	url, err := s.Core.TenantExportURL(c.Context(), job)
+	if err != nil {
+		return err
+	}
+
+	return c.Redirect(url, 302)
+}
+
+// getTenantJob returns the job of the id param if it belongs to the tenant of
+// the session
+func (s *Server) getTenantJob(c *fiber.Ctx) (*layerhub.TenantJob, error) {
+	session, _ := s.getSession(c)
+	id := string(c.Params("id"))
+	job, err := s.Core.GetTenantJob(c.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.CompanyID != session.Company.ID {
+		return nil, errors.NotFound(fmt.Sprintf("job '%s' not found", id))
+	}
+
+	if session.Customer != nil && (job.CustomerID != session.Customer.ID) {
+		return nil, errors.NotFound(fmt.Sprintf("job '%s' not found", id))
+	}
+
+	return job, nil
+}
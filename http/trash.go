@@ -0,0 +1,242 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+	"github.com/gofiber/fiber/v2"
+)
+
+// trashFilter returns the filter of the items in the trash of the session's
+// company, customers only see their own items
+func (s *Server) trashFilter(c *fiber.Ctx) (*layerhub.Filter, error) {
+	var req listParams
+	if err := s.requestParser(c, &req); err != nil {
+		return nil, errors.E(errors.KindValidation, err)
+	}
+
+	session, _ := s.getSession(c)
+	filter := &layerhub.Filter{
+		CompanyID: session.Company.ID,
+		Trashed:   true,
+	}
+
+	if session.Customer != nil {
+		filter.CustomerID = session.Customer.ID
+	}
+
+	if err := req.apply(filter, designFields...); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// checkTrashedItem returns an error if the item in the trash isn't owned by
+// the session's company, or by the session's customer
+func (s *Server) checkTrashedItem(c *fiber.Ctx, id, companyID, customerID string) error {
+	session, _ := s.getSession(c)
+
+	if companyID != session.Company.ID {
+		return errors.Authorization(id)
+	}
+
+	if session.Customer != nil && customerID != session.Customer.ID {
+		return errors.Authorization(id)
+	}
+
+	return nil
+}
+
+func (s *Server) handleListTrashedTemplates(c *fiber.Ctx) error {
+	type response struct {
+		Templates  []layerhub.Template `json:"templates"`
+		Total      int                 `json:"total"`
+		NextCursor string              `json:"next_cursor,omitempty"`
+	}
+
+	filter, err := s.trashFilter(c)
+	if err != nil {
+		return err
+	}
+
+	templates, count, err := s.Core.FindTemplates(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{templates, count, layerhub.NextCursor(filter, templates)})
+}
+
+func (s *Server) handleListTrashedProjects(c *fiber.Ctx) error {
+	type response struct {
+		Projects   []layerhub.Project `json:"projects"`
+		Total      int                `json:"total"`
+		NextCursor string             `json:"next_cursor,omitempty"`
+	}
+
+	filter, err := s.trashFilter(c)
+	if err != nil {
+		return err
+	}
+
+	projects, count, err := s.Core.FindProjects(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{projects, count, layerhub.NextCursor(filter, projects)})
+}
+
+func (s *Server) handleListTrashedComponents(c *fiber.Ctx) error {
+	type response struct {
+		Components []layerhub.Component `json:"components"`
+		Total      int                  `json:"total"`
+		NextCursor string               `json:"next_cursor,omitempty"`
+	}
+
+	filter, err := s.trashFilter(c)
+	if err != nil {
+		return err
+	}
+
+	components, count, err := s.Core.FindComponents(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{components, count, layerhub.NextCursor(filter, components)})
+}
+
+func (s *Server) handleListTrashedUploads(c *fiber.Ctx) error {
+	type response struct {
+		Uploads    []layerhub.Upload `json:"uploads"`
+		Total      int               `json:"total"`
+		NextCursor string            `json:"next_cursor,omitempty"`
+	}
+
+	filter, err := s.trashFilter(c)
+	if err != nil {
+		return err
+	}
+
+	uploads, count, err := s.Core.FindUploads(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{uploads, count, layerhub.NextCursor(filter, uploads)})
+}
+
+func (s *Server) handleRestoreTemplate(c *fiber.Ctx) error {
+	type response struct {
+		Template *layerhub.Template `json:"template"`
+	}
+
+	id := c.Params("id")
+	templates, _, err := s.Core.FindTemplates(c.Context(), &layerhub.Filter{ID: id, Trashed: true})
+	if err != nil {
+		return err
+	}
+
+	if len(templates) == 0 {
+		return errors.NotFound(fmt.Sprintf("template '%s' not found in the trash", id))
+	}
+
+	template := &templates[0]
+	if err := s.checkTrashedItem(c, template.ID, template.CompanyID, template.CustomerID); err != nil {
+		return err
+	}
+
+	err = s.Core.RestoreTemplate(c.Context(), template)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{template})
+}
+
+func (s *Server) handleRestoreProject(c *fiber.Ctx) error {
+	type response struct {
+		Project *layerhub.Project `json:"project"`
+	}
+
+	id := c.Params("id")
+	projects, _, err := s.Core.FindProjects(c.Context(), &layerhub.Filter{ID: id, Trashed: true})
+	if err != nil {
+		return err
+	}
+
+	if len(projects) == 0 {
+		return errors.NotFound(fmt.Sprintf("project '%s' not found in the trash", id))
+	}
+
+	project := &projects[0]
+	if err := s.checkTrashedItem(c, project.ID, project.CompanyID, project.CustomerID); err != nil {
+		return err
+	}
+
+	err = s.Core.RestoreProject(c.Context(), project)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{project})
+}
+
+func (s *Server) handleRestoreComponent(c *fiber.Ctx) error {
+	type response struct {
+		Component *layerhub.Component `json:"component"`
+	}
+
+	id := c.Params("id")
+	components, _, err := s.Core.FindComponents(c.Context(), &layerhub.Filter{ID: id, Trashed: true})
+	if err != nil {
+		return err
+	}
+
+	if len(components) == 0 {
+		return errors.NotFound(fmt.Sprintf("component '%s' not found in the trash", id))
+	}
+
+	component := &components[0]
+	if err := s.checkTrashedItem(c, component.ID, component.CompanyID, component.CustomerID); err != nil {
+		return err
+	}
+
+	err = s.Core.RestoreComponent(c.Context(), component)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{component})
+}
+
+func (s *Server) handleRestoreUpload(c *fiber.Ctx) error {
+	type response struct {
+		Upload *layerhub.Upload `json:"upload"`
+	}
+
+	id := c.Params("id")
+	uploads, _, err := s.Core.FindUploads(c.Context(), &layerhub.Filter{ID: id, Trashed: true})
+	if err != nil {
+		return err
+	}
+
+	if len(uploads) == 0 {
+		return errors.NotFound(fmt.Sprintf("upload '%s' not found in the trash", id))
+	}
+
+	upload := &uploads[0]
+	if err := s.checkTrashedItem(c, upload.ID, upload.CompanyID, upload.CustomerID); err != nil {
+		return err
+	}
+
+	err = s.Core.RestoreUpload(c.Context(), upload)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response{upload})
+}
@@ -11,14 +11,15 @@ import (
 
 func (s *Server) handleListUpload(c *fiber.Ctx) error {
 	type request struct {
+		listParams
 		CustomerID string `query:"customer_id"`
-		Limit      int    `query:"limit"`
-		Offset     int    `query:"offset"`
+		FolderID   string `query:"folder_id"`
 	}
 
 	type response struct {
-		Uploads []layerhub.Upload `json:"uploads"`
-		Total   int               `json:"total"`
+		Uploads    []layerhub.Upload `json:"uploads"`
+		Total      int               `json:"total"`
+		NextCursor string            `json:"next_cursor,omitempty"`
 	}
 
 	var req request
@@ -30,20 +31,23 @@ func (s *Server) handleListUpload(c *fiber.Ctx) error {
 	filter := &layerhub.Filter{
 		OptionalCustomerID: req.CustomerID,
 		OptionalCompanyID:  session.Company.ID,
-		Limit:              req.Limit,
-		Offset:             req.Offset,
+		FolderID:           req.FolderID,
 	}
 
 	if session.Customer != nil {
 		filter.OptionalCustomerID = session.Customer.ID
 	}
 
+	if err := req.apply(filter, designFields...); err != nil {
+		return err
+	}
+
 	uploads, count, err := s.Core.FindUploads(c.Context(), filter)
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(response{uploads, count})
+	return c.JSON(response{uploads, count, layerhub.NextCursor(filter, uploads)})
 }
 
 func (s *Server) handleCreateSignedURL(c *fiber.Ctx) error {
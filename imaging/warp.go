@@ -0,0 +1,226 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Quad is a quadrilateral in clockwise order starting from the top left corner
+type Quad [4]Point
+
+// Bounds returns the smallest rectangle containing the quad
+func (q Quad) Bounds() image.Rectangle {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range q {
+		minX = math.Min(minX, p.X)
+		minY = math.Min(minY, p.Y)
+		maxX = math.Max(maxX, p.X)
+		maxY = math.Max(maxY, p.Y)
+	}
+	return image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+}
+
+// Matrix is a 3x3 projective transformation in row-major order
+type Matrix [9]float64
+
+// SquareToQuad returns the projective transformation that maps the unit
+// square onto the given quad.
+func SquareToQuad(q Quad) Matrix {
+	x0, y0 := q[0].X, q[0].Y
+	x1, y1 := q[1].X, q[1].Y
+	x2, y2 := q[2].X, q[2].Y
+	x3, y3 := q[3].X, q[3].Y
+
+	dx3 := x0 - x1 + x2 - x3
+	dy3 := y0 - y1 + y2 - y3
+
+	if dx3 == 0 && dy3 == 0 {
+		// Affine transformation
+		return Matrix{
+			x1 - x0, x2 - x1, x0,
+			y1 - y0, y2 - y1, y0,
+			0, 0, 1,
+		}
+	}
+
+	dx1, dx2 := x1-x2, x3-x2
+	dy1, dy2 := y1-y2, y3-y2
+	den := dx1*dy2 - dx2*dy1
+	g := (dx3*dy2 - dx2*dy3) / den
+	h := (dx1*dy3 - dx3*dy1) / den
+
+	return Matrix{
+		x1 - x0 + g*x1, x3 - x0 + h*x3, x0,
+		y1 - y0 + g*y1, y3 - y0 + h*y3, y0,
+		g, h, 1,
+	}
+}
+
+// Apply transforms the point (x, y)
+func (m Matrix) Apply(x, y float64) (float64, float64) {
+	w := m[6]*x + m[7]*y + m[8]
+	return (m[0]*x + m[1]*y + m[2]) / w, (m[3]*x + m[4]*y + m[5]) / w
+}
+
+// Inverse returns the inverse transformation, ok is false if the matrix is singular
+func (m Matrix) Inverse() (Matrix, bool) {
+	a, b, c := m[0], m[1], m[2]
+	d, e, f := m[3], m[4], m[5]
+	g, h, i := m[6], m[7], m[8]
+
+	det := a*(e*i-f*h) - b*(d*i-f*g) + c*(d*h-e*g)
+	if det == 0 || math.IsNaN(det) {
+		return Matrix{}, false
+	}
+
+	return Matrix{
+		(e*i - f*h) / det, (c*h - b*i) / det, (b*f - c*e) / det,
+		(f*g - d*i) / det, (a*i - c*g) / det, (c*d - a*f) / det,
+		(d*h - e*g) / det, (b*g - a*h) / det, (a*e - b*d) / det,
+	}, true
+}
+
+type Options struct {
+	// Quad is where the design is placed on the photo
+	Quad Quad
+
+	// Displacement is an optional grayscale map with the photo size, mid gray
+	// means no displacement
+	Displacement image.Image
+
+	// DisplacementStrength is the maximum displacement in design pixels
+	DisplacementStrength float64
+
+	// Shading is an optional grayscale overlay with the photo size, it's
+	// multiplied over the warped design to keep folds and highlights
+	Shading image.Image
+}
+
+// Composite warps the design into the quad and draws it over the photo
+func Composite(photo image.Image, design image.Image, opts Options) *image.RGBA {
+	dst := toRGBA(photo)
+	src := toRGBA(design)
+
+	inv, ok := SquareToQuad(opts.Quad).Inverse()
+	if !ok {
+		return dst
+	}
+
+	sw := float64(src.Bounds().Dx())
+	sh := float64(src.Bounds().Dy())
+	area := opts.Quad.Bounds().Intersect(dst.Bounds())
+
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			u, v := inv.Apply(float64(x)+0.5, float64(y)+0.5)
+			if u < 0 || u > 1 || v < 0 || v > 1 {
+				continue
+			}
+
+			sx := u*sw - 0.5
+			sy := v*sh - 0.5
+			if opts.Displacement != nil && opts.DisplacementStrength != 0 {
+				d := (gray(opts.Displacement, x, y, 0.5) - 0.5) * 2 * opts.DisplacementStrength
+				sx += d
+				sy += d
+			}
+
+			r, g, b, a := bilinear(src, sx, sy)
+			if a == 0 {
+				continue
+			}
+
+			if opts.Shading != nil {
+				s := gray(opts.Shading, x, y, 1)
+				r *= s
+				g *= s
+				b *= s
+			}
+
+			i := dst.PixOffset(x, y)
+			p := dst.Pix[i : i+4 : i+4]
+			p[0] = over(r, p[0], a)
+			p[1] = over(g, p[1], a)
+			p[2] = over(b, p[2], a)
+			p[3] = over(a, p[3], a)
+		}
+	}
+
+	return dst
+}
+
+// over composites a premultiplied source channel over a destination channel
+func over(s float64, d uint8, sa float64) uint8 {
+	return clamp(s + float64(d)*(1-sa/0xff))
+}
+
+func clamp(v float64) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 0xff {
+		return 0xff
+	}
+	return uint8(v + 0.5)
+}
+
+// bilinear samples the premultiplied image at (x, y), coordinates outside the
+// image are clamped to its edges
+func bilinear(img *image.RGBA, x, y float64) (float64, float64, float64, float64) {
+	x0 := int(math.Floor(x))
+	y0 := int(math.Floor(y))
+	fx := x - float64(x0)
+	fy := y - float64(y0)
+
+	var out [4]float64
+	for j := 0; j < 2; j++ {
+		for i := 0; i < 2; i++ {
+			w := (1 - math.Abs(float64(i)-fx)) * (1 - math.Abs(float64(j)-fy))
+			if w == 0 {
+				continue
+			}
+			px, py := clampInt(x0+i, img.Rect.Min.X, img.Rect.Max.X-1), clampInt(y0+j, img.Rect.Min.Y, img.Rect.Max.Y-1)
+			off := img.PixOffset(px, py)
+			for c := 0; c < 4; c++ {
+				out[c] += w * float64(img.Pix[off+c])
+			}
+		}
+	}
+
+	return out[0], out[1], out[2], out[3]
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// gray returns the luminance of the pixel at (x, y) in the range [0, 1], def
+// is returned for pixels outside the image
+func gray(img image.Image, x, y int, def float64) float64 {
+	if !(image.Point{x, y}).In(img.Bounds()) {
+		return def
+	}
+	g := color.GrayModel.Convert(img.At(x, y)).(color.Gray)
+	return float64(g.Y) / 0xff
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst
+}
@@ -0,0 +1,91 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"testing"
+)
+
+func TestSquareToQuad(t *testing.T) {
+	testcases := []struct {
+		name string
+		quad Quad
+	}{
+		{"affine", Quad{{10, 10}, {110, 10}, {110, 60}, {10, 60}}},
+		{"perspective", Quad{{20, 10}, {100, 30}, {90, 120}, {5, 90}}},
+	}
+
+	corners := []Point{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := SquareToQuad(tc.quad)
+			inv, ok := m.Inverse()
+			if !ok {
+				t.Fatal("matrix should be invertible")
+			}
+
+			for i, c := range corners {
+				x, y := m.Apply(c.X, c.Y)
+				if !near(x, tc.quad[i].X) || !near(y, tc.quad[i].Y) {
+					t.Errorf("corner %d: got (%v, %v), want %v", i, x, y, tc.quad[i])
+				}
+
+				u, v := inv.Apply(x, y)
+				if !near(u, c.X) || !near(v, c.Y) {
+					t.Errorf("inverse corner %d: got (%v, %v), want %v", i, u, v, c)
+				}
+			}
+		})
+	}
+}
+
+func TestComposite(t *testing.T) {
+	photo := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	draw.Draw(photo, photo.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	design := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	draw.Draw(design, design.Bounds(), image.NewUniform(color.RGBA{255, 0, 0, 255}), image.Point{}, draw.Src)
+
+	shading := image.NewGray(image.Rect(0, 0, 100, 100))
+	draw.Draw(shading, shading.Bounds(), image.NewUniform(color.Gray{128}), image.Point{}, draw.Src)
+
+	testcases := []struct {
+		name    string
+		opts    Options
+		inside  color.RGBA
+		outside color.RGBA
+	}{
+		{
+			name:    "plain",
+			opts:    Options{Quad: Quad{{20, 20}, {80, 25}, {75, 70}, {25, 65}}},
+			inside:  color.RGBA{255, 0, 0, 255},
+			outside: color.RGBA{255, 255, 255, 255},
+		},
+		{
+			name:    "shading",
+			opts:    Options{Quad: Quad{{20, 20}, {80, 25}, {75, 70}, {25, 65}}, Shading: shading},
+			inside:  color.RGBA{128, 0, 0, 255},
+			outside: color.RGBA{255, 255, 255, 255},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Composite(photo, design, tc.opts)
+
+			if c := got.RGBAAt(50, 45); c != tc.inside {
+				t.Errorf("inside pixel: got %v, want %v", c, tc.inside)
+			}
+			if c := got.RGBAAt(5, 5); c != tc.outside {
+				t.Errorf("outside pixel: got %v, want %v", c, tc.outside)
+			}
+		})
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
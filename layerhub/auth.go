@@ -39,15 +39,13 @@ func (c *Core) RegisterUser(ctx context.Context, user *User, company *Company, p
 
 	user.PasswordHash = hash
 	user.CompanyID = company.ID
-	if err := c.db.PutUser(ctx, user); err != nil {
-		return err
-	}
-
-	if err := c.db.PutCompany(ctx, company); err != nil {
-		return err
-	}
 
-	return nil
+	return c.db.WithTx(ctx, func(tx DB) error {
+		if err := tx.PutCompany(ctx, company); err != nil {
+			return err
+		}
+		return tx.PutUser(ctx, user)
+	})
 }
 
 func (c *Core) RegisterCustomer(ctx context.Context, customer *Customer, password string) error {
@@ -0,0 +1,269 @@
+package layerhub
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/echovl/orderflo-dev/barcode"
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+type BarcodeFormat string
+
+const (
+	BarcodeCode128 BarcodeFormat = "code128"
+	BarcodeEAN13   BarcodeFormat = "ean13"
+	BarcodeEAN8    BarcodeFormat = "ean8"
+)
+
+const (
+	// QRQuietZone is the default margin of QR codes in modules
+	QRQuietZone = 4
+	// BarcodeQuietZone is the default horizontal margin of barcodes in
+	// modules
+	BarcodeQuietZone = 10
+)
+
+const (
+	defaultModuleSize    = 4
+	defaultBarcodeHeight = 80
+)
+
+var qrLevels = map[string]barcode.Level{
+	"":  barcode.M,
+	"L": barcode.L,
+	"M": barcode.M,
+	"Q": barcode.Q,
+	"H": barcode.H,
+}
+
+// codeSpec is what QR code and barcode layers have in common once their data
+// is bound
+type codeSpec struct {
+	bitmap     *barcode.Bitmap
+	quietZone  int
+	linear     bool
+	fill       string
+	background string
+}
+
+// renderLayers returns a copy of the layers where QR codes and barcodes are
+// replaced by the paths that draw them, the renderer doesn't know about them
+func renderLayers(layers []*Layer, params map[string]any) ([]*Layer, error) {
+	out := make([]*Layer, 0, len(layers))
+	for _, layer := range layers {
+		switch props := layer.Props.(type) {
+		case *GroupProps:
+			objects, err := renderLayers(props.Objects, params)
+			if err != nil {
+				return nil, err
+			}
+			l := *layer
+			l.Props = &GroupProps{Objects: objects}
+			out = append(out, &l)
+		case *DynamicGroupProps:
+			objects, err := renderLayers(props.Objects, params)
+			if err != nil {
+				return nil, err
+			}
+			l := *layer
+			l.Props = &DynamicGroupProps{Key: props.Key, Objects: objects}
+			out = append(out, &l)
+		case *QRCodeProps, *DynamicQRCodeProps, *BarcodeProps:
+			spec, err := layerCode(layer, params)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, codePaths(layer, spec)...)
+		default:
+			out = append(out, layer)
+		}
+	}
+
+	return out, nil
+}
+
+// layerCode binds the data of the layer to the params and encodes it
+func layerCode(layer *Layer, params map[string]any) (*codeSpec, error) {
+	var (
+		spec codeSpec
+		err  error
+	)
+
+	switch props := layer.Props.(type) {
+	case *QRCodeProps:
+		spec.bitmap, err = encodeQRCode(props.Data, props.ErrorCorrection)
+		spec.quietZone, spec.fill, spec.background = props.QuietZone, props.Fill, props.BackgroundColor
+	case *DynamicQRCodeProps:
+		spec.bitmap, err = encodeQRCode(paramData(params, props.Key, props.Data), props.ErrorCorrection)
+		spec.quietZone, spec.fill, spec.background = props.QuietZone, props.Fill, props.BackgroundColor
+	case *BarcodeProps:
+		spec.bitmap, err = encodeBarcode(props.Format, paramData(params, props.Key, props.Data))
+		spec.quietZone, spec.fill, spec.background = props.QuietZone, props.Fill, props.BackgroundColor
+		spec.linear = true
+	default:
+		return nil, errors.Errorf("layer '%s' is not a code", layer.ID)
+	}
+	if err != nil {
+		return nil, errors.Validation(fmt.Sprintf("layer '%s': %s", layer.ID, err))
+	}
+
+	if spec.quietZone < 0 {
+		spec.quietZone = 0
+	}
+	if spec.fill == "" {
+		spec.fill = "#000000"
+	}
+
+	return &spec, nil
+}
+
+func encodeQRCode(data, errorCorrection string) (*barcode.Bitmap, error) {
+	level, ok := qrLevels[strings.ToUpper(errorCorrection)]
+	if !ok {
+		return nil, fmt.Errorf("unknown error correction level '%s'", errorCorrection)
+	}
+	if data == "" {
+		return nil, fmt.Errorf("QR code data is empty")
+	}
+
+	return barcode.QR([]byte(data), level)
+}
+
+func encodeBarcode(format BarcodeFormat, data string) (*barcode.Bitmap, error) {
+	switch format {
+	case BarcodeCode128:
+		return barcode.Code128(data)
+	case BarcodeEAN13:
+		if len(data) != 12 && len(data) != 13 {
+			return nil, fmt.Errorf("EAN-13 data must have 12 or 13 digits")
+		}
+		return barcode.EAN(data)
+	case BarcodeEAN8:
+		if len(data) != 7 && len(data) != 8 {
+			return nil, fmt.Errorf("EAN-8 data must have 7 or 8 digits")
+		}
+		return barcode.EAN(data)
+	default:
+		return nil, fmt.Errorf("unknown barcode format '%s'", format)
+	}
+}
+
+// paramData returns the param bound to key, or data if there is none
+func paramData(params map[string]any, key, data string) string {
+	if key == "" {
+		return data
+	}
+	if v, ok := params[key]; ok && v != nil {
+		return fmt.Sprint(v)
+	}
+	return data
+}
+
+// codePaths draws the code as a path layer with the geometry of the original
+// layer, preceded by a path for its background when it has one
+func codePaths(layer *Layer, spec *codeSpec) []*Layer {
+	cols := spec.bitmap.Width + 2*spec.quietZone
+	rows := spec.bitmap.Height
+	if !spec.linear {
+		rows += 2 * spec.quietZone
+	}
+
+	width, height := layer.Width, layer.Height
+	if width <= 0 {
+		width = float64(cols * defaultModuleSize)
+	}
+	if height <= 0 {
+		height = float64(rows * defaultModuleSize)
+		if spec.linear {
+			height = defaultBarcodeHeight
+		}
+	}
+	mw, mh := width/float64(cols), height/float64(rows)
+
+	// the path starts and ends at opposite corners so its bounding box covers
+	// the quiet zone
+	path := [][]any{{"M", 0.0, 0.0}}
+	for y := 0; y < spec.bitmap.Height; y++ {
+		for x := 0; x < spec.bitmap.Width; {
+			if !spec.bitmap.At(x, y) {
+				x++
+				continue
+			}
+			start := x
+			for x < spec.bitmap.Width && spec.bitmap.At(x, y) {
+				x++
+			}
+
+			top, bottom := float64(y+spec.quietZone)*mh, float64(y+spec.quietZone+1)*mh
+			if spec.linear {
+				top, bottom = 0, height
+			}
+			left, right := float64(start+spec.quietZone)*mw, float64(x+spec.quietZone)*mw
+			path = append(path,
+				[]any{"M", left, top},
+				[]any{"L", right, top},
+				[]any{"L", right, bottom},
+				[]any{"L", left, bottom},
+				[]any{"Z"},
+			)
+		}
+	}
+	path = append(path, []any{"M", width, height})
+
+	base := layer.BaseLayer
+	base.Type = LayerStaticPath
+	base.Width, base.Height = width, height
+
+	code := &Layer{
+		BaseLayer: base,
+		Props:     &StaticPathProps{Path: path, Fill: spec.fill},
+	}
+
+	if spec.background == "" {
+		return []*Layer{code}
+	}
+
+	bg := base
+	bg.ID = layer.ID + "_background"
+	background := &Layer{
+		BaseLayer: bg,
+		Props: &StaticPathProps{
+			Path: [][]any{
+				{"M", 0.0, 0.0},
+				{"L", width, 0.0},
+				{"L", width, height},
+				{"L", 0.0, height},
+				{"Z"},
+			},
+			Fill: spec.background,
+		},
+	}
+
+	return []*Layer{background, code}
+}
+
+// renderSchema replaces the codes of templates and projects before they are
+// sent to the renderer, the original design isn't modified
+func renderSchema(sch any, params map[string]any) (any, error) {
+	switch s := sch.(type) {
+	case *Template:
+		layers, err := renderLayers(s.Layers, params)
+		if err != nil {
+			return nil, err
+		}
+		t := *s
+		t.Layers = layers
+		return &t, nil
+	case *Project:
+		layers, err := renderLayers(s.Layers, params)
+		if err != nil {
+			return nil, err
+		}
+		p := *s
+		p.Layers = layers
+		return &p, nil
+	default:
+		return sch, nil
+	}
+}
@@ -0,0 +1,137 @@
+package layerhub
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+func TestRenderLayers(t *testing.T) {
+	decode := func(s string) []*Layer {
+		var layers []*Layer
+		if err := json.Unmarshal([]byte(s), &layers); err != nil {
+			t.Fatal(err)
+		}
+		return layers
+	}
+
+	testcases := []struct {
+		name   string
+		layers string
+		params map[string]any
+		types  []LayerType
+		fills  []string
+		err    string
+	}{
+		{
+			name:   "untouched",
+			layers: `[{"type":"StaticText","text":"hi"}]`,
+			types:  []LayerType{LayerStaticText},
+			fills:  []string{""},
+		},
+		{
+			name:   "static qr code",
+			layers: `[{"id":"qr","type":"StaticQRCode","data":"hello","width":100,"height":100,"backgroundColor":"#fff"}]`,
+			types:  []LayerType{LayerStaticPath, LayerStaticPath},
+			fills:  []string{"#fff", "#000000"},
+		},
+		{
+			name:   "dynamic qr code in group",
+			layers: `[{"type":"Group","objects":[{"type":"DynamicQRCode","key":"url","fill":"red"}]}]`,
+			params: map[string]any{"url": "https://example.com"},
+			types:  []LayerType{LayerGroup},
+			fills:  []string{""},
+		},
+		{
+			name:   "barcode bound to param",
+			layers: `[{"type":"Barcode","format":"ean13","key":"ean","data":"0"}]`,
+			params: map[string]any{"ean": 400638133393},
+			types:  []LayerType{LayerStaticPath},
+			fills:  []string{"#000000"},
+		},
+		{
+			name:   "invalid barcode",
+			layers: `[{"id":"bar","type":"Barcode","format":"ean8","data":"123"}]`,
+			err:    "layer 'bar': EAN-8 data must have 7 or 8 digits",
+		},
+		{
+			name:   "dynamic qr code without data",
+			layers: `[{"id":"qr","type":"DynamicQRCode","key":"url"}]`,
+			err:    "layer 'qr': QR code data is empty",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			layers := decode(tc.layers)
+			original := mustMarshalJSON(t, layers)
+
+			out, err := renderLayers(layers, tc.params)
+			if tc.err != "" {
+				if err == nil || !errors.Is(err, errors.KindValidation) || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected validation error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var types []LayerType
+			var fills []string
+			for _, l := range out {
+				types = append(types, l.Type)
+				fill := ""
+				if p, ok := l.Props.(*StaticPathProps); ok {
+					fill = p.Fill
+				}
+				fills = append(fills, fill)
+			}
+			if !reflect.DeepEqual(types, tc.types) {
+				t.Errorf("got types %v, want %v", types, tc.types)
+			}
+			if !reflect.DeepEqual(fills, tc.fills) {
+				t.Errorf("got fills %v, want %v", fills, tc.fills)
+			}
+
+			if got := mustMarshalJSON(t, layers); string(got) != string(original) {
+				t.Errorf("original layers were modified")
+			}
+		})
+	}
+}
+
+func TestCodePaths(t *testing.T) {
+	layer := &Layer{
+		BaseLayer: BaseLayer{ID: "bar", Type: LayerBarcode, Left: 10, Top: 20, Width: 134, Height: 40, Angle: 90},
+		Props:     &BarcodeProps{Format: BarcodeEAN8, Data: "9638507", QuietZone: 0},
+	}
+
+	spec, err := layerCode(layer, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := codePaths(layer, spec)
+	if len(paths) != 1 {
+		t.Fatalf("got %d layers, want 1", len(paths))
+	}
+
+	code := paths[0]
+	if code.ID != "bar" || code.Left != 10 || code.Top != 20 || code.Angle != 90 {
+		t.Errorf("geometry wasn't kept: %+v", code.BaseLayer)
+	}
+
+	path := code.Props.(*StaticPathProps).Path
+	if first, last := path[0], path[len(path)-1]; !reflect.DeepEqual(first, []any{"M", 0.0, 0.0}) || !reflect.DeepEqual(last, []any{"M", 134.0, 40.0}) {
+		t.Errorf("path isn't anchored to the corners: %v %v", first, last)
+	}
+
+	// 67 modules of 2 units, the first bar is one module wide
+	if got := path[1:4]; !reflect.DeepEqual(got, [][]any{{"M", 0.0, 0.0}, {"L", 2.0, 0.0}, {"L", 2.0, 40.0}}) {
+		t.Errorf("unexpected first bar %v", got)
+	}
+}
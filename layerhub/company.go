@@ -55,6 +55,15 @@ func (c *Core) FindCompanies(ctx context.Context, filter *Filter) ([]Company, in
 	return companies, count, nil
 }
 
-func (c *Core) DeleteCompany(ctx context.Context, id string) error {
-	return c.db.DeleteCompany(ctx, id)
+// DeleteCompany starts the job deleting the company with its users, its
+// customers and the rows and the files they own
+func (c *Core) DeleteCompany(ctx context.Context, company *Company) (*TenantJob, error) {
+	job := NewTenantJob(TenantDeleteCompany)
+	job.CompanyID = company.ID
+
+	if err := c.startTenantJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
 }
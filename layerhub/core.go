@@ -1,6 +1,8 @@
 package layerhub
 
 import (
+	"context"
+
 	"github.com/echovl/orderflo-dev/cloud/github"
 	"github.com/echovl/orderflo-dev/cloud/google"
 	"github.com/echovl/orderflo-dev/feeds"
@@ -20,8 +22,12 @@ type Core struct {
 	paymentProvider payments.Provider
 	github          *github.Client
 	google          *google.Client
+	searchIndex     SearchIndex
 
-	renderer Renderer
+	renderer    Renderer
+	designs     designStore
+	designCache *cachedDesigns
+	fonts       *fontCache
 
 	Logger *zap.SugaredLogger
 }
@@ -37,10 +43,16 @@ type CoreConfig struct {
 	Renderer        Renderer
 	GithubClient    *github.Client
 	GoogleClient    *google.Client
+	SearchIndex     SearchIndex
+
+	// DesignStorage selects the store of the designs, the uploader by
+	// default
+	DesignStorage DesignStorage
+	DesignCache   DesignCacheConfig
 }
 
 func New(cfg CoreConfig) *Core {
-	return &Core{
+	c := &Core{
 		Logger:          cfg.Logger.Sugar(),
 		db:              cfg.DB,
 		jsonDB:          cfg.JSONDB,
@@ -50,6 +62,32 @@ func New(cfg CoreConfig) *Core {
 		paymentProvider: cfg.PaymentProvider,
 		github:          cfg.GithubClient,
 		google:          cfg.GoogleClient,
-		renderer:        cfg.Renderer,
+		searchIndex:     cfg.SearchIndex,
+		fonts:           &fontCache{},
 	}
+	c.renderer = &fittingRenderer{Renderer: cfg.Renderer, core: c}
+
+	switch cfg.DesignStorage {
+	case DesignStorageJSONDB:
+		c.designs = &jsonDBDesigns{db: cfg.JSONDB}
+	default:
+		c.designs = &uploaderDesigns{uploader: cfg.Uploader}
+	}
+
+	if cfg.DesignCache.Size > 0 || cfg.DesignCache.KeyValueDB != nil {
+		c.designCache = newCachedDesigns(c.designs, cfg.DesignCache, c.Logger)
+		c.designs = c.designCache
+	}
+
+	return c
+}
+
+// withTx runs fn as a unit of work, the core given to fn makes its database
+// calls in the same transaction. Calls to withTx on it join the unit of work
+func (c *Core) withTx(ctx context.Context, fn func(c *Core) error) error {
+	return c.db.WithTx(ctx, func(tx DB) error {
+		txc := *c
+		txc.db = tx
+		return fn(&txc)
+	})
 }
@@ -0,0 +1,110 @@
+package layerhub
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+type SortField string
+
+const (
+	SortID        SortField = "id"
+	SortName      SortField = "name"
+	SortCreatedAt SortField = "created_at"
+	SortUpdatedAt SortField = "updated_at"
+)
+
+// cursorTimeLayout is the layout of the time values of a cursor, it's
+// compared against DATETIME columns
+const cursorTimeLayout = "2006-01-02 15:04:05"
+
+func ParseSortField(s string) (SortField, error) {
+	switch f := SortField(s); f {
+	case "", SortID, SortName, SortCreatedAt, SortUpdatedAt:
+		return f, nil
+	default:
+		return "", errors.Validation(fmt.Sprintf("can't sort by '%s'", s))
+	}
+}
+
+// Cursor is the position of the last item of a page, the value of the sort
+// field and the ID break ties between items with the same value
+type Cursor struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// Encode returns the cursor as an opaque string
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Validation("invalid cursor")
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, errors.Validation("invalid cursor")
+	}
+
+	return &c, nil
+}
+
+// NextCursor returns the cursor of the page after items, items is a slice of
+// db structs. It's empty when items is the last page
+func NextCursor(filter *Filter, items any) string {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice || v.Len() == 0 || filter.Limit == 0 || v.Len() < filter.Limit {
+		return ""
+	}
+
+	last := reflect.Indirect(v.Index(v.Len() - 1))
+
+	id, ok := columnValue(last, string(SortID))
+	if !ok {
+		return ""
+	}
+
+	sortBy := filter.SortBy
+	if sortBy == "" {
+		sortBy = SortID
+	}
+
+	value, ok := columnValue(last, string(sortBy))
+	if !ok {
+		return ""
+	}
+
+	return (&Cursor{Value: value, ID: id}).Encode()
+}
+
+// columnValue returns the value of the struct field mapped to the column,
+// fields without a db tag are mapped by their lowercase name
+func columnValue(v reflect.Value, column string) (string, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("db")
+		if tag != column && (tag != "" || strings.ToLower(f.Name) != column) {
+			continue
+		}
+
+		switch fv := v.Field(i).Interface().(type) {
+		case time.Time:
+			return fv.UTC().Format(cursorTimeLayout), true
+		default:
+			return fmt.Sprint(fv), true
+		}
+	}
+	return "", false
+}
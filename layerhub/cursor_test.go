@@ -0,0 +1,57 @@
+package layerhub
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextCursor(t *testing.T) {
+	created := time.Date(2022, 6, 1, 10, 30, 0, 0, time.UTC)
+	projects := []Project{
+		{ID: "proj_1", Name: "First", CreatedAt: created},
+		{ID: "proj_2", Name: "Second", CreatedAt: created.Add(time.Hour)},
+	}
+
+	testcases := []struct {
+		name   string
+		filter *Filter
+		items  any
+		want   *Cursor
+	}{
+		{"last page", &Filter{Limit: 3}, projects, nil},
+		{"no limit", &Filter{}, projects, nil},
+		{"empty", &Filter{Limit: 2}, []Project{}, nil},
+		{"by id", &Filter{Limit: 2}, projects, &Cursor{Value: "proj_2", ID: "proj_2"}},
+		{"by name", &Filter{Limit: 2, SortBy: SortName}, projects, &Cursor{Value: "Second", ID: "proj_2"}},
+		{"by time", &Filter{Limit: 2, SortBy: SortCreatedAt}, projects, &Cursor{Value: "2022-06-01 11:30:00", ID: "proj_2"}},
+		{"pointers", &Filter{Limit: 1}, []*Project{&projects[0]}, &Cursor{Value: "proj_1", ID: "proj_1"}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NextCursor(tc.filter, tc.items)
+			if tc.want == nil {
+				if got != "" {
+					t.Fatalf("got cursor %q, want none", got)
+				}
+				return
+			}
+
+			cursor, err := DecodeCursor(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *cursor != *tc.want {
+				t.Errorf("got %+v, want %+v", cursor, tc.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	for _, s := range []string{"", "not base64!", "e30"} {
+		if _, err := DecodeCursor(s); err == nil {
+			t.Errorf("expected an error decoding %q", s)
+		}
+	}
+}
@@ -61,6 +61,16 @@ func (c *Core) FindCustomers(ctx context.Context, filter *Filter) ([]Customer, i
 	return customers, count, nil
 }
 
-func (c *Core) DeleteCustomer(ctx context.Context, id string) error {
-	return c.db.DeleteCustomer(ctx, id)
+// DeleteCustomer starts the job deleting the customer with the rows and the
+// files they own
+func (c *Core) DeleteCustomer(ctx context.Context, customer *Customer) (*TenantJob, error) {
+	job := NewTenantJob(TenantDeleteCustomer)
+	job.CompanyID = customer.CompanyID
+	job.CustomerID = customer.ID
+
+	if err := c.startTenantJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
 }
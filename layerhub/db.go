@@ -2,11 +2,29 @@ package layerhub
 
 import (
 	"context"
+	"time"
 )
 
 type Filter struct {
 	Limit  int
 	Offset int
+	// After continues the listing after the cursor, it's used instead of
+	// Offset
+	After *Cursor
+
+	SortBy   SortField
+	SortDesc bool
+
+	// CreatedAfter, CreatedBefore, UpdatedAfter and UpdatedBefore are
+	// inclusive, zero values are ignored
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+
+	IDs         []string
+	CustomerIDs []string
+	NamePrefix  string
 
 	ID               string
 	ShortID          string
@@ -21,7 +39,34 @@ type Filter struct {
 	Public           *bool
 	UsedInTemplate   *bool
 	AuthSource       AuthSource
-
+	DesignID         string
+	MockupTemplateID string
+	ProjectID        string
+	Status           string
+	Published        *bool
+
+	// FolderID filters the items stored directly in the folder, the items of
+	// its subfolders aren't included. Folders are filtered by their parent
+	// and RootFolder filters the items outside of any folder
+	FolderID string
+
+	// Tag, Color, Orientation, License and the frame size filter templates
+	Tag         string
+	Color       string
+	Orientation string
+	License     string
+	FrameWidth  float64
+	FrameHeight float64
+	FrameUnit   FrameUnit
+
+	// Rows in the trash are excluded unless Trashed lists only them or
+	// WithTrashed lists them with the rest. TrashedBefore lists the rows
+	// moved to the trash before the time
+	Trashed       bool
+	WithTrashed   bool
+	TrashedBefore time.Time
+
+	PublicOrCompanyID  string
 	OptionalCustomerID string
 	OptionalCompanyID  string
 	OptionalUserID     string
@@ -31,12 +76,18 @@ func (f *Filter) WithoutPagination() *Filter {
 	fc := *f
 	fc.Limit = 0
 	fc.Offset = 0
+	fc.After = nil
 	return &fc
 }
 
 type DB interface {
+	// WithTx runs fn as a unit of work, the writes made through tx are
+	// committed together when fn returns nil and discarded otherwise
+	WithTx(ctx context.Context, fn func(tx DB) error) error
+
 	PutUser(ctx context.Context, user *User) error
 	FindUsers(ctx context.Context, filter *Filter) ([]User, error)
+	DeleteUser(ctx context.Context, id string) error
 
 	PutCompany(ctx context.Context, company *Company) error
 	FindCompanies(ctx context.Context, filter *Filter) ([]Company, error)
@@ -85,12 +136,61 @@ type DB interface {
 
 	PutSubscriptionPlan(ctx context.Context, plan *SubscriptionPlan) error
 	FindSubscriptionPlans(ctx context.Context) ([]SubscriptionPlan, error)
+
+	PutMockupTemplate(ctx context.Context, mt *MockupTemplate) error
+	FindMockupTemplates(ctx context.Context, filter *Filter) ([]MockupTemplate, error)
+	CountMockupTemplates(ctx context.Context, filter *Filter) (int, error)
+	DeleteMockupTemplate(ctx context.Context, id string) error
+
+	PutMockup(ctx context.Context, mockup *Mockup) error
+	FindMockups(ctx context.Context, filter *Filter) ([]Mockup, error)
+	DeleteMockup(ctx context.Context, id string) error
+
+	PutOrder(ctx context.Context, order *Order) error
+	// ClaimOrder saves the status and the update time of the order when its
+	// row still has the given status and update time, it reports whether the
+	// row was updated
+	ClaimOrder(ctx context.Context, order *Order, status OrderStatus, updatedAt time.Time) (bool, error)
+	FindOrders(ctx context.Context, filter *Filter) ([]Order, error)
+	CountOrders(ctx context.Context, filter *Filter) (int, error)
+	// DeleteOrder deletes the order with its items
+	DeleteOrder(ctx context.Context, id string) error
+
+	// PutProof saves the proof, a new proof with the version of another proof
+	// of the project is a conflict
+	PutProof(ctx context.Context, proof *Proof) error
+	FindProofs(ctx context.Context, filter *Filter) ([]Proof, error)
+	PutProofComment(ctx context.Context, comment *ProofComment) error
+	// DeleteProof deletes the proof with its comments
+	DeleteProof(ctx context.Context, id string) error
+
+	PutFolder(ctx context.Context, folder *Folder) error
+	FindFolders(ctx context.Context, filter *Filter) ([]Folder, error)
+	CountFolders(ctx context.Context, filter *Filter) (int, error)
+	DeleteFolder(ctx context.Context, id string) error
+
+	PutTenantJob(ctx context.Context, job *TenantJob) error
+	// ClaimTenantJob saves the status and the update time of the job when
+	// its row still has the given status and update time, it reports
+	// whether the row was updated
+	ClaimTenantJob(ctx context.Context, job *TenantJob, status TenantJobStatus, updatedAt time.Time) (bool, error)
+	FindTenantJobs(ctx context.Context, filter *Filter) ([]TenantJob, error)
 }
 
+// JSONDB stores the designs as documents, it's the design store of
+// DesignStorageJSONDB
 type JSONDB interface {
 	PutTemplate(ctx context.Context, template *Template) error
 	FindTemplates(ctx context.Context, filter *Filter) ([]Template, error)
 	DeleteTemplate(ctx context.Context, id string) error
 
+	PutProject(ctx context.Context, project *Project) error
+	FindProjects(ctx context.Context, filter *Filter) ([]Project, error)
+	DeleteProject(ctx context.Context, id string) error
+
+	PutComponent(ctx context.Context, component *Component) error
+	FindComponents(ctx context.Context, filter *Filter) ([]Component, error)
+	DeleteComponent(ctx context.Context, id string) error
+
 	Close(ctx context.Context) error
 }
@@ -2,7 +2,6 @@ package layerhub
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
@@ -22,6 +21,7 @@ type Template struct {
 	Description string    `json:"description" bson:"description"`
 	Public      bool      `json:"public" db:"public"`
 	Published   bool      `json:"published" bson:"published" db:"published"`
+	FolderID    string    `json:"folder_id" bson:"folder_id" db:"folder_id"`
 	Tags        []string  `json:"tags" bson:"tags"`
 	Colors      []string  `json:"colors" bson:"colors"`
 	CustomerID  string    `json:"customer_id" db:"customer_id"`
@@ -29,6 +29,9 @@ type Template struct {
 	CreatedAt   time.Time `json:"created_at" bson:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at" db:"updated_at"`
 
+	// DeletedAt is set while the template is in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty" db:"deleted_at"`
+
 	// Layers is a collection of layers like StaticImage, StaticPath, etc.
 	Layers []*Layer `json:"layers" bson:"layers"`
 
@@ -54,17 +57,78 @@ func (t *Template) Key() string {
 	return string(t.ID) + ".layerhub"
 }
 
-func (c *Core) uploadDesign(ctx context.Context, dsg Design) {
-	json, err := json.Marshal(dsg)
+// putDesign writes the design with put and stores its content as a unit of
+// work. The design store isn't transactional so storing the content is the
+// last step before the commit and a failed store rolls back the write. If the
+// commit fails after it the previous content is stored back, or the content of
+// a new design is deleted
+func (c *Core) putDesign(ctx context.Context, dsg Design, put func(db DB) error) error {
+	existed, err := c.designExists(ctx, dsg)
 	if err != nil {
-		c.Logger.Errorf("design upload: %s", err)
-		return
+		return err
+	}
+
+	// The content of designs in the db is restored on failure, it can't be
+	// told apart from the content of a new design if it can't be read. New
+	// designs aren't read, stores like S3 without the permission to list
+	// the bucket deny the reads of missing files
+	previous := emptyDesign(dsg)
+	if existed {
+		if err := c.designs.Get(ctx, previous); errors.Is(err, errors.KindNotFound) {
+			existed = false
+		} else if err != nil {
+			return err
+		}
+	}
+
+	stored := false
+	err = c.db.WithTx(ctx, func(tx DB) error {
+		if err := put(tx); err != nil {
+			return err
+		}
+		if err := c.designs.Put(ctx, dsg); err != nil {
+			return err
+		}
+		stored = true
+
+		return nil
+	})
+	if err != nil && stored {
+		var restoreErr error
+		if existed {
+			restoreErr = c.designs.Put(ctx, previous)
+		} else {
+			restoreErr = c.designs.Delete(ctx, dsg)
+		}
+		if restoreErr != nil {
+			c.Logger.Errorf("design store: restoring %s: %s", dsg.Key(), restoreErr)
+		}
+	}
+
+	return err
+}
+
+// designExists reports whether the design has a row in the db, trashed or not
+func (c *Core) designExists(ctx context.Context, dsg Design) (bool, error) {
+	var (
+		count int
+		err   error
+	)
+	switch d := dsg.(type) {
+	case *Template:
+		count, err = c.db.CountTemplates(ctx, &Filter{ID: d.ID, WithTrashed: true})
+	case *Project:
+		count, err = c.db.CountProjects(ctx, &Filter{ID: d.ID, WithTrashed: true})
+	case *Component:
+		count, err = c.db.CountComponents(ctx, &Filter{ID: d.ID, WithTrashed: true})
+	default:
+		return false, errors.Unexpected(fmt.Sprintf("unknown design %T", dsg))
 	}
-	_, err = c.uploader.Upload(ctx, dsg.Key(), json)
 	if err != nil {
-		c.Logger.Errorf("design upload: %s", err)
-		return
+		return false, err
 	}
+
+	return count > 0, nil
 }
 
 func (c *Core) PutTemplate(ctx context.Context, template *Template) error {
@@ -79,13 +143,14 @@ func (c *Core) PutTemplate(ctx context.Context, template *Template) error {
 	}
 	template.Preview = url
 
-	if err := c.db.PutTemplate(ctx, template); err != nil {
+	err = c.putDesign(ctx, template, func(db DB) error {
+		return db.PutTemplate(ctx, template)
+	})
+	if err != nil {
 		return err
 	}
 
-	go c.uploadDesign(ctx, template)
-
-	return nil
+	return c.indexDocument(ctx, templateDocument(template))
 }
 
 func (c *Core) FindTemplates(ctx context.Context, filter *Filter) ([]Template, int, error) {
@@ -111,45 +176,54 @@ func (c *Core) GetTemplate(ctx context.Context, id string) (*Template, error) {
 		return nil, errors.NotFound(fmt.Sprintf("template '%s' not found", id))
 	}
 
-	content, err := c.uploader.Download(ctx, templates[0].Key())
-	if err != nil {
-		return nil, err
-	}
-
-	err = json.Unmarshal(content, &templates[0])
-	if err != nil {
+	if err := c.designs.Get(ctx, &templates[0]); err != nil {
 		return nil, err
 	}
 
 	return &templates[0], nil
 }
 
+// DeleteTemplate moves the template to the trash, it's deleted by PurgeTrash
+// after the retention period
 func (c *Core) DeleteTemplate(ctx context.Context, id string) error {
-	// TODO: Delete template from s3?
-	err := c.db.DeleteTemplate(ctx, id)
+	templates, err := c.db.FindTemplates(ctx, &Filter{ID: id})
 	if err != nil {
 		return err
 	}
-	err = c.db.DeleteFrame(ctx, id)
-	if err != nil {
+	if len(templates) == 0 {
+		return errors.NotFound(fmt.Sprintf("template '%s' not found", id))
+	}
+
+	now := Now()
+	templates[0].DeletedAt = &now
+	if err := c.db.PutTemplate(ctx, &templates[0]); err != nil {
 		return err
 	}
 
-	return nil
+	return c.deleteDocument(ctx, SearchTemplates, id)
 }
 
 // Project is a simplified representation of a Fabric.js canvas
 type Project struct {
-	ID          string    `json:"id"`
+	ID          string    `json:"id" bson:"_id"`
 	ShortID     string    `json:"short_id" db:"short_id"`
 	Type        string    `json:"type" db:"type"`
 	Name        string    `json:"name" db:"name"`
 	CustomerID  string    `json:"customer_id" db:"customer_id"`
 	CompanyID   string    `json:"company_id" db:"company_id"`
 	Description string    `json:"description"`
+	FolderID    string    `json:"folder_id" db:"folder_id"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 
+	// DeletedAt is set while the project is in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+
+	// TemplateID and TemplateUpdatedAt are the template and the revision of
+	// the template the project was started from
+	TemplateID        string     `json:"template_id,omitempty" db:"template_id"`
+	TemplateUpdatedAt *time.Time `json:"template_updated_at,omitempty" db:"template_updated_at"`
+
 	// Layers is a collection of layers like StaticImage, StaticPath, etc.
 	Layers []*Layer `json:"layers"`
 
@@ -189,14 +263,15 @@ func (c *Core) PutProject(ctx context.Context, project *Project) error {
 
 	t2 := time.Now()
 
-	if err := c.db.PutProject(ctx, project); err != nil {
+	err = c.putDesign(ctx, project, func(db DB) error {
+		return db.PutProject(ctx, project)
+	})
+	if err != nil {
 		return err
 	}
 
 	t3 := time.Now()
 
-	go c.uploadDesign(ctx, project)
-
 	c.Logger.Infof("render: %v", t2.Sub(t1).Milliseconds())
 	c.Logger.Infof("db: %v", t3.Sub(t2).Milliseconds())
 
@@ -226,31 +301,26 @@ func (c *Core) GetProject(ctx context.Context, id string) (*Project, error) {
 		return nil, errors.NotFound(fmt.Sprintf("project '%s' not found", id))
 	}
 
-	content, err := c.uploader.Download(ctx, projects[0].Key())
-	if err != nil {
-		return nil, err
-	}
-
-	err = json.Unmarshal(content, &projects[0])
-	if err != nil {
+	if err := c.designs.Get(ctx, &projects[0]); err != nil {
 		return nil, err
 	}
 
 	return &projects[0], nil
 }
 
+// DeleteProject moves the project to the trash
 func (c *Core) DeleteProject(ctx context.Context, id string) error {
-	// TODO: Delete project from s3?
-	err := c.db.DeleteProject(ctx, id)
+	projects, err := c.db.FindProjects(ctx, &Filter{ID: id})
 	if err != nil {
 		return err
 	}
-	err = c.db.DeleteFrame(ctx, id)
-	if err != nil {
-		return err
+	if len(projects) == 0 {
+		return errors.NotFound(fmt.Sprintf("project '%s' not found", id))
 	}
 
-	return nil
+	now := Now()
+	projects[0].DeletedAt = &now
+	return c.db.PutProject(ctx, &projects[0])
 }
 
 type Metadata struct {
@@ -267,6 +337,11 @@ const (
 	Inches      FrameUnit = "in"
 )
 
+// FrameSizeTolerance is the largest difference between two frame sizes that
+// are considered the same, some databases store the sizes as single precision
+// floats
+const FrameSizeTolerance = 0.01
+
 type Frame struct {
 	ID             string    `json:"id,omitempty" db:"id"`
 	Name           string    `json:"name,omitempty" db:"name"`
@@ -274,6 +349,8 @@ type Frame struct {
 	Height         float64   `json:"height" db:"height"`
 	UsedInTemplate bool      `json:"used_in_template" db:"used_in_template"`
 	Unit           FrameUnit `json:"unit" db:"unit"`
+	Bleed          float64   `json:"bleed" db:"bleed"`
+	SafeMargin     float64   `json:"safe_margin" db:"safe_margin"`
 	Preview        string    `json:"preview" db:"preview"`
 	Public         bool      `json:"public" db:"public"`
 	CustomerID     string    `json:"customer_id,omitempty" db:"customer_id"`
@@ -327,11 +404,15 @@ type Component struct {
 	Preview    string    `json:"preview" db:"preview"`
 	CustomerID string    `json:"customer_id" db:"customer_id"`
 	CompanyID  string    `json:"company_id" db:"company_id"`
-	UserID     string    `json:"user_id" db:"user_id"`
+	UserID     string    `json:"user_id" bson:"user_id" db:"user_id"`
 	Public     bool      `json:"public" db:"public"`
+	FolderID   string    `json:"folder_id" db:"folder_id"`
 	CreatedAt  time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
 
+	// DeletedAt is set while the component is in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty" db:"deleted_at"`
+
 	// Layers is a collection of layers like StaticImage, StaticPath, etc.
 	Layers []*Layer `json:"layers" bson:"layers"`
 
@@ -366,13 +447,14 @@ func (c *Core) PutComponent(ctx context.Context, comp *Component) error {
 		return err
 	}
 	comp.Preview = preview
-	if err := c.db.PutComponent(ctx, comp); err != nil {
+	err = c.putDesign(ctx, comp, func(db DB) error {
+		return db.PutComponent(ctx, comp)
+	})
+	if err != nil {
 		return err
 	}
 
-	go c.uploadDesign(ctx, comp)
-
-	return nil
+	return c.indexDocument(ctx, componentDocument(comp))
 }
 
 func (c *Core) GetComponent(ctx context.Context, id string) (*Component, error) {
@@ -385,13 +467,7 @@ func (c *Core) GetComponent(ctx context.Context, id string) (*Component, error)
 		return nil, errors.NotFound(fmt.Sprintf("components '%s' not found", id))
 	}
 
-	content, err := c.uploader.Download(ctx, comps[0].Key())
-	if err != nil {
-		return nil, err
-	}
-
-	err = json.Unmarshal(content, &comps[0])
-	if err != nil {
+	if err := c.designs.Get(ctx, &comps[0]); err != nil {
 		return nil, err
 	}
 
@@ -411,10 +487,21 @@ func (c *Core) FindComponents(ctx context.Context, filter *Filter) ([]Component,
 	return comps, count, nil
 }
 
+// DeleteComponent moves the component to the trash
 func (c *Core) DeleteComponent(ctx context.Context, id string) error {
-	err := c.db.DeleteComponent(ctx, id)
+	comps, err := c.db.FindComponents(ctx, &Filter{ID: id})
 	if err != nil {
 		return err
 	}
-	return nil
+	if len(comps) == 0 {
+		return errors.NotFound(fmt.Sprintf("components '%s' not found", id))
+	}
+
+	now := Now()
+	comps[0].DeletedAt = &now
+	if err := c.db.PutComponent(ctx, &comps[0]); err != nil {
+		return err
+	}
+
+	return c.deleteDocument(ctx, SearchComponents, id)
 }
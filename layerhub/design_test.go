@@ -0,0 +1,124 @@
+package layerhub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/echovl/orderflo-dev/errors"
+	"go.uber.org/zap"
+)
+
+// commitDB runs the units of work and fails their commit with err, it has
+// the rows of the templates in templates
+type commitDB struct {
+	DB
+	err       error
+	templates int
+}
+
+func (db *commitDB) CountTemplates(ctx context.Context, filter *Filter) (int, error) {
+	return db.templates, nil
+}
+
+func (db *commitDB) WithTx(ctx context.Context, fn func(tx DB) error) error {
+	if err := fn(db); err != nil {
+		return err
+	}
+	return db.err
+}
+
+// unreadableDesigns fails the reads of the designs
+type unreadableDesigns struct {
+	*countingDesigns
+}
+
+func (s *unreadableDesigns) Get(ctx context.Context, dsg Design) error {
+	return errors.Unexpected("store unavailable")
+}
+
+func TestPutDesign(t *testing.T) {
+	ctx := context.Background()
+	put := func(db DB) error { return nil }
+
+	testcases := []struct {
+		name     string
+		existing bool
+		store    func(*countingDesigns) designStore
+		commit   error
+		wantErr  bool
+		want     string
+	}{
+		{
+			name:     "committed",
+			existing: true,
+			want:     "new",
+		},
+		{
+			name:     "previous content restored",
+			existing: true,
+			commit:   errors.Unexpected("commit failed"),
+			wantErr:  true,
+			want:     "previous",
+		},
+		{
+			name:    "new content deleted",
+			commit:  errors.Unexpected("commit failed"),
+			wantErr: true,
+		},
+		{
+			name:  "new design not read",
+			store: func(s *countingDesigns) designStore { return &unreadableDesigns{s} },
+			want:  "new",
+		},
+		{
+			name:    "new design deleted without reading it",
+			store:   func(s *countingDesigns) designStore { return &unreadableDesigns{s} },
+			commit:  errors.Unexpected("commit failed"),
+			wantErr: true,
+		},
+		{
+			name:     "unreadable previous content",
+			existing: true,
+			store:    func(s *countingDesigns) designStore { return &unreadableDesigns{s} },
+			commit:   errors.Unexpected("commit failed"),
+			wantErr:  true,
+			want:     "previous",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			designs := &countingDesigns{designs: map[string]Template{}}
+			db := &commitDB{err: tc.commit}
+			if tc.existing {
+				designs.designs["temp_1.layerhub"] = Template{ID: "temp_1", Description: "previous"}
+				db.templates = 1
+			}
+
+			c := &Core{
+				Logger:  zap.NewNop().Sugar(),
+				db:      db,
+				designs: designs,
+			}
+			if tc.store != nil {
+				c.designs = tc.store(designs)
+			}
+
+			err := c.putDesign(ctx, &Template{ID: "temp_1", Description: "new"}, put)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tc.wantErr)
+			}
+
+			got, ok := designs.designs["temp_1.layerhub"]
+			if tc.want == "" {
+				if ok {
+					t.Errorf("got content %q, want it deleted", got.Description)
+				}
+				return
+			}
+			if got.Description != tc.want {
+				t.Errorf("got content %q, want %q", got.Description, tc.want)
+			}
+		})
+	}
+}
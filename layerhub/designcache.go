@@ -0,0 +1,340 @@
+package layerhub
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/echovl/orderflo-dev/db"
+	"github.com/echovl/orderflo-dev/errors"
+	"go.uber.org/zap"
+)
+
+// DesignCacheConfig configures the read-through cache of the designs. A zero
+// Size disables the in-process tier and a nil KeyValueDB the shared one, the
+// cache is disabled without both
+type DesignCacheConfig struct {
+	// Size is the number of designs kept in-process, without a KeyValueDB
+	// the writes of other instances don't invalidate them
+	Size int
+
+	// KeyValueDB is shared by the instances, its entries expire after TTL
+	KeyValueDB db.KeyValueDB
+	TTL        time.Duration
+}
+
+// DesignCacheStats are the counters of the design cache since the core was
+// created
+type DesignCacheStats struct {
+	LocalHits  int64 `json:"local_hits"`
+	RemoteHits int64 `json:"remote_hits"`
+	Misses     int64 `json:"misses"`
+}
+
+// cachedDesigns caches the designs read from the store. Entries are keyed by
+// the design key and hold the revision of the design they were read at, an
+// entry of another revision is a miss. With a shared tier the revision is a
+// token of the KeyValueDB replaced by every Put and Delete, so the writes of
+// an instance invalidate the entries of the others. Without it the entries are
+// checked against the UpdatedAt of the row and only the writes of the instance
+// invalidate them, the in-process tier alone is meant for a single instance
+type cachedDesigns struct {
+	// The counters are first to be aligned for the atomic operations
+	localHits  int64
+	remoteHits int64
+	misses     int64
+
+	designs designStore
+	local   *lru
+	remote  db.KeyValueDB
+	ttl     time.Duration
+	logger  *zap.SugaredLogger
+}
+
+// cachedDesign is the cached content of a design at a revision
+type cachedDesign struct {
+	Revision string          `json:"revision"`
+	Version  time.Time       `json:"version"`
+	Content  json.RawMessage `json:"content"`
+}
+
+func (d *cachedDesign) matches(revision string, version time.Time) bool {
+	return d.Revision == revision && d.Version.Equal(version)
+}
+
+func newCachedDesigns(designs designStore, conf DesignCacheConfig, logger *zap.SugaredLogger) *cachedDesigns {
+	c := &cachedDesigns{
+		designs: designs,
+		remote:  conf.KeyValueDB,
+		ttl:     conf.TTL,
+		logger:  logger,
+	}
+	if conf.Size > 0 {
+		c.local = newLRU(conf.Size)
+	}
+	return c
+}
+
+func (c *cachedDesigns) Put(ctx context.Context, dsg Design) error {
+	defer c.invalidate(ctx, dsg.Key())
+	return c.designs.Put(ctx, dsg)
+}
+
+// Get reads the design over its row, the fields of the row are kept
+func (c *cachedDesigns) Get(ctx context.Context, dsg Design) error {
+	// Designs without a row, like the previous content read by putDesign,
+	// aren't cached
+	version := designVersion(dsg)
+	if version.IsZero() {
+		return c.designs.Get(ctx, dsg)
+	}
+
+	key := dsg.Key()
+	revision, ok := c.revision(ctx, key)
+	if !ok {
+		return c.designs.Get(ctx, dsg)
+	}
+
+	row := reflect.ValueOf(dsg).Elem()
+	saved := reflect.New(row.Type()).Elem()
+	saved.Set(row)
+
+	if content, ok := c.lookup(ctx, key, revision, version); ok {
+		if err := json.Unmarshal(content, dsg); err != nil {
+			return errors.E(errors.KindUnexpected, err)
+		}
+		keepRow(row, saved)
+		return nil
+	}
+	atomic.AddInt64(&c.misses, 1)
+
+	if err := c.designs.Get(ctx, dsg); err != nil {
+		return err
+	}
+	keepRow(row, saved)
+
+	content, err := json.Marshal(dsg)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	c.store(ctx, key, cachedDesign{revision, version, content})
+
+	return nil
+}
+
+func (c *cachedDesigns) Delete(ctx context.Context, dsg Design) error {
+	defer c.invalidate(ctx, dsg.Key())
+	return c.designs.Delete(ctx, dsg)
+}
+
+func (c *cachedDesigns) Stats() DesignCacheStats {
+	return DesignCacheStats{
+		LocalHits:  atomic.LoadInt64(&c.localHits),
+		RemoteHits: atomic.LoadInt64(&c.remoteHits),
+		Misses:     atomic.LoadInt64(&c.misses),
+	}
+}
+
+// DesignCacheStats returns the counters of the design cache, they're zero
+// when the cache is disabled
+func (c *Core) DesignCacheStats() DesignCacheStats {
+	if c.designCache == nil {
+		return DesignCacheStats{}
+	}
+	return c.designCache.Stats()
+}
+
+// revision returns the current revision of the design, it's empty without a
+// shared tier. A design without a revision gets a new one before the store
+// is read, so content read before a concurrent write is never current. It
+// returns false if the revision can't be read or set, the cache is bypassed
+func (c *cachedDesigns) revision(ctx context.Context, key string) (string, bool) {
+	if c.remote == nil {
+		return "", true
+	}
+
+	// A missing key is an error of the KeyValueDB
+	if data, err := c.remote.Get(ctx, revisionKey(key)); err == nil {
+		return string(data), true
+	}
+
+	revision, err := c.bump(ctx, key)
+	if err != nil {
+		c.logger.Warnf("design cache: revision of %s: %s", key, err)
+		return "", false
+	}
+	return revision, true
+}
+
+// bump sets a new revision of the design
+func (c *cachedDesigns) bump(ctx context.Context, key string) (string, error) {
+	revision := UniqueID("rev")
+	if err := c.remote.Set(ctx, revisionKey(key), []byte(revision), c.ttl); err != nil {
+		return "", err
+	}
+	return revision, nil
+}
+
+// lookup returns the cached content of the design at the revision, a hit of
+// the shared tier is kept in-process
+func (c *cachedDesigns) lookup(ctx context.Context, key, revision string, version time.Time) (json.RawMessage, bool) {
+	if c.local != nil {
+		if entry, ok := c.local.get(key); ok && entry.matches(revision, version) {
+			atomic.AddInt64(&c.localHits, 1)
+			return entry.Content, true
+		}
+	}
+
+	if c.remote != nil {
+		data, err := c.remote.Get(ctx, cacheKey(key))
+		if err != nil {
+			return nil, false
+		}
+
+		var entry cachedDesign
+		if err := json.Unmarshal(data, &entry); err != nil || !entry.matches(revision, version) {
+			return nil, false
+		}
+		atomic.AddInt64(&c.remoteHits, 1)
+
+		if c.local != nil {
+			c.local.add(key, entry)
+		}
+		return entry.Content, true
+	}
+
+	return nil, false
+}
+
+func (c *cachedDesigns) store(ctx context.Context, key string, entry cachedDesign) {
+	if c.local != nil {
+		c.local.add(key, entry)
+	}
+
+	if c.remote != nil {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			c.logger.Errorf("design cache: %s", err)
+			return
+		}
+		if err := c.remote.Set(ctx, cacheKey(key), data, c.ttl); err != nil {
+			c.logger.Warnf("design cache: storing %s: %s", key, err)
+		}
+	}
+}
+
+// invalidate replaces the revision of the design, the entries of every
+// instance become stale
+func (c *cachedDesigns) invalidate(ctx context.Context, key string) {
+	if c.local != nil {
+		c.local.remove(key)
+	}
+
+	if c.remote != nil {
+		if _, err := c.bump(ctx, key); err != nil {
+			c.logger.Warnf("design cache: invalidating %s: %s", key, err)
+		}
+		if err := c.remote.Del(ctx, cacheKey(key)); err != nil {
+			c.logger.Warnf("design cache: invalidating %s: %s", key, err)
+		}
+	}
+}
+
+func cacheKey(key string) string {
+	return "design:" + key
+}
+
+func revisionKey(key string) string {
+	return "design-revision:" + key
+}
+
+// designVersion returns the UpdatedAt of the row of the design
+func designVersion(dsg Design) time.Time {
+	switch d := dsg.(type) {
+	case *Template:
+		return d.UpdatedAt
+	case *Project:
+		return d.UpdatedAt
+	case *Component:
+		return d.UpdatedAt
+	}
+	return time.Time{}
+}
+
+// keepRow sets the fields of the db columns of row back to their saved
+// values, the DB has the current row while the content may have been cached
+// before the row was updated
+func keepRow(row, saved reflect.Value) {
+	t := row.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if tag := t.Field(i).Tag.Get("db"); tag != "" && tag != "-" {
+			row.Field(i).Set(saved.Field(i))
+		}
+	}
+}
+
+// lru keeps the most recently used designs
+type lru struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key    string
+	design cachedDesign
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:    size,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+func (l *lru) get(key string) (cachedDesign, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.entries[key]
+	if !ok {
+		return cachedDesign{}, false
+	}
+	l.order.MoveToFront(el)
+
+	return el.Value.(*lruEntry).design, true
+}
+
+func (l *lru) add(key string, design cachedDesign) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if el, ok := l.entries[key]; ok {
+		el.Value.(*lruEntry).design = design
+		l.order.MoveToFront(el)
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(&lruEntry{key, design})
+	if l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (l *lru) remove(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if el, ok := l.entries[key]; ok {
+		l.order.Remove(el)
+		delete(l.entries, key)
+	}
+}
@@ -0,0 +1,180 @@
+package layerhub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/echovl/orderflo-dev/errors"
+	"go.uber.org/zap"
+)
+
+// countingDesigns keeps the designs in memory and counts the reads
+type countingDesigns struct {
+	designs map[string]Template
+	reads   int
+}
+
+func (s *countingDesigns) Put(ctx context.Context, dsg Design) error {
+	s.designs[dsg.Key()] = *dsg.(*Template)
+	return nil
+}
+
+func (s *countingDesigns) Get(ctx context.Context, dsg Design) error {
+	s.reads++
+	found, ok := s.designs[dsg.Key()]
+	if !ok {
+		return errors.NotFound("design not found")
+	}
+	*dsg.(*Template) = found
+	return nil
+}
+
+func (s *countingDesigns) Delete(ctx context.Context, dsg Design) error {
+	delete(s.designs, dsg.Key())
+	return nil
+}
+
+// mapKeyValueDB is a KeyValueDB without expiration
+type mapKeyValueDB map[string][]byte
+
+func (kv mapKeyValueDB) Get(ctx context.Context, key string) ([]byte, error) {
+	val, ok := kv[key]
+	if !ok {
+		return nil, errors.NotFound("key not found")
+	}
+	return val, nil
+}
+
+func (kv mapKeyValueDB) Set(ctx context.Context, key string, val any, expiration time.Duration) error {
+	kv[key] = val.([]byte)
+	return nil
+}
+
+func (kv mapKeyValueDB) Del(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(kv, key)
+	}
+	return nil
+}
+
+func (kv mapKeyValueDB) Close(ctx context.Context) error {
+	return nil
+}
+
+func TestCachedDesigns(t *testing.T) {
+	ctx := context.Background()
+	v1 := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	v2 := v1.Add(time.Minute)
+
+	store := &countingDesigns{designs: map[string]Template{}}
+	remote := mapKeyValueDB{}
+	cache := newCachedDesigns(store, DesignCacheConfig{Size: 1, KeyValueDB: remote}, zap.NewNop().Sugar())
+
+	get := func(id string, version time.Time) Template {
+		t.Helper()
+		tpl := Template{ID: id, UpdatedAt: version}
+		if err := cache.Get(ctx, &tpl); err != nil {
+			t.Fatal(err)
+		}
+		return tpl
+	}
+	want := func(stats DesignCacheStats, reads int) {
+		t.Helper()
+		if got := cache.Stats(); got != stats {
+			t.Errorf("got stats %+v, want %+v", got, stats)
+		}
+		if store.reads != reads {
+			t.Errorf("got %d reads of the store, want %d", store.reads, reads)
+		}
+	}
+
+	if err := cache.Put(ctx, &Template{ID: "a", Description: "first", UpdatedAt: v1}); err != nil {
+		t.Fatal(err)
+	}
+	store.designs["b.layerhub"] = Template{ID: "b", Description: "other", UpdatedAt: v1}
+
+	if tpl := get("a", v1); tpl.Description != "first" {
+		t.Errorf("got template %q, want first", tpl.Description)
+	}
+	want(DesignCacheStats{Misses: 1}, 1)
+
+	if tpl := get("a", v1); tpl.Description != "first" {
+		t.Errorf("got cached template %q, want first", tpl.Description)
+	}
+	want(DesignCacheStats{LocalHits: 1, Misses: 1}, 1)
+
+	// b evicts a from the in-process tier, a is still shared
+	get("b", v1)
+	get("a", v1)
+	want(DesignCacheStats{LocalHits: 1, RemoteHits: 1, Misses: 2}, 2)
+
+	// Another revision of the row is a miss
+	store.designs["a.layerhub"] = Template{ID: "a", Description: "second", UpdatedAt: v2}
+	if tpl := get("a", v2); tpl.Description != "second" {
+		t.Errorf("got stale template %q, want second", tpl.Description)
+	}
+	want(DesignCacheStats{LocalHits: 1, RemoteHits: 1, Misses: 3}, 3)
+
+	// Writes invalidate both tiers
+	if err := cache.Put(ctx, &Template{ID: "a", Description: "third", UpdatedAt: v2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := remote[cacheKey("a.layerhub")]; ok {
+		t.Error("put didn't invalidate the shared tier")
+	}
+	if tpl := get("a", v2); tpl.Description != "third" {
+		t.Errorf("got stale template %q, want third", tpl.Description)
+	}
+	want(DesignCacheStats{LocalHits: 1, RemoteHits: 1, Misses: 4}, 4)
+
+	if err := cache.Delete(ctx, &Template{ID: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Get(ctx, &Template{ID: "a", UpdatedAt: v2}); !errors.Is(err, errors.KindNotFound) {
+		t.Errorf("got %v after delete, want not found", err)
+	}
+
+	// Designs without a revision bypass the cache
+	get("b", time.Time{})
+	want(DesignCacheStats{LocalHits: 1, RemoteHits: 1, Misses: 5}, 6)
+}
+
+func TestCachedDesignsInstances(t *testing.T) {
+	ctx := context.Background()
+	version := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store := &countingDesigns{designs: map[string]Template{}}
+	remote := mapKeyValueDB{}
+	first := newCachedDesigns(store, DesignCacheConfig{Size: 10, KeyValueDB: remote}, zap.NewNop().Sugar())
+	second := newCachedDesigns(store, DesignCacheConfig{Size: 10, KeyValueDB: remote}, zap.NewNop().Sugar())
+
+	get := func(cache *cachedDesigns, row Template) Template {
+		t.Helper()
+		if err := cache.Get(ctx, &row); err != nil {
+			t.Fatal(err)
+		}
+		return row
+	}
+
+	if err := first.Put(ctx, &Template{ID: "a", Description: "first", FolderID: "folder_1", UpdatedAt: version}); err != nil {
+		t.Fatal(err)
+	}
+	get(second, Template{ID: "a", FolderID: "folder_1", UpdatedAt: version})
+
+	// A write of another instance within the same second of the row
+	if err := first.Put(ctx, &Template{ID: "a", Description: "second", FolderID: "folder_1", UpdatedAt: version}); err != nil {
+		t.Fatal(err)
+	}
+	if tpl := get(second, Template{ID: "a", FolderID: "folder_1", UpdatedAt: version}); tpl.Description != "second" {
+		t.Errorf("got stale template %q, want second", tpl.Description)
+	}
+
+	// The fields of the row are kept over the cached ones
+	if tpl := get(second, Template{ID: "a", FolderID: "folder_2", UpdatedAt: version}); tpl.FolderID != "folder_2" || tpl.Description != "second" {
+		t.Errorf("got template %+v, want folder_2 and second", tpl)
+	}
+	if stats := second.Stats(); stats.LocalHits != 1 {
+		t.Errorf("got stats %+v, want a local hit", stats)
+	}
+}
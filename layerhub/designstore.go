@@ -0,0 +1,207 @@
+package layerhub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/upload"
+)
+
+// DesignStorage is where the content of the designs is stored, the DB keeps
+// their rows
+type DesignStorage string
+
+const (
+	// DesignStorageUploader stores the designs as .layerhub files
+	DesignStorageUploader DesignStorage = "uploader"
+	// DesignStorageJSONDB stores the designs as documents of the JSONDB
+	DesignStorageJSONDB DesignStorage = "jsondb"
+)
+
+// designStore keeps the content of templates, projects and components
+type designStore interface {
+	Put(ctx context.Context, dsg Design) error
+	// Get reads the stored design over dsg, dsg has the ID of the design
+	Get(ctx context.Context, dsg Design) error
+	Delete(ctx context.Context, dsg Design) error
+}
+
+// uploaderDesigns stores the designs as JSON files named after their key
+type uploaderDesigns struct {
+	uploader upload.Uploader
+}
+
+func (s *uploaderDesigns) Put(ctx context.Context, dsg Design) error {
+	content, err := json.Marshal(dsg)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	_, err = s.uploader.Upload(ctx, dsg.Key(), content)
+	return err
+}
+
+func (s *uploaderDesigns) Get(ctx context.Context, dsg Design) error {
+	content, err := s.uploader.Download(ctx, dsg.Key())
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, dsg)
+}
+
+func (s *uploaderDesigns) Delete(ctx context.Context, dsg Design) error {
+	return s.uploader.Delete(ctx, dsg.Key())
+}
+
+// jsonDBDesigns stores the designs as documents of the JSONDB
+type jsonDBDesigns struct {
+	db JSONDB
+}
+
+func (s *jsonDBDesigns) Put(ctx context.Context, dsg Design) error {
+	switch d := dsg.(type) {
+	case *Template:
+		return s.db.PutTemplate(ctx, d)
+	case *Project:
+		return s.db.PutProject(ctx, d)
+	case *Component:
+		return s.db.PutComponent(ctx, d)
+	}
+	return errors.Unexpected(fmt.Sprintf("unknown design %T", dsg))
+}
+
+func (s *jsonDBDesigns) Get(ctx context.Context, dsg Design) error {
+	switch d := dsg.(type) {
+	case *Template:
+		found, err := s.db.FindTemplates(ctx, &Filter{ID: d.ID})
+		if err != nil {
+			return err
+		}
+		if len(found) == 0 {
+			return errors.NotFound(fmt.Sprintf("design '%s' not found", d.Key()))
+		}
+		*d = found[0]
+	case *Project:
+		found, err := s.db.FindProjects(ctx, &Filter{ID: d.ID})
+		if err != nil {
+			return err
+		}
+		if len(found) == 0 {
+			return errors.NotFound(fmt.Sprintf("design '%s' not found", d.Key()))
+		}
+		*d = found[0]
+	case *Component:
+		found, err := s.db.FindComponents(ctx, &Filter{ID: d.ID})
+		if err != nil {
+			return err
+		}
+		if len(found) == 0 {
+			return errors.NotFound(fmt.Sprintf("design '%s' not found", d.Key()))
+		}
+		*d = found[0]
+	default:
+		return errors.Unexpected(fmt.Sprintf("unknown design %T", dsg))
+	}
+
+	return nil
+}
+
+func (s *jsonDBDesigns) Delete(ctx context.Context, dsg Design) error {
+	switch d := dsg.(type) {
+	case *Template:
+		return s.db.DeleteTemplate(ctx, d.ID)
+	case *Project:
+		return s.db.DeleteProject(ctx, d.ID)
+	case *Component:
+		return s.db.DeleteComponent(ctx, d.ID)
+	}
+	return errors.Unexpected(fmt.Sprintf("unknown design %T", dsg))
+}
+
+// emptyDesign returns a design of the same kind and ID as dsg
+func emptyDesign(dsg Design) Design {
+	switch d := dsg.(type) {
+	case *Template:
+		return &Template{ID: d.ID}
+	case *Project:
+		return &Project{ID: d.ID}
+	case *Component:
+		return &Component{ID: d.ID}
+	}
+	return nil
+}
+
+// copyBatch is the number of rows read at once by CopyDesigns
+const copyBatch = 100
+
+// CopyDesigns copies the .layerhub files of the designs to the JSONDB before
+// switching to DesignStorageJSONDB. Documents already in the JSONDB are
+// overwritten and designs without a file are skipped, it returns the number
+// of designs copied
+func (c *Core) CopyDesigns(ctx context.Context) (int, error) {
+	from := &uploaderDesigns{uploader: c.uploader}
+	to := &jsonDBDesigns{db: c.jsonDB}
+
+	copied := 0
+	copyDesign := func(dsg Design) error {
+		if err := from.Get(ctx, dsg); err != nil {
+			c.Logger.Warnf("copy designs: skipping %s: %s", dsg.Key(), err)
+			return nil
+		}
+		if err := to.Put(ctx, dsg); err != nil {
+			return err
+		}
+		copied++
+		return nil
+	}
+
+	for offset := 0; ; offset += copyBatch {
+		templates, err := c.db.FindTemplates(ctx, &Filter{Limit: copyBatch, Offset: offset})
+		if err != nil {
+			return copied, err
+		}
+		for i := range templates {
+			if err := copyDesign(&templates[i]); err != nil {
+				return copied, err
+			}
+		}
+		if len(templates) < copyBatch {
+			break
+		}
+	}
+
+	for offset := 0; ; offset += copyBatch {
+		projects, err := c.db.FindProjects(ctx, &Filter{Limit: copyBatch, Offset: offset})
+		if err != nil {
+			return copied, err
+		}
+		for i := range projects {
+			if err := copyDesign(&projects[i]); err != nil {
+				return copied, err
+			}
+		}
+		if len(projects) < copyBatch {
+			break
+		}
+	}
+
+	for offset := 0; ; offset += copyBatch {
+		comps, err := c.db.FindComponents(ctx, &Filter{Limit: copyBatch, Offset: offset})
+		if err != nil {
+			return copied, err
+		}
+		for i := range comps {
+			if err := copyDesign(&comps[i]); err != nil {
+				return copied, err
+			}
+		}
+		if len(comps) < copyBatch {
+			break
+		}
+	}
+
+	return copied, nil
+}
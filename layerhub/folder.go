@@ -0,0 +1,292 @@
+package layerhub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+// RootFolder is the FolderID filter of the items that are not in a folder
+const RootFolder = "root"
+
+// Folder groups templates, projects, components and uploads. Folders are
+// nested through ParentID, an empty ParentID is a top level folder
+type Folder struct {
+	ID         string    `json:"id" db:"id"`
+	Name       string    `json:"name" db:"name"`
+	ParentID   string    `json:"parent_id" db:"parent_id"`
+	CompanyID  string    `json:"company_id" db:"company_id"`
+	CustomerID string    `json:"customer_id" db:"customer_id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// FolderItems are the IDs of the items moved to a folder
+type FolderItems struct {
+	Templates  []string `json:"templates"`
+	Projects   []string `json:"projects"`
+	Components []string `json:"components"`
+	Uploads    []string `json:"uploads"`
+}
+
+func NewFolder() *Folder {
+	now := Now()
+	return &Folder{
+		ID:        UniqueID("folder"),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// ownedBy returns true if the folder belongs to the company and, for
+// customers, to the customer
+func (f *Folder) ownedBy(companyID, customerID string) bool {
+	return f.CompanyID == companyID && (customerID == "" || f.CustomerID == customerID)
+}
+
+// accepts returns true if an item of the company and customer can be stored
+// in the folder, customer folders only hold the items of the customer
+func (f *Folder) accepts(companyID, customerID string) bool {
+	return f.CompanyID == companyID && (f.CustomerID == "" || f.CustomerID == customerID)
+}
+
+func (c *Core) PutFolder(ctx context.Context, folder *Folder) error {
+	folder.Name = strings.TrimSpace(folder.Name)
+	if folder.Name == "" {
+		return errors.Validation("folder name is required")
+	}
+
+	if err := c.validateParent(ctx, folder, folder.ParentID); err != nil {
+		return err
+	}
+
+	return c.db.PutFolder(ctx, folder)
+}
+
+func (c *Core) GetFolder(ctx context.Context, id string) (*Folder, error) {
+	folders, err := c.db.FindFolders(ctx, &Filter{ID: id, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(folders) == 0 {
+		return nil, errors.NotFound(fmt.Sprintf("folder '%s' not found", id))
+	}
+
+	return &folders[0], nil
+}
+
+func (c *Core) FindFolders(ctx context.Context, filter *Filter) ([]Folder, int, error) {
+	folders, err := c.db.FindFolders(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	count, err := c.db.CountFolders(ctx, filter.WithoutPagination())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return folders, count, nil
+}
+
+func (c *Core) RenameFolder(ctx context.Context, folder *Folder, name string) error {
+	folder.Name = name
+	folder.UpdatedAt = Now()
+	return c.PutFolder(ctx, folder)
+}
+
+// MoveFolder moves the folder with its contents inside the parent, an empty
+// parentID moves the folder to the top level
+func (c *Core) MoveFolder(ctx context.Context, folder *Folder, parentID string) error {
+	folder.ParentID = parentID
+	folder.UpdatedAt = Now()
+	return c.PutFolder(ctx, folder)
+}
+
+// validateParent checks that the parent can hold the folder and that it's
+// not the folder itself or one of its subfolders
+func (c *Core) validateParent(ctx context.Context, folder *Folder, parentID string) error {
+	for id := parentID; id != ""; {
+		if id == folder.ID {
+			return errors.Validation("a folder can't be moved inside itself")
+		}
+
+		parent, err := c.GetFolder(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if id == parentID && !parent.accepts(folder.CompanyID, folder.CustomerID) {
+			return errors.Authorization(parent.ID)
+		}
+
+		id = parent.ParentID
+	}
+
+	return nil
+}
+
+// DeleteFolder deletes the folder, its subfolders and every item stored in
+// them as a unit of work. The search documents of the items are removed as
+// they're deleted, they aren't restored if the unit of work is rolled back
+func (c *Core) DeleteFolder(ctx context.Context, folder *Folder) error {
+	return c.withTx(ctx, func(c *Core) error {
+		children, err := c.db.FindFolders(ctx, &Filter{FolderID: folder.ID, CompanyID: folder.CompanyID})
+		if err != nil {
+			return err
+		}
+
+		for i := range children {
+			if err := c.DeleteFolder(ctx, &children[i]); err != nil {
+				return err
+			}
+		}
+
+		if err := c.deleteFolderItems(ctx, folder); err != nil {
+			return err
+		}
+
+		return c.db.DeleteFolder(ctx, folder.ID)
+	})
+}
+
+func (c *Core) deleteFolderItems(ctx context.Context, folder *Folder) error {
+	filter := &Filter{FolderID: folder.ID, CompanyID: folder.CompanyID}
+
+	templates, err := c.db.FindTemplates(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for _, t := range templates {
+		if err := c.DeleteTemplate(ctx, t.ID); err != nil {
+			return err
+		}
+	}
+
+	projects, err := c.db.FindProjects(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for _, p := range projects {
+		if err := c.DeleteProject(ctx, p.ID); err != nil {
+			return err
+		}
+	}
+
+	comps, err := c.db.FindComponents(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for _, comp := range comps {
+		if err := c.DeleteComponent(ctx, comp.ID); err != nil {
+			return err
+		}
+	}
+
+	uploads, err := c.db.FindUploads(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for _, u := range uploads {
+		if err := c.DeleteUpload(ctx, u.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MoveToFolder moves the items of the company, or of the customer if it's not
+// empty, to the folder. An empty folderID moves the items out of any folder
+func (c *Core) MoveToFolder(ctx context.Context, companyID, customerID, folderID string, items *FolderItems) error {
+	var folder *Folder
+	if folderID != "" {
+		f, err := c.GetFolder(ctx, folderID)
+		if err != nil {
+			return err
+		}
+		if !f.ownedBy(companyID, customerID) {
+			return errors.Authorization(f.ID)
+		}
+		folder = f
+	}
+
+	// owned checks the item before it's moved
+	owned := func(id, itemCompanyID, itemCustomerID string) error {
+		if itemCompanyID != companyID || (customerID != "" && itemCustomerID != customerID) {
+			return errors.Authorization(id)
+		}
+		if folder != nil && !folder.accepts(itemCompanyID, itemCustomerID) {
+			return errors.Validation(fmt.Sprintf("'%s' can't be moved to folder '%s'", id, folder.ID))
+		}
+		return nil
+	}
+
+	for _, id := range items.Templates {
+		t, err := c.GetTemplate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := owned(t.ID, t.CompanyID, t.CustomerID); err != nil {
+			return err
+		}
+		t.FolderID = folderID
+		if err := c.saveTemplate(ctx, t); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range items.Projects {
+		p, err := c.GetProject(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := owned(p.ID, p.CompanyID, p.CustomerID); err != nil {
+			return err
+		}
+		p.FolderID = folderID
+		err = c.putDesign(ctx, p, func(db DB) error {
+			return db.PutProject(ctx, p)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range items.Components {
+		comp, err := c.GetComponent(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := owned(comp.ID, comp.CompanyID, comp.CustomerID); err != nil {
+			return err
+		}
+		comp.FolderID = folderID
+		err = c.putDesign(ctx, comp, func(db DB) error {
+			return db.PutComponent(ctx, comp)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range items.Uploads {
+		u, err := c.GetUpload(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := owned(u.ID, u.CompanyID, u.CustomerID); err != nil {
+			return err
+		}
+		u.Folder = folderID
+		u.UpdatedAt = Now()
+		if err := c.db.PutUpload(ctx, u); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,19 +1,20 @@
 package layerhub
 
 import (
-	"bytes"
 	"context"
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"os/exec"
-	"regexp"
-	"strings"
 
 	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/fontinfo"
 )
 
+// maxFontSize is the largest font file downloaded by buildFont
+const maxFontSize = 20 << 20
+
 type Font struct {
 	ID             string `json:"id" db:"id"`
 	Family         string `json:"family" db:"family"`
@@ -26,11 +27,84 @@ type Font struct {
 	CustomerID     string `json:"customer_id,omitempty" db:"customer_id"`
 	CompanyID      string `json:"company_id,omitempty" db:"company_id"`
 	Public         bool   `json:"public" db:"public"`
+
+	// The metadata read from the font file, see buildFont
+	Format       fontinfo.Format    `json:"format" db:"format"`
+	Weight       int                `json:"weight" db:"weight"`
+	Width        int                `json:"width" db:"width"`
+	Italic       bool               `json:"italic" db:"italic"`
+	Axes         FontAxes           `json:"axes,omitempty" db:"axes"`
+	NumGlyphs    int                `json:"num_glyphs" db:"num_glyphs"`
+	Subsets      FontSubsets        `json:"subsets,omitempty" db:"subsets"`
+	Embedding    fontinfo.Embedding `json:"embedding" db:"embedding"`
+	NoSubsetting bool               `json:"no_subsetting" db:"no_subsetting"`
+}
+
+// FontAxes are the design axes of a variable font
+type FontAxes []fontinfo.Axis
+
+func (a FontAxes) Value() (driver.Value, error) {
+	return jsonList(a)
+}
+
+func (a *FontAxes) Scan(src any) error {
+	if err := scanJSONList(src, a, "font axes"); err != nil {
+		return err
+	}
+	if len(*a) == 0 {
+		*a = nil
+	}
+	return nil
+}
+
+// FontSubsets are the scripts covered by a font, like "latin" or "cyrillic"
+type FontSubsets []string
+
+func (s FontSubsets) Value() (driver.Value, error) {
+	return jsonList(s)
+}
+
+func (s *FontSubsets) Scan(src any) error {
+	if err := scanJSONList(src, s, "font subsets"); err != nil {
+		return err
+	}
+	if len(*s) == 0 {
+		*s = nil
+	}
+	return nil
+}
+
+// jsonList encodes a list column, empty lists are stored as []
+func jsonList(list any) (driver.Value, error) {
+	data, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+	if string(data) == "null" {
+		return "[]", nil
+	}
+	return string(data), nil
+}
+
+// scanJSONList decodes a list column into list
+func scanJSONList(src any, list any, name string) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, list)
+	case string:
+		return json.Unmarshal([]byte(src), list)
+	case nil:
+		return nil
+	default:
+		return fmt.Errorf("%s: unsupported type %T", name, src)
+	}
 }
 
 func NewFont() *Font {
 	return &Font{
-		ID: UniqueID("font"),
+		ID:     UniqueID("font"),
+		Weight: 400,
+		Width:  5,
 	}
 }
 
@@ -90,7 +164,7 @@ func (c *Core) DisableFonts(ctx context.Context, userID string, fontIDs []string
 }
 
 func (c *Core) PutFont(ctx context.Context, font *Font) error {
-	err := c.buildFont(font)
+	err := c.buildFont(ctx, font)
 	if err != nil {
 		return err
 	}
@@ -128,48 +202,80 @@ func (c *Core) DeleteFont(ctx context.Context, id string) error {
 	return c.db.DeleteFont(ctx, id)
 }
 
-func (c *Core) buildFont(font *Font) error {
+// buildFont reads the metadata of the font file, names already set on the
+// font are kept
+func (c *Core) buildFont(ctx context.Context, font *Font) error {
 	if font.URL == "" {
 		return nil
 	}
 
-	fontFile, err := os.CreateTemp(os.TempDir(), "tmp")
+	data, err := fetchFont(ctx, c.clientFor(font.URL), font.URL)
 	if err != nil {
 		return err
 	}
-	defer fontFile.Close()
-	defer os.Remove(fontFile.Name())
 
-	resp, err := http.Get(font.URL)
+	info, err := fontinfo.Parse(data)
 	if err != nil {
-		return err
+		return errors.Validation(fmt.Sprintf("font '%s': %s", font.URL, err))
 	}
-	defer resp.Body.Close()
 
-	_, err = io.Copy(fontFile, resp.Body)
-	if err != nil {
-		return err
+	if font.Family == "" {
+		font.Family = info.Family
 	}
+	if font.FullName == "" {
+		font.FullName = info.FullName
+	}
+	if font.Style == "" {
+		font.Style = info.Subfamily
+	}
+	if font.Category == "" {
+		font.Category = info.Category
+	}
+	font.PostscriptName = info.PostscriptName
+	font.Format = info.Format
+	font.Weight = info.Weight
+	font.Width = info.Width
+	font.Italic = info.Italic
+	font.Axes = info.Axes
+	font.NumGlyphs = info.NumGlyphs
+	font.Subsets = info.Subsets
+	font.Embedding = info.Embedding
+	font.NoSubsetting = info.NoSubsetting
+	if len(font.Axes) == 0 {
+		font.Axes = nil
+	}
+	if len(font.Subsets) == 0 {
+		font.Subsets = nil
+	}
+
+	return nil
+}
 
-	scanOut := &bytes.Buffer{}
-	fcScan := exec.Command("fc-scan", fontFile.Name())
-	fcScan.Stdout = scanOut
-	fcScan.Stderr = os.Stderr
+// fetchFont downloads the font file with the client, files larger than
+// maxFontSize are rejected
+func fetchFont(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Validation(fmt.Sprintf("font '%s': %s", url, err))
+	}
 
-	err = fcScan.Run()
+	resp, err := client.Do(req)
 	if err != nil {
-		return err
+		return nil, errors.Validation(fmt.Sprintf("font '%s': %s", url, err))
 	}
+	defer resp.Body.Close()
 
-	scanLines := strings.Split(scanOut.String(), "\n")
-	for _, line := range scanLines {
-		if strings.Contains(line, "postscriptname") {
-			postscriptName := regexp.MustCompile(`".*"`).FindAll([]byte(line), 1)
-			if len(postscriptName) == 1 {
-				font.PostscriptName = strings.Trim(string(postscriptName[0]), `"`)
-			}
-		}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Validation(fmt.Sprintf("font '%s': status %d", url, resp.StatusCode))
 	}
 
-	return nil
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFontSize+1))
+	if err != nil {
+		return nil, errors.Errorf("font: %s", err)
+	}
+	if len(data) > maxFontSize {
+		return nil, errors.Validation(fmt.Sprintf("font '%s' is larger than %d bytes", url, maxFontSize))
+	}
+
+	return data, nil
 }
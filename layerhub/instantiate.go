@@ -0,0 +1,127 @@
+package layerhub
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+// UseTemplate starts a project for the customer from the template, the layers
+// are copied with new IDs and the params are set as the initial values of
+// the dynamic layers bound to them
+func (c *Core) UseTemplate(ctx context.Context, template *Template, companyID, customerID string, params map[string]any) (*Project, error) {
+	layers, err := copyLayers(template.Layers)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyParams(layers, params); err != nil {
+		return nil, err
+	}
+
+	revision := template.UpdatedAt
+
+	project := NewProject()
+	project.Type = template.Type
+	project.Name = template.Name
+	project.Description = template.Description
+	project.CompanyID = companyID
+	project.CustomerID = customerID
+	project.TemplateID = template.ID
+	project.TemplateUpdatedAt = &revision
+	project.Frame = template.Frame
+	project.Frame.ID = ""
+	project.Layers = layers
+
+	if err := c.PutProject(ctx, project); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// copyLayers returns a deep copy of the layers, every layer including nested
+// objects gets a new ID
+func copyLayers(layers []*Layer) ([]*Layer, error) {
+	data, err := json.Marshal(layers)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	copied := []*Layer{}
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	renewLayerIDs(copied)
+
+	return copied, nil
+}
+
+func renewLayerIDs(layers []*Layer) {
+	for _, layer := range layers {
+		layer.ID = UniqueID("layer")
+		if objects := layer.Objects(); objects != nil {
+			renewLayerIDs(objects)
+		}
+	}
+}
+
+// applyParams sets the params as the values of the dynamic layers, dynamic
+// images become static images since they have no value of their own. Params
+// the layers don't use are ignored
+func applyParams(layers []*Layer, params map[string]any) error {
+	if len(params) == 0 {
+		return nil
+	}
+
+	schema := DesignParams(layers)
+	values := map[string]string{}
+	for _, p := range schema.Params {
+		raw, ok := params[p.Key]
+		if !ok || raw == nil {
+			continue
+		}
+
+		value := paramString(raw)
+		if err := p.validate(value); err != nil {
+			return err
+		}
+		values[p.Key] = value
+	}
+
+	setParams(layers, values)
+
+	return nil
+}
+
+func setParams(layers []*Layer, values map[string]string) {
+	for _, layer := range layers {
+		switch props := layer.Props.(type) {
+		case *DynamicTextProps:
+			for i, kv := range props.KeyValues {
+				if v, ok := values[kv.Key]; ok {
+					props.KeyValues[i].Value = v
+				}
+			}
+		case *DynamicImageProps:
+			if v, ok := values[props.Key]; ok {
+				layer.Type = LayerStaticImage
+				layer.Props = &StaticImageProps{Src: v}
+			}
+		case *DynamicQRCodeProps:
+			if v, ok := values[props.Key]; ok {
+				props.Data = v
+			}
+		case *BarcodeProps:
+			if v, ok := values[props.Key]; ok && props.Key != "" {
+				props.Data = v
+			}
+		}
+
+		if objects := layer.Objects(); objects != nil {
+			setParams(objects, values)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package layerhub
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCopyLayers(t *testing.T) {
+	var layers []*Layer
+	err := json.Unmarshal([]byte(`[
+		{"id":"a","type":"StaticText","text":"hi"},
+		{"id":"b","type":"Group","objects":[{"id":"c","type":"DynamicImage","key":"logo"}]}
+	]`), &layers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	copied, err := copyLayers(layers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := map[string]bool{}
+	var walk func([]*Layer)
+	walk = func(ls []*Layer) {
+		for _, l := range ls {
+			if l.ID == "" || l.ID == "a" || l.ID == "b" || l.ID == "c" || ids[l.ID] {
+				t.Errorf("layer %s wasn't given a new ID", l.ID)
+			}
+			ids[l.ID] = true
+			walk(l.Objects())
+		}
+	}
+	walk(copied)
+
+	if len(ids) != 3 {
+		t.Errorf("got %d layers, want 3", len(ids))
+	}
+
+	copied[0].Props.(*StaticTextProps).Text = "bye"
+	if layers[0].Props.(*StaticTextProps).Text != "hi" {
+		t.Errorf("copy shares props with the template")
+	}
+}
+
+func TestApplyParams(t *testing.T) {
+	testcases := []struct {
+		name    string
+		params  map[string]any
+		want    [3]string
+		wantErr bool
+	}{
+		{"no params", nil, [3]string{"you", "DynamicImage", ""}, false},
+		{"text and qr", map[string]any{"name": "Jane", "url": "https://example.com", "other": 1}, [3]string{"Jane", "DynamicImage", "https://example.com"}, false},
+		{"json numbers", map[string]any{"name": float64(1000000), "url": 2.5}, [3]string{"1000000", "DynamicImage", "2.5"}, false},
+		{"image becomes static", map[string]any{"photo": "https://example.com/a.png"}, [3]string{"you", "StaticImage", ""}, false},
+		{"invalid image", map[string]any{"photo": "a.png"}, [3]string{}, true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var layers []*Layer
+			err := json.Unmarshal([]byte(`[
+				{"type":"DynamicText","text":"Hi {{name}}","keyValues":[{"key":"name","value:":"you"}]},
+				{"type":"DynamicImage","key":"photo"},
+				{"type":"DynamicQRCode","key":"url"}
+			]`), &layers)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = applyParams(layers, tc.params)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := [3]string{
+				layers[0].Props.(*DynamicTextProps).KeyValues[0].Value,
+				string(layers[1].Type),
+				layers[2].Props.(*DynamicQRCodeProps).Data,
+			}
+			if got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -3,32 +3,37 @@ package layerhub
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
 
+	"github.com/echovl/orderflo-dev/errors"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
 type LayerType string
 
 const (
-	LayerStaticVector LayerType = "StaticVector"
-	LayerStaticGroup  LayerType = "StaticGroup"
-	LayerDynamicGroup LayerType = "DynamicGroup"
-	LayerStaticPath   LayerType = "StaticPath"
-	LayerDynamicPath  LayerType = "DynamicPath"
-	LayerStaticImage  LayerType = "StaticImage"
-	LayerStaticVideo  LayerType = "StaticVideo"
-	LayerStaticAudio  LayerType = "StaticAudio"
-	LayerDynamicImage LayerType = "DynamicImage"
-	LayerStaticText   LayerType = "StaticText"
-	LayerDynamicText  LayerType = "DynamicText"
-	LayerBackground   LayerType = "Background"
-	LayerFrame        LayerType = "Frame"
-	LayerGroup        LayerType = "Group"
+	LayerStaticVector  LayerType = "StaticVector"
+	LayerStaticGroup   LayerType = "StaticGroup"
+	LayerDynamicGroup  LayerType = "DynamicGroup"
+	LayerStaticPath    LayerType = "StaticPath"
+	LayerDynamicPath   LayerType = "DynamicPath"
+	LayerStaticImage   LayerType = "StaticImage"
+	LayerStaticVideo   LayerType = "StaticVideo"
+	LayerStaticAudio   LayerType = "StaticAudio"
+	LayerDynamicImage  LayerType = "DynamicImage"
+	LayerStaticText    LayerType = "StaticText"
+	LayerDynamicText   LayerType = "DynamicText"
+	LayerBackground    LayerType = "Background"
+	LayerFrame         LayerType = "Frame"
+	LayerGroup         LayerType = "Group"
+	LayerStaticQRCode  LayerType = "StaticQRCode"
+	LayerDynamicQRCode LayerType = "DynamicQRCode"
+	LayerBarcode       LayerType = "Barcode"
 )
 
 var (
@@ -129,10 +134,15 @@ type StaticTextProps struct {
 	Lineheight  float64     `json:"lineheight" bson:"lineheight"`
 	Fill        string      `json:"fill,omitempty" bson:"fill,omitempty"`
 	Text        string      `json:"text" bson:"text"`
+	TextFit     TextFit     `json:"textFit,omitempty" bson:"textFit,omitempty"`
+	MinFontSize float64     `json:"minFontSize,omitempty" bson:"minFontSize,omitempty"`
 }
 
+// DynamicTextProps are text props whose text has {{key}} placeholders bound to
+// the render params, KeyValues hold the default values
 type DynamicTextProps struct {
-	KeyValues []KeyValue `json:"keyValues,omitempty" bson:"keyValues,omitempty"`
+	StaticTextProps `bson:"inline"`
+	KeyValues       []KeyValue `json:"keyValues,omitempty" bson:"keyValues,omitempty"`
 }
 
 type StaticVectorProps struct {
@@ -150,7 +160,48 @@ type StaticPathProps struct {
 }
 
 type GroupProps struct {
-	Objects []*Layer `json:"objects"`
+	Objects []*Layer `json:"objects" bson:"objects"`
+}
+
+type DynamicGroupProps struct {
+	Key     string   `json:"key" bson:"key"`
+	Objects []*Layer `json:"objects" bson:"objects"`
+}
+
+type DynamicPathProps struct {
+	Key  string  `json:"key" bson:"key"`
+	Path [][]any `json:"path" bson:"path"`
+	Fill string  `json:"fill,omitempty" bson:"fill,omitempty"`
+}
+
+type QRCodeProps struct {
+	Data            string `json:"data" bson:"data"`
+	ErrorCorrection string `json:"errorCorrection,omitempty" bson:"errorCorrection,omitempty"`
+	Fill            string `json:"fill,omitempty" bson:"fill,omitempty"`
+	BackgroundColor string `json:"backgroundColor,omitempty" bson:"backgroundColor,omitempty"`
+	QuietZone       int    `json:"quietZone" bson:"quietZone"`
+}
+
+// DynamicQRCodeProps are bound to the render param named Key, Data is used
+// when the param is missing
+type DynamicQRCodeProps struct {
+	Key         string `json:"key" bson:"key"`
+	QRCodeProps `bson:"inline"`
+}
+
+// BarcodeProps are bound to the render param named Key if it's set
+type BarcodeProps struct {
+	Format          BarcodeFormat `json:"format" bson:"format"`
+	Key             string        `json:"key,omitempty" bson:"key,omitempty"`
+	Data            string        `json:"data" bson:"data"`
+	Fill            string        `json:"fill,omitempty" bson:"fill,omitempty"`
+	BackgroundColor string        `json:"backgroundColor,omitempty" bson:"backgroundColor,omitempty"`
+	QuietZone       int           `json:"quietZone" bson:"quietZone"`
+}
+
+// FrameProps are the props of the page a design is drawn on
+type FrameProps struct {
+	Fill string `json:"fill,omitempty" bson:"fill,omitempty"`
 }
 
 type GroupMetadata struct {
@@ -163,7 +214,9 @@ func (m *GroupMetadata) SetPreviewURL(url string) {
 	m.PreviewURL = url
 }
 
-func NewLayer(b BaseLayer) *Layer {
+// NewLayer returns a layer with empty props for its type, unknown types are
+// rejected with a validation error
+func NewLayer(b BaseLayer) (*Layer, error) {
 	l := &Layer{BaseLayer: b}
 
 	switch b.Type {
@@ -185,18 +238,49 @@ func NewLayer(b BaseLayer) *Layer {
 		l.Props = &StaticPathProps{}
 	case LayerBackground:
 		l.Props = &BackgroundProps{}
-	case LayerGroup:
+	case LayerGroup, LayerStaticGroup:
 		l.Props = &GroupProps{}
+	case LayerDynamicGroup:
+		l.Props = &DynamicGroupProps{}
+	case LayerDynamicPath:
+		l.Props = &DynamicPathProps{}
+	case LayerFrame:
+		l.Props = &FrameProps{}
+	case LayerStaticQRCode:
+		l.Props = &QRCodeProps{QuietZone: QRQuietZone}
+	case LayerDynamicQRCode:
+		l.Props = &DynamicQRCodeProps{QRCodeProps: QRCodeProps{QuietZone: QRQuietZone}}
+	case LayerBarcode:
+		l.Props = &BarcodeProps{QuietZone: BarcodeQuietZone}
 	default:
-		panic("unknown layer type " + b.Type)
+		return nil, errors.Validation(fmt.Sprintf("unknown layer type '%s'", b.Type))
 	}
 
-	return l
+	return l, nil
+}
+
+// Objects returns the nested layers of groups, nil for other layers
+func (l *Layer) Objects() []*Layer {
+	switch p := l.Props.(type) {
+	case *GroupProps:
+		return p.Objects
+	case *DynamicGroupProps:
+		return p.Objects
+	default:
+		return nil
+	}
 }
 
 // persistLayerResources uploads or saves layer resources that may expire, like images, videos, etc
 func (c *Core) persistLayerResources(ctx context.Context, layers []*Layer) error {
 	for _, layer := range layers {
+		if objects := layer.Objects(); objects != nil {
+			if err := c.persistLayerResources(ctx, objects); err != nil {
+				return err
+			}
+			continue
+		}
+
 		if layer.Type == LayerStaticImage {
 			props, ok := layer.Props.(*StaticImageProps)
 			if !ok {
@@ -256,7 +340,10 @@ func (l *Layer) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	ll := NewLayer(raw.BaseLayer)
+	ll, err := NewLayer(raw.BaseLayer)
+	if err != nil {
+		return err
+	}
 	ll.GroupMetadata = raw.GroupMetadata
 	if err := json.Unmarshal(data, ll.Props); err != nil {
 		return err
@@ -321,6 +408,36 @@ func (l *Layer) MarshalJSON() ([]byte, error) {
 			layer
 			*GroupProps
 		}{layer(*l), p}
+	case *DynamicGroupProps:
+		ll = struct {
+			layer
+			*DynamicGroupProps
+		}{layer(*l), p}
+	case *DynamicPathProps:
+		ll = struct {
+			layer
+			*DynamicPathProps
+		}{layer(*l), p}
+	case *FrameProps:
+		ll = struct {
+			layer
+			*FrameProps
+		}{layer(*l), p}
+	case *QRCodeProps:
+		ll = struct {
+			layer
+			*QRCodeProps
+		}{layer(*l), p}
+	case *DynamicQRCodeProps:
+		ll = struct {
+			layer
+			*DynamicQRCodeProps
+		}{layer(*l), p}
+	case *BarcodeProps:
+		ll = struct {
+			layer
+			*BarcodeProps
+		}{layer(*l), p}
 	default:
 		ll = struct {
 			layer
@@ -342,7 +459,10 @@ func (l *Layer) UnmarshalBSON(data []byte) error {
 		return err
 	}
 
-	ll := NewLayer(raw.BaseLayer)
+	ll, err := NewLayer(raw.BaseLayer)
+	if err != nil {
+		return err
+	}
 	ll.GroupMetadata = raw.GroupMetadata
 	if err := bson.Unmarshal(data, ll.Props); err != nil {
 		return err
@@ -0,0 +1,131 @@
+package layerhub
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestLayerRoundTrip(t *testing.T) {
+	nested := `{"type":"StaticPath","path":[["M",0,0],["L",10,10]],"fill":"#000"}`
+	for i := 0; i < 5; i++ {
+		typ := LayerGroup
+		if i%2 == 1 {
+			typ = LayerStaticGroup
+		}
+		nested = fmt.Sprintf(`{"type":"%s","objects":[%s,{"type":"DynamicImage","key":"logo%d"}]}`, typ, nested, i)
+	}
+
+	testcases := []struct {
+		name  string
+		layer string
+		props any
+	}{
+		{"static vector", `{"type":"StaticVector","src":"a.svg","colorMap":{"#000":"#fff"}}`, &StaticVectorProps{}},
+		{"static group", `{"type":"StaticGroup","objects":[{"type":"StaticText","text":"hi"}]}`, &GroupProps{}},
+		{"dynamic group", `{"type":"DynamicGroup","key":"badge","objects":[{"type":"StaticImage","src":"a.png"}]}`, &DynamicGroupProps{}},
+		{"static path", `{"type":"StaticPath","path":[["M",0,0],["Z"]],"fill":"red"}`, &StaticPathProps{}},
+		{"dynamic path", `{"type":"DynamicPath","key":"outline","path":[["M",0,0],["Z"]],"fill":"red"}`, &DynamicPathProps{}},
+		{"static image", `{"type":"StaticImage","src":"a.png","cropX":1,"cropY":2}`, &StaticImageProps{}},
+		{"static video", `{"type":"StaticVideo","src":"a.mp4","speedFactor":1,"between":{"from":0,"to":1},"cut":{"from":0,"to":1}}`, &StaticVideoProps{}},
+		{"static audio", `{"type":"StaticAudio","src":"a.mp3","speedFactor":1,"between":{"from":0,"to":1},"cut":{"from":0,"to":1}}`, &StaticAudioProps{}},
+		{"dynamic image", `{"type":"DynamicImage","key":"photo"}`, &DynamicImageProps{}},
+		{"static text", `{"type":"StaticText","text":"hi","fontFamily":"Roboto","fontSize":12,"fontWeight":"bold"}`, &StaticTextProps{}},
+		{"dynamic text", `{"type":"DynamicText","text":"Hi {{name}}","fontSize":12,"textFit":"shrink","minFontSize":8,"keyValues":[{"key":"name","value:":"Jane"}]}`, &DynamicTextProps{}},
+		{"background", `{"type":"Background","fill":"#fff"}`, &BackgroundProps{}},
+		{"frame", `{"type":"Frame","fill":"#fff"}`, &FrameProps{}},
+		{"static qr code", `{"type":"StaticQRCode","data":"hi","errorCorrection":"H","fill":"#000","backgroundColor":"#fff","quietZone":2}`, &QRCodeProps{}},
+		{"dynamic qr code", `{"type":"DynamicQRCode","key":"url","data":"https://example.com","quietZone":4}`, &DynamicQRCodeProps{}},
+		{"barcode", `{"type":"Barcode","format":"code128","key":"sku","data":"ABC-123","quietZone":10}`, &BarcodeProps{}},
+		{"group", `{"type":"Group","objects":[{"type":"Frame"},{"type":"DynamicPath","key":"k","path":[]}]}`, &GroupProps{}},
+		{"deeply nested group", nested, &GroupProps{}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var layer Layer
+			if err := json.Unmarshal([]byte(tc.layer), &layer); err != nil {
+				t.Fatalf("unmarshal json: %s", err)
+			}
+
+			if reflect.TypeOf(layer.Props) != reflect.TypeOf(tc.props) {
+				t.Fatalf("got props %T, want %T", layer.Props, tc.props)
+			}
+
+			want := mustMarshalJSON(t, &layer)
+
+			// JSON
+			var fromJSON Layer
+			if err := json.Unmarshal(want, &fromJSON); err != nil {
+				t.Fatalf("unmarshal json: %s", err)
+			}
+			if got := mustMarshalJSON(t, &fromJSON); !jsonEqual(t, got, want) {
+				t.Errorf("json round trip\ngot  %s\nwant %s", got, want)
+			}
+
+			// BSON
+			doc, err := bson.Marshal(struct {
+				Layer *Layer `bson:"layer"`
+			}{&layer})
+			if err != nil {
+				t.Fatalf("marshal bson: %s", err)
+			}
+
+			var fromBSON struct {
+				Layer *Layer `bson:"layer"`
+			}
+			if err := bson.Unmarshal(doc, &fromBSON); err != nil {
+				t.Fatalf("unmarshal bson: %s", err)
+			}
+			if got := mustMarshalJSON(t, fromBSON.Layer); !jsonEqual(t, got, want) {
+				t.Errorf("bson round trip\ngot  %s\nwant %s", got, want)
+			}
+		})
+	}
+}
+
+func TestLayerObjects(t *testing.T) {
+	var layer Layer
+	err := json.Unmarshal([]byte(`{"type":"DynamicGroup","key":"k","objects":[{"type":"StaticGroup","objects":[{"type":"StaticImage","src":"a.png"}]}]}`), &layer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objects := layer.Objects()
+	if len(objects) != 1 || objects[0].Type != LayerStaticGroup {
+		t.Fatalf("unexpected objects %+v", objects)
+	}
+
+	inner := objects[0].Objects()
+	if len(inner) != 1 || inner[0].Props.(*StaticImageProps).Src != "a.png" {
+		t.Fatalf("unexpected inner objects %+v", inner)
+	}
+
+	if inner[0].Objects() != nil {
+		t.Errorf("expected no objects for %s", inner[0].Type)
+	}
+}
+
+func mustMarshalJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal json: %s", err)
+	}
+	return data
+}
+
+func jsonEqual(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var va, vb any
+	if err := json.Unmarshal(a, &va); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		t.Fatal(err)
+	}
+	return reflect.DeepEqual(va, vb)
+}
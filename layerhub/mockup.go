@@ -0,0 +1,385 @@
+package layerhub
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/imaging"
+)
+
+// MockupPlacement is the quad of the product photo where designs are placed,
+// corners are in clockwise order starting from the top left corner
+type MockupPlacement imaging.Quad
+
+func (p MockupPlacement) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+func (p *MockupPlacement) Scan(src any) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, p)
+	case string:
+		return json.Unmarshal([]byte(src), p)
+	case nil:
+		*p = MockupPlacement{}
+		return nil
+	default:
+		return fmt.Errorf("mockup placement: unsupported type %T", src)
+	}
+}
+
+// MockupTemplate is a product photo where designs can be composited
+type MockupTemplate struct {
+	ID                   string          `json:"id" db:"id"`
+	Name                 string          `json:"name" db:"name"`
+	PhotoURL             string          `json:"photo_url" db:"photo_url"`
+	Placement            MockupPlacement `json:"placement" db:"placement"`
+	ShadingURL           string          `json:"shading_url" db:"shading_url"`
+	DisplacementURL      string          `json:"displacement_url" db:"displacement_url"`
+	DisplacementStrength float64         `json:"displacement_strength" db:"displacement_strength"`
+	Public               bool            `json:"public" db:"public"`
+	CustomerID           string          `json:"customer_id,omitempty" db:"customer_id"`
+	CompanyID            string          `json:"company_id,omitempty" db:"company_id"`
+	CreatedAt            time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+func NewMockupTemplate() *MockupTemplate {
+	now := Now()
+	return &MockupTemplate{
+		ID:        UniqueID("mockup_template"),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Mockup is a cached composite of a design over a mockup template
+type Mockup struct {
+	ID               string    `json:"id" db:"id"`
+	DesignID         string    `json:"design_id" db:"design_id"`
+	MockupTemplateID string    `json:"mockup_template_id" db:"mockup_template_id"`
+	URL              string    `json:"url" db:"url"`
+	DesignUpdatedAt  time.Time `json:"design_updated_at" db:"design_updated_at"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
+
+func NewMockup() *Mockup {
+	return &Mockup{
+		ID:        UniqueID("mockup"),
+		CreatedAt: Now(),
+	}
+}
+
+func (c *Core) PutMockupTemplate(ctx context.Context, mt *MockupTemplate) error {
+	if _, ok := imaging.SquareToQuad(imaging.Quad(mt.Placement)).Inverse(); !ok {
+		return errors.Validation("mockup placement must be a non degenerate quad")
+	}
+	return c.db.PutMockupTemplate(ctx, mt)
+}
+
+func (c *Core) GetMockupTemplate(ctx context.Context, id string) (*MockupTemplate, error) {
+	mts, err := c.db.FindMockupTemplates(ctx, &Filter{ID: id, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(mts) == 0 {
+		return nil, errors.NotFound(fmt.Sprintf("mockup template '%s' not found", id))
+	}
+
+	return &mts[0], nil
+}
+
+func (c *Core) FindMockupTemplates(ctx context.Context, filter *Filter) ([]MockupTemplate, int, error) {
+	mts, err := c.db.FindMockupTemplates(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	count, err := c.db.CountMockupTemplates(ctx, filter.WithoutPagination())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return mts, count, nil
+}
+
+// DeleteMockupTemplate deletes the mockup template with its mockups and their
+// images
+func (c *Core) DeleteMockupTemplate(ctx context.Context, id string) error {
+	mt, err := c.GetMockupTemplate(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return c.deleteMockupTemplate(ctx, mt)
+}
+
+// TemplateMockup returns the template composited over the mockup template,
+// the mockup is generated again if the template changed since the last time
+func (c *Core) TemplateMockup(ctx context.Context, template *Template, mt *MockupTemplate) (*Mockup, error) {
+	return c.mockup(ctx, template.ID, template.UpdatedAt, template, mt)
+}
+
+// ProjectMockup returns the project composited over the mockup template, the
+// mockup is generated again if the project changed since the last time
+func (c *Core) ProjectMockup(ctx context.Context, project *Project, mt *MockupTemplate) (*Mockup, error) {
+	return c.mockup(ctx, project.ID, project.UpdatedAt, project, mt)
+}
+
+func (c *Core) mockup(ctx context.Context, designID string, updatedAt time.Time, sch any, mt *MockupTemplate) (*Mockup, error) {
+	updatedAt = updatedAt.UTC().Truncate(time.Second)
+
+	mockups, err := c.db.FindMockups(ctx, &Filter{
+		DesignID:         designID,
+		MockupTemplateID: mt.ID,
+		Limit:            1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mockup := NewMockup()
+	staleURL := ""
+	if len(mockups) != 0 {
+		cached := &mockups[0]
+		if cached.DesignUpdatedAt.Equal(updatedAt) && !mt.UpdatedAt.After(cached.CreatedAt) {
+			return cached, nil
+		}
+		mockup.ID = cached.ID
+		staleURL = cached.URL
+	}
+
+	img, err := c.composeMockup(ctx, sch, mt)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := c.uploader.Upload(ctx, UniqueID("mockup")+".png", img)
+	if err != nil {
+		return nil, err
+	}
+
+	mockup.DesignID = designID
+	mockup.MockupTemplateID = mt.ID
+	mockup.URL = url
+	mockup.DesignUpdatedAt = updatedAt
+	if err := c.db.PutMockup(ctx, mockup); err != nil {
+		return nil, err
+	}
+
+	// The image of the previous mockup is no longer referenced, failing to
+	// delete it only leaves an orphan file
+	if staleURL != "" && staleURL != url {
+		if err := c.deleteFile(ctx, staleURL); err != nil {
+			c.Logger.Warnf("mockup: deleting '%s': %s", staleURL, err)
+		}
+	}
+
+	return mockup, nil
+}
+
+// composeMockup renders the design and warps it into the mockup placement
+func (c *Core) composeMockup(ctx context.Context, sch any, mt *MockupTemplate) ([]byte, error) {
+	rendered, err := c.renderer.RawRender(ctx, sch, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	design, err := png.Decode(bytes.NewReader(rendered))
+	if err != nil {
+		return nil, errors.Errorf("mockup: decoding design: %s", err)
+	}
+
+	photo, err := fetchImage(ctx, c.clientFor(mt.PhotoURL), mt.PhotoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := imaging.Options{
+		Quad:                 imaging.Quad(mt.Placement),
+		DisplacementStrength: mt.DisplacementStrength,
+	}
+
+	if mt.ShadingURL != "" {
+		opts.Shading, err = fetchImage(ctx, c.clientFor(mt.ShadingURL), mt.ShadingURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if mt.DisplacementURL != "" {
+		opts.Displacement, err = fetchImage(ctx, c.clientFor(mt.DisplacementURL), mt.DisplacementURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out := &bytes.Buffer{}
+	if err := png.Encode(out, imaging.Composite(photo, design, opts)); err != nil {
+		return nil, errors.Errorf("mockup: encoding: %s", err)
+	}
+
+	return out.Bytes(), nil
+}
+
+const (
+	// maxImageSize and maxImagePixels are the largest image files downloaded
+	// and decoded by fetchImage
+	maxImageSize   = 50 << 20
+	maxImagePixels = 50_000_000
+)
+
+// fetchImage downloads and decodes the image, images larger than
+// maxImageSize bytes or maxImagePixels pixels are rejected before decoding
+func fetchImage(ctx context.Context, client *http.Client, url string) (image.Image, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Validation(fmt.Sprintf("fetching image '%s': %s", url, err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("fetching image '%s': %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageSize+1))
+	if err != nil {
+		return nil, errors.Errorf("fetching image '%s': %s", url, err)
+	}
+	if len(data) > maxImageSize {
+		return nil, errors.Validation(fmt.Sprintf("image '%s' is larger than %d bytes", url, maxImageSize))
+	}
+
+	conf, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Errorf("decoding image '%s': %s", url, err)
+	}
+	if conf.Width*conf.Height > maxImagePixels {
+		return nil, errors.Validation(fmt.Sprintf("image '%s' is larger than %d pixels", url, maxImagePixels))
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Errorf("decoding image '%s': %s", url, err)
+	}
+
+	return img, nil
+}
+
+// clientFor returns the HTTP client used to download the user supplied URL,
+// only the files of the uploader are downloaded without restrictions
+func (c *Core) clientFor(fileURL string) *http.Client {
+	if _, ok := c.uploader.Key(fileURL); ok {
+		return http.DefaultClient
+	}
+	return publicClient
+}
+
+// publicClient refuses to connect to private, loopback and link-local
+// addresses so user supplied URLs can't reach internal services. The address
+// is checked after the name is resolved and on every redirect
+var publicClient = &http.Client{
+	Timeout: 30 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: refuseInternalAddress,
+		}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+	},
+}
+
+func refuseInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("address %s is not public", host)
+	}
+	return nil
+}
+
+// isPublicIP reports whether the address is routable on the internet
+func isPublicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+		// Shared address space (RFC 6598)
+		if ip[0] == 100 && ip[1]&0xc0 == 64 {
+			return false
+		}
+	}
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+// deleteMockupsOf deletes the mockups of the design with their images
+func (c *Core) deleteMockupsOf(ctx context.Context, designID string) error {
+	mockups, err := c.db.FindMockups(ctx, &Filter{DesignID: designID})
+	if err != nil {
+		return err
+	}
+
+	for _, m := range mockups {
+		if err := c.deleteFile(ctx, m.URL); err != nil {
+			return err
+		}
+		if err := c.db.DeleteMockup(ctx, m.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Core) deleteMockupTemplate(ctx context.Context, mt *MockupTemplate) error {
+	mockups, err := c.db.FindMockups(ctx, &Filter{MockupTemplateID: mt.ID})
+	if err != nil {
+		return err
+	}
+
+	// The files are deleted first, a failed delete is retried from the rows.
+	// Only the mockup images are uploaded by the core, the photo, shading
+	// and displacement URLs come from the client and may point to files of
+	// someone else
+	for _, m := range mockups {
+		if err := c.deleteFile(ctx, m.URL); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range mockups {
+		if err := c.db.DeleteMockup(ctx, m.ID); err != nil {
+			return err
+		}
+	}
+
+	return c.db.DeleteMockupTemplate(ctx, mt.ID)
+}
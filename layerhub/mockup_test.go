@@ -0,0 +1,92 @@
+package layerhub
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+// encodePNG encodes a small image and sets the size in its header to
+// width x height
+func encodePNG(t *testing.T, width, height uint32) []byte {
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, image.NewGray(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+
+	// The IHDR chunk follows the 8 bytes of the signature, its data starts
+	// after the length and the type of the chunk
+	data := buf.Bytes()
+	ihdr := data[16:29]
+	binary.BigEndian.PutUint32(ihdr[0:4], width)
+	binary.BigEndian.PutUint32(ihdr[4:8], height)
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+
+	return data
+}
+
+func TestFetchImage(t *testing.T) {
+	files := map[string][]byte{
+		"/small.png": encodePNG(t, 2, 2),
+		"/huge.png":  encodePNG(t, 100000, 100000),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(files[r.URL.Path])
+	}))
+	defer server.Close()
+
+	// The test server listens on a loopback address
+	_, err := fetchImage(context.Background(), publicClient, server.URL+"/small.png")
+	if !errors.Is(err, errors.KindValidation) {
+		t.Fatalf("got error %v, want a validation error", err)
+	}
+
+	img, err := fetchImage(context.Background(), server.Client(), server.URL+"/small.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size.X != 2 || size.Y != 2 {
+		t.Errorf("got size %v, want 2x2", size)
+	}
+
+	_, err = fetchImage(context.Background(), server.Client(), server.URL+"/huge.png")
+	if !errors.Is(err, errors.KindValidation) {
+		t.Errorf("got error %v, want a validation error", err)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	testcases := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"10.0.0.1", false},
+		{"172.16.5.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tc := range testcases {
+		if got := isPublicIP(net.ParseIP(tc.ip)); got != tc.want {
+			t.Errorf("isPublicIP(%s) = %v, want %v", tc.ip, got, tc.want)
+		}
+	}
+}
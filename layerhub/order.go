@@ -0,0 +1,241 @@
+package layerhub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+type OrderStatus string
+
+const (
+	OrderDraft       OrderStatus = "draft"
+	OrderSubmitted   OrderStatus = "submitted"
+	OrderReady       OrderStatus = "ready"
+	OrderPrintFailed OrderStatus = "print_failed"
+)
+
+type PrintStatus string
+
+const (
+	PrintPending    PrintStatus = "pending"
+	PrintProcessing PrintStatus = "processing"
+	PrintDone       PrintStatus = "done"
+	PrintFailed     PrintStatus = "failed"
+)
+
+type PrintFormat string
+
+const (
+	PrintPDF PrintFormat = "pdf"
+	PrintPNG PrintFormat = "png"
+)
+
+// DefaultPrintDPI is used for order items without an explicit resolution
+const DefaultPrintDPI = 300
+
+type Order struct {
+	ID          string       `json:"id" db:"id"`
+	Number      string       `json:"number" db:"number"`
+	Status      OrderStatus  `json:"status" db:"status"`
+	CustomerID  string       `json:"customer_id" db:"customer_id"`
+	CompanyID   string       `json:"company_id" db:"company_id"`
+	Items       []*OrderItem `json:"items"`
+	SubmittedAt *time.Time   `json:"submitted_at,omitempty" db:"submitted_at"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+func NewOrder() *Order {
+	now := Now()
+	return &Order{
+		ID:        UniqueID("order"),
+		Number:    fmt.Sprintf("%s-%s", now.Format("060102"), strings.ToUpper(RandomString(6))),
+		Status:    OrderDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// OrderItem is an order line, the print file is generated from the project
+// once the order is submitted
+type OrderItem struct {
+	ID            string      `json:"id" db:"id"`
+	OrderID       string      `json:"-" db:"order_id"`
+	Position      int         `json:"position" db:"position"`
+	ProjectID     string      `json:"project_id" db:"project_id"`
+	ProofID       string      `json:"proof_id,omitempty" db:"proof_id"`
+	SKU           string      `json:"sku" db:"sku"`
+	Quantity      int         `json:"quantity" db:"quantity"`
+	Format        PrintFormat `json:"format" db:"format"`
+	DPI           int         `json:"dpi" db:"dpi"`
+	PrintFileURL  string      `json:"print_file_url,omitempty" db:"print_file_url"`
+	PrintStatus   PrintStatus `json:"print_status,omitempty" db:"print_status"`
+	PrintError    string      `json:"print_error,omitempty" db:"print_error"`
+	PrintAttempts int         `json:"print_attempts" db:"print_attempts"`
+}
+
+func NewOrderItem() *OrderItem {
+	return &OrderItem{
+		ID:       UniqueID("order_item"),
+		Quantity: 1,
+		Format:   PrintPDF,
+		DPI:      DefaultPrintDPI,
+	}
+}
+
+// PrintFileName returns the name of the item's print file, it's unique within
+// the order even if the same SKU is ordered more than once
+func (o *Order) PrintFileName(item *OrderItem) string {
+	return fmt.Sprintf("%s_%s_%d.%s", o.Number, item.SKU, item.Position+1, item.Format)
+}
+
+// PutOrder validates and stores a draft order, every item must reference a
+// project owned by the order's customer
+func (c *Core) PutOrder(ctx context.Context, order *Order) error {
+	if order.Status != OrderDraft {
+		return errors.Validation(fmt.Sprintf("order '%s' is already %s", order.ID, order.Status))
+	}
+
+	if len(order.Items) == 0 {
+		return errors.Validation("order must have at least one item")
+	}
+
+	for i, item := range order.Items {
+		if item.SKU == "" {
+			return errors.Validation(fmt.Sprintf("item %d: sku is required", i))
+		}
+		if item.Quantity < 1 {
+			return errors.Validation(fmt.Sprintf("item %d: quantity must be positive", i))
+		}
+		if item.Format != PrintPDF && item.Format != PrintPNG {
+			return errors.Validation(fmt.Sprintf("item %d: unsupported print format '%s'", i, item.Format))
+		}
+
+		projects, err := c.db.FindProjects(ctx, &Filter{ID: item.ProjectID, Limit: 1})
+		if err != nil {
+			return err
+		}
+		if len(projects) == 0 {
+			return errors.NotFound(fmt.Sprintf("project '%s' not found", item.ProjectID))
+		}
+
+		project := projects[0]
+		if project.CompanyID != order.CompanyID || (order.CustomerID != "" && project.CustomerID != order.CustomerID) {
+			return errors.Authorization(project.ID)
+		}
+
+		item.OrderID = order.ID
+		item.Position = i
+		if item.DPI == 0 {
+			item.DPI = DefaultPrintDPI
+		}
+	}
+
+	return c.db.PutOrder(ctx, order)
+}
+
+func (c *Core) GetOrder(ctx context.Context, id string) (*Order, error) {
+	orders, err := c.db.FindOrders(ctx, &Filter{ID: id, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(orders) == 0 {
+		return nil, errors.NotFound(fmt.Sprintf("order '%s' not found", id))
+	}
+
+	return &orders[0], nil
+}
+
+func (c *Core) FindOrders(ctx context.Context, filter *Filter) ([]Order, int, error) {
+	orders, err := c.db.FindOrders(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	count, err := c.db.CountOrders(ctx, filter.WithoutPagination())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return orders, count, nil
+}
+
+// SubmitOrder submits a draft order and starts the generation of its print
+// files in the background, every project needs an approved proof and orders
+// with projects that fail the preflight checks are rejected
+func (c *Core) SubmitOrder(ctx context.Context, order *Order) error {
+	if order.Status != OrderDraft {
+		return errors.Validation(fmt.Sprintf("order '%s' is already %s", order.ID, order.Status))
+	}
+
+	for i, item := range order.Items {
+		project, err := c.GetProject(ctx, item.ProjectID)
+		if err != nil {
+			return err
+		}
+
+		report, err := c.PreflightProject(ctx, project)
+		if err != nil {
+			return err
+		}
+
+		if report.HasErrors() {
+			issue := report.Errors[0]
+			return errors.Validation(fmt.Sprintf(
+				"item %d: project '%s' failed preflight with %d errors, %s: %s",
+				i, project.ID, len(report.Errors), issue.Path, issue.Message,
+			))
+		}
+
+		proof, err := c.approvedProof(ctx, project)
+		if err != nil {
+			return err
+		}
+		item.ProofID = proof.ID
+	}
+
+	now := Now()
+	order.Status = OrderSubmitted
+	order.SubmittedAt = &now
+	order.UpdatedAt = now
+	for _, item := range order.Items {
+		item.PrintStatus = PrintPending
+		item.PrintError = ""
+	}
+
+	if err := c.db.PutOrder(ctx, order); err != nil {
+		return err
+	}
+
+	go c.runPrintJob(order.ID)
+
+	return nil
+}
+
+// RetryPrintFiles generates again the print files that failed
+func (c *Core) RetryPrintFiles(ctx context.Context, order *Order) error {
+	if order.Status != OrderPrintFailed {
+		return errors.Validation(fmt.Sprintf("order '%s' has no failed print files", order.ID))
+	}
+
+	order.Status = OrderSubmitted
+	order.UpdatedAt = Now()
+	for _, item := range order.Items {
+		if item.PrintStatus == PrintFailed {
+			item.PrintStatus = PrintPending
+			item.PrintError = ""
+		}
+	}
+
+	if err := c.db.PutOrder(ctx, order); err != nil {
+		return err
+	}
+
+	go c.runPrintJob(order.ID)
+
+	return nil
+}
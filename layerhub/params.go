@@ -0,0 +1,215 @@
+package layerhub
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/echovl/orderflo-dev/barcode"
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+type ParamType string
+
+const (
+	ParamText  ParamType = "text"
+	ParamImage ParamType = "image"
+	// ParamPath and ParamGroup values are defined by the renderer, they're
+	// passed to it as they are
+	ParamPath  ParamType = "path"
+	ParamGroup ParamType = "group"
+)
+
+// Param is a render param accepted by a design, it's bound to every dynamic
+// layer with its key
+type Param struct {
+	Key      string    `json:"key"`
+	Type     ParamType `json:"type"`
+	Default  string    `json:"default,omitempty"`
+	Required bool      `json:"required"`
+	// MaxLength is the maximum number of characters, zero means unlimited
+	MaxLength int `json:"max_length,omitempty"`
+	// Pattern is a regular expression the value must match
+	Pattern string `json:"pattern,omitempty"`
+	// Layers are the IDs of the layers bound to the param
+	Layers []string `json:"layers"`
+}
+
+type ParamSchema struct {
+	Params []*Param `json:"params"`
+}
+
+var barcodePatterns = map[BarcodeFormat]string{
+	BarcodeCode128: `^[ -~]+$`,
+	BarcodeEAN13:   `^[0-9]{12,13}$`,
+	BarcodeEAN8:    `^[0-9]{7,8}$`,
+}
+
+// DesignParams derives the param schema of the layers, a key used by several
+// layers has the type and default of the first one and the constraints of all
+// of them
+func DesignParams(layers []*Layer) *ParamSchema {
+	params := map[string]*Param{}
+	collectParams(params, layers)
+
+	schema := &ParamSchema{Params: []*Param{}}
+	for _, p := range params {
+		schema.Params = append(schema.Params, p)
+	}
+	sort.Slice(schema.Params, func(i, j int) bool {
+		return schema.Params[i].Key < schema.Params[j].Key
+	})
+
+	return schema
+}
+
+func collectParams(params map[string]*Param, layers []*Layer) {
+	for _, layer := range layers {
+		switch props := layer.Props.(type) {
+		case *DynamicTextProps:
+			for _, kv := range props.KeyValues {
+				addParam(params, layer, Param{Key: kv.Key, Type: ParamText, Default: kv.Value})
+			}
+		case *DynamicImageProps:
+			addParam(params, layer, Param{Key: props.Key, Type: ParamImage})
+		case *DynamicPathProps:
+			addParam(params, layer, Param{Key: props.Key, Type: ParamPath})
+		case *DynamicGroupProps:
+			addParam(params, layer, Param{Key: props.Key, Type: ParamGroup})
+		case *DynamicQRCodeProps:
+			max := barcode.QRCapacity(barcode.M)
+			if level, ok := qrLevels[strings.ToUpper(props.ErrorCorrection)]; ok {
+				max = barcode.QRCapacity(level)
+			}
+			addParam(params, layer, Param{
+				Key:       props.Key,
+				Type:      ParamText,
+				Default:   props.Data,
+				Required:  props.Data == "",
+				MaxLength: max,
+			})
+		case *BarcodeProps:
+			if props.Key != "" {
+				addParam(params, layer, Param{
+					Key:      props.Key,
+					Type:     ParamText,
+					Default:  props.Data,
+					Required: props.Data == "",
+					Pattern:  barcodePatterns[props.Format],
+				})
+			}
+		}
+
+		if objects := layer.Objects(); objects != nil {
+			collectParams(params, objects)
+		}
+	}
+}
+
+func addParam(params map[string]*Param, layer *Layer, p Param) {
+	if strings.TrimSpace(p.Key) == "" {
+		return
+	}
+
+	existing, ok := params[p.Key]
+	if !ok {
+		p.Layers = []string{layer.ID}
+		params[p.Key] = &p
+		return
+	}
+
+	existing.Layers = append(existing.Layers, layer.ID)
+	if existing.Default == "" {
+		existing.Default = p.Default
+	}
+	existing.Required = existing.Required && p.Required
+	if p.MaxLength != 0 && (existing.MaxLength == 0 || p.MaxLength < existing.MaxLength) {
+		existing.MaxLength = p.MaxLength
+	}
+	if existing.Pattern == "" {
+		existing.Pattern = p.Pattern
+	}
+}
+
+// Coerce validates the params against the schema and returns them as the
+// renderer expects them, unknown params are dropped and missing params take
+// their default
+func (s *ParamSchema) Coerce(params map[string]any) (map[string]any, error) {
+	out := make(map[string]any, len(s.Params))
+	for _, p := range s.Params {
+		raw, ok := params[p.Key]
+		if !ok || raw == nil {
+			if p.Required && p.Default == "" {
+				return nil, errors.Validation(fmt.Sprintf("param '%s' is required", p.Key))
+			}
+			if p.Default != "" {
+				out[p.Key] = p.Default
+			}
+			continue
+		}
+
+		if p.Type == ParamPath || p.Type == ParamGroup {
+			out[p.Key] = raw
+			continue
+		}
+
+		value := paramString(raw)
+		if err := p.validate(value); err != nil {
+			return nil, err
+		}
+		out[p.Key] = value
+	}
+
+	return out, nil
+}
+
+// paramString formats the param value, numbers decoded from JSON are
+// float64 and are written without an exponent
+func paramString(raw any) string {
+	if v, ok := raw.(float64); ok {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(raw)
+}
+
+func (p *Param) validate(value string) error {
+	if p.MaxLength != 0 && utf8.RuneCountInString(value) > p.MaxLength {
+		return errors.Validation(fmt.Sprintf("param '%s' is longer than %d characters", p.Key, p.MaxLength))
+	}
+
+	if p.Pattern != "" {
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return errors.E(errors.KindUnexpected, err)
+		}
+		if !re.MatchString(value) {
+			return errors.Validation(fmt.Sprintf("param '%s' doesn't match '%s'", p.Key, p.Pattern))
+		}
+	}
+
+	if p.Type == ParamImage {
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.Validation(fmt.Sprintf("param '%s' must be an http or https URL", p.Key))
+		}
+	}
+
+	return nil
+}
+
+// designParams returns the param schema of templates and projects, other
+// schemas accept any param
+func designParams(sch any) *ParamSchema {
+	switch s := sch.(type) {
+	case *Template:
+		return DesignParams(s.Layers)
+	case *Project:
+		return DesignParams(s.Layers)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,117 @@
+package layerhub
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+func TestDesignParams(t *testing.T) {
+	var layers []*Layer
+	err := json.Unmarshal([]byte(`[
+		{"id":"title","type":"DynamicText","keyValues":[{"key":"name","value:":"Jane"},{"key":"","value:":"x"}]},
+		{"id":"photo","type":"DynamicImage","key":"photo"},
+		{"id":"group","type":"Group","objects":[
+			{"id":"subtitle","type":"DynamicText","keyValues":[{"key":"name","value:":""}]},
+			{"id":"qr","type":"DynamicQRCode","key":"url","errorCorrection":"H"}
+		]},
+		{"id":"ean","type":"Barcode","format":"ean13","key":"ean","data":"400638133393"},
+		{"id":"static","type":"Barcode","format":"code128","data":"ABC"},
+		{"id":"shape","type":"DynamicPath","key":"shape","path":[["M",0,0]]},
+		{"id":"badge","type":"DynamicGroup","key":"badge","objects":[
+			{"id":"badge_photo","type":"DynamicImage","key":"photo"}
+		]}
+	]`), &layers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*Param{
+		{Key: "badge", Type: ParamGroup, Layers: []string{"badge"}},
+		{Key: "ean", Type: ParamText, Default: "400638133393", Pattern: `^[0-9]{12,13}$`, Layers: []string{"ean"}},
+		{Key: "name", Type: ParamText, Default: "Jane", Layers: []string{"title", "subtitle"}},
+		{Key: "photo", Type: ParamImage, Layers: []string{"photo", "badge_photo"}},
+		{Key: "shape", Type: ParamPath, Layers: []string{"shape"}},
+		{Key: "url", Type: ParamText, Required: true, MaxLength: 1273, Layers: []string{"qr"}},
+	}
+
+	if got := DesignParams(layers).Params; !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(want)
+		t.Errorf("got\n%s\nwant\n%s", gotJSON, wantJSON)
+	}
+}
+
+func TestParamSchemaCoerce(t *testing.T) {
+	schema := &ParamSchema{Params: []*Param{
+		{Key: "ean", Type: ParamText, Default: "400638133393", Pattern: `^[0-9]{12,13}$`},
+		{Key: "name", Type: ParamText, Default: "Jane", MaxLength: 5},
+		{Key: "photo", Type: ParamImage},
+		{Key: "shape", Type: ParamPath},
+		{Key: "badge", Type: ParamGroup},
+		{Key: "url", Type: ParamText, Required: true},
+	}}
+
+	testcases := []struct {
+		name   string
+		params map[string]any
+		want   map[string]any
+		err    string
+	}{
+		{
+			name:   "defaults",
+			params: map[string]any{"url": "https://example.com", "unknown": "x"},
+			want:   map[string]any{"ean": "400638133393", "name": "Jane", "url": "https://example.com"},
+		},
+		{
+			name:   "coerced",
+			params: map[string]any{"url": 42, "ean": 4006381333931, "photo": "https://example.com/a.png", "name": "Zoë"},
+			want:   map[string]any{"ean": "4006381333931", "name": "Zoë", "photo": "https://example.com/a.png", "url": "42"},
+		},
+		{
+			name: "passed through",
+			params: map[string]any{
+				"url":   "x",
+				"shape": []any{[]any{"M", 0.0, 0.0}, []any{"L", 10.0, 10.0}},
+				"badge": map[string]any{"visible": false},
+			},
+			want: map[string]any{
+				"ean":   "400638133393",
+				"name":  "Jane",
+				"url":   "x",
+				"shape": []any{[]any{"M", 0.0, 0.0}, []any{"L", 10.0, 10.0}},
+				"badge": map[string]any{"visible": false},
+			},
+		},
+		{
+			name:   "json numbers",
+			params: map[string]any{"url": 1000000.0, "ean": 4006381333931.0, "name": 0.25},
+			want:   map[string]any{"ean": "4006381333931", "name": "0.25", "url": "1000000"},
+		},
+		{name: "missing required", params: map[string]any{}, err: "param 'url' is required"},
+		{name: "too long", params: map[string]any{"url": "x", "name": "Johnny"}, err: "param 'name' is longer than 5 characters"},
+		{name: "pattern", params: map[string]any{"url": "x", "ean": "abc"}, err: "param 'ean' doesn't match"},
+		{name: "image url", params: map[string]any{"url": "x", "photo": "file:///etc/passwd"}, err: "param 'photo' must be an http or https URL"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := schema.Coerce(tc.params)
+			if tc.err != "" {
+				if err == nil || !errors.Is(err, errors.KindValidation) || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected validation error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
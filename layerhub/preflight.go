@@ -0,0 +1,334 @@
+package layerhub
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+const (
+	// MinPrintDPI is the resolution below which images are rejected
+	MinPrintDPI = 150
+	// RecommendedPrintDPI is the resolution below which images are flagged
+	RecommendedPrintDPI = 300
+)
+
+type PreflightSeverity string
+
+const (
+	PreflightError   PreflightSeverity = "error"
+	PreflightWarning PreflightSeverity = "warning"
+)
+
+const (
+	PreflightLowDPI          = "low_dpi"
+	PreflightUnreadableImage = "unreadable_image"
+	PreflightOutsideSafeZone = "outside_safe_zone"
+	PreflightShortOfBleed    = "short_of_bleed"
+	PreflightFontNotEnabled  = "font_not_enabled"
+	PreflightInvisibleLayer  = "invisible_layer"
+	PreflightEmptyDynamicKey = "empty_dynamic_key"
+	PreflightInvalidCode     = "invalid_code"
+)
+
+type PreflightIssue struct {
+	Severity PreflightSeverity `json:"severity"`
+	Code     string            `json:"code"`
+	Path     string            `json:"path"`
+	LayerID  string            `json:"layer_id,omitempty"`
+	Message  string            `json:"message"`
+}
+
+// PreflightReport lists the problems that would show up when the design is
+// printed, errors block the design from being ordered
+type PreflightReport struct {
+	Errors   []PreflightIssue `json:"errors"`
+	Warnings []PreflightIssue `json:"warnings"`
+}
+
+func (r *PreflightReport) HasErrors() bool {
+	return len(r.Errors) != 0
+}
+
+// PreflightTemplate checks if the template is printable
+func (c *Core) PreflightTemplate(ctx context.Context, template *Template) (*PreflightReport, error) {
+	return c.preflight(ctx, template.Frame, template.Layers, template.CompanyID, template.CustomerID)
+}
+
+// PreflightProject checks if the project is printable
+func (c *Core) PreflightProject(ctx context.Context, project *Project) (*PreflightReport, error) {
+	return c.preflight(ctx, project.Frame, project.Layers, project.CompanyID, project.CustomerID)
+}
+
+func (c *Core) preflight(ctx context.Context, frame Frame, layers []*Layer, companyID, customerID string) (*PreflightReport, error) {
+	fonts, err := c.availableFonts(ctx, companyID, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &preflighter{
+		frame: frame,
+		fonts: fonts,
+		imageConfig: func(url string) (image.Config, error) {
+			return fetchImageConfig(ctx, c.clientFor(url), url)
+		},
+		report: &PreflightReport{
+			Errors:   []PreflightIssue{},
+			Warnings: []PreflightIssue{},
+		},
+	}
+	p.checkLayers("layers", layers, 1, 1, true)
+
+	return p.report, nil
+}
+
+// availableFonts returns the names of the fonts the customer enabled, or every
+// font of the company if there is no customer
+func (c *Core) availableFonts(ctx context.Context, companyID, customerID string) (map[string]bool, error) {
+	filter := &Filter{OptionalCompanyID: companyID}
+	if customerID != "" {
+		enabled := true
+		filter.OptionalCustomerID = customerID
+		filter.EnabledFonts = &enabled
+	}
+
+	fonts, err := c.db.FindFonts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for _, f := range fonts {
+		names[strings.ToLower(f.Family)] = true
+		names[strings.ToLower(f.FullName)] = true
+		names[strings.ToLower(f.PostscriptName)] = true
+	}
+
+	return names, nil
+}
+
+type preflighter struct {
+	frame       Frame
+	fonts       map[string]bool
+	imageConfig func(url string) (image.Config, error)
+	report      *PreflightReport
+}
+
+func (p *preflighter) add(severity PreflightSeverity, code, path string, layer *Layer, format string, args ...any) {
+	issue := PreflightIssue{
+		Severity: severity,
+		Code:     code,
+		Path:     path,
+		LayerID:  layer.ID,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	if severity == PreflightError {
+		p.report.Errors = append(p.report.Errors, issue)
+	} else {
+		p.report.Warnings = append(p.report.Warnings, issue)
+	}
+}
+
+// checkLayers walks the layer tree, scaleX and scaleY are the accumulated
+// scales of the parent groups, only top level layers are checked against the
+// frame since group objects are positioned relative to the group
+func (p *preflighter) checkLayers(path string, layers []*Layer, scaleX, scaleY float64, top bool) {
+	for i, layer := range layers {
+		lpath := fmt.Sprintf("%s[%d]", path, i)
+
+		if !layer.Visible || layer.Opacity == 0 {
+			p.add(PreflightWarning, PreflightInvisibleLayer, lpath, layer, "layer '%s' is invisible and won't be printed", layer.Name)
+			continue
+		}
+
+		sx, sy := scaleX*math.Abs(layer.ScaleX), scaleY*math.Abs(layer.ScaleY)
+
+		switch props := layer.Props.(type) {
+		case *StaticImageProps:
+			p.checkImage(lpath, layer, props, sx, sy)
+		case *StaticTextProps:
+			family := strings.ToLower(props.FontFamily)
+			if family != "" && !p.fonts[family] {
+				p.add(PreflightError, PreflightFontNotEnabled, lpath, layer, "font '%s' is not enabled", props.FontFamily)
+			}
+		case *DynamicImageProps:
+			if strings.TrimSpace(props.Key) == "" {
+				p.add(PreflightError, PreflightEmptyDynamicKey, lpath, layer, "dynamic image has an empty key")
+			}
+		case *DynamicTextProps:
+			family := strings.ToLower(props.FontFamily)
+			if family != "" && !p.fonts[family] {
+				p.add(PreflightError, PreflightFontNotEnabled, lpath, layer, "font '%s' is not enabled", props.FontFamily)
+			}
+			for j, kv := range props.KeyValues {
+				if strings.TrimSpace(kv.Key) == "" {
+					p.add(PreflightError, PreflightEmptyDynamicKey, fmt.Sprintf("%s.keyValues[%d]", lpath, j), layer, "dynamic text has an empty key")
+				}
+			}
+		case *DynamicPathProps:
+			if strings.TrimSpace(props.Key) == "" {
+				p.add(PreflightError, PreflightEmptyDynamicKey, lpath, layer, "dynamic path has an empty key")
+			}
+		case *QRCodeProps:
+			if _, err := encodeQRCode(props.Data, props.ErrorCorrection); err != nil {
+				p.add(PreflightError, PreflightInvalidCode, lpath, layer, "invalid QR code: %s", err)
+			}
+		case *DynamicQRCodeProps:
+			if strings.TrimSpace(props.Key) == "" {
+				p.add(PreflightError, PreflightEmptyDynamicKey, lpath, layer, "dynamic QR code has an empty key")
+			}
+		case *BarcodeProps:
+			if props.Key == "" {
+				if _, err := encodeBarcode(props.Format, props.Data); err != nil {
+					p.add(PreflightError, PreflightInvalidCode, lpath, layer, "invalid barcode: %s", err)
+				}
+			}
+		case *DynamicGroupProps:
+			if strings.TrimSpace(props.Key) == "" {
+				p.add(PreflightError, PreflightEmptyDynamicKey, lpath, layer, "dynamic group has an empty key")
+			}
+			p.checkLayers(lpath+".objects", props.Objects, sx, sy, false)
+		case *GroupProps:
+			p.checkLayers(lpath+".objects", props.Objects, sx, sy, false)
+		}
+
+		if top && layer.Type != LayerBackground && layer.Type != LayerFrame {
+			p.checkPlacement(lpath, layer)
+		}
+	}
+}
+
+func (p *preflighter) checkImage(path string, layer *Layer, props *StaticImageProps, scaleX, scaleY float64) {
+	cfg, err := p.imageConfig(props.Src)
+	if err != nil {
+		p.add(PreflightWarning, PreflightUnreadableImage, path, layer, "image could not be inspected: %s", err)
+		return
+	}
+
+	width := layer.Width * scaleX / ScreenDPI
+	height := layer.Height * scaleY / ScreenDPI
+	if width == 0 || height == 0 {
+		return
+	}
+
+	dpi := math.Min(float64(cfg.Width)/width, float64(cfg.Height)/height)
+	switch {
+	case dpi < MinPrintDPI:
+		p.add(PreflightError, PreflightLowDPI, path, layer, "image resolution is %.0f dpi, at least %d dpi are required", dpi, MinPrintDPI)
+	case dpi < RecommendedPrintDPI:
+		p.add(PreflightWarning, PreflightLowDPI, path, layer, "image resolution is %.0f dpi, %d dpi are recommended", dpi, RecommendedPrintDPI)
+	}
+}
+
+// checkPlacement flags text crossing the safe zone, objects crossing the safe
+// zone without being meant to bleed, and objects that go past the trim line
+// without reaching the bleed edge
+func (p *preflighter) checkPlacement(path string, layer *Layer) {
+	ppu := p.frame.PixelsPerUnit()
+	trim := box{0, 0, p.frame.Width * ppu, p.frame.Height * ppu}
+	safe := trim.inset(p.frame.SafeMargin * ppu)
+	bleed := trim.inset(-p.frame.Bleed * ppu)
+	b := layerBounds(layer)
+
+	_, isText := layer.Props.(*StaticTextProps)
+	if _, ok := layer.Props.(*DynamicTextProps); ok {
+		isText = true
+	}
+
+	if p.frame.SafeMargin > 0 && !safe.contains(b) {
+		if isText {
+			p.add(PreflightError, PreflightOutsideSafeZone, path, layer, "text crosses the safe zone and may be trimmed")
+		} else if trim.contains(b) {
+			p.add(PreflightWarning, PreflightOutsideSafeZone, path, layer, "object crosses the safe zone and may be trimmed")
+		}
+	}
+
+	if p.frame.Bleed > 0 && !isText && !trim.contains(b) && !b.reaches(trim, bleed) {
+		p.add(PreflightWarning, PreflightShortOfBleed, path, layer, "object goes past the trim line but doesn't reach the bleed edge")
+	}
+}
+
+type box struct {
+	minX, minY, maxX, maxY float64
+}
+
+func (b box) inset(d float64) box {
+	return box{b.minX + d, b.minY + d, b.maxX - d, b.maxY - d}
+}
+
+func (b box) contains(o box) bool {
+	const eps = 1e-6
+	return o.minX >= b.minX-eps && o.minY >= b.minY-eps && o.maxX <= b.maxX+eps && o.maxY <= b.maxY+eps
+}
+
+// reaches reports whether every side of b that goes past trim also reaches the
+// bleed edge
+func (b box) reaches(trim, bleed box) bool {
+	const eps = 1e-6
+	return (b.minX >= trim.minX || b.minX <= bleed.minX+eps) &&
+		(b.minY >= trim.minY || b.minY <= bleed.minY+eps) &&
+		(b.maxX <= trim.maxX || b.maxX >= bleed.maxX-eps) &&
+		(b.maxY <= trim.maxY || b.maxY >= bleed.maxY-eps)
+}
+
+// layerBounds returns the axis aligned bounding box of the layer in design
+// pixels, skewing is ignored
+func layerBounds(l *Layer) box {
+	w := l.Width * math.Abs(l.ScaleX)
+	h := l.Height * math.Abs(l.ScaleY)
+	ox, oy := originOffset(l.OriginX, "right"), originOffset(l.OriginY, "bottom")
+
+	sin, cos := math.Sincos(l.Angle * math.Pi / 180)
+	b := box{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, c := range [4][2]float64{{0, 0}, {w, 0}, {w, h}, {0, h}} {
+		x, y := c[0]-ox*w, c[1]-oy*h
+		rx := l.Left + x*cos - y*sin
+		ry := l.Top + x*sin + y*cos
+		b.minX, b.maxX = math.Min(b.minX, rx), math.Max(b.maxX, rx)
+		b.minY, b.maxY = math.Min(b.minY, ry), math.Max(b.maxY, ry)
+	}
+
+	return b
+}
+
+// originOffset returns the position of a Fabric.js origin as a fraction of the
+// object size
+func originOffset(origin, end string) float64 {
+	switch origin {
+	case "center":
+		return 0.5
+	case end:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func fetchImageConfig(ctx context.Context, client *http.Client, url string) (image.Config, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return image.Config{}, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return image.Config{}, errors.Validation(fmt.Sprintf("fetching image '%s': %s", url, err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return image.Config{}, errors.Errorf("fetching image '%s': %s", url, resp.Status)
+	}
+
+	cfg, _, err := image.DecodeConfig(resp.Body)
+	if err != nil {
+		return image.Config{}, errors.Errorf("decoding image '%s': %s", url, err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,123 @@
+package layerhub
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestPreflight(t *testing.T) {
+	frame := Frame{Width: 10, Height: 5, Unit: Inches, Bleed: 0.125, SafeMargin: 0.25}
+
+	layer := func(b BaseLayer, props any) *Layer {
+		b.Visible = true
+		b.Opacity = 1
+		if b.ScaleX == 0 {
+			b.ScaleX, b.ScaleY = 1, 1
+		}
+		l, err := NewLayer(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l.Props = props
+		return l
+	}
+
+	testcases := []struct {
+		name     string
+		layers   []*Layer
+		errors   []string
+		warnings []string
+	}{
+		{
+			name: "printable",
+			layers: []*Layer{
+				layer(BaseLayer{Type: LayerBackground, Width: 960, Height: 480}, &BackgroundProps{}),
+				layer(BaseLayer{Type: LayerStaticText, Left: 100, Top: 100, Width: 200, Height: 50}, &StaticTextProps{FontFamily: "Roboto"}),
+				layer(BaseLayer{Type: LayerStaticImage, Left: 300, Top: 100, Width: 96, Height: 96}, &StaticImageProps{Src: "300dpi.png"}),
+			},
+		},
+		{
+			name: "low resolution images",
+			layers: []*Layer{
+				layer(BaseLayer{Type: LayerStaticImage, Left: 100, Top: 100, Width: 96, Height: 96, ScaleX: 2, ScaleY: 2}, &StaticImageProps{Src: "300dpi.png"}),
+				layer(BaseLayer{Type: LayerStaticImage, Left: 100, Top: 100, Width: 96, Height: 96}, &StaticImageProps{Src: "100dpi.png"}),
+			},
+			errors:   []string{PreflightLowDPI},
+			warnings: []string{PreflightLowDPI},
+		},
+		{
+			name: "fonts and dynamic keys",
+			layers: []*Layer{
+				layer(BaseLayer{Type: LayerStaticText, Left: 100, Top: 100, Width: 200, Height: 50}, &StaticTextProps{FontFamily: "Comic Sans"}),
+				layer(BaseLayer{Type: LayerDynamicImage, Left: 100, Top: 100, Width: 50, Height: 50}, &DynamicImageProps{}),
+				layer(BaseLayer{Type: LayerGroup, Left: 100, Top: 100, Width: 50, Height: 50}, &GroupProps{Objects: []*Layer{
+					layer(BaseLayer{Type: LayerDynamicText, Width: 50, Height: 50}, &DynamicTextProps{KeyValues: []KeyValue{{Key: " "}}}),
+				}}),
+			},
+			errors: []string{PreflightFontNotEnabled, PreflightEmptyDynamicKey, PreflightEmptyDynamicKey},
+		},
+		{
+			name: "codes",
+			layers: []*Layer{
+				layer(BaseLayer{Type: LayerStaticQRCode, Left: 100, Top: 100, Width: 96, Height: 96}, &QRCodeProps{Data: "https://example.com"}),
+				layer(BaseLayer{Type: LayerStaticQRCode, Left: 100, Top: 100, Width: 96, Height: 96}, &QRCodeProps{Data: "x", ErrorCorrection: "Z"}),
+				layer(BaseLayer{Type: LayerDynamicQRCode, Left: 100, Top: 100, Width: 96, Height: 96}, &DynamicQRCodeProps{}),
+				layer(BaseLayer{Type: LayerBarcode, Left: 100, Top: 100, Width: 200, Height: 50}, &BarcodeProps{Format: BarcodeEAN13, Data: "4006381333932"}),
+				layer(BaseLayer{Type: LayerBarcode, Left: 100, Top: 100, Width: 200, Height: 50}, &BarcodeProps{Format: BarcodeCode128, Key: "sku"}),
+			},
+			errors: []string{PreflightInvalidCode, PreflightEmptyDynamicKey, PreflightInvalidCode},
+		},
+		{
+			name: "invisible layers",
+			layers: []*Layer{
+				{BaseLayer: BaseLayer{Type: LayerStaticPath, Visible: false, Opacity: 1}, Props: &StaticPathProps{}},
+				{BaseLayer: BaseLayer{Type: LayerStaticPath, Visible: true, Opacity: 0}, Props: &StaticPathProps{}},
+			},
+			warnings: []string{PreflightInvisibleLayer, PreflightInvisibleLayer},
+		},
+		{
+			name: "safe zone and bleed",
+			layers: []*Layer{
+				layer(BaseLayer{Type: LayerStaticText, Left: 10, Top: 100, Width: 200, Height: 50}, &StaticTextProps{FontFamily: "Roboto"}),
+				layer(BaseLayer{Type: LayerStaticPath, Left: 10, Top: 100, Width: 50, Height: 50}, &StaticPathProps{}),
+				layer(BaseLayer{Type: LayerStaticPath, Left: -5, Top: 100, Width: 50, Height: 50}, &StaticPathProps{}),
+				layer(BaseLayer{Type: LayerStaticPath, Left: -12, Top: -12, Width: 984, Height: 504}, &StaticPathProps{}),
+			},
+			errors:   []string{PreflightOutsideSafeZone},
+			warnings: []string{PreflightOutsideSafeZone, PreflightShortOfBleed},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &preflighter{
+				frame: frame,
+				fonts: map[string]bool{"roboto": true},
+				imageConfig: func(url string) (image.Config, error) {
+					if url == "100dpi.png" {
+						return image.Config{Width: 100, Height: 100}, nil
+					}
+					return image.Config{Width: 300, Height: 300}, nil
+				},
+				report: &PreflightReport{},
+			}
+			p.checkLayers("layers", tc.layers, 1, 1, true)
+
+			if got := issueCodes(p.report.Errors); !reflect.DeepEqual(got, tc.errors) {
+				t.Errorf("errors: got %v, want %v", got, tc.errors)
+			}
+			if got := issueCodes(p.report.Warnings); !reflect.DeepEqual(got, tc.warnings) {
+				t.Errorf("warnings: got %v, want %v", got, tc.warnings)
+			}
+		})
+	}
+}
+
+func issueCodes(issues []PreflightIssue) []string {
+	var codes []string
+	for _, issue := range issues {
+		codes = append(codes, issue.Code)
+	}
+	return codes
+}
@@ -0,0 +1,243 @@
+package layerhub
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"math"
+	"time"
+
+	"github.com/cenkalti/backoff/v3"
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/pdf"
+)
+
+// ScreenDPI is the resolution designs are edited at, frames in physical units
+// are converted to design pixels with it
+const ScreenDPI = 96
+
+const (
+	printRetries    = 3
+	printJobTimeout = 30 * time.Minute
+
+	// StalePrintJob is how long a submitted order goes without progress
+	// before its print job is considered lost, orders with a file being
+	// generated are lost once the job timeout has passed
+	StalePrintJob = 5 * time.Minute
+)
+
+// PixelsPerUnit returns the number of design pixels in one frame unit
+func (f Frame) PixelsPerUnit() float64 {
+	switch f.Unit {
+	case Centimeters:
+		return ScreenDPI / 2.54
+	case Inches:
+		return ScreenDPI
+	default:
+		return 1
+	}
+}
+
+// PrintDesign returns a copy of the project scaled to the given resolution,
+// the canvas is extended by the frame's bleed on every side, backgrounds and
+// frame layers are stretched to cover it
+func PrintDesign(project *Project, dpi int) *Project {
+	ppu := project.Frame.PixelsPerUnit()
+	bleed := project.Frame.Bleed * ppu
+	scale := float64(dpi) / ScreenDPI
+
+	width := math.Round((project.Frame.Width*ppu + 2*bleed) * scale)
+	height := math.Round((project.Frame.Height*ppu + 2*bleed) * scale)
+
+	layers := make([]*Layer, len(project.Layers))
+	for i, l := range project.Layers {
+		layer := *l
+		if layer.Type == LayerBackground || layer.Type == LayerFrame {
+			layer.Left, layer.Top = 0, 0
+			layer.Width, layer.Height = width, height
+			layer.ScaleX, layer.ScaleY = 1, 1
+		} else {
+			layer.Left = (layer.Left + bleed) * scale
+			layer.Top = (layer.Top + bleed) * scale
+			layer.ScaleX *= scale
+			layer.ScaleY *= scale
+		}
+		layers[i] = &layer
+	}
+
+	return &Project{
+		ID:        project.ID,
+		Name:      project.Name,
+		CompanyID: project.CompanyID,
+		Frame: Frame{
+			Width:  width,
+			Height: height,
+			Unit:   Pixels,
+		},
+		Layers: layers,
+	}
+}
+
+// ResumePrintJobs runs again the print jobs lost by a restart. The files
+// already generated are kept, the pending ones and the ones that were being
+// generated start over. An order is claimed before its job runs so it's
+// resumed by a single instance
+func (c *Core) ResumePrintJobs(ctx context.Context) (int, error) {
+	orders, err := c.db.FindOrders(ctx, &Filter{
+		Status:        string(OrderSubmitted),
+		UpdatedBefore: Now().Add(-StalePrintJob),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	resumed := 0
+	for i := range orders {
+		order := &orders[i]
+
+		processing := false
+		for _, item := range order.Items {
+			if item.PrintStatus == PrintProcessing {
+				processing = true
+			}
+		}
+		if processing && order.UpdatedAt.After(Now().Add(-printJobTimeout)) {
+			continue
+		}
+
+		updatedAt := order.UpdatedAt
+		order.UpdatedAt = Now()
+		claimed, err := c.db.ClaimOrder(ctx, order, OrderSubmitted, updatedAt)
+		if err != nil {
+			return resumed, err
+		}
+		if !claimed {
+			continue
+		}
+
+		for _, item := range order.Items {
+			if item.PrintStatus == PrintProcessing {
+				item.PrintStatus = PrintPending
+			}
+		}
+		if err := c.db.PutOrder(ctx, order); err != nil {
+			return resumed, err
+		}
+
+		go c.runPrintJob(order.ID)
+		resumed++
+	}
+
+	return resumed, nil
+}
+
+func (c *Core) runPrintJob(orderID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), printJobTimeout)
+	defer cancel()
+
+	if err := c.GeneratePrintFiles(ctx, orderID); err != nil {
+		c.Logger.Errorf("print job: order '%s': %s", orderID, err)
+	}
+}
+
+// GeneratePrintFiles renders the pending print files of the order, every file
+// is retried with an exponential backoff and failures are kept on its item
+func (c *Core) GeneratePrintFiles(ctx context.Context, orderID string) error {
+	order, err := c.GetOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range order.Items {
+		if item.PrintStatus != PrintPending {
+			continue
+		}
+
+		item.PrintStatus = PrintProcessing
+		order.UpdatedAt = Now()
+		if err := c.db.PutOrder(ctx, order); err != nil {
+			return err
+		}
+
+		bo := backoff.WithContext(backoff.WithMaxRetries(backoff.NewExponentialBackOff(), printRetries), ctx)
+		err := backoff.Retry(func() error {
+			item.PrintAttempts++
+			url, err := c.printFile(ctx, order, item)
+			if err != nil {
+				if errors.Is(err, errors.KindNotFound) {
+					return backoff.Permanent(err)
+				}
+				return err
+			}
+			item.PrintFileURL = url
+			return nil
+		}, bo)
+		if err != nil {
+			c.Logger.Errorf("print job: order '%s' item '%s': %s", order.ID, item.ID, err)
+			item.PrintStatus = PrintFailed
+			item.PrintError = err.Error()
+		} else {
+			item.PrintStatus = PrintDone
+			item.PrintError = ""
+		}
+
+		order.UpdatedAt = Now()
+		if err := c.db.PutOrder(ctx, order); err != nil {
+			return err
+		}
+	}
+
+	order.Status = OrderReady
+	for _, item := range order.Items {
+		if item.PrintStatus == PrintFailed {
+			order.Status = OrderPrintFailed
+			break
+		}
+	}
+	order.UpdatedAt = Now()
+
+	return c.db.PutOrder(ctx, order)
+}
+
+// printFile renders the item's approved proof at print resolution and uploads
+// it
+func (c *Core) printFile(ctx context.Context, order *Order, item *OrderItem) (string, error) {
+	proof, err := c.GetProof(ctx, item.ProofID)
+	if err != nil {
+		return "", err
+	}
+
+	project := &Project{
+		ID:        proof.ProjectID,
+		CompanyID: proof.CompanyID,
+		Frame:     proof.Frame,
+		Layers:    proof.Layers,
+	}
+
+	img, err := c.renderer.RawRender(ctx, PrintDesign(project, item.DPI), nil)
+	if err != nil {
+		return "", err
+	}
+
+	if item.Format == PrintPDF {
+		decoded, err := png.Decode(bytes.NewReader(img))
+		if err != nil {
+			return "", errors.Errorf("print: decoding render: %s", err)
+		}
+
+		size := decoded.Bounds().Size()
+		out := &bytes.Buffer{}
+		err = pdf.Encode(
+			out,
+			decoded,
+			float64(size.X)/float64(item.DPI)*pdf.PointsPerInch,
+			float64(size.Y)/float64(item.DPI)*pdf.PointsPerInch,
+		)
+		if err != nil {
+			return "", errors.Errorf("print: encoding pdf: %s", err)
+		}
+		img = out.Bytes()
+	}
+
+	return c.uploader.Upload(ctx, "print/"+order.Number+"/"+order.PrintFileName(item), img)
+}
@@ -0,0 +1,272 @@
+package layerhub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+type ProofStatus string
+
+const (
+	ProofPending          ProofStatus = "pending"
+	ProofApproved         ProofStatus = "approved"
+	ProofChangesRequested ProofStatus = "changes_requested"
+)
+
+// maxProofAttempts is the number of times a proof is saved when concurrent
+// proofs take its version
+const maxProofAttempts = 5
+
+// Proof is a snapshot of a project the customer has to sign off before it's
+// sent to production, proofs of projects without a customer are signed off by
+// the company
+type Proof struct {
+	ID               string          `json:"id" db:"id"`
+	ProjectID        string          `json:"project_id" db:"project_id"`
+	Version          int             `json:"version" db:"version"`
+	Status           ProofStatus     `json:"status" db:"status"`
+	Preview          string          `json:"preview" db:"preview"`
+	ProjectUpdatedAt time.Time       `json:"project_updated_at" db:"project_updated_at"`
+	UserID           string          `json:"user_id" db:"user_id"`
+	CustomerID       string          `json:"customer_id" db:"customer_id"`
+	CompanyID        string          `json:"company_id" db:"company_id"`
+	ReviewedAt       *time.Time      `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt        time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at" db:"updated_at"`
+	Comments         []*ProofComment `json:"comments"`
+
+	// Frame and Layers are the snapshot of the project
+	Frame  Frame    `json:"frame"`
+	Layers []*Layer `json:"layers"`
+}
+
+func NewProof() *Proof {
+	now := Now()
+	return &Proof{
+		ID:        UniqueID("proof"),
+		Status:    ProofPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func (p *Proof) Key() string {
+	return string(p.ID) + ".layerhub"
+}
+
+type ProofComment struct {
+	ID         string    `json:"id" db:"id"`
+	ProofID    string    `json:"-" db:"proof_id"`
+	UserID     string    `json:"user_id,omitempty" db:"user_id"`
+	CustomerID string    `json:"customer_id,omitempty" db:"customer_id"`
+	Body       string    `json:"body" db:"body"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+func NewProofComment() *ProofComment {
+	return &ProofComment{
+		ID:        UniqueID("proof_comment"),
+		CreatedAt: Now(),
+	}
+}
+
+type proofSnapshot struct {
+	Frame  Frame    `json:"frame"`
+	Layers []*Layer `json:"layers"`
+}
+
+// CreateProof snapshots the project's current layers and renders its preview,
+// the proof supersedes the previous proofs of the project
+func (c *Core) CreateProof(ctx context.Context, project *Project, userID string) (*Proof, error) {
+	proof := NewProof()
+	proof.ProjectID = project.ID
+	proof.ProjectUpdatedAt = project.UpdatedAt.UTC().Truncate(time.Second)
+	proof.UserID = userID
+	proof.CustomerID = project.CustomerID
+	proof.CompanyID = project.CompanyID
+	proof.Frame = project.Frame
+	proof.Layers = project.Layers
+
+	url, err := c.renderer.Render(ctx, project, nil)
+	if err != nil {
+		return nil, err
+	}
+	proof.Preview = url
+
+	snapshot, err := json.Marshal(proofSnapshot{proof.Frame, proof.Layers})
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	if _, err := c.uploader.Upload(ctx, proof.Key(), snapshot); err != nil {
+		return nil, err
+	}
+
+	if err := c.putProofVersion(ctx, proof); err != nil {
+		return nil, err
+	}
+
+	proof.Comments = []*ProofComment{}
+
+	return proof, nil
+}
+
+// putProofVersion saves the new proof as the next version of the project's
+// proofs. The unique version of the project is checked by the db, concurrent
+// proofs that get the same version are saved again with the next one
+func (c *Core) putProofVersion(ctx context.Context, proof *Proof) error {
+	for attempt := 1; ; attempt++ {
+		err := c.db.WithTx(ctx, func(tx DB) error {
+			proofs, err := tx.FindProofs(ctx, &Filter{ProjectID: proof.ProjectID})
+			if err != nil {
+				return err
+			}
+
+			proof.Version = 1
+			for _, other := range proofs {
+				if other.Version >= proof.Version {
+					proof.Version = other.Version + 1
+				}
+			}
+
+			return tx.PutProof(ctx, proof)
+		})
+		if errors.Is(err, errors.KindConflict) && attempt < maxProofAttempts {
+			continue
+		}
+		return err
+	}
+}
+
+func (c *Core) GetProof(ctx context.Context, id string) (*Proof, error) {
+	proofs, err := c.db.FindProofs(ctx, &Filter{ID: id, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(proofs) == 0 {
+		return nil, errors.NotFound(fmt.Sprintf("proof '%s' not found", id))
+	}
+
+	content, err := c.uploader.Download(ctx, proofs[0].Key())
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot proofSnapshot
+	err = json.Unmarshal(content, &snapshot)
+	if err != nil {
+		return nil, err
+	}
+	proofs[0].Frame = snapshot.Frame
+	proofs[0].Layers = snapshot.Layers
+
+	return &proofs[0], nil
+}
+
+// FindProjectProofs returns the proof history of the project, newest first
+func (c *Core) FindProjectProofs(ctx context.Context, projectID string) ([]Proof, error) {
+	proofs, err := c.db.FindProofs(ctx, &Filter{ProjectID: projectID})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(proofs, func(i, j int) bool {
+		return proofs[i].Version > proofs[j].Version
+	})
+
+	return proofs, nil
+}
+
+// ApproveProof signs off the proof, only the latest proof of a project can be
+// reviewed
+func (c *Core) ApproveProof(ctx context.Context, proof *Proof, comment *ProofComment) error {
+	return c.reviewProof(ctx, proof, ProofApproved, comment)
+}
+
+// RequestProofChanges rejects the proof, the comment should explain what has
+// to be changed
+func (c *Core) RequestProofChanges(ctx context.Context, proof *Proof, comment *ProofComment) error {
+	if comment == nil {
+		return errors.Validation("a comment is required to request changes")
+	}
+	return c.reviewProof(ctx, proof, ProofChangesRequested, comment)
+}
+
+func (c *Core) reviewProof(ctx context.Context, proof *Proof, status ProofStatus, comment *ProofComment) error {
+	if proof.Status != ProofPending {
+		return errors.Validation(fmt.Sprintf("proof '%s' is already %s", proof.ID, proof.Status))
+	}
+
+	latest, err := c.latestProof(ctx, proof.ProjectID)
+	if err != nil {
+		return err
+	}
+	if latest.ID != proof.ID {
+		return errors.Validation(fmt.Sprintf("proof '%s' was superseded by version %d", proof.ID, latest.Version))
+	}
+
+	now := Now()
+	proof.Status = status
+	proof.ReviewedAt = &now
+	proof.UpdatedAt = now
+	if err := c.db.PutProof(ctx, proof); err != nil {
+		return err
+	}
+
+	if comment != nil {
+		return c.AddProofComment(ctx, proof, comment)
+	}
+
+	return nil
+}
+
+func (c *Core) AddProofComment(ctx context.Context, proof *Proof, comment *ProofComment) error {
+	comment.ProofID = proof.ID
+	if err := c.db.PutProofComment(ctx, comment); err != nil {
+		return err
+	}
+	proof.Comments = append(proof.Comments, comment)
+
+	return nil
+}
+
+func (c *Core) latestProof(ctx context.Context, projectID string) (*Proof, error) {
+	proofs, err := c.FindProjectProofs(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(proofs) == 0 {
+		return nil, errors.NotFound(fmt.Sprintf("project '%s' has no proofs", projectID))
+	}
+
+	return &proofs[0], nil
+}
+
+// approvedProof returns the project's latest proof if it was approved and the
+// project didn't change since then
+func (c *Core) approvedProof(ctx context.Context, project *Project) (*Proof, error) {
+	proof, err := c.latestProof(ctx, project.ID)
+	if err != nil {
+		if errors.Is(err, errors.KindNotFound) {
+			return nil, errors.Validation(fmt.Sprintf("project '%s' has no approved proof", project.ID))
+		}
+		return nil, err
+	}
+
+	if proof.Status != ProofApproved {
+		return nil, errors.Validation(fmt.Sprintf("proof '%s' of project '%s' is %s", proof.ID, project.ID, proof.Status))
+	}
+
+	if project.UpdatedAt.UTC().Truncate(time.Second).After(proof.ProjectUpdatedAt) {
+		return nil, errors.Validation(fmt.Sprintf("project '%s' changed after its proof was approved", project.ID))
+	}
+
+	return proof, nil
+}
@@ -0,0 +1,69 @@
+package layerhub
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+// PublishTemplate makes the template visible in the gallery, public templates
+// are visible to every company. The template must have a preview and pass the
+// preflight checks
+func (c *Core) PublishTemplate(ctx context.Context, template *Template, public bool) (*PreflightReport, error) {
+	if template.Preview == "" {
+		return nil, errors.Validation(fmt.Sprintf("template '%s' has no preview", template.ID))
+	}
+
+	report, err := c.PreflightTemplate(ctx, template)
+	if err != nil {
+		return nil, err
+	}
+
+	if report.HasErrors() {
+		return report, errors.Validation(fmt.Sprintf("template '%s' didn't pass the preflight checks", template.ID))
+	}
+
+	template.Published = true
+	template.Public = public
+	template.UpdatedAt = Now()
+
+	if err := c.saveTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// UnpublishTemplate removes the template from the gallery
+func (c *Core) UnpublishTemplate(ctx context.Context, template *Template) error {
+	template.Published = false
+	template.Public = false
+	template.UpdatedAt = Now()
+
+	return c.saveTemplate(ctx, template)
+}
+
+// saveTemplate saves the template without rendering it again
+func (c *Core) saveTemplate(ctx context.Context, template *Template) error {
+	err := c.putDesign(ctx, template, func(db DB) error {
+		return db.PutTemplate(ctx, template)
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.indexDocument(ctx, templateDocument(template))
+}
+
+// FindGalleryTemplates returns the published templates visible to the company,
+// its own and the public ones
+func (c *Core) FindGalleryTemplates(ctx context.Context, companyID string, filter *Filter) ([]Template, int, error) {
+	published := true
+
+	fc := *filter
+	fc.Published = &published
+	fc.PublicOrCompanyID = companyID
+
+	return c.FindTemplates(ctx, &fc)
+}
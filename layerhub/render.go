@@ -59,6 +59,11 @@ func (r *renderer) RawRender(ctx context.Context, sch any, params map[string]any
 		Image string `json:"image"`
 	}
 
+	sch, err := renderSchema(sch, params)
+	if err != nil {
+		return nil, err
+	}
+
 	body, err := json.Marshal(request{sch, params})
 	if err != nil {
 		return nil, errors.Errorf("renderer: %s", err)
@@ -98,7 +103,17 @@ func (r *renderer) RawRender(ctx context.Context, sch any, params map[string]any
 	return img, nil
 }
 
+// Render renders templates and projects with the params coerced to their
+// param schema
 func (c *Core) Render(ctx context.Context, sch any, params map[string]any) ([]byte, error) {
+	if schema := designParams(sch); schema != nil {
+		coerced, err := schema.Coerce(params)
+		if err != nil {
+			return nil, err
+		}
+		params = coerced
+	}
+
 	return c.renderer.RawRender(ctx, sch, params)
 }
 
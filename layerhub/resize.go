@@ -0,0 +1,153 @@
+package layerhub
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+type ResizeStrategy string
+
+const (
+	// ResizeFit scales the design uniformly until it fits the new frame, the
+	// design is centered and the remaining space is left empty
+	ResizeFit ResizeStrategy = "fit"
+	// ResizeFill scales the design uniformly until it covers the new frame,
+	// the design is centered and what overflows is cropped
+	ResizeFill ResizeStrategy = "fill"
+	// ResizeAnchor scales the layers like ResizeFit but keeps every layer at
+	// the same distance, scaled, from the nearest edges of the frame
+	ResizeAnchor ResizeStrategy = "anchor"
+)
+
+// ResizeDesign creates a copy of the template or project for the frame, the
+// copy is saved with its own ID
+func (c *Core) ResizeDesign(ctx context.Context, dsg Design, frame Frame, strategy ResizeStrategy) (Design, error) {
+	if frame.Width <= 0 || frame.Height <= 0 {
+		return nil, errors.Validation("frame width and height must be greater than zero")
+	}
+
+	switch d := dsg.(type) {
+	case *Template:
+		layers, err := ResizeLayers(d.Layers, d.Frame, frame, strategy)
+		if err != nil {
+			return nil, err
+		}
+
+		template := NewTemplate()
+		template.Type = d.Type
+		template.Name = resizedName(d.Name, frame)
+		template.Description = d.Description
+		template.Tags = d.Tags
+		template.Colors = d.Colors
+		template.CustomerID = d.CustomerID
+		template.CompanyID = d.CompanyID
+		template.Metadata = d.Metadata
+		template.Frame = frame
+		template.Layers = layers
+
+		if err := c.PutTemplate(ctx, template); err != nil {
+			return nil, err
+		}
+		return template, nil
+	case *Project:
+		layers, err := ResizeLayers(d.Layers, d.Frame, frame, strategy)
+		if err != nil {
+			return nil, err
+		}
+
+		project := NewProject()
+		project.Type = d.Type
+		project.Name = resizedName(d.Name, frame)
+		project.Description = d.Description
+		project.CustomerID = d.CustomerID
+		project.CompanyID = d.CompanyID
+		project.Frame = frame
+		project.Layers = layers
+
+		if err := c.PutProject(ctx, project); err != nil {
+			return nil, err
+		}
+		return project, nil
+	default:
+		return nil, errors.Validation(fmt.Sprintf("design '%s' can't be resized", dsg.Key()))
+	}
+}
+
+func resizedName(name string, frame Frame) string {
+	unit := frame.Unit
+	if unit == "" {
+		unit = Pixels
+	}
+	return fmt.Sprintf("%s (%gx%g%s)", name, frame.Width, frame.Height, unit)
+}
+
+// ResizeLayers returns a copy of the layers with new IDs moved and scaled from
+// one frame to the other, backgrounds are stretched to the new frame. Group
+// objects are relative to their group so only top level layers are changed
+func ResizeLayers(layers []*Layer, from, to Frame, strategy ResizeStrategy) ([]*Layer, error) {
+	fromW, fromH := from.Width*from.PixelsPerUnit(), from.Height*from.PixelsPerUnit()
+	toW, toH := to.Width*to.PixelsPerUnit(), to.Height*to.PixelsPerUnit()
+	if fromW <= 0 || fromH <= 0 {
+		return nil, errors.Validation("the design has an empty frame")
+	}
+
+	sx, sy := toW/fromW, toH/fromH
+
+	var scale float64
+	switch strategy {
+	case ResizeFit, ResizeAnchor, "":
+		scale = math.Min(sx, sy)
+	case ResizeFill:
+		scale = math.Max(sx, sy)
+	default:
+		return nil, errors.Validation(fmt.Sprintf("unknown resize strategy '%s'", strategy))
+	}
+
+	// centered designs are moved by the same offset on every layer
+	centerX := (toW - fromW*scale) / 2
+	centerY := (toH - fromH*scale) / 2
+
+	out, err := copyLayers(layers)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, layer := range out {
+		if layer.Type == LayerBackground || layer.Type == LayerFrame {
+			layer.Left, layer.Top = 0, 0
+			layer.Width, layer.Height = toW, toH
+			layer.ScaleX, layer.ScaleY = 1, 1
+			continue
+		}
+
+		offsetX, offsetY := centerX, centerY
+		if strategy == ResizeAnchor {
+			b := layerBounds(layer)
+			offsetX = anchorOffset((b.minX+b.maxX)/2, fromW, toW, scale)
+			offsetY = anchorOffset((b.minY+b.maxY)/2, fromH, toH, scale)
+		}
+
+		layer.Left = offsetX + layer.Left*scale
+		layer.Top = offsetY + layer.Top*scale
+		layer.ScaleX *= scale
+		layer.ScaleY *= scale
+	}
+
+	return out, nil
+}
+
+// anchorOffset returns the offset that keeps a layer centered at c anchored
+// to the start, center or end of the axis, whichever third it falls in
+func anchorOffset(c, from, to, scale float64) float64 {
+	switch {
+	case c < from/3:
+		return 0
+	case c > from*2/3:
+		return to - from*scale
+	default:
+		return (to - from*scale) / 2
+	}
+}
@@ -0,0 +1,108 @@
+package layerhub
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestResizeLayers(t *testing.T) {
+	square := Frame{Width: 100, Height: 100, Unit: Pixels}
+	banner := Frame{Width: 200, Height: 100, Unit: Pixels}
+
+	newLayer := func(typ LayerType, left, top, width, height float64) *Layer {
+		return &Layer{BaseLayer: BaseLayer{
+			Type:   typ,
+			Left:   left,
+			Top:    top,
+			Width:  width,
+			Height: height,
+			ScaleX: 1,
+			ScaleY: 1,
+		}}
+	}
+
+	testcases := []struct {
+		name     string
+		layer    *Layer
+		from, to Frame
+		strategy ResizeStrategy
+		// want is left, top, scaleX, scaleY
+		want [4]float64
+	}{
+		{"fit centers", newLayer(LayerStaticImage, 10, 10, 20, 20), square, banner, ResizeFit, [4]float64{60, 10, 1, 1}},
+		{"fit scales down", newLayer(LayerStaticImage, 100, 50, 20, 20), banner, square, ResizeFit, [4]float64{50, 50, 0.5, 0.5}},
+		{"fill scales up", newLayer(LayerStaticImage, 10, 10, 20, 20), square, banner, ResizeFill, [4]float64{20, -30, 2, 2}},
+		{"anchor top left", newLayer(LayerStaticImage, 10, 10, 20, 20), square, banner, ResizeAnchor, [4]float64{10, 10, 1, 1}},
+		{"anchor bottom right", newLayer(LayerStaticImage, 70, 70, 20, 20), square, banner, ResizeAnchor, [4]float64{170, 70, 1, 1}},
+		{"anchor center", newLayer(LayerStaticImage, 40, 40, 20, 20), square, banner, ResizeAnchor, [4]float64{90, 40, 1, 1}},
+		{"background stretched", newLayer(LayerBackground, 0, 0, 100, 100), square, banner, ResizeFit, [4]float64{0, 0, 1, 1}},
+		{"units", newLayer(LayerStaticImage, 0, 0, 10, 10), Frame{Width: 1, Height: 1, Unit: Inches}, Frame{Width: 2, Height: 2, Unit: Inches}, ResizeFit, [4]float64{0, 0, 2, 2}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			layers, err := ResizeLayers([]*Layer{tc.layer}, tc.from, tc.to, tc.strategy)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			l := layers[0]
+			got := [4]float64{l.Left, l.Top, l.ScaleX, l.ScaleY}
+			for i := range got {
+				if math.Abs(got[i]-tc.want[i]) > 1e-9 {
+					t.Fatalf("got %v, want %v", got, tc.want)
+				}
+			}
+
+			if l == tc.layer {
+				t.Errorf("expected a copy of the layer")
+			}
+
+			if l.Type == LayerBackground && (l.Width != tc.to.Width || l.Height != tc.to.Height) {
+				t.Errorf("background is %vx%v, want %vx%v", l.Width, l.Height, tc.to.Width, tc.to.Height)
+			}
+		})
+	}
+
+	if _, err := ResizeLayers(nil, square, banner, "stretch"); err == nil {
+		t.Errorf("expected an error for an unknown strategy")
+	}
+}
+
+func TestResizeLayersCopy(t *testing.T) {
+	var layers []*Layer
+	err := json.Unmarshal([]byte(`[
+		{"id":"a","type":"StaticText","text":"hi","left":10,"scaleX":1,"scaleY":1},
+		{"id":"b","type":"Group","objects":[{"id":"c","type":"StaticText","text":"nested"}]}
+	]`), &layers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	square := Frame{Width: 100, Height: 100, Unit: Pixels}
+	banner := Frame{Width: 200, Height: 100, Unit: Pixels}
+	resized, err := ResizeLayers(layers, square, banner, ResizeFit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resized[0].ID == "a" || resized[1].ID == "b" || resized[1].Objects()[0].ID == "c" {
+		t.Errorf("the resized layers kept the IDs of the source")
+	}
+
+	resized[0].Props.(*StaticTextProps).Text = "changed"
+	resized[1].Objects()[0].Props.(*StaticTextProps).Text = "changed"
+	resized[1].Objects()[0].Left = 50
+
+	if text := layers[0].Props.(*StaticTextProps).Text; text != "hi" {
+		t.Errorf("the source text changed to %q", text)
+	}
+	nested := layers[1].Objects()[0]
+	if text := nested.Props.(*StaticTextProps).Text; text != "nested" || nested.Left != 0 {
+		t.Errorf("the source group object changed: %q at %v", text, nested.Left)
+	}
+	if layers[0].Left != 10 {
+		t.Errorf("the source layer moved to %v", layers[0].Left)
+	}
+}
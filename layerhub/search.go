@@ -0,0 +1,375 @@
+package layerhub
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+type SearchKind string
+
+const (
+	SearchTemplates  SearchKind = "template"
+	SearchComponents SearchKind = "component"
+	SearchUploads    SearchKind = "upload"
+)
+
+const defaultSearchLimit = 20
+
+// maxSearchLimit is the maximum number of documents returned by a search
+const maxSearchLimit = 100
+
+// reindexBatch is the number of rows indexed at once by Reindex
+const reindexBatch = 100
+
+// SearchDocument is the indexed representation of a template, component or
+// upload
+type SearchDocument struct {
+	ID          string
+	Kind        SearchKind
+	CompanyID   string
+	CustomerID  string
+	Public      bool
+	Name        string
+	Description string
+	Tags        []string
+	Colors      []string
+	// Text is the text content of the layers
+	Text   []string
+	Width  float64
+	Height float64
+	Unit   FrameUnit
+}
+
+// Size returns the frame size of the document as used by the size facet
+func (d *SearchDocument) Size() string {
+	if d.Width == 0 || d.Height == 0 {
+		return ""
+	}
+
+	unit := d.Unit
+	if unit == "" {
+		unit = Pixels
+	}
+	return fmt.Sprintf("%gx%g%s", d.Width, d.Height, unit)
+}
+
+// SearchQuery matches the documents of a kind visible to the company, its own
+// documents and the public ones. Customers only see their documents and the
+// ones shared with the company
+type SearchQuery struct {
+	Kind       SearchKind
+	Text       string
+	CompanyID  string
+	CustomerID string
+	Tags       []string
+	Colors     []string
+	Width      float64
+	Height     float64
+	Limit      int
+	Offset     int
+}
+
+type SearchFacet struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Facet names of a SearchResult
+const (
+	FacetTags   = "tags"
+	FacetColors = "colors"
+	FacetSize   = "size"
+)
+
+type SearchResult struct {
+	// IDs are the matching documents ordered by relevance
+	IDs    []string                 `json:"ids"`
+	Total  int                      `json:"total"`
+	Facets map[string][]SearchFacet `json:"facets"`
+}
+
+// SearchIndex is a full-text index of documents, documents are replaced when
+// indexed again with the same kind and ID
+type SearchIndex interface {
+	IndexDocument(ctx context.Context, doc *SearchDocument) error
+	DeleteDocument(ctx context.Context, kind SearchKind, id string) error
+	Search(ctx context.Context, query *SearchQuery) (*SearchResult, error)
+}
+
+func (c *Core) Search(ctx context.Context, query *SearchQuery) (*SearchResult, error) {
+	if c.searchIndex == nil {
+		return nil, errors.E(errors.KindUnexpected, "search index is not configured")
+	}
+
+	if query.CompanyID == "" {
+		return nil, errors.Validation("search requires a company")
+	}
+
+	if query.Limit < 0 || query.Offset < 0 {
+		return nil, errors.Validation("search limit and offset can't be negative")
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultSearchLimit
+	}
+	if query.Limit > maxSearchLimit {
+		query.Limit = maxSearchLimit
+	}
+
+	return c.searchIndex.Search(ctx, query)
+}
+
+func (c *Core) SearchTemplates(ctx context.Context, query *SearchQuery) ([]Template, *SearchResult, error) {
+	query.Kind = SearchTemplates
+	result, err := c.Search(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	templates := []Template{}
+	if len(result.IDs) == 0 {
+		return templates, result, nil
+	}
+
+	found, err := c.db.FindTemplates(ctx, &Filter{IDs: result.IDs})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := map[string]Template{}
+	for _, row := range found {
+		byID[row.ID] = row
+	}
+	for _, id := range result.IDs {
+		if row, ok := byID[id]; ok {
+			templates = append(templates, row)
+		}
+	}
+
+	return templates, result, nil
+}
+
+func (c *Core) SearchComponents(ctx context.Context, query *SearchQuery) ([]Component, *SearchResult, error) {
+	query.Kind = SearchComponents
+	result, err := c.Search(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	comps := []Component{}
+	if len(result.IDs) == 0 {
+		return comps, result, nil
+	}
+
+	found, err := c.db.FindComponents(ctx, &Filter{IDs: result.IDs})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := map[string]Component{}
+	for _, row := range found {
+		byID[row.ID] = row
+	}
+	for _, id := range result.IDs {
+		if row, ok := byID[id]; ok {
+			comps = append(comps, row)
+		}
+	}
+
+	return comps, result, nil
+}
+
+func (c *Core) SearchUploads(ctx context.Context, query *SearchQuery) ([]Upload, *SearchResult, error) {
+	query.Kind = SearchUploads
+	result, err := c.Search(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	uploads := []Upload{}
+	if len(result.IDs) == 0 {
+		return uploads, result, nil
+	}
+
+	found, err := c.db.FindUploads(ctx, &Filter{IDs: result.IDs})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := map[string]Upload{}
+	for _, row := range found {
+		byID[row.ID] = row
+	}
+	for _, id := range result.IDs {
+		if row, ok := byID[id]; ok {
+			uploads = append(uploads, row)
+		}
+	}
+
+	return uploads, result, nil
+}
+
+// indexDocument keeps the search index in sync with the db, it does nothing
+// when there is no index
+func (c *Core) indexDocument(ctx context.Context, doc *SearchDocument) error {
+	if c.searchIndex == nil {
+		return nil
+	}
+	return c.searchIndex.IndexDocument(ctx, doc)
+}
+
+// Reindex indexes every template, component and upload out of the trash and
+// returns the number of documents indexed. It fills indexes that don't
+// persist the documents, like in-memory ones, at startup
+func (c *Core) Reindex(ctx context.Context) (int, error) {
+	if c.searchIndex == nil {
+		return 0, nil
+	}
+
+	indexed := 0
+	// loadDesign loads the layers of the design, designs without content are
+	// indexed without their text
+	loadDesign := func(dsg Design) error {
+		if err := c.designs.Get(ctx, dsg); err != nil && !errors.Is(err, errors.KindNotFound) {
+			return err
+		}
+		return nil
+	}
+
+	filter := &Filter{Limit: reindexBatch}
+	for {
+		templates, err := c.db.FindTemplates(ctx, filter)
+		if err != nil {
+			return indexed, err
+		}
+		for i := range templates {
+			if err := loadDesign(&templates[i]); err != nil {
+				return indexed, err
+			}
+			if err := c.indexDocument(ctx, templateDocument(&templates[i])); err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+		if len(templates) < reindexBatch {
+			break
+		}
+		last := templates[len(templates)-1].ID
+		filter.After = &Cursor{Value: last, ID: last}
+	}
+
+	filter = &Filter{Limit: reindexBatch}
+	for {
+		comps, err := c.db.FindComponents(ctx, filter)
+		if err != nil {
+			return indexed, err
+		}
+		for i := range comps {
+			if err := loadDesign(&comps[i]); err != nil {
+				return indexed, err
+			}
+			if err := c.indexDocument(ctx, componentDocument(&comps[i])); err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+		if len(comps) < reindexBatch {
+			break
+		}
+		last := comps[len(comps)-1].ID
+		filter.After = &Cursor{Value: last, ID: last}
+	}
+
+	filter = &Filter{Limit: reindexBatch}
+	for {
+		uploads, err := c.db.FindUploads(ctx, filter)
+		if err != nil {
+			return indexed, err
+		}
+		for i := range uploads {
+			if err := c.indexDocument(ctx, uploadDocument(&uploads[i])); err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+		if len(uploads) < reindexBatch {
+			break
+		}
+		last := uploads[len(uploads)-1].ID
+		filter.After = &Cursor{Value: last, ID: last}
+	}
+
+	return indexed, nil
+}
+
+func (c *Core) deleteDocument(ctx context.Context, kind SearchKind, id string) error {
+	if c.searchIndex == nil {
+		return nil
+	}
+	return c.searchIndex.DeleteDocument(ctx, kind, id)
+}
+
+func templateDocument(template *Template) *SearchDocument {
+	return &SearchDocument{
+		ID:          template.ID,
+		Kind:        SearchTemplates,
+		CompanyID:   template.CompanyID,
+		CustomerID:  template.CustomerID,
+		Public:      template.Public && template.Published,
+		Name:        template.Name,
+		Description: template.Description,
+		Tags:        template.Tags,
+		Colors:      template.Colors,
+		Text:        layersText(template.Layers),
+		Width:       template.Frame.Width,
+		Height:      template.Frame.Height,
+		Unit:        template.Frame.Unit,
+	}
+}
+
+func componentDocument(comp *Component) *SearchDocument {
+	doc := &SearchDocument{
+		ID:         comp.ID,
+		Kind:       SearchComponents,
+		CompanyID:  comp.CompanyID,
+		CustomerID: comp.CustomerID,
+		Public:     comp.Public,
+		Name:       comp.Name,
+		Text:       layersText(comp.Layers),
+	}
+	if len(comp.Layers) > 0 {
+		doc.Width = comp.Layers[0].Width
+		doc.Height = comp.Layers[0].Height
+	}
+	return doc
+}
+
+func uploadDocument(upload *Upload) *SearchDocument {
+	return &SearchDocument{
+		ID:         upload.ID,
+		Kind:       SearchUploads,
+		CompanyID:  upload.CompanyID,
+		CustomerID: upload.CustomerID,
+		Name:       upload.Name,
+	}
+}
+
+// layersText returns the text of every text layer, nested layers included
+func layersText(layers []*Layer) []string {
+	text := []string{}
+	for _, layer := range layers {
+		switch props := layer.Props.(type) {
+		case *StaticTextProps:
+			text = append(text, props.Text)
+		case *DynamicTextProps:
+			text = append(text, props.Text)
+		}
+
+		if objects := layer.Objects(); objects != nil {
+			text = append(text, layersText(objects)...)
+		}
+	}
+	return text
+}
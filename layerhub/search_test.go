@@ -0,0 +1,39 @@
+package layerhub
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTemplateDocument(t *testing.T) {
+	var layers []*Layer
+	err := json.Unmarshal([]byte(`[
+		{"type":"StaticText","text":"Hello"},
+		{"type":"Group","objects":[{"type":"DynamicText","text":"Hi {{name}}"},{"type":"StaticImage","src":"a.png"}]}
+	]`), &layers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &Template{
+		ID:        "template_1",
+		Name:      "Greeting",
+		Tags:      []string{"hello"},
+		Public:    true,
+		Published: false,
+		Layers:    layers,
+		Frame:     Frame{Width: 1080, Height: 1920, Unit: Pixels},
+	}
+
+	doc := templateDocument(template)
+	if !reflect.DeepEqual(doc.Text, []string{"Hello", "Hi {{name}}"}) {
+		t.Errorf("got text %v", doc.Text)
+	}
+	if doc.Public {
+		t.Errorf("unpublished templates must not be public")
+	}
+	if size := doc.Size(); size != "1080x1920px" {
+		t.Errorf("got size %s, want 1080x1920px", size)
+	}
+}
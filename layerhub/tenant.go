@@ -0,0 +1,818 @@
+package layerhub
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+type TenantJobKind string
+
+const (
+	TenantDeleteCompany  TenantJobKind = "delete_company"
+	TenantDeleteCustomer TenantJobKind = "delete_customer"
+	TenantExportCustomer TenantJobKind = "export_customer"
+)
+
+type TenantJobStatus string
+
+const (
+	TenantJobPending TenantJobStatus = "pending"
+	TenantJobRunning TenantJobStatus = "running"
+	TenantJobDone    TenantJobStatus = "done"
+	TenantJobFailed  TenantJobStatus = "failed"
+)
+
+const (
+	// tenantBatch is the number of rows read at once by the tenant jobs, the
+	// progress of a job is saved after every batch
+	tenantBatch      = 100
+	tenantJobTimeout = time.Hour
+
+	// StaleTenantJob is how long a job stays pending before it's considered
+	// lost, running jobs are lost once their timeout has passed
+	StaleTenantJob = 5 * time.Minute
+)
+
+// TenantJob deletes a company or a customer with the rows and the files they
+// own, or exports the records and the files of a customer to a ZIP. Done is
+// the number of rows processed out of Total
+type TenantJob struct {
+	ID         string          `json:"id" db:"id"`
+	Kind       TenantJobKind   `json:"kind" db:"kind"`
+	Status     TenantJobStatus `json:"status" db:"status"`
+	CompanyID  string          `json:"company_id" db:"company_id"`
+	CustomerID string          `json:"customer_id,omitempty" db:"customer_id"`
+	Total      int             `json:"total" db:"total"`
+	Done       int             `json:"done" db:"done"`
+	Error      string          `json:"error,omitempty" db:"error"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at" db:"updated_at"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty" db:"finished_at"`
+
+	// ExportKey is the uploaded ZIP of a finished export, it's removed when
+	// the customer is deleted
+	ExportKey string `json:"-" db:"export_key"`
+}
+
+func NewTenantJob(kind TenantJobKind) *TenantJob {
+	now := Now()
+	return &TenantJob{
+		ID:        UniqueID("job"),
+		Kind:      kind,
+		Status:    TenantJobPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// ExportName is the file name of the ZIP of an export job
+func (j *TenantJob) ExportName() string {
+	return "export-" + j.CustomerID + ".zip"
+}
+
+// filter returns the filter of the rows owned by the tenant of the job, rows
+// in the trash included
+func (j *TenantJob) filter() *Filter {
+	if j.CustomerID != "" {
+		return &Filter{CustomerID: j.CustomerID, WithTrashed: true}
+	}
+	return &Filter{CompanyID: j.CompanyID, WithTrashed: true}
+}
+
+// framesFilter returns the filter of the frames owned by the tenant, the
+// frames of the designs are deleted with them
+func (j *TenantJob) framesFilter() *Filter {
+	usedInTemplate := false
+	filter := j.filter()
+	filter.UsedInTemplate = &usedInTemplate
+	return filter
+}
+
+// ExportCustomer starts the job exporting the records and the files of the
+// customer
+func (c *Core) ExportCustomer(ctx context.Context, customer *Customer) (*TenantJob, error) {
+	job := NewTenantJob(TenantExportCustomer)
+	job.CompanyID = customer.CompanyID
+	job.CustomerID = customer.ID
+
+	if err := c.startTenantJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (c *Core) GetTenantJob(ctx context.Context, id string) (*TenantJob, error) {
+	jobs, err := c.db.FindTenantJobs(ctx, &Filter{ID: id, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(jobs) == 0 {
+		return nil, errors.NotFound(fmt.Sprintf("job '%s' not found", id))
+	}
+
+	return &jobs[0], nil
+}
+
+// TenantExportURL returns a URL that downloads the ZIP of a finished export
+// job for a limited time
+func (c *Core) TenantExportURL(ctx context.Context, job *TenantJob) (string, error) {
+	if job.Kind != TenantExportCustomer || job.Status != TenantJobDone || job.ExportKey == "" {
+		return "", errors.NotFound(fmt.Sprintf("export of job '%s' not found", job.ID))
+	}
+
+	return c.uploader.GetDownloadURL(ctx, job.ExportKey, job.ExportName())
+}
+
+// startTenantJob saves the job and runs it in the background
+func (c *Core) startTenantJob(ctx context.Context, job *TenantJob) error {
+	if err := c.db.PutTenantJob(ctx, job); err != nil {
+		return err
+	}
+
+	go c.runTenantJob(job.ID)
+	return nil
+}
+
+// ResumeTenantJobs runs again the jobs lost by a restart, the pending jobs
+// that didn't start and the running jobs past their timeout. A resumed job
+// starts over, deleted rows stay deleted and exports are written again. A job
+// is claimed before it's resumed so it's resumed by a single instance
+func (c *Core) ResumeTenantJobs(ctx context.Context) (int, error) {
+	stale := map[TenantJobStatus]time.Duration{
+		TenantJobPending: StaleTenantJob,
+		TenantJobRunning: tenantJobTimeout,
+	}
+
+	resumed := 0
+	for status, age := range stale {
+		updatedBefore := Now().Add(-age)
+		jobs, err := c.db.FindTenantJobs(ctx, &Filter{Status: string(status), UpdatedBefore: updatedBefore})
+		if err != nil {
+			return resumed, err
+		}
+
+		for i := range jobs {
+			job := &jobs[i]
+			updatedAt := job.UpdatedAt
+			job.Status = TenantJobPending
+			job.UpdatedAt = Now()
+			claimed, err := c.db.ClaimTenantJob(ctx, job, status, updatedAt)
+			if err != nil {
+				return resumed, err
+			}
+			if !claimed {
+				continue
+			}
+
+			job.Done = 0
+			job.Error = ""
+			job.ExportKey = ""
+			if err := c.startTenantJob(ctx, job); err != nil {
+				return resumed, err
+			}
+			resumed++
+		}
+	}
+
+	return resumed, nil
+}
+
+func (c *Core) runTenantJob(id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), tenantJobTimeout)
+	defer cancel()
+
+	if err := c.RunTenantJob(ctx, id); err != nil {
+		c.Logger.Errorf("tenant job: '%s': %s", id, err)
+	}
+}
+
+// RunTenantJob runs the pending job, the job is marked as failed with the
+// error that stopped it. Deleted rows stay deleted so a failed deletion is
+// completed by another job. The job is claimed before it runs so a job
+// started twice only runs once
+func (c *Core) RunTenantJob(ctx context.Context, id string) error {
+	job, err := c.GetTenantJob(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if job.Status != TenantJobPending {
+		return errors.Validation(fmt.Sprintf("job '%s' is %s", id, job.Status))
+	}
+
+	updatedAt := job.UpdatedAt
+	job.Status = TenantJobRunning
+	job.UpdatedAt = Now()
+	claimed, err := c.db.ClaimTenantJob(ctx, job, TenantJobPending, updatedAt)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return errors.Validation(fmt.Sprintf("job '%s' was started by another run", id))
+	}
+
+	job.Total, err = c.countTenantRows(ctx, job)
+	if err != nil {
+		return c.failTenantJob(ctx, job, err)
+	}
+	job.UpdatedAt = Now()
+	if err := c.db.PutTenantJob(ctx, job); err != nil {
+		return err
+	}
+
+	if job.Kind == TenantExportCustomer {
+		err = c.exportTenant(ctx, job)
+	} else {
+		err = c.deleteTenant(ctx, job)
+	}
+	if err != nil {
+		return c.failTenantJob(ctx, job, err)
+	}
+
+	now := Now()
+	job.Status = TenantJobDone
+	job.UpdatedAt = now
+	job.FinishedAt = &now
+
+	return c.db.PutTenantJob(ctx, job)
+}
+
+func (c *Core) failTenantJob(ctx context.Context, job *TenantJob, cause error) error {
+	now := Now()
+	job.Status = TenantJobFailed
+	job.Error = cause.Error()
+	job.UpdatedAt = now
+	job.FinishedAt = &now
+
+	if err := c.db.PutTenantJob(ctx, job); err != nil {
+		return err
+	}
+
+	return cause
+}
+
+// advanceTenantJob adds n processed rows to the job and saves its progress,
+// rows created while the job runs grow its total
+func (c *Core) advanceTenantJob(ctx context.Context, job *TenantJob, n int) error {
+	job.Done += n
+	if job.Done > job.Total {
+		job.Total = job.Done
+	}
+	job.UpdatedAt = Now()
+
+	return c.db.PutTenantJob(ctx, job)
+}
+
+// countTenantRows returns the number of rows processed by the job, the rows
+// deleted with their parent like order items aren't counted
+func (c *Core) countTenantRows(ctx context.Context, job *TenantJob) (int, error) {
+	filter := job.filter()
+	counts := []func(ctx context.Context, filter *Filter) (int, error){
+		c.db.CountTemplates,
+		c.db.CountProjects,
+		c.db.CountComponents,
+		c.db.CountUploads,
+		c.db.CountFonts,
+		c.db.CountMockupTemplates,
+		c.db.CountOrders,
+		c.db.CountFolders,
+	}
+
+	// The customer or the company
+	total := 1
+	for _, count := range counts {
+		n, err := count(ctx, filter)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+
+	frames, err := c.db.CountFrames(ctx, job.framesFilter())
+	if err != nil {
+		return 0, err
+	}
+	total += frames
+
+	proofs, err := c.db.FindProofs(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	total += len(proofs)
+
+	if job.CustomerID == "" {
+		customers, err := c.db.CountCustomers(ctx, filter)
+		if err != nil {
+			return 0, err
+		}
+		users, err := c.db.FindUsers(ctx, filter)
+		if err != nil {
+			return 0, err
+		}
+		total += customers + len(users)
+	}
+
+	return total, nil
+}
+
+// deleteTenant deletes the rows and the files of the job's tenant, the
+// customer or the company is deleted last
+func (c *Core) deleteTenant(ctx context.Context, job *TenantJob) error {
+	filter := job.filter()
+	filter.Limit = tenantBatch
+	frames := job.framesFilter()
+	frames.Limit = tenantBatch
+
+	// Every step deletes the rows it reads so each batch starts again at the
+	// first row
+	steps := []func() (int, error){
+		func() (int, error) {
+			templates, err := c.db.FindTemplates(ctx, filter)
+			if err != nil {
+				return 0, err
+			}
+			for i := range templates {
+				if err := c.deleteDocument(ctx, SearchTemplates, templates[i].ID); err != nil {
+					return 0, err
+				}
+				if err := c.purgeDesign(ctx, &templates[i]); err != nil {
+					return 0, err
+				}
+			}
+			return len(templates), nil
+		},
+		func() (int, error) {
+			projects, err := c.db.FindProjects(ctx, filter)
+			if err != nil {
+				return 0, err
+			}
+			for i := range projects {
+				if err := c.purgeDesign(ctx, &projects[i]); err != nil {
+					return 0, err
+				}
+			}
+			return len(projects), nil
+		},
+		func() (int, error) {
+			comps, err := c.db.FindComponents(ctx, filter)
+			if err != nil {
+				return 0, err
+			}
+			for i := range comps {
+				if err := c.deleteDocument(ctx, SearchComponents, comps[i].ID); err != nil {
+					return 0, err
+				}
+				if err := c.purgeDesign(ctx, &comps[i]); err != nil {
+					return 0, err
+				}
+			}
+			return len(comps), nil
+		},
+		func() (int, error) {
+			uploads, err := c.db.FindUploads(ctx, filter)
+			if err != nil {
+				return 0, err
+			}
+			for i := range uploads {
+				if err := c.deleteDocument(ctx, SearchUploads, uploads[i].ID); err != nil {
+					return 0, err
+				}
+				if err := c.purgeUpload(ctx, &uploads[i]); err != nil {
+					return 0, err
+				}
+			}
+			return len(uploads), nil
+		},
+		func() (int, error) {
+			found, err := c.db.FindFrames(ctx, frames)
+			if err != nil {
+				return 0, err
+			}
+			for _, f := range found {
+				if err := c.db.DeleteFrame(ctx, f.ID); err != nil {
+					return 0, err
+				}
+			}
+			return len(found), nil
+		},
+		func() (int, error) {
+			fonts, err := c.db.FindFonts(ctx, filter)
+			if err != nil {
+				return 0, err
+			}
+			for _, f := range fonts {
+				if err := c.deleteFile(ctx, f.URL); err != nil {
+					return 0, err
+				}
+				if err := c.db.DeleteFont(ctx, f.ID); err != nil {
+					return 0, err
+				}
+			}
+			return len(fonts), nil
+		},
+		func() (int, error) {
+			mts, err := c.db.FindMockupTemplates(ctx, filter)
+			if err != nil {
+				return 0, err
+			}
+			for i := range mts {
+				if err := c.deleteMockupTemplate(ctx, &mts[i]); err != nil {
+					return 0, err
+				}
+			}
+			return len(mts), nil
+		},
+		func() (int, error) {
+			orders, err := c.db.FindOrders(ctx, filter)
+			if err != nil {
+				return 0, err
+			}
+			for _, o := range orders {
+				for _, item := range o.Items {
+					if err := c.deleteFile(ctx, item.PrintFileURL); err != nil {
+						return 0, err
+					}
+				}
+				if err := c.db.DeleteOrder(ctx, o.ID); err != nil {
+					return 0, err
+				}
+			}
+			return len(orders), nil
+		},
+		func() (int, error) {
+			proofs, err := c.db.FindProofs(ctx, filter)
+			if err != nil {
+				return 0, err
+			}
+			for _, p := range proofs {
+				if err := c.uploader.Delete(ctx, p.Key()); err != nil {
+					return 0, err
+				}
+				if err := c.deleteFile(ctx, p.Preview); err != nil {
+					return 0, err
+				}
+				if err := c.db.DeleteProof(ctx, p.ID); err != nil {
+					return 0, err
+				}
+			}
+			return len(proofs), nil
+		},
+		func() (int, error) {
+			folders, err := c.db.FindFolders(ctx, filter)
+			if err != nil {
+				return 0, err
+			}
+			for _, f := range folders {
+				if err := c.db.DeleteFolder(ctx, f.ID); err != nil {
+					return 0, err
+				}
+			}
+			return len(folders), nil
+		},
+	}
+
+	if job.CustomerID == "" {
+		steps = append(steps,
+			func() (int, error) {
+				customers, err := c.db.FindCustomers(ctx, filter)
+				if err != nil {
+					return 0, err
+				}
+				for _, cus := range customers {
+					if err := c.deleteCustomerRow(ctx, cus.ID); err != nil {
+						return 0, err
+					}
+				}
+				return len(customers), nil
+			},
+			func() (int, error) {
+				users, err := c.db.FindUsers(ctx, filter)
+				if err != nil {
+					return 0, err
+				}
+				for _, u := range users {
+					if err := c.db.DeleteUser(ctx, u.ID); err != nil {
+						return 0, err
+					}
+				}
+				return len(users), nil
+			},
+		)
+	}
+
+	for _, step := range steps {
+		for {
+			n, err := step()
+			if err != nil {
+				return err
+			}
+			if n != 0 {
+				if err := c.advanceTenantJob(ctx, job, n); err != nil {
+					return err
+				}
+			}
+			if n < tenantBatch {
+				break
+			}
+		}
+	}
+
+	if err := c.deleteExports(ctx, job); err != nil {
+		return err
+	}
+
+	if job.CustomerID != "" {
+		if err := c.deleteCustomerRow(ctx, job.CustomerID); err != nil {
+			return err
+		}
+	} else if err := c.db.DeleteCompany(ctx, job.CompanyID); err != nil {
+		return err
+	}
+
+	return c.advanceTenantJob(ctx, job, 1)
+}
+
+// deleteCustomerRow deletes the customer with their enabled fonts
+func (c *Core) deleteCustomerRow(ctx context.Context, customerID string) error {
+	enabled, err := c.db.FindEnabledFonts(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	if len(enabled) != 0 {
+		ids := make([]string, len(enabled))
+		for i, f := range enabled {
+			ids[i] = f.ID
+		}
+		if err := c.db.BatchDeleteEnabledFonts(ctx, ids); err != nil {
+			return err
+		}
+	}
+
+	return c.db.DeleteCustomer(ctx, customerID)
+}
+
+// deleteExports deletes the ZIPs of the tenant's exports
+func (c *Core) deleteExports(ctx context.Context, job *TenantJob) error {
+	jobs, err := c.db.FindTenantJobs(ctx, job.filter())
+	if err != nil {
+		return err
+	}
+
+	for i := range jobs {
+		export := &jobs[i]
+		if export.ExportKey == "" {
+			continue
+		}
+		if err := c.uploader.Delete(ctx, export.ExportKey); err != nil {
+			return err
+		}
+		export.ExportKey = ""
+		export.UpdatedAt = Now()
+		if err := c.db.PutTenantJob(ctx, export); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportTenant writes the records of the job's customer to a ZIP as JSON
+// files, with the files of their uploads and fonts, and uploads the ZIP.
+// Files that can't be read are left out of the export
+func (c *Core) exportTenant(ctx context.Context, job *TenantJob) error {
+	customer, err := c.GetCustomer(ctx, job.CustomerID)
+	if err != nil {
+		return err
+	}
+
+	// The ZIP is written to a temporary file, exports with many files don't
+	// fit in memory
+	out, err := os.CreateTemp("", "export-*.zip")
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	filter := job.filter()
+	archive := &tenantArchive{zw: zip.NewWriter(out), core: c}
+
+	if err := archive.writeJSON("customer.json", customer); err != nil {
+		return err
+	}
+	if err := c.advanceTenantJob(ctx, job, 1); err != nil {
+		return err
+	}
+
+	templates, err := c.db.FindTemplates(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for i := range templates {
+		deletedAt := templates[i].DeletedAt
+		archive.readDesign(ctx, &templates[i])
+		templates[i].DeletedAt = deletedAt
+	}
+	if err := archive.writeRecords(ctx, job, "templates.json", templates, len(templates)); err != nil {
+		return err
+	}
+
+	projects, err := c.db.FindProjects(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for i := range projects {
+		deletedAt := projects[i].DeletedAt
+		archive.readDesign(ctx, &projects[i])
+		projects[i].DeletedAt = deletedAt
+	}
+	if err := archive.writeRecords(ctx, job, "projects.json", projects, len(projects)); err != nil {
+		return err
+	}
+
+	comps, err := c.db.FindComponents(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for i := range comps {
+		deletedAt := comps[i].DeletedAt
+		archive.readDesign(ctx, &comps[i])
+		comps[i].DeletedAt = deletedAt
+	}
+	if err := archive.writeRecords(ctx, job, "components.json", comps, len(comps)); err != nil {
+		return err
+	}
+
+	uploads, err := c.db.FindUploads(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for _, u := range uploads {
+		if err := archive.writeFile(ctx, "uploads/"+u.ID, u.URL); err != nil {
+			return err
+		}
+	}
+	if err := archive.writeRecords(ctx, job, "uploads.json", uploads, len(uploads)); err != nil {
+		return err
+	}
+
+	frames, err := c.db.FindFrames(ctx, job.framesFilter())
+	if err != nil {
+		return err
+	}
+	if err := archive.writeRecords(ctx, job, "frames.json", frames, len(frames)); err != nil {
+		return err
+	}
+
+	fonts, err := c.db.FindFonts(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for _, f := range fonts {
+		if err := archive.writeFile(ctx, "fonts/"+f.ID, f.URL); err != nil {
+			return err
+		}
+	}
+	if err := archive.writeRecords(ctx, job, "fonts.json", fonts, len(fonts)); err != nil {
+		return err
+	}
+
+	enabled, err := c.db.FindEnabledFonts(ctx, customer.ID)
+	if err != nil {
+		return err
+	}
+	fontIDs := make([]string, len(enabled))
+	for i, f := range enabled {
+		fontIDs[i] = f.FontID
+	}
+	if err := archive.writeJSON("enabled_fonts.json", fontIDs); err != nil {
+		return err
+	}
+
+	mts, err := c.db.FindMockupTemplates(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if err := archive.writeRecords(ctx, job, "mockup_templates.json", mts, len(mts)); err != nil {
+		return err
+	}
+
+	orders, err := c.db.FindOrders(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if err := archive.writeRecords(ctx, job, "orders.json", orders, len(orders)); err != nil {
+		return err
+	}
+
+	proofs, err := c.db.FindProofs(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if err := archive.writeRecords(ctx, job, "proofs.json", proofs, len(proofs)); err != nil {
+		return err
+	}
+
+	folders, err := c.db.FindFolders(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if err := archive.writeRecords(ctx, job, "folders.json", folders, len(folders)); err != nil {
+		return err
+	}
+
+	if err := archive.zw.Close(); err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	if _, err := out.Seek(0, io.SeekStart); err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	key := "exports/" + job.ID + ".zip"
+	if _, err := c.uploader.UploadFile(ctx, key, out); err != nil {
+		return err
+	}
+	job.ExportKey = key
+
+	return nil
+}
+
+// tenantArchive is the ZIP written by an export job
+type tenantArchive struct {
+	zw   *zip.Writer
+	core *Core
+}
+
+func (a *tenantArchive) writeJSON(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	w, err := a.zw.Create(name)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+// writeRecords writes the n records as a JSON file and saves the progress of
+// the job
+func (a *tenantArchive) writeRecords(ctx context.Context, job *TenantJob, name string, records any, n int) error {
+	if err := a.writeJSON(name, records); err != nil {
+		return err
+	}
+
+	return a.core.advanceTenantJob(ctx, job, n)
+}
+
+// writeFile writes the uploaded file of the URL, the file keeps the extension
+// of its key
+func (a *tenantArchive) writeFile(ctx context.Context, name, fileURL string) error {
+	key, ok := a.core.uploader.Key(fileURL)
+	if !ok {
+		return nil
+	}
+
+	data, err := a.core.uploader.Download(ctx, key)
+	if err != nil {
+		a.core.Logger.Warnf("tenant export: skipping %s: %s", key, err)
+		return nil
+	}
+
+	w, err := a.zw.Create(name + path.Ext(key))
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+// readDesign reads the content of the design over its row, the row is
+// exported alone when its content can't be read
+func (a *tenantArchive) readDesign(ctx context.Context, dsg Design) {
+	if err := a.core.designs.Get(ctx, dsg); err != nil {
+		a.core.Logger.Warnf("tenant export: skipping the content of %s: %s", dsg.Key(), err)
+	}
+}
@@ -0,0 +1,487 @@
+package layerhub
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// TextFit is how text that doesn't fit its layer is handled
+type TextFit string
+
+const (
+	// TextFitShrink reduces the font size down to the layer's minimum
+	TextFitShrink TextFit = "shrink"
+	// TextFitEllipsis drops the lines that don't fit and ends the last one
+	// with an ellipsis
+	TextFitEllipsis TextFit = "ellipsis"
+	// TextFitGrow extends the height of the layer
+	TextFitGrow TextFit = "grow"
+)
+
+const (
+	defaultMinFontSize = 6
+	fontSizeStep       = 0.5
+	ellipsis           = "…"
+
+	// defaultLineHeight and fontSizeMult are the Fabric.js defaults, they are
+	// used to compute the height of the lines
+	defaultLineHeight = 1.16
+	fontSizeMult      = 1.13
+)
+
+var (
+	fallbackFontOnce sync.Once
+	fallbackFont     *sfnt.Font
+)
+
+// fittingRenderer fits the text of designs to their layers before they are
+// rendered
+type fittingRenderer struct {
+	Renderer
+	core *Core
+}
+
+func (r *fittingRenderer) Render(ctx context.Context, sch any, params map[string]any) (string, error) {
+	sch, err := r.core.fitDesign(ctx, sch, params)
+	if err != nil {
+		return "", err
+	}
+	return r.Renderer.Render(ctx, sch, params)
+}
+
+func (r *fittingRenderer) RawRender(ctx context.Context, sch any, params map[string]any) ([]byte, error) {
+	sch, err := r.core.fitDesign(ctx, sch, params)
+	if err != nil {
+		return nil, err
+	}
+	return r.Renderer.RawRender(ctx, sch, params)
+}
+
+// fitDesign returns a copy of templates and projects with their text layers
+// fitted, other schemas are returned as is
+func (c *Core) fitDesign(ctx context.Context, sch any, params map[string]any) (any, error) {
+	switch s := sch.(type) {
+	case *Template:
+		f := &textFitter{core: c, companyID: s.CompanyID, params: params}
+		layers, err := f.fitLayers(ctx, s.Layers)
+		if err != nil {
+			return nil, err
+		}
+		t := *s
+		t.Layers = layers
+		return &t, nil
+	case *Project:
+		f := &textFitter{core: c, companyID: s.CompanyID, params: params}
+		layers, err := f.fitLayers(ctx, s.Layers)
+		if err != nil {
+			return nil, err
+		}
+		p := *s
+		p.Layers = layers
+		return &p, nil
+	default:
+		return sch, nil
+	}
+}
+
+type textFitter struct {
+	core      *Core
+	companyID string
+	params    map[string]any
+	fonts     map[string]*sfnt.Font
+}
+
+func (f *textFitter) fitLayers(ctx context.Context, layers []*Layer) ([]*Layer, error) {
+	out := make([]*Layer, len(layers))
+	for i, layer := range layers {
+		out[i] = layer
+
+		switch props := layer.Props.(type) {
+		case *GroupProps:
+			objects, err := f.fitLayers(ctx, props.Objects)
+			if err != nil {
+				return nil, err
+			}
+			l := *layer
+			l.Props = &GroupProps{Objects: objects}
+			out[i] = &l
+		case *DynamicGroupProps:
+			objects, err := f.fitLayers(ctx, props.Objects)
+			if err != nil {
+				return nil, err
+			}
+			l := *layer
+			l.Props = &DynamicGroupProps{Key: props.Key, Objects: objects}
+			out[i] = &l
+		case *StaticTextProps:
+			if props.TextFit == "" {
+				continue
+			}
+			l, err := f.fitText(ctx, layer, *props)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = l
+		case *DynamicTextProps:
+			if props.TextFit == "" {
+				continue
+			}
+			// the text is resolved here so it can be measured, the layer
+			// is rendered as static text
+			text := props.StaticTextProps
+			text.Text = dynamicText(props, f.params)
+			l, err := f.fitText(ctx, layer, text)
+			if err != nil {
+				return nil, err
+			}
+			l.Type = LayerStaticText
+			out[i] = l
+		}
+	}
+
+	return out, nil
+}
+
+func (f *textFitter) fitText(ctx context.Context, layer *Layer, props StaticTextProps) (*Layer, error) {
+	face, err := f.font(ctx, props.FontFamily)
+	if err != nil {
+		return nil, err
+	}
+
+	fitted, height := fitText(newSFNTMeasurer(face), props, layer.Width, layer.Height)
+
+	l := *layer
+	l.Props = &fitted
+	if height > l.Height {
+		l.Height = height
+	}
+
+	return &l, nil
+}
+
+// font returns the font of the family, fonts that can't be found fall back
+// to Go Regular so text is still fitted
+func (f *textFitter) font(ctx context.Context, family string) (*sfnt.Font, error) {
+	if face, ok := f.fonts[family]; ok {
+		return face, nil
+	}
+
+	face, err := f.core.loadFontFamily(ctx, f.companyID, family)
+	if err != nil {
+		return nil, err
+	}
+	if face == nil {
+		f.core.Logger.Warnf("text fit: font '%s' not found, using the fallback font", family)
+		face = defaultFont()
+	}
+
+	if f.fonts == nil {
+		f.fonts = map[string]*sfnt.Font{}
+	}
+	f.fonts[family] = face
+
+	return face, nil
+}
+
+// loadFontFamily returns the parsed font file of the family, nil if the
+// company has no such font
+func (c *Core) loadFontFamily(ctx context.Context, companyID, family string) (*sfnt.Font, error) {
+	if family == "" {
+		return nil, nil
+	}
+
+	fonts, err := c.db.FindFonts(ctx, &Filter{
+		PostscriptName:    family,
+		OptionalCompanyID: companyID,
+		Limit:             1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(fonts) == 0 || fonts[0].URL == "" {
+		return nil, nil
+	}
+
+	return c.loadFont(ctx, fonts[0].URL)
+}
+
+// loadFont downloads and parses the font file, parsed fonts are cached by URL.
+// Files that can't be parsed, like WOFF and WOFF2 fonts, fall back to Go
+// Regular so the design is still rendered
+func (c *Core) loadFont(ctx context.Context, url string) (*sfnt.Font, error) {
+	if face, ok := c.fonts.get(url); ok {
+		return face, nil
+	}
+
+	data, err := fetchFont(ctx, c.clientFor(url), url)
+	if err != nil {
+		return nil, err
+	}
+
+	face, err := sfnt.Parse(data)
+	if err != nil {
+		c.Logger.Warnf("text fit: parsing font '%s': %s, using the fallback font", url, err)
+		face = defaultFont()
+	}
+
+	c.fonts.add(url, face)
+
+	return face, nil
+}
+
+// maxCachedFonts is the number of parsed fonts kept by the font cache
+const maxCachedFonts = 50
+
+// fontCache keeps the most recently used fonts
+type fontCache struct {
+	sync.Mutex
+	order *list.List
+	byURL map[string]*list.Element
+}
+
+type fontEntry struct {
+	url  string
+	face *sfnt.Font
+}
+
+func (fc *fontCache) get(url string) (*sfnt.Font, bool) {
+	fc.Lock()
+	defer fc.Unlock()
+
+	el, ok := fc.byURL[url]
+	if !ok {
+		return nil, false
+	}
+	fc.order.MoveToFront(el)
+
+	return el.Value.(*fontEntry).face, true
+}
+
+func (fc *fontCache) add(url string, face *sfnt.Font) {
+	fc.Lock()
+	defer fc.Unlock()
+
+	if fc.byURL == nil {
+		fc.order = list.New()
+		fc.byURL = map[string]*list.Element{}
+	}
+	if el, ok := fc.byURL[url]; ok {
+		el.Value.(*fontEntry).face = face
+		fc.order.MoveToFront(el)
+		return
+	}
+
+	fc.byURL[url] = fc.order.PushFront(&fontEntry{url, face})
+	if fc.order.Len() > maxCachedFonts {
+		oldest := fc.order.Back()
+		fc.order.Remove(oldest)
+		delete(fc.byURL, oldest.Value.(*fontEntry).url)
+	}
+}
+
+func defaultFont() *sfnt.Font {
+	fallbackFontOnce.Do(func() {
+		face, err := sfnt.Parse(goregular.TTF)
+		if err != nil {
+			panic(err)
+		}
+		fallbackFont = face
+	})
+	return fallbackFont
+}
+
+// dynamicText replaces the {{key}} placeholders of the text with the params,
+// or with their default values
+func dynamicText(props *DynamicTextProps, params map[string]any) string {
+	text := props.Text
+	for _, kv := range props.KeyValues {
+		value := kv.Value
+		if v, ok := params[kv.Key]; ok && v != nil {
+			value = fmt.Sprint(v)
+		}
+		text = strings.ReplaceAll(text, "{{"+kv.Key+"}}", value)
+	}
+	return text
+}
+
+type textMeasurer interface {
+	// advance returns the width of the text at the font size in pixels
+	advance(text string, size float64) float64
+}
+
+type sfntMeasurer struct {
+	font *sfnt.Font
+	buf  sfnt.Buffer
+}
+
+func newSFNTMeasurer(f *sfnt.Font) *sfntMeasurer {
+	return &sfntMeasurer{font: f}
+}
+
+func (m *sfntMeasurer) advance(text string, size float64) float64 {
+	ppem := fixed.Int26_6(size * 64)
+
+	var width fixed.Int26_6
+	prev := sfnt.GlyphIndex(0)
+	for i, r := range text {
+		idx, err := m.font.GlyphIndex(&m.buf, r)
+		if err != nil {
+			continue
+		}
+
+		if i > 0 {
+			if kern, err := m.font.Kern(&m.buf, prev, idx, ppem, font.HintingNone); err == nil {
+				width += kern
+			}
+		}
+
+		adv, err := m.font.GlyphAdvance(&m.buf, idx, ppem, font.HintingNone)
+		if err == nil {
+			width += adv
+		}
+		prev = idx
+	}
+
+	return float64(width) / 64
+}
+
+// fitText fits the text to the box with the props' fit mode and returns the
+// fitted props along with the height of the text
+func fitText(m textMeasurer, props StaticTextProps, width, height float64) (StaticTextProps, float64) {
+	if props.FontSize <= 0 || width <= 0 {
+		return props, 0
+	}
+
+	lineHeight := props.Lineheight
+	if lineHeight <= 0 {
+		lineHeight = defaultLineHeight
+	}
+
+	measure := func(size float64) func(string) float64 {
+		return func(s string) float64 {
+			// charspacing is in thousandths of an em
+			spacing := props.Charspacing / 1000 * size * float64(len([]rune(s)))
+			return m.advance(s, size) + spacing
+		}
+	}
+
+	switch props.TextFit {
+	case TextFitShrink:
+		min := props.MinFontSize
+		if min <= 0 {
+			min = defaultMinFontSize
+		}
+
+		size := props.FontSize
+		for size > min {
+			lines, fits := wrapText(props.Text, width, measure(size), false)
+			if fits && textHeight(len(lines), size, lineHeight) <= height {
+				break
+			}
+			size = math.Max(size-fontSizeStep, min)
+		}
+		props.FontSize = size
+
+		lines, _ := wrapText(props.Text, width, measure(size), false)
+		return props, textHeight(len(lines), size, lineHeight)
+	case TextFitEllipsis:
+		measureLine := measure(props.FontSize)
+		lines, fits := wrapText(props.Text, width, measureLine, true)
+
+		maxLines := 1
+		for textHeight(maxLines+1, props.FontSize, lineHeight) <= height {
+			maxLines++
+		}
+
+		if len(lines) > maxLines {
+			lines = lines[:maxLines]
+			last := []rune(strings.TrimRightFunc(lines[maxLines-1], unicode.IsSpace))
+			for len(last) > 0 && measureLine(string(last)+ellipsis) > width {
+				last = []rune(strings.TrimRightFunc(string(last[:len(last)-1]), unicode.IsSpace))
+			}
+			lines[maxLines-1] = string(last) + ellipsis
+			fits = false
+		}
+
+		if !fits {
+			props.Text = strings.Join(lines, "\n")
+		}
+		return props, textHeight(len(lines), props.FontSize, lineHeight)
+	case TextFitGrow:
+		lines, _ := wrapText(props.Text, width, measure(props.FontSize), false)
+		return props, textHeight(len(lines), props.FontSize, lineHeight)
+	default:
+		return props, 0
+	}
+}
+
+// textHeight is the height of a text box with n lines as Fabric.js computes
+// it, the last line doesn't have line spacing
+func textHeight(n int, size, lineHeight float64) float64 {
+	if n == 0 {
+		return 0
+	}
+	return size * fontSizeMult * (lineHeight*float64(n-1) + 1)
+}
+
+// wrapText wraps the text at spaces like a Fabric.js text box. Words wider
+// than the box are split when breakWords is set, fits reports whether every
+// line is within the width and no word was split
+func wrapText(text string, width float64, measure func(string) float64, breakWords bool) (lines []string, fits bool) {
+	fits = true
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Split(paragraph, " ") {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if measure(candidate) <= width {
+				line = candidate
+				continue
+			}
+
+			if line != "" {
+				lines = append(lines, line)
+			}
+			line = word
+
+			if measure(word) > width {
+				fits = false
+				if breakWords {
+					var parts []string
+					parts, line = splitWord(word, width, measure)
+					lines = append(lines, parts...)
+				}
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, fits
+}
+
+// splitWord splits the word in parts that fit the width, the last part is
+// returned apart so following words can be appended to it
+func splitWord(word string, width float64, measure func(string) float64) ([]string, string) {
+	var parts []string
+	part := []rune{}
+	for _, r := range word {
+		if len(part) > 0 && measure(string(append(part, r))) > width {
+			parts = append(parts, string(part))
+			part = part[:0]
+		}
+		part = append(part, r)
+	}
+	return parts, string(part)
+}
@@ -0,0 +1,201 @@
+package layerhub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/testhelpers/fakes"
+	"go.uber.org/zap"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// monospace measures every character as half the font size
+type monospace struct{}
+
+func (monospace) advance(text string, size float64) float64 {
+	return float64(len([]rune(text))) * size / 2
+}
+
+func TestFitText(t *testing.T) {
+	testcases := []struct {
+		name     string
+		props    StaticTextProps
+		width    float64
+		height   float64
+		fontSize float64
+		text     string
+		textH    float64
+	}{
+		{
+			name:     "without fit",
+			props:    StaticTextProps{Text: "hello world", FontSize: 20},
+			width:    100,
+			height:   30,
+			fontSize: 20,
+			text:     "hello world",
+		},
+		{
+			name:     "shrink",
+			props:    StaticTextProps{Text: "hello world", FontSize: 20, TextFit: TextFitShrink},
+			width:    100,
+			height:   30,
+			fontSize: 18,
+			text:     "hello world",
+			textH:    18 * fontSizeMult,
+		},
+		{
+			name:     "shrink to minimum",
+			props:    StaticTextProps{Text: "hello world", FontSize: 20, TextFit: TextFitShrink, MinFontSize: 19},
+			width:    100,
+			height:   30,
+			fontSize: 19,
+			text:     "hello world",
+			textH:    19 * fontSizeMult * (defaultLineHeight + 1),
+		},
+		{
+			name:     "ellipsis",
+			props:    StaticTextProps{Text: "one two three four five", FontSize: 10, TextFit: TextFitEllipsis},
+			width:    50,
+			height:   12,
+			fontSize: 10,
+			text:     "one two…",
+			textH:    10 * fontSizeMult,
+		},
+		{
+			name:     "ellipsis splits long words",
+			props:    StaticTextProps{Text: "abcdefghijkl", FontSize: 10, TextFit: TextFitEllipsis},
+			width:    50,
+			height:   100,
+			fontSize: 10,
+			text:     "abcdefghij\nkl",
+			textH:    10 * fontSizeMult * (defaultLineHeight + 1),
+		},
+		{
+			name:     "ellipsis within the box",
+			props:    StaticTextProps{Text: "one two", FontSize: 10, TextFit: TextFitEllipsis},
+			width:    50,
+			height:   12,
+			fontSize: 10,
+			text:     "one two",
+			textH:    10 * fontSizeMult,
+		},
+		{
+			name:     "grow",
+			props:    StaticTextProps{Text: "one two three four five", FontSize: 10, Lineheight: 1, TextFit: TextFitGrow},
+			width:    50,
+			height:   10,
+			fontSize: 10,
+			text:     "one two three four five",
+			textH:    10 * fontSizeMult * 3,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			props, height := fitText(monospace{}, tc.props, tc.width, tc.height)
+			if props.FontSize != tc.fontSize {
+				t.Errorf("got font size %v, want %v", props.FontSize, tc.fontSize)
+			}
+			if props.Text != tc.text {
+				t.Errorf("got text %q, want %q", props.Text, tc.text)
+			}
+			if diff := height - tc.textH; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("got height %v, want %v", height, tc.textH)
+			}
+		})
+	}
+}
+
+func TestSFNTMeasurer(t *testing.T) {
+	m := newSFNTMeasurer(defaultFont())
+
+	if narrow, wide := m.advance("iiii", 20), m.advance("WWWW", 20); narrow >= wide {
+		t.Errorf("expected 'iiii' (%v) to be narrower than 'WWWW' (%v)", narrow, wide)
+	}
+
+	if small, large := m.advance("hello", 10), m.advance("hello", 20); large < 1.9*small || large > 2.1*small {
+		t.Errorf("expected the width to scale with the font size: %v, %v", small, large)
+	}
+}
+
+func TestLoadFont(t *testing.T) {
+	ctx := context.Background()
+	files := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".woff2") {
+			w.Write([]byte("wOF2 not a font"))
+			return
+		}
+		w.Write(goregular.TTF)
+	}))
+	defer files.Close()
+
+	uploader := fakes.NewUploader()
+	c := &Core{Logger: zap.NewNop().Sugar(), fonts: &fontCache{}, uploader: uploader}
+
+	// The test server listens on a loopback address outside of the uploader
+	_, err := c.loadFont(ctx, files.URL+"/font.ttf")
+	if !errors.Is(err, errors.KindValidation) {
+		t.Fatalf("got error %v, want a validation error", err)
+	}
+
+	uploader.BaseURL = files.URL
+	face, err := c.loadFont(ctx, files.URL+"/font.woff2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if face != defaultFont() {
+		t.Error("expected the fallback font for a font that can't be parsed")
+	}
+
+	for i := 0; i < maxCachedFonts+10; i++ {
+		if _, err := c.loadFont(ctx, fmt.Sprintf("%s/%d.ttf", files.URL, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(c.fonts.byURL); n != maxCachedFonts {
+		t.Errorf("got %d cached fonts, want %d", n, maxCachedFonts)
+	}
+	if _, ok := c.fonts.get(files.URL + "/font.woff2"); ok {
+		t.Error("expected the least recently used font to be evicted")
+	}
+}
+
+func TestFitDesign(t *testing.T) {
+	c := &Core{Logger: zap.NewNop().Sugar(), fonts: &fontCache{}}
+
+	text, err := NewLayer(BaseLayer{ID: "title", Type: LayerDynamicText, Width: 100, Height: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	props := text.Props.(*DynamicTextProps)
+	props.Text = "Hello {{name}}, welcome to {{place}}"
+	props.FontSize = 20
+	props.TextFit = TextFitGrow
+	props.KeyValues = []KeyValue{{Key: "name", Value: "friend"}, {Key: "place", Value: "the party"}}
+
+	template := &Template{Layers: []*Layer{text}}
+	sch, err := c.fitDesign(context.Background(), template, map[string]any{"name": "Jane"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fitted := sch.(*Template).Layers[0]
+	if fitted.Type != LayerStaticText {
+		t.Errorf("got type %s, want %s", fitted.Type, LayerStaticText)
+	}
+	if got := fitted.Props.(*StaticTextProps).Text; got != "Hello Jane, welcome to the party" {
+		t.Errorf("got text %q", got)
+	}
+	if fitted.Height <= 10 {
+		t.Errorf("expected the layer to grow, got height %v", fitted.Height)
+	}
+
+	if template.Layers[0] != text || text.Height != 10 || text.Type != LayerDynamicText {
+		t.Errorf("the original template was modified")
+	}
+}
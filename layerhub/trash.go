@@ -0,0 +1,253 @@
+package layerhub
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultTrashRetention is how long the deleted templates, projects,
+// components and uploads stay in the trash before they're purged
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// purgeBatch is the number of rows deleted at once by PurgeTrash
+const purgeBatch = 100
+
+// RestoreTemplate moves the template out of the trash, template is a row of
+// FindTemplates with Filter.Trashed
+func (c *Core) RestoreTemplate(ctx context.Context, template *Template) error {
+	if err := c.designs.Get(ctx, template); err != nil {
+		return err
+	}
+
+	folderID, err := c.restoredFolder(ctx, template.FolderID)
+	if err != nil {
+		return err
+	}
+	template.FolderID = folderID
+	template.DeletedAt = nil
+
+	err = c.putDesign(ctx, template, func(db DB) error {
+		return db.PutTemplate(ctx, template)
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.indexDocument(ctx, templateDocument(template))
+}
+
+// RestoreProject moves the project out of the trash
+func (c *Core) RestoreProject(ctx context.Context, project *Project) error {
+	if err := c.designs.Get(ctx, project); err != nil {
+		return err
+	}
+
+	folderID, err := c.restoredFolder(ctx, project.FolderID)
+	if err != nil {
+		return err
+	}
+	project.FolderID = folderID
+	project.DeletedAt = nil
+
+	return c.putDesign(ctx, project, func(db DB) error {
+		return db.PutProject(ctx, project)
+	})
+}
+
+// RestoreComponent moves the component out of the trash
+func (c *Core) RestoreComponent(ctx context.Context, comp *Component) error {
+	if err := c.designs.Get(ctx, comp); err != nil {
+		return err
+	}
+
+	folderID, err := c.restoredFolder(ctx, comp.FolderID)
+	if err != nil {
+		return err
+	}
+	comp.FolderID = folderID
+	comp.DeletedAt = nil
+
+	err = c.putDesign(ctx, comp, func(db DB) error {
+		return db.PutComponent(ctx, comp)
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.indexDocument(ctx, componentDocument(comp))
+}
+
+// RestoreUpload moves the upload out of the trash
+func (c *Core) RestoreUpload(ctx context.Context, upload *Upload) error {
+	folderID, err := c.restoredFolder(ctx, upload.Folder)
+	if err != nil {
+		return err
+	}
+	upload.Folder = folderID
+	upload.DeletedAt = nil
+
+	if err := c.db.PutUpload(ctx, upload); err != nil {
+		return err
+	}
+
+	return c.indexDocument(ctx, uploadDocument(upload))
+}
+
+// restoredFolder returns the folder of a restored item, items of a folder
+// deleted while they were in the trash are restored outside of any folder
+func (c *Core) restoredFolder(ctx context.Context, folderID string) (string, error) {
+	if folderID == "" {
+		return "", nil
+	}
+
+	count, err := c.db.CountFolders(ctx, &Filter{ID: folderID})
+	if err != nil {
+		return "", err
+	}
+	if count == 0 {
+		return "", nil
+	}
+
+	return folderID, nil
+}
+
+// PurgeTrash deletes the templates, projects, components and uploads moved
+// to the trash before the time, with their designs and uploaded files. It
+// returns the number of items deleted
+func (c *Core) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	filter := &Filter{TrashedBefore: before, Limit: purgeBatch}
+	purged := 0
+
+	// Every batch is deleted so the next one starts again at the first row
+	for {
+		templates, err := c.db.FindTemplates(ctx, filter)
+		if err != nil {
+			return purged, err
+		}
+		for i := range templates {
+			if err := c.purgeDesign(ctx, &templates[i]); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+		if len(templates) < purgeBatch {
+			break
+		}
+	}
+
+	for {
+		projects, err := c.db.FindProjects(ctx, filter)
+		if err != nil {
+			return purged, err
+		}
+		for i := range projects {
+			if err := c.purgeDesign(ctx, &projects[i]); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+		if len(projects) < purgeBatch {
+			break
+		}
+	}
+
+	for {
+		comps, err := c.db.FindComponents(ctx, filter)
+		if err != nil {
+			return purged, err
+		}
+		for i := range comps {
+			if err := c.purgeDesign(ctx, &comps[i]); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+		if len(comps) < purgeBatch {
+			break
+		}
+	}
+
+	for {
+		uploads, err := c.db.FindUploads(ctx, filter)
+		if err != nil {
+			return purged, err
+		}
+		for i := range uploads {
+			if err := c.purgeUpload(ctx, &uploads[i]); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+		if len(uploads) < purgeBatch {
+			break
+		}
+	}
+
+	return purged, nil
+}
+
+// purgeDesign deletes the mockups, the preview and the content of the design,
+// then its row and its frame. The files are deleted first, a failed purge is
+// retried from the row
+func (c *Core) purgeDesign(ctx context.Context, dsg Design) error {
+	var (
+		preview string
+		err     error
+	)
+	switch d := dsg.(type) {
+	case *Template:
+		preview = d.Preview
+		err = c.deleteMockupsOf(ctx, d.ID)
+	case *Project:
+		preview = d.Preview
+		err = c.deleteMockupsOf(ctx, d.ID)
+	case *Component:
+		preview = d.Preview
+	}
+	if err != nil {
+		return err
+	}
+	if err := c.deleteFile(ctx, preview); err != nil {
+		return err
+	}
+	if err := c.designs.Delete(ctx, dsg); err != nil {
+		return err
+	}
+
+	return c.db.WithTx(ctx, func(tx DB) error {
+		switch d := dsg.(type) {
+		case *Template:
+			if err := tx.DeleteTemplate(ctx, d.ID); err != nil {
+				return err
+			}
+			return tx.DeleteFrame(ctx, d.ID)
+		case *Project:
+			if err := tx.DeleteProject(ctx, d.ID); err != nil {
+				return err
+			}
+			return tx.DeleteFrame(ctx, d.ID)
+		case *Component:
+			return tx.DeleteComponent(ctx, d.ID)
+		}
+		return nil
+	})
+}
+
+// purgeUpload deletes the uploaded file, then the upload
+func (c *Core) purgeUpload(ctx context.Context, upload *Upload) error {
+	if err := c.deleteFile(ctx, upload.URL); err != nil {
+		return err
+	}
+
+	return c.db.DeleteUpload(ctx, upload.ID)
+}
+
+// deleteFile deletes the uploaded file of the URL, URLs of files that weren't
+// uploaded are ignored
+func (c *Core) deleteFile(ctx context.Context, fileURL string) error {
+	key, ok := c.uploader.Key(fileURL)
+	if !ok {
+		return nil
+	}
+	return c.uploader.Delete(ctx, key)
+}
@@ -19,6 +19,9 @@ type Upload struct {
 	CustomerID  string    `json:"customer_id" db:"customer_id"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+
+	// DeletedAt is set while the upload is in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 func NewUpload() *Upload {
@@ -51,7 +54,7 @@ func (c *Core) PutUpload(ctx context.Context, upload *Upload) error {
 	if err := c.db.PutUpload(ctx, upload); err != nil {
 		return err
 	}
-	return nil
+	return c.indexDocument(ctx, uploadDocument(upload))
 }
 
 func (c *Core) GetUpload(ctx context.Context, id string) (*Upload, error) {
@@ -67,6 +70,19 @@ func (c *Core) GetUpload(ctx context.Context, id string) (*Upload, error) {
 	return &uploads[0], nil
 }
 
+// DeleteUpload moves the upload to the trash, the uploaded file is kept until
+// it's purged
 func (c *Core) DeleteUpload(ctx context.Context, id string) error {
-	return c.db.DeleteUpload(ctx, id)
+	upload, err := c.GetUpload(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := Now()
+	upload.DeletedAt = &now
+	if err := c.db.PutUpload(ctx, upload); err != nil {
+		return err
+	}
+
+	return c.deleteDocument(ctx, SearchUploads, id)
 }
@@ -32,6 +32,7 @@ type User struct {
 	PhoneVerified bool       `json:"phone_verified" db:"phone_verified"`
 	PasswordHash  string     `json:"password_hash" db:"password_hash"`
 	PlanID        string     `json:"plan_id" db:"plan_id"`
+	ApiToken      string     `json:"-" db:"api_token"`
 	Role          UserRole   `json:"role" db:"role"`
 	Source        AuthSource `json:"source" db:"source"`
 	CompanyID     string     `json:"company_id" db:"company_id"`
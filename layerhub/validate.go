@@ -0,0 +1,277 @@
+package layerhub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+const (
+	// MaxDesignSize is the maximum size in bytes of a design payload
+	MaxDesignSize = 4 << 20
+	// MaxLayerDepth is the maximum nesting of groups
+	MaxLayerDepth = 10
+	// MaxLayerCount is the maximum number of layers in a design, including
+	// the objects of groups
+	MaxLayerCount = 1000
+)
+
+type layerSpec struct {
+	// required props must be present
+	required []string
+	// nonEmpty props must be present and be a non empty string
+	nonEmpty []string
+	// children is true for layers with nested objects
+	children bool
+}
+
+var layerSpecs = map[LayerType]layerSpec{
+	LayerStaticText:    {required: []string{"text"}},
+	LayerDynamicText:   {},
+	LayerStaticImage:   {nonEmpty: []string{"src"}},
+	LayerStaticVideo:   {nonEmpty: []string{"src"}},
+	LayerStaticAudio:   {nonEmpty: []string{"src"}},
+	LayerDynamicImage:  {required: []string{"key"}},
+	LayerStaticVector:  {nonEmpty: []string{"src"}},
+	LayerStaticPath:    {required: []string{"path"}},
+	LayerBackground:    {},
+	LayerGroup:         {required: []string{"objects"}, children: true},
+	LayerStaticGroup:   {required: []string{"objects"}, children: true},
+	LayerDynamicGroup:  {required: []string{"key", "objects"}, children: true},
+	LayerDynamicPath:   {required: []string{"key", "path"}},
+	LayerFrame:         {},
+	LayerStaticQRCode:  {nonEmpty: []string{"data"}},
+	LayerDynamicQRCode: {required: []string{"key"}},
+	LayerBarcode:       {nonEmpty: []string{"format"}},
+}
+
+var layerNumberProps = []string{
+	"top", "left", "angle", "width", "height", "scaleX", "scaleY", "opacity",
+	"skewX", "skewY", "strokeWidth", "duration", "quietZone", "fontSize",
+	"minFontSize",
+}
+
+// layerEnumProps are string props with a fixed set of values
+var layerEnumProps = map[string][]string{
+	"textFit": {"", string(TextFitShrink), string(TextFitEllipsis), string(TextFitGrow)},
+}
+
+var layerColorProps = []string{"fill", "stroke", "backgroundColor"}
+
+var colorPattern = regexp.MustCompile(`^(#([0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})|(rgba?|hsla?)\(\s*[-+0-9.%\s,/]+\)|[a-zA-Z]+)$`)
+
+// ValidateDesignJSON validates the layers of a raw design payload before it's
+// decoded, errors are of kind validation and point to the offending value,
+// e.g. layers[2].objects[0].type
+func ValidateDesignJSON(data []byte) error {
+	if len(data) > MaxDesignSize {
+		return errors.Validation(fmt.Sprintf("design is %d bytes, the maximum is %d bytes", len(data), MaxDesignSize))
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return errors.Validation(fmt.Sprintf("malformed design: %s", err))
+	}
+
+	obj, ok := doc.(map[string]any)
+	if !ok {
+		return errors.Validation("design must be an object")
+	}
+
+	layers, ok := obj["layers"]
+	if !ok || layers == nil {
+		return nil
+	}
+
+	v := &layerValidator{}
+	return v.layers("layers", layers, 1)
+}
+
+type layerValidator struct {
+	count int
+}
+
+func (v *layerValidator) layers(path string, raw any, depth int) error {
+	if depth > MaxLayerDepth {
+		return errors.Validation(fmt.Sprintf("%s: groups can't be nested more than %d levels", path, MaxLayerDepth))
+	}
+
+	layers, ok := raw.([]any)
+	if !ok {
+		return errors.Validation(fmt.Sprintf("%s must be an array", path))
+	}
+
+	for i, layer := range layers {
+		v.count++
+		if v.count > MaxLayerCount {
+			return errors.Validation(fmt.Sprintf("design has more than %d layers", MaxLayerCount))
+		}
+
+		if err := v.layer(fmt.Sprintf("%s[%d]", path, i), layer, depth); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *layerValidator) layer(path string, raw any, depth int) error {
+	obj, ok := raw.(map[string]any)
+	if !ok {
+		return errors.Validation(fmt.Sprintf("%s must be an object", path))
+	}
+
+	typ, ok := obj["type"].(string)
+	if !ok {
+		return errors.Validation(fmt.Sprintf("%s.type is required", path))
+	}
+
+	spec, ok := layerSpecs[LayerType(typ)]
+	if !ok {
+		return errors.Validation(fmt.Sprintf("%s.type: unknown layer type '%s'", path, typ))
+	}
+
+	for _, name := range layerNumberProps {
+		if val, ok := obj[name]; ok && val != nil {
+			if _, ok := val.(json.Number); !ok {
+				return errors.Validation(fmt.Sprintf("%s.%s must be a number", path, name))
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(obj) {
+		if name == "objects" {
+			continue
+		}
+		if err := finite(path+"."+name, obj[name]); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range spec.required {
+		if val, ok := obj[name]; !ok || val == nil {
+			return errors.Validation(fmt.Sprintf("%s.%s is required", path, name))
+		}
+	}
+
+	for _, name := range spec.nonEmpty {
+		if val, ok := obj[name].(string); !ok || val == "" {
+			return errors.Validation(fmt.Sprintf("%s.%s is required", path, name))
+		}
+	}
+
+	for _, name := range sortedKeys(obj) {
+		values, ok := layerEnumProps[name]
+		if !ok || obj[name] == nil {
+			continue
+		}
+		if !oneOf(obj[name], values) {
+			return errors.Validation(fmt.Sprintf("%s.%s must be one of '%s'", path, name, strings.Join(values[1:], "', '")))
+		}
+	}
+
+	for _, name := range layerColorProps {
+		if err := color(path+"."+name, obj[name]); err != nil {
+			return err
+		}
+	}
+
+	if shadow, ok := obj["shadow"].(map[string]any); ok {
+		if err := color(path+".shadow.color", shadow["color"]); err != nil {
+			return err
+		}
+	}
+
+	if colorMap, ok := obj["colorMap"].(map[string]any); ok {
+		for _, k := range sortedKeys(colorMap) {
+			if err := color(fmt.Sprintf("%s.colorMap[%q]", path, k), colorMap[k]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if spec.children {
+		return v.layers(path+".objects", obj["objects"], depth+1)
+	}
+
+	return nil
+}
+
+// finite checks that every number in the value fits in a float64
+func finite(path string, val any) error {
+	switch val := val.(type) {
+	case json.Number:
+		f, err := strconv.ParseFloat(string(val), 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return errors.Validation(fmt.Sprintf("%s: '%s' is not a finite number", path, val))
+		}
+	case []any:
+		for i, v := range val {
+			if err := finite(fmt.Sprintf("%s[%d]", path, i), v); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		for _, k := range sortedKeys(val) {
+			if err := finite(path+"."+k, val[k]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// color checks that the value is empty or a CSS color
+func color(path string, val any) error {
+	if val == nil {
+		return nil
+	}
+
+	s, ok := val.(string)
+	if !ok {
+		return errors.Validation(fmt.Sprintf("%s must be a color", path))
+	}
+
+	if s != "" && !colorPattern.MatchString(s) {
+		return errors.Validation(fmt.Sprintf("%s: '%s' is not a valid color", path, s))
+	}
+
+	return nil
+}
+
+func oneOf(val any, values []string) bool {
+	s, ok := val.(string)
+	if !ok {
+		return false
+	}
+	for _, v := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
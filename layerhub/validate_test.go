@@ -0,0 +1,84 @@
+package layerhub
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/echovl/orderflo-dev/errors"
+)
+
+func TestValidateDesignJSON(t *testing.T) {
+	nested := `{"type":"StaticPath","path":[]}`
+	for i := 0; i < MaxLayerDepth; i++ {
+		nested = fmt.Sprintf(`{"type":"Group","objects":[%s]}`, nested)
+	}
+
+	many := strings.TrimSuffix(strings.Repeat(`{"type":"Background"},`, MaxLayerCount+1), ",")
+
+	testcases := []struct {
+		name   string
+		design string
+		err    string
+	}{
+		{"empty", ``, ""},
+		{"without layers", `{"name":"untitled"}`, ""},
+		{
+			"valid",
+			`{"layers":[{"type":"Background","fill":"#fff"},{"type":"Group","objects":[{"type":"StaticText","text":"hi","fill":"rgba(0, 0, 0, 0.5)","shadow":{"color":"black"}}]}]}`,
+			"",
+		},
+		{
+			"declared types",
+			`{"layers":[{"type":"Frame","fill":"#fff"},{"type":"DynamicGroup","key":"badge","objects":[{"type":"StaticGroup","objects":[{"type":"DynamicPath","key":"outline","path":[["M",0,0]]}]}]}]}`,
+			"",
+		},
+		{"dynamic path without key", `{"layers":[{"type":"StaticGroup","objects":[{"type":"DynamicPath","path":[]}]}]}`, "layers[0].objects[0].key is required"},
+		{"barcode without format", `{"layers":[{"type":"Barcode","data":"123"}]}`, "layers[0].format is required"},
+		{"invalid code background", `{"layers":[{"type":"StaticQRCode","data":"x","backgroundColor":"#ggg"}]}`, "layers[0].backgroundColor: '#ggg' is not a valid color"},
+		{"invalid text fit", `{"layers":[{"type":"StaticText","text":"hi","textFit":"squeeze"}]}`, "layers[0].textFit must be one of 'shrink', 'ellipsis', 'grow'"},
+		{"unknown type", `{"layers":[{"type":"Background"},{"type":"Group","objects":[{"type":"Sticker"}]}]}`, "layers[1].objects[0].type: unknown layer type 'Sticker'"},
+		{"missing type", `{"layers":[{"top":1}]}`, "layers[0].type is required"},
+		{"layer not an object", `{"layers":[1]}`, "layers[0] must be an object"},
+		{"missing required prop", `{"layers":[{"type":"StaticImage","src":""}]}`, "layers[0].src is required"},
+		{"non numeric prop", `{"layers":[{"type":"Background","top":"10"}]}`, "layers[0].top must be a number"},
+		{"non finite number", `{"layers":[{"type":"StaticPath","path":[["M",1e999,0]]}]}`, "layers[0].path[0][1]: '1e999' is not a finite number"},
+		{"invalid color", `{"layers":[{"type":"Background","fill":"#12"}]}`, "layers[0].fill: '#12' is not a valid color"},
+		{"invalid shadow color", `{"layers":[{"type":"Background","shadow":{"color":"rgb(1;2)"}}]}`, "layers[0].shadow.color: 'rgb(1;2)' is not a valid color"},
+		{"too deep", `{"layers":[` + nested + `]}`, "groups can't be nested more than 10 levels"},
+		{"too many layers", `{"layers":[` + many + `]}`, "design has more than 1000 layers"},
+		{"too large", `{"name":"` + strings.Repeat("a", MaxDesignSize) + `"}`, "the maximum is"},
+		{"malformed", `{"layers":[`, "malformed design"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDesignJSON([]byte(tc.design))
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected error %q", tc.err)
+			}
+			if !errors.Is(err, errors.KindValidation) {
+				t.Errorf("expected a validation error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("got error %q, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestLayerUnmarshalUnknownType(t *testing.T) {
+	var layers []*Layer
+	err := json.Unmarshal([]byte(`[{"type":"Sticker"}]`), &layers)
+	if err == nil || !strings.Contains(err.Error(), "unknown layer type 'Sticker'") {
+		t.Fatalf("expected unknown layer type error, got %v", err)
+	}
+}
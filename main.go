@@ -11,12 +11,15 @@ import (
 	"github.com/echovl/orderflo-dev/cloud/google"
 	"github.com/echovl/orderflo-dev/db/mongodb"
 	"github.com/echovl/orderflo-dev/db/mysql"
+	"github.com/echovl/orderflo-dev/db/postgres"
 	"github.com/echovl/orderflo-dev/db/redis"
+	"github.com/echovl/orderflo-dev/db/sqlite"
 	"github.com/echovl/orderflo-dev/feeds/pexels"
 	"github.com/echovl/orderflo-dev/feeds/pixabay"
 	"github.com/echovl/orderflo-dev/http"
 	"github.com/echovl/orderflo-dev/layerhub"
 	"github.com/echovl/orderflo-dev/payments/paypal"
+	"github.com/echovl/orderflo-dev/search/bleve"
 	"github.com/echovl/orderflo-dev/upload/s3"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -30,6 +33,7 @@ type Config struct {
 	MongoURL           string `mapstructure:"MONGO_URL"`
 	MongoDBName        string `mapstructure:"MONGO_DB_NAME"`
 	MySQLDSN           string `mapstructure:"MYSQL_DSN"`
+	PostgresDSN        string `mapstructure:"POSTGRES_DSN"`
 	AWSAccessKeyID     string `mapstructure:"AWS_ACCESS_KEY_ID"`
 	AWSSecretAccessKey string `mapstructure:"AWS_SECRET_ACCESS_KEY"`
 	AWSRegion          string `mapstructure:"AWS_REGION"`
@@ -46,6 +50,14 @@ type Config struct {
 	GoogleClientID     string `mapstructure:"GOOGLE_CLIENT_ID"`
 	GoogleClientSecret string `mapstructure:"GOOGLE_CLIENT_SECRET"`
 	GoogleRedirectURI  string `mapstructure:"GOOGLE_REDIRECT_URI"`
+	SearchIndexPath    string `mapstructure:"SEARCH_INDEX_PATH"`
+	SQLitePath         string `mapstructure:"SQLITE_PATH"`
+	SkipMigrations     bool   `mapstructure:"SKIP_MIGRATIONS"`
+	DesignStorage      string `mapstructure:"DESIGN_STORAGE"`
+	DesignCacheSize    int    `mapstructure:"DESIGN_CACHE_SIZE"`
+	DesignCacheRedis   bool   `mapstructure:"DESIGN_CACHE_REDIS"`
+	TrashRetention     string `mapstructure:"TRASH_RETENTION"`
+	MetricsToken       string `mapstructure:"METRICS_TOKEN"`
 }
 
 func loadConfig(path string) (Config, error) {
@@ -90,6 +102,21 @@ func main() {
 
 	logger.Sugar().Infof("%+v", config)
 
+	// The migrations are applied on startup unless they're skipped, the
+	// migrate command applies them and exits
+	migrateOnly := len(os.Args) > 1 && os.Args[1] == "migrate"
+	if migrateOnly || !config.SkipMigrations {
+		version, err := migrateDB(config)
+		if err != nil {
+			log.Panic(err)
+		}
+		logger.Sugar().Infof("schema version: %d", version)
+
+		if migrateOnly {
+			return
+		}
+	}
+
 	uploader, err := s3.New(config.AWSRegion, config.AWSBucket, config.CDNBase)
 	if err != nil {
 		log.Panic(err)
@@ -112,16 +139,82 @@ func main() {
 		RedirectURI: config.GoogleRedirectURI,
 	})
 
-	mysqlDB, err := mysql.New(&mysql.Config{
-		DSN:             config.MySQLDSN,
-		ConnMaxIdleTime: 15 * time.Minute,
-		MaxOpenConns:    10,
-		MaxIdleConns:    5,
-	})
+	// Self-hosted deployments keep everything in a SQLite file, PostgreSQL is
+	// used when it has a DSN and MySQL otherwise
+	var db layerhub.DB
+	switch {
+	case config.SQLitePath != "":
+		db, err = sqlite.New(&sqlite.Config{Path: config.SQLitePath})
+	case config.PostgresDSN != "":
+		db, err = postgres.New(&postgres.Config{
+			DSN:             config.PostgresDSN,
+			ConnMaxIdleTime: 15 * time.Minute,
+			MaxOpenConns:    10,
+			MaxIdleConns:    5,
+		})
+	default:
+		db, err = mysql.New(&mysql.Config{
+			DSN:             config.MySQLDSN,
+			ConnMaxIdleTime: 15 * time.Minute,
+			MaxOpenConns:    10,
+			MaxIdleConns:    5,
+		})
+	}
 	if err != nil {
 		log.Panic(err)
 	}
 
+	// The embedded index is used when it has a path or with SQLite, it only
+	// sees the writes of its own instance. PostgreSQL and MySQL deployments
+	// without a path share an index stored in their database, tsvector and
+	// FULLTEXT respectively. With SQLite the index is kept next to the
+	// database file
+	searchIndexPath := config.SearchIndexPath
+	if searchIndexPath == "" && config.SQLitePath != "" {
+		searchIndexPath = config.SQLitePath + ".index"
+	}
+	var searchIndex layerhub.SearchIndex
+	// newIndex is true when the index starts empty, it's filled from the db
+	// before serving
+	newIndex := false
+	switch {
+	case searchIndexPath != "":
+		if _, err := os.Stat(searchIndexPath); os.IsNotExist(err) {
+			newIndex = true
+		}
+		bleveIndex, err := bleve.New(searchIndexPath)
+		if err != nil {
+			log.Panic(err)
+		}
+		defer bleveIndex.Close()
+		searchIndex = bleveIndex
+	case config.PostgresDSN != "":
+		postgresIndex, err := postgres.NewSearchIndex(&postgres.Config{
+			DSN:             config.PostgresDSN,
+			ConnMaxIdleTime: 15 * time.Minute,
+			MaxOpenConns:    5,
+			MaxIdleConns:    2,
+		})
+		if err != nil {
+			log.Panic(err)
+		}
+		newIndex, err = postgresIndex.Empty(context.Background())
+		if err != nil {
+			log.Panic(err)
+		}
+		searchIndex = postgresIndex
+	default:
+		searchIndex, err = mysql.NewSearchIndex(&mysql.Config{
+			DSN:             config.MySQLDSN,
+			ConnMaxIdleTime: 15 * time.Minute,
+			MaxOpenConns:    5,
+			MaxIdleConns:    2,
+		})
+		if err != nil {
+			log.Panic(err)
+		}
+	}
+
 	mongoDB, err := mongodb.New(&mongodb.Config{
 		URI: config.MongoURL,
 		DB:  config.MongoDBName,
@@ -144,20 +237,94 @@ func main() {
 	}
 	defer redisClient.Close(context.TODO())
 
+	designCache := layerhub.DesignCacheConfig{Size: config.DesignCacheSize}
+	if config.DesignCacheRedis {
+		designCache.KeyValueDB = redisClient
+		designCache.TTL = 24 * time.Hour
+	}
+
+	core := layerhub.New(layerhub.CoreConfig{
+		Logger:          logger,
+		DB:              db,
+		JSONDB:          mongoDB,
+		Uploader:        uploader,
+		Pixabay:         pixabayFeed,
+		Pexels:          pexelsFeed,
+		PaymentProvider: paymentProvider,
+		Renderer:        renderer,
+		GithubClient:    githubClient,
+		GoogleClient:    googleClient,
+		SearchIndex:     searchIndex,
+		DesignStorage:   layerhub.DesignStorage(config.DesignStorage),
+		DesignCache:     designCache,
+	})
+
+	// The copy-designs command copies the designs from the uploader to the
+	// JSONDB before switching DESIGN_STORAGE to jsondb
+	if len(os.Args) > 1 && os.Args[1] == "copy-designs" {
+		copied, err := core.CopyDesigns(context.Background())
+		if err != nil {
+			log.Panic(err)
+		}
+		logger.Sugar().Infof("designs copied: %d", copied)
+		return
+	}
+
+	// The purge-trash command deletes the items that have been in the trash
+	// for longer than TRASH_RETENTION, it's meant to run periodically
+	if len(os.Args) > 1 && os.Args[1] == "purge-trash" {
+		retention := layerhub.DefaultTrashRetention
+		if config.TrashRetention != "" {
+			retention, err = time.ParseDuration(config.TrashRetention)
+			if err != nil {
+				log.Panic(err)
+			}
+		}
+		purged, err := core.PurgeTrash(context.Background(), layerhub.Now().Add(-retention))
+		if err != nil {
+			log.Panic(err)
+		}
+		logger.Sugar().Infof("trash items purged: %d", purged)
+		return
+	}
+
+	if newIndex {
+		indexed, err := core.Reindex(context.Background())
+		if err != nil {
+			log.Panic(err)
+		}
+		logger.Sugar().Infof("search documents indexed: %d", indexed)
+	}
+
+	// Tenant and print jobs run in the background of the instance that
+	// started them, the jobs lost by a restart are resumed
+	go func() {
+		for {
+			resumed, err := core.ResumeTenantJobs(context.Background())
+			if err != nil {
+				logger.Sugar().Errorf("resume tenant jobs: %s", err)
+			} else if resumed > 0 {
+				logger.Sugar().Infof("tenant jobs resumed: %d", resumed)
+			}
+			time.Sleep(layerhub.StaleTenantJob)
+		}
+	}()
+	go func() {
+		for {
+			resumed, err := core.ResumePrintJobs(context.Background())
+			if err != nil {
+				logger.Sugar().Errorf("resume print jobs: %s", err)
+			} else if resumed > 0 {
+				logger.Sugar().Infof("print jobs resumed: %d", resumed)
+			}
+			time.Sleep(layerhub.StalePrintJob)
+		}
+	}()
+
 	server := http.NewServer(http.Config{
-		Core: layerhub.New(layerhub.CoreConfig{
-			Logger:          logger,
-			DB:              mysqlDB,
-			JSONDB:          mongoDB,
-			Uploader:        uploader,
-			Pixabay:         pixabayFeed,
-			Pexels:          pexelsFeed,
-			PaymentProvider: paymentProvider,
-			Renderer:        renderer,
-			GithubClient:    githubClient,
-			GoogleClient:    googleClient,
-		}),
+		Core:         core,
 		SessionDB:    redisClient,
+		MetricsToken: config.MetricsToken,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  15 * time.Minute,
@@ -166,3 +333,15 @@ func main() {
 		log.Panic(err)
 	}
 }
+
+// migrateDB applies the migrations of the configured database
+func migrateDB(config Config) (uint, error) {
+	switch {
+	case config.SQLitePath != "":
+		return sqlite.Migrate(&sqlite.Config{Path: config.SQLitePath})
+	case config.PostgresDSN != "":
+		return postgres.Migrate(&postgres.Config{DSN: config.PostgresDSN})
+	default:
+		return mysql.Migrate(&mysql.Config{DSN: config.MySQLDSN})
+	}
+}
@@ -0,0 +1,124 @@
+// Package pdf writes raster images as single page PDF documents.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+)
+
+// PointsPerInch is the PDF user space unit
+const PointsPerInch = 72
+
+// Encode writes img as a single page PDF, the page size is given in points
+// and the image is stretched to cover it. Transparent images keep their alpha
+// channel as a soft mask.
+func Encode(w io.Writer, img image.Image, width, height float64) error {
+	rgb, alpha, opaque := splitChannels(img)
+
+	bounds := img.Bounds()
+	doc := &document{}
+
+	doc.object("<< /Type /Catalog /Pages 2 0 R >>")
+	doc.object("<< /Type /Pages /Kids [3 0 R] /Count 1 >>")
+	doc.object(fmt.Sprintf(
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /XObject << /Im0 5 0 R >> >> /Contents 4 0 R >>",
+		number(width), number(height),
+	))
+	doc.stream("", []byte(fmt.Sprintf("q %s 0 0 %s 0 0 cm /Im0 Do Q", number(width), number(height))), false)
+
+	smask := ""
+	if !opaque {
+		smask = " /SMask 6 0 R"
+	}
+	err := doc.stream(fmt.Sprintf(
+		"/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8%s",
+		bounds.Dx(), bounds.Dy(), smask,
+	), rgb, true)
+	if err != nil {
+		return err
+	}
+
+	if !opaque {
+		err := doc.stream(fmt.Sprintf(
+			"/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8",
+			bounds.Dx(), bounds.Dy(),
+		), alpha, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = w.Write(doc.bytes())
+	return err
+}
+
+// splitChannels returns the non premultiplied color samples and the alpha
+// samples of the image, opaque is true if every pixel is fully opaque
+func splitChannels(img image.Image) ([]byte, []byte, bool) {
+	b := img.Bounds()
+	rgb := make([]byte, 0, b.Dx()*b.Dy()*3)
+	alpha := make([]byte, 0, b.Dx()*b.Dy())
+	opaque := true
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			rgb = append(rgb, c.R, c.G, c.B)
+			alpha = append(alpha, c.A)
+			if c.A != 0xff {
+				opaque = false
+			}
+		}
+	}
+
+	return rgb, alpha, opaque
+}
+
+type document struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+func (d *document) object(body string) {
+	if d.buf.Len() == 0 {
+		d.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	}
+	d.offsets = append(d.offsets, d.buf.Len())
+	fmt.Fprintf(&d.buf, "%d 0 obj\n%s\nendobj\n", len(d.offsets), body)
+}
+
+func (d *document) stream(dict string, data []byte, compress bool) error {
+	if compress {
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		if _, err := zw.Write(data); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		data = z.Bytes()
+		dict += " /Filter /FlateDecode"
+	}
+
+	d.object(fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data))
+	return nil
+}
+
+func (d *document) bytes() []byte {
+	xref := d.buf.Len()
+	fmt.Fprintf(&d.buf, "xref\n0 %d\n0000000000 65535 f \n", len(d.offsets)+1)
+	for _, off := range d.offsets {
+		fmt.Fprintf(&d.buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&d.buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(d.offsets)+1, xref)
+	return d.buf.Bytes()
+}
+
+func number(v float64) string {
+	return fmt.Sprintf("%.4f", v)
+}
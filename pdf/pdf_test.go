@@ -0,0 +1,70 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	opaque := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	transparent := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for i := 0; i < 8; i++ {
+		opaque.Set(i%4, i/4, color.NRGBA{255, 0, 0, 255})
+		transparent.Set(i%4, i/4, color.NRGBA{255, 0, 0, uint8(i * 30)})
+	}
+
+	testcases := []struct {
+		name    string
+		img     image.Image
+		objects int
+	}{
+		{"opaque", opaque, 5},
+		{"transparent", transparent, 6},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if err := Encode(out, tc.img, 288, 144); err != nil {
+				t.Fatal(err)
+			}
+			data := out.Bytes()
+
+			if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) {
+				t.Fatalf("missing header: %q", data[:16])
+			}
+			if !bytes.Contains(data, []byte("/MediaBox [0 0 288.0000 144.0000]")) {
+				t.Error("missing page size")
+			}
+			if got := bytes.Contains(data, []byte("/SMask")); got != (tc.objects == 6) {
+				t.Errorf("soft mask: got %v", got)
+			}
+
+			m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+			if m == nil {
+				t.Fatal("missing startxref")
+			}
+			xref, _ := strconv.Atoi(string(m[1]))
+			if !bytes.HasPrefix(data[xref:], []byte("xref\n")) {
+				t.Fatalf("startxref points to %q", data[xref:xref+8])
+			}
+
+			entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[xref:], -1)
+			if len(entries) != tc.objects {
+				t.Fatalf("got %d objects, want %d", len(entries), tc.objects)
+			}
+			for i, e := range entries {
+				off, _ := strconv.Atoi(string(e[1]))
+				want := fmt.Sprintf("%d 0 obj\n", i+1)
+				if !bytes.HasPrefix(data[off:], []byte(want)) {
+					t.Errorf("object %d: offset %d points to %q", i+1, off, data[off:off+len(want)])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package bleve
+
+import (
+	"context"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/layerhub"
+)
+
+// noCustomer is indexed as the customer of the documents shared with the
+// whole company, empty keywords can't be queried
+const noCustomer = "_"
+
+const facetSize = 20
+
+// BleveIndex is an embedded search index, it's meant for local development
+// and single node deployments
+type BleveIndex struct {
+	index bleve.Index
+}
+
+var _ layerhub.SearchIndex = (*BleveIndex)(nil)
+
+// New opens the index at path or creates it if it doesn't exist, an empty path
+// creates an in-memory index
+func New(path string) (*BleveIndex, error) {
+	if path == "" {
+		index, err := bleve.NewMemOnly(newMapping())
+		if err != nil {
+			return nil, err
+		}
+		return &BleveIndex{index}, nil
+	}
+
+	index, err := bleve.Open(path)
+	if err == bleve.ErrorIndexPathDoesNotExist {
+		index, err = bleve.New(path, newMapping())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &BleveIndex{index}, nil
+}
+
+func newMapping() mapping.IndexMapping {
+	keyword := bleve.NewKeywordFieldMapping()
+	text := bleve.NewTextFieldMapping()
+	text.Store = false
+	number := bleve.NewNumericFieldMapping()
+	boolean := bleve.NewBooleanFieldMapping()
+
+	doc := bleve.NewDocumentStaticMapping()
+	doc.AddFieldMappingsAt("kind", keyword)
+	doc.AddFieldMappingsAt("id", keyword)
+	doc.AddFieldMappingsAt("company_id", keyword)
+	doc.AddFieldMappingsAt("customer_id", keyword)
+	doc.AddFieldMappingsAt("public", boolean)
+	doc.AddFieldMappingsAt("name", text)
+	doc.AddFieldMappingsAt("description", text)
+	doc.AddFieldMappingsAt("text", text)
+	doc.AddFieldMappingsAt("tags", keyword)
+	doc.AddFieldMappingsAt("colors", keyword)
+	doc.AddFieldMappingsAt("size", keyword)
+	doc.AddFieldMappingsAt("width", number)
+	doc.AddFieldMappingsAt("height", number)
+
+	m := bleve.NewIndexMapping()
+	m.DefaultMapping = doc
+
+	return m
+}
+
+func docID(kind layerhub.SearchKind, id string) string {
+	return string(kind) + ":" + id
+}
+
+func (b *BleveIndex) IndexDocument(ctx context.Context, doc *layerhub.SearchDocument) error {
+	customerID := doc.CustomerID
+	if customerID == "" {
+		customerID = noCustomer
+	}
+
+	err := b.index.Index(docID(doc.Kind, doc.ID), map[string]any{
+		"kind":        string(doc.Kind),
+		"id":          doc.ID,
+		"company_id":  doc.CompanyID,
+		"customer_id": customerID,
+		"public":      doc.Public,
+		"name":        doc.Name,
+		"description": doc.Description,
+		"text":        strings.Join(doc.Text, "\n"),
+		"tags":        doc.Tags,
+		"colors":      doc.Colors,
+		"size":        doc.Size(),
+		"width":       doc.Width,
+		"height":      doc.Height,
+	})
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (b *BleveIndex) DeleteDocument(ctx context.Context, kind layerhub.SearchKind, id string) error {
+	if err := b.index.Delete(docID(kind, id)); err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+	return nil
+}
+
+func (b *BleveIndex) Search(ctx context.Context, q *layerhub.SearchQuery) (*layerhub.SearchResult, error) {
+	req := bleve.NewSearchRequestOptions(searchQuery(q), q.Limit, q.Offset, false)
+	req.AddFacet(layerhub.FacetTags, bleve.NewFacetRequest("tags", facetSize))
+	req.AddFacet(layerhub.FacetColors, bleve.NewFacetRequest("colors", facetSize))
+	req.AddFacet(layerhub.FacetSize, bleve.NewFacetRequest("size", facetSize))
+	if q.Text == "" {
+		req.SortBy([]string{"name", "_id"})
+	}
+
+	res, err := b.index.SearchInContext(ctx, req)
+	if err != nil {
+		return nil, errors.E(errors.KindUnexpected, err)
+	}
+
+	result := &layerhub.SearchResult{
+		IDs:    []string{},
+		Total:  int(res.Total),
+		Facets: map[string][]layerhub.SearchFacet{},
+	}
+
+	prefix := string(q.Kind) + ":"
+	for _, hit := range res.Hits {
+		result.IDs = append(result.IDs, strings.TrimPrefix(hit.ID, prefix))
+	}
+
+	for name, facet := range res.Facets {
+		terms := []layerhub.SearchFacet{}
+		for _, term := range facet.Terms.Terms() {
+			terms = append(terms, layerhub.SearchFacet{Value: term.Term, Count: term.Count})
+		}
+		result.Facets[name] = terms
+	}
+
+	return result, nil
+}
+
+func searchQuery(q *layerhub.SearchQuery) query.Query {
+	kind := bleve.NewTermQuery(string(q.Kind))
+	kind.SetField("kind")
+
+	company := bleve.NewTermQuery(q.CompanyID)
+	company.SetField("company_id")
+
+	var owned query.Query = company
+	if q.CustomerID != "" {
+		customer := bleve.NewTermQuery(q.CustomerID)
+		customer.SetField("customer_id")
+		shared := bleve.NewTermQuery(noCustomer)
+		shared.SetField("customer_id")
+		owned = bleve.NewConjunctionQuery(company, bleve.NewDisjunctionQuery(customer, shared))
+	}
+
+	public := bleve.NewBoolFieldQuery(true)
+	public.SetField("public")
+
+	conjuncts := []query.Query{kind, bleve.NewDisjunctionQuery(owned, public)}
+
+	if q.Text != "" {
+		name := bleve.NewMatchQuery(q.Text)
+		name.SetField("name")
+		name.SetBoost(3)
+		description := bleve.NewMatchQuery(q.Text)
+		description.SetField("description")
+		text := bleve.NewMatchQuery(q.Text)
+		text.SetField("text")
+		tags := bleve.NewTermQuery(q.Text)
+		tags.SetField("tags")
+		tags.SetBoost(2)
+		colors := bleve.NewTermQuery(q.Text)
+		colors.SetField("colors")
+
+		conjuncts = append(conjuncts, bleve.NewDisjunctionQuery(name, description, text, tags, colors))
+	}
+
+	for _, tag := range q.Tags {
+		term := bleve.NewTermQuery(tag)
+		term.SetField("tags")
+		conjuncts = append(conjuncts, term)
+	}
+
+	for _, color := range q.Colors {
+		term := bleve.NewTermQuery(color)
+		term.SetField("colors")
+		conjuncts = append(conjuncts, term)
+	}
+
+	if q.Width != 0 {
+		conjuncts = append(conjuncts, numberQuery("width", q.Width))
+	}
+
+	if q.Height != 0 {
+		conjuncts = append(conjuncts, numberQuery("height", q.Height))
+	}
+
+	return bleve.NewConjunctionQuery(conjuncts...)
+}
+
+func numberQuery(field string, n float64) query.Query {
+	inclusive := true
+	q := bleve.NewNumericRangeInclusiveQuery(&n, &n, &inclusive, &inclusive)
+	q.SetField(field)
+	return q
+}
+
+func (b *BleveIndex) Close() error {
+	return b.index.Close()
+}
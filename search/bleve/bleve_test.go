@@ -0,0 +1,149 @@
+package bleve
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/echovl/orderflo-dev/layerhub"
+)
+
+func TestBleveIndex_Search(t *testing.T) {
+	docs := []*layerhub.SearchDocument{
+		{
+			ID:          "template_1",
+			Kind:        layerhub.SearchTemplates,
+			CompanyID:   "company_1",
+			Name:        "Birthday party",
+			Description: "Invitation card",
+			Tags:        []string{"birthday", "party"},
+			Colors:      []string{"#ffffff"},
+			Text:        []string{"You are invited"},
+			Width:       1080,
+			Height:      1080,
+		},
+		{
+			ID:         "template_2",
+			Kind:       layerhub.SearchTemplates,
+			CompanyID:  "company_1",
+			CustomerID: "customer_1",
+			Name:       "Wedding",
+			Tags:       []string{"wedding"},
+			Colors:     []string{"#000000"},
+			Width:      1080,
+			Height:     1920,
+		},
+		{
+			ID:        "template_3",
+			Kind:      layerhub.SearchTemplates,
+			CompanyID: "company_2",
+			Public:    true,
+			Name:      "Public birthday",
+			Tags:      []string{"birthday"},
+			Width:     1080,
+			Height:    1080,
+		},
+		{
+			ID:        "template_4",
+			Kind:      layerhub.SearchTemplates,
+			CompanyID: "company_2",
+			Name:      "Private birthday",
+			Tags:      []string{"birthday"},
+		},
+		{
+			ID:        "upload_1",
+			Kind:      layerhub.SearchUploads,
+			CompanyID: "company_1",
+			Name:      "birthday.png",
+		},
+	}
+
+	testcases := []struct {
+		name  string
+		query layerhub.SearchQuery
+		ids   []string
+	}{
+		{"company", layerhub.SearchQuery{CompanyID: "company_1"}, []string{"template_1", "template_2", "template_3"}},
+		{"customer", layerhub.SearchQuery{CompanyID: "company_1", CustomerID: "customer_2"}, []string{"template_1", "template_3"}},
+		{"text", layerhub.SearchQuery{CompanyID: "company_1", Text: "birthday"}, []string{"template_1", "template_3"}},
+		{"layer text", layerhub.SearchQuery{CompanyID: "company_1", Text: "invited"}, []string{"template_1"}},
+		{"tags", layerhub.SearchQuery{CompanyID: "company_1", Tags: []string{"birthday", "party"}}, []string{"template_1"}},
+		{"colors", layerhub.SearchQuery{CompanyID: "company_1", Colors: []string{"#000000"}}, []string{"template_2"}},
+		{"size", layerhub.SearchQuery{CompanyID: "company_1", Width: 1080, Height: 1080}, []string{"template_1", "template_3"}},
+		{"other company", layerhub.SearchQuery{CompanyID: "company_2"}, []string{"template_3", "template_4"}},
+	}
+
+	index, err := New("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer index.Close()
+
+	for _, doc := range docs {
+		if err := index.IndexDocument(context.TODO(), doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.query.Kind = layerhub.SearchTemplates
+			tc.query.Limit = 10
+
+			res, err := index.Search(context.TODO(), &tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sort.Strings(res.IDs)
+			if !reflect.DeepEqual(res.IDs, tc.ids) {
+				t.Errorf("got %v, want %v", res.IDs, tc.ids)
+			}
+			if res.Total != len(tc.ids) {
+				t.Errorf("got total %d, want %d", res.Total, len(tc.ids))
+			}
+		})
+	}
+
+	t.Run("facets", func(t *testing.T) {
+		res, err := index.Search(context.TODO(), &layerhub.SearchQuery{
+			Kind:      layerhub.SearchTemplates,
+			CompanyID: "company_1",
+			Limit:     10,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []layerhub.SearchFacet{{Value: "birthday", Count: 2}, {Value: "party", Count: 1}, {Value: "wedding", Count: 1}}
+		if !reflect.DeepEqual(res.Facets[layerhub.FacetTags], want) {
+			t.Errorf("got tags %v, want %v", res.Facets[layerhub.FacetTags], want)
+		}
+
+		want = []layerhub.SearchFacet{{Value: "1080x1080px", Count: 2}, {Value: "1080x1920px", Count: 1}}
+		if !reflect.DeepEqual(res.Facets[layerhub.FacetSize], want) {
+			t.Errorf("got sizes %v, want %v", res.Facets[layerhub.FacetSize], want)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := index.DeleteDocument(context.TODO(), layerhub.SearchTemplates, "template_1"); err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := index.Search(context.TODO(), &layerhub.SearchQuery{
+			Kind:      layerhub.SearchTemplates,
+			CompanyID: "company_1",
+			Text:      "birthday",
+			Limit:     10,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(res.IDs, []string{"template_3"}) {
+			t.Errorf("got %v after delete", res.IDs)
+		}
+	})
+}
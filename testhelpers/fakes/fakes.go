@@ -0,0 +1,4 @@
+// Package fakes implements the external services used by layerhub.Core, the
+// renderer, the uploader, the media feeds and the payment provider, without
+// leaving the process. They're meant for tests
+package fakes
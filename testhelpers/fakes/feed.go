@@ -0,0 +1,54 @@
+package fakes
+
+import (
+	"github.com/echovl/orderflo-dev/feeds"
+)
+
+// MediaFeed returns pages of Images and Videos, every query matches all of
+// them. Pages start at 1
+type MediaFeed struct {
+	Domain string
+	Images []feeds.Image
+	Videos []feeds.Video
+}
+
+var _ feeds.MediaFeed = (*MediaFeed)(nil)
+
+func (f *MediaFeed) FetchImage(query string, page, perPage int) ([]feeds.Image, int, error) {
+	start, end := pageBounds(len(f.Images), page, perPage)
+	return f.Images[start:end], len(f.Images), nil
+}
+
+func (f *MediaFeed) FetchVideo(query string, page, perPage int) ([]feeds.Video, int, error) {
+	start, end := pageBounds(len(f.Videos), page, perPage)
+	return f.Videos[start:end], len(f.Videos), nil
+}
+
+func (f *MediaFeed) ImageDomain() string {
+	return f.Domain
+}
+
+func (f *MediaFeed) VideoDomain() string {
+	return f.Domain
+}
+
+// pageBounds returns the bounds of the page in a slice of n items
+func pageBounds(n, page, perPage int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage <= 0 {
+		return 0, n
+	}
+
+	start := (page - 1) * perPage
+	if start > n {
+		start = n
+	}
+	end := start + perPage
+	if end > n {
+		end = n
+	}
+
+	return start, end
+}
@@ -0,0 +1,85 @@
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/payments"
+)
+
+// PaymentProvider keeps the products, plans and subscriptions in memory
+type PaymentProvider struct {
+	mu            sync.Mutex
+	products      []payments.Product
+	plans         []payments.Plan
+	subscriptions map[string]payments.Subscription
+}
+
+var _ payments.Provider = (*PaymentProvider)(nil)
+
+func NewPaymentProvider() *PaymentProvider {
+	return &PaymentProvider{subscriptions: map[string]payments.Subscription{}}
+}
+
+// CreateProduct stores the product, an empty product ID is generated
+func (p *PaymentProvider) CreateProduct(ctx context.Context, product *payments.Product) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if product.ID == "" {
+		product.ID = fmt.Sprintf("PROD-%d", len(p.products)+1)
+	}
+	p.products = append(p.products, *product)
+
+	return nil
+}
+
+func (p *PaymentProvider) GetProducts(ctx context.Context) ([]payments.Product, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]payments.Product{}, p.products...), nil
+}
+
+// CreatePlan stores the plan, an empty plan ID is generated
+func (p *PaymentProvider) CreatePlan(ctx context.Context, plan *payments.Plan) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if plan.ID == "" {
+		plan.ID = fmt.Sprintf("P-%d", len(p.plans)+1)
+	}
+	p.plans = append(p.plans, *plan)
+
+	return nil
+}
+
+// Plans returns the created plans
+func (p *PaymentProvider) Plans() []payments.Plan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]payments.Plan{}, p.plans...)
+}
+
+func (p *PaymentProvider) GetSubscription(ctx context.Context, id string) (*payments.Subscription, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sub, ok := p.subscriptions[id]
+	if !ok {
+		return nil, errors.NotFound(fmt.Sprintf("subscription '%s' not found", id))
+	}
+
+	return &sub, nil
+}
+
+// PutSubscription stores a subscription returned by GetSubscription
+func (p *PaymentProvider) PutSubscription(sub payments.Subscription) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.subscriptions[sub.ID] = sub
+}
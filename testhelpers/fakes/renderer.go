@@ -0,0 +1,68 @@
+package fakes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"sync"
+
+	"github.com/echovl/orderflo-dev/upload"
+)
+
+// Renderer renders every design as a blank PNG, the rendered designs are
+// uploaded with Uploader
+type Renderer struct {
+	Uploader upload.Uploader
+
+	// Width and Height are the size of the rendered images, 16x16 if unset
+	Width  int
+	Height int
+
+	mu       sync.Mutex
+	rendered int
+}
+
+func (r *Renderer) Render(ctx context.Context, sch any, params map[string]any) (string, error) {
+	img, err := r.RawRender(ctx, sch, params)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	key := fmt.Sprintf("render/%d.png", r.rendered)
+	r.mu.Unlock()
+
+	return r.Uploader.Upload(ctx, key, img)
+}
+
+func (r *Renderer) RawRender(ctx context.Context, sch any, params map[string]any) ([]byte, error) {
+	r.mu.Lock()
+	r.rendered++
+	r.mu.Unlock()
+
+	width, height := r.Width, r.Height
+	if width == 0 || height == 0 {
+		width, height = 16, 16
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	out := &bytes.Buffer{}
+	if err := png.Encode(out, img); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// Rendered returns the number of rendered designs
+func (r *Renderer) Rendered() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rendered
+}
@@ -0,0 +1,110 @@
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/echovl/orderflo-dev/errors"
+	"github.com/echovl/orderflo-dev/upload"
+)
+
+// Uploader keeps the uploaded files in memory. It's also an http.Handler that
+// serves the files, BaseURL is the URL of the server that serves them
+type Uploader struct {
+	BaseURL string
+
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+var _ upload.SignedUploader = (*Uploader)(nil)
+
+func NewUploader() *Uploader {
+	return &Uploader{
+		BaseURL: "http://uploads.test",
+		files:   map[string][]byte{},
+	}
+}
+
+func (u *Uploader) Upload(ctx context.Context, key string, data []byte) (string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.files[key] = append([]byte{}, data...)
+	return u.url(key), nil
+}
+
+func (u *Uploader) UploadFile(ctx context.Context, key string, file io.ReadSeeker) (string, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	return u.Upload(ctx, key, data)
+}
+
+func (u *Uploader) Download(ctx context.Context, key string) ([]byte, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	data, ok := u.files[key]
+	if !ok {
+		return nil, errors.NotFound(fmt.Sprintf("file '%s' not found", key))
+	}
+
+	return append([]byte{}, data...), nil
+}
+
+func (u *Uploader) Delete(ctx context.Context, key string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	delete(u.files, key)
+	return nil
+}
+
+func (u *Uploader) Key(fileURL string) (string, bool) {
+	key := strings.TrimPrefix(fileURL, u.BaseURL+"/")
+	if key == fileURL || key == "" {
+		return "", false
+	}
+	return key, true
+}
+
+func (u *Uploader) GetPresignedURL(ctx context.Context, key string) (string, error) {
+	return u.url(key) + "?signature=fake", nil
+}
+
+func (u *Uploader) GetDownloadURL(ctx context.Context, key, name string) (string, error) {
+	return u.url(key) + "?signature=fake&filename=" + url.QueryEscape(name), nil
+}
+
+// Files returns the keys of the uploaded files
+func (u *Uploader) Files() []string {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	keys := []string{}
+	for key := range u.files {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (u *Uploader) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data, err := u.Download(r.Context(), strings.TrimPrefix(r.URL.Path, "/"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Write(data)
+}
+
+func (u *Uploader) url(key string) string {
+	return u.BaseURL + "/" + key
+}
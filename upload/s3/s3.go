@@ -3,19 +3,26 @@ package s3
 import (
 	"bytes"
 	"context"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"mime"
 	"net/url"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/echovl/orderflo-dev/errors"
 	"github.com/echovl/orderflo-dev/upload"
 )
 
+// downloadURLExpiration is how long the URLs of GetDownloadURL are valid
+const downloadURLExpiration = 15 * time.Minute
+
 type S3Uploader struct {
 	client  *s3.Client
 	bucket  string
@@ -36,10 +43,14 @@ func New(region string, bucket string, cdnBase string) (upload.SignedUploader, e
 }
 
 func (s *S3Uploader) Upload(ctx context.Context, key string, data []byte) (string, error) {
+	return s.UploadFile(ctx, key, bytes.NewReader(data))
+}
+
+func (s *S3Uploader) UploadFile(ctx context.Context, key string, file io.ReadSeeker) (string, error) {
 	input := &s3.PutObjectInput{
 		Bucket:      aws.String(s.bucket),
 		Key:         aws.String(key),
-		Body:        bytes.NewReader(data),
+		Body:        file,
 		ContentType: aws.String(mime.TypeByExtension(path.Ext(key))),
 	}
 
@@ -68,6 +79,10 @@ func (s *S3Uploader) Download(ctx context.Context, key string) ([]byte, error) {
 	}
 
 	out, err := s.client.GetObject(ctx, input)
+	var noSuchKey *types.NoSuchKey
+	if stderrors.As(err, &noSuchKey) {
+		return nil, errors.NotFound(fmt.Sprintf("file '%s' not found", key))
+	}
 	if err != nil {
 		return nil, errors.E(errors.KindUnexpected, err)
 	}
@@ -76,6 +91,42 @@ func (s *S3Uploader) Download(ctx context.Context, key string) ([]byte, error) {
 	return io.ReadAll(out.Body)
 }
 
+func (s *S3Uploader) Delete(ctx context.Context, key string) error {
+	input := &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}
+
+	_, err := s.client.DeleteObject(ctx, input)
+	if err != nil {
+		return errors.E(errors.KindUnexpected, err)
+	}
+
+	return nil
+}
+
+func (s *S3Uploader) Key(fileURL string) (string, bool) {
+	base := fmt.Sprintf("https://%v.s3.amazonaws.com/", s.bucket)
+	if s.cdnBase != "" {
+		// The keys are resolved against the CDN base like in Upload
+		var err error
+		base, err = joinURLs(s.cdnBase, ".")
+		if err != nil {
+			return "", false
+		}
+	}
+
+	if !strings.HasPrefix(fileURL, base) {
+		return "", false
+	}
+	key, err := url.PathUnescape(strings.TrimPrefix(fileURL, base))
+	if err != nil || key == "" {
+		return "", false
+	}
+
+	return key, true
+}
+
 func (s *S3Uploader) GetPresignedURL(ctx context.Context, key string) (string, error) {
 	pClient := s3.NewPresignClient(s.client)
 
@@ -93,6 +144,23 @@ func (s *S3Uploader) GetPresignedURL(ctx context.Context, key string) (string, e
 	return res.URL, nil
 }
 
+func (s *S3Uploader) GetDownloadURL(ctx context.Context, key, name string) (string, error) {
+	pClient := s3.NewPresignClient(s.client)
+
+	params := &s3.GetObjectInput{
+		Bucket:                     aws.String(s.bucket),
+		Key:                        aws.String(key),
+		ResponseContentDisposition: aws.String(fmt.Sprintf("attachment; filename=%q", name)),
+	}
+
+	res, err := pClient.PresignGetObject(ctx, params, s3.WithPresignExpires(downloadURLExpiration))
+	if err != nil {
+		return "", errors.E(errors.KindUnexpected, err)
+	}
+
+	return res.URL, nil
+}
+
 func joinURLs(base string, elem ...string) (result string, err error) {
 	url, err := url.Parse(base)
 	if err != nil {
@@ -0,0 +1,33 @@
+package s3
+
+import "testing"
+
+func TestKey(t *testing.T) {
+	testcases := []struct {
+		name    string
+		cdnBase string
+		url     string
+		key     string
+		ok      bool
+	}{
+		{"bucket", "", "https://layerhub.s3.amazonaws.com/uploads/a.png", "uploads/a.png", true},
+		{"cdn", "https://cdn.layerhub.io", "https://cdn.layerhub.io/uploads/a.png", "uploads/a.png", true},
+		{"cdn with a prefix", "https://cdn.layerhub.io/assets/", "https://cdn.layerhub.io/assets/uploads/a.png", "uploads/a.png", true},
+		{"escaped key", "https://cdn.layerhub.io/assets/", "https://cdn.layerhub.io/assets/my%20logo.png", "my logo.png", true},
+		{"other prefix", "https://cdn.layerhub.io/assets/", "https://cdn.layerhub.io/other/a.png", "", false},
+		{"foreign url", "https://cdn.layerhub.io", "https://pixabay.com/1.png", "", false},
+		{"bucket url with a cdn", "https://cdn.layerhub.io", "https://layerhub.s3.amazonaws.com/a.png", "", false},
+		{"empty", "", "", "", false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &S3Uploader{bucket: "layerhub", cdnBase: tc.cdnBase}
+
+			key, ok := s.Key(tc.url)
+			if key != tc.key || ok != tc.ok {
+				t.Errorf("got %q, %v, want %q, %v", key, ok, tc.key, tc.ok)
+			}
+		})
+	}
+}
@@ -2,14 +2,25 @@ package upload
 
 import (
 	"context"
+	"io"
 )
 
 type Uploader interface {
 	Upload(ctx context.Context, key string, data []byte) (string, error)
+	// UploadFile uploads the content of the file without reading it in memory
+	UploadFile(ctx context.Context, key string, file io.ReadSeeker) (string, error)
 	Download(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the file, a missing file isn't an error
+	Delete(ctx context.Context, key string) error
+	// Key returns the key of the file of a URL returned by Upload, it's
+	// false for the URLs of other files
+	Key(fileURL string) (string, bool)
 }
 
 type SignedUploader interface {
 	Uploader
 	GetPresignedURL(ctx context.Context, key string) (string, error)
+	// GetDownloadURL returns a URL that downloads the file as an attachment
+	// named name for a limited time
+	GetDownloadURL(ctx context.Context, key, name string) (string, error)
 }